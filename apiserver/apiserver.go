@@ -33,6 +33,15 @@ type CombinedBroker interface {
 	brokerapi.ServiceBroker
 }
 
+// ReloadableBroker is a broker that can reload its configuration file. The
+// management API then serves a route to reload it, and reports metrics for
+// the service offerings of the reloaded configuration.
+type ReloadableBroker interface {
+	CombinedBroker
+	mgmtapi.ServiceCatalog
+	mgmtapi.ConfigReloader
+}
+
 func New(
 	conf config.Config,
 	broker CombinedBroker,
@@ -42,7 +51,12 @@ func New(
 ) *http.Server {
 
 	brokerRouter := mux.NewRouter()
-	mgmtapi.AttachRoutes(brokerRouter, broker, conf.ServiceCatalog, mgmtapiLoggerFactory)
+	var serviceCatalog mgmtapi.ServiceCatalog = conf
+	if reloadableBroker, ok := broker.(ReloadableBroker); ok {
+		serviceCatalog = reloadableBroker
+		mgmtapi.AttachConfigReloadRoute(brokerRouter, reloadableBroker, mgmtapiLoggerFactory)
+	}
+	mgmtapi.AttachRoutes(brokerRouter, broker, serviceCatalog, mgmtapiLoggerFactory)
 	brokerapi.AttachRoutes(brokerRouter, broker, lager.NewLogger(componentName))
 	authProtectedBrokerAPI := apiauth.
 		NewWrapper(conf.Broker.Username, conf.Broker.Password).
@@ -59,6 +73,7 @@ func New(
 		negroni.NewRecovery(),
 		negroniLogger,
 		negroni.NewStatic(http.Dir("public")),
+		requestMetrics(brokerRouter),
 	)
 
 	server.UseHandler(authProtectedBrokerAPI)
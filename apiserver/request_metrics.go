@@ -0,0 +1,46 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/urfave/negroni"
+)
+
+// requestMetrics times Open Service Broker API requests. Requests are labelled
+// with the route template rather than the path, so that instance and binding
+// IDs do not end up in the metric labels.
+func requestMetrics(router *mux.Router) negroni.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		var match mux.RouteMatch
+		if !strings.HasPrefix(r.URL.Path, "/v2/") || !router.Match(r, &match) {
+			next(rw, r)
+			return
+		}
+
+		route, err := match.Route.GetPathTemplate()
+		if err != nil {
+			next(rw, r)
+			return
+		}
+
+		startedAt := time.Now()
+		next(rw, r)
+
+		status := http.StatusOK
+		if res, ok := rw.(negroni.ResponseWriter); ok && res.Status() != 0 {
+			status = res.Status()
+		}
+		metrics.OSBAPIRequestDuration.WithLabelValues(r.Method, route, strconv.Itoa(status)).Observe(time.Since(startedAt).Seconds())
+	}
+}
@@ -41,6 +41,11 @@ type BOSHDeployment interface {
 	director.Deployment
 }
 
+//go:generate counterfeiter -o fakes/fake_release.go . Release
+type Release interface {
+	director.Release
+}
+
 //go:generate counterfeiter -o fakes/fake_task.go . Task
 type Task interface {
 	director.Task
@@ -0,0 +1,43 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector
+
+import (
+	"log"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/pkg/errors"
+)
+
+const cloudConfigsLimit = 100
+
+// GetCloudConfigs returns the latest version of each named cloud config, which
+// BOSH merges when deploying. The director lists the versions of the configs
+// newest first.
+func (c *Client) GetCloudConfigs(logger *log.Logger) ([]BoshConfig, error) {
+	logger.Println("getting cloud configs")
+	d, err := c.Director(director.NewNoopTaskReporter())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to build director")
+	}
+
+	directorConfigs, err := d.ListConfigs(cloudConfigsLimit, director.ConfigsFilter{Type: "cloud"})
+	if err != nil {
+		return nil, errors.Wrap(err, "BOSH error getting cloud configs")
+	}
+
+	var configs []BoshConfig
+	names := map[string]bool{}
+	for _, config := range directorConfigs {
+		if names[config.Name] {
+			continue
+		}
+		names[config.Name] = true
+		configs = append(configs, BoshConfig{Type: config.Type, Name: config.Name, Content: config.Content})
+	}
+	return configs, nil
+}
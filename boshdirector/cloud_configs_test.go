@@ -0,0 +1,44 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+)
+
+var _ = Describe("getting cloud configs", func() {
+	It("returns the latest version of each cloud config", func() {
+		fakeDirector.ListConfigsReturns([]director.Config{
+			{Type: "cloud", Name: "default", Content: "networks: [{name: new}]"},
+			{Type: "cloud", Name: "service-instance_some-id", Content: "vm_types: [{name: large}]"},
+			{Type: "cloud", Name: "default", Content: "networks: [{name: old}]"},
+		}, nil)
+
+		configs, err := c.GetCloudConfigs(logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(Equal([]boshdirector.BoshConfig{
+			{Type: "cloud", Name: "default", Content: "networks: [{name: new}]"},
+			{Type: "cloud", Name: "service-instance_some-id", Content: "vm_types: [{name: large}]"},
+		}))
+		_, filter := fakeDirector.ListConfigsArgsForCall(0)
+		Expect(filter).To(Equal(director.ConfigsFilter{Type: "cloud"}))
+	})
+
+	It("returns an error when the configs cannot be listed", func() {
+		fakeDirector.ListConfigsReturns(nil, errors.New("oops"))
+
+		_, err := c.GetCloudConfigs(logger)
+
+		Expect(err).To(MatchError(ContainSubstring("BOSH error getting cloud configs")))
+	})
+})
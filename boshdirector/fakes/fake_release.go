@@ -0,0 +1,531 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/cppforlife/go-semi-semantic/version"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+)
+
+type FakeRelease struct {
+	CommitHashWithMarkStub        func(string) string
+	commitHashWithMarkMutex       sync.RWMutex
+	commitHashWithMarkArgsForCall []struct {
+		arg1 string
+	}
+	commitHashWithMarkReturns struct {
+		result1 string
+	}
+	commitHashWithMarkReturnsOnCall map[int]struct {
+		result1 string
+	}
+	DeleteStub        func(bool) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 bool
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	JobsStub        func() ([]director.Job, error)
+	jobsMutex       sync.RWMutex
+	jobsArgsForCall []struct {
+	}
+	jobsReturns struct {
+		result1 []director.Job
+		result2 error
+	}
+	jobsReturnsOnCall map[int]struct {
+		result1 []director.Job
+		result2 error
+	}
+	NameStub        func() string
+	nameMutex       sync.RWMutex
+	nameArgsForCall []struct {
+	}
+	nameReturns struct {
+		result1 string
+	}
+	nameReturnsOnCall map[int]struct {
+		result1 string
+	}
+	PackagesStub        func() ([]director.Package, error)
+	packagesMutex       sync.RWMutex
+	packagesArgsForCall []struct {
+	}
+	packagesReturns struct {
+		result1 []director.Package
+		result2 error
+	}
+	packagesReturnsOnCall map[int]struct {
+		result1 []director.Package
+		result2 error
+	}
+	VersionStub        func() version.Version
+	versionMutex       sync.RWMutex
+	versionArgsForCall []struct {
+	}
+	versionReturns struct {
+		result1 version.Version
+	}
+	versionReturnsOnCall map[int]struct {
+		result1 version.Version
+	}
+	VersionMarkStub        func(string) string
+	versionMarkMutex       sync.RWMutex
+	versionMarkArgsForCall []struct {
+		arg1 string
+	}
+	versionMarkReturns struct {
+		result1 string
+	}
+	versionMarkReturnsOnCall map[int]struct {
+		result1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRelease) CommitHashWithMark(arg1 string) string {
+	fake.commitHashWithMarkMutex.Lock()
+	ret, specificReturn := fake.commitHashWithMarkReturnsOnCall[len(fake.commitHashWithMarkArgsForCall)]
+	fake.commitHashWithMarkArgsForCall = append(fake.commitHashWithMarkArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CommitHashWithMarkStub
+	fakeReturns := fake.commitHashWithMarkReturns
+	fake.recordInvocation("CommitHashWithMark", []interface{}{arg1})
+	fake.commitHashWithMarkMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRelease) CommitHashWithMarkCallCount() int {
+	fake.commitHashWithMarkMutex.RLock()
+	defer fake.commitHashWithMarkMutex.RUnlock()
+	return len(fake.commitHashWithMarkArgsForCall)
+}
+
+func (fake *FakeRelease) CommitHashWithMarkCalls(stub func(string) string) {
+	fake.commitHashWithMarkMutex.Lock()
+	defer fake.commitHashWithMarkMutex.Unlock()
+	fake.CommitHashWithMarkStub = stub
+}
+
+func (fake *FakeRelease) CommitHashWithMarkArgsForCall(i int) string {
+	fake.commitHashWithMarkMutex.RLock()
+	defer fake.commitHashWithMarkMutex.RUnlock()
+	argsForCall := fake.commitHashWithMarkArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRelease) CommitHashWithMarkReturns(result1 string) {
+	fake.commitHashWithMarkMutex.Lock()
+	defer fake.commitHashWithMarkMutex.Unlock()
+	fake.CommitHashWithMarkStub = nil
+	fake.commitHashWithMarkReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeRelease) CommitHashWithMarkReturnsOnCall(i int, result1 string) {
+	fake.commitHashWithMarkMutex.Lock()
+	defer fake.commitHashWithMarkMutex.Unlock()
+	fake.CommitHashWithMarkStub = nil
+	if fake.commitHashWithMarkReturnsOnCall == nil {
+		fake.commitHashWithMarkReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.commitHashWithMarkReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeRelease) Delete(arg1 bool) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRelease) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeRelease) DeleteCalls(stub func(bool) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeRelease) DeleteArgsForCall(i int) bool {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRelease) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRelease) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRelease) Jobs() ([]director.Job, error) {
+	fake.jobsMutex.Lock()
+	ret, specificReturn := fake.jobsReturnsOnCall[len(fake.jobsArgsForCall)]
+	fake.jobsArgsForCall = append(fake.jobsArgsForCall, struct {
+	}{})
+	stub := fake.JobsStub
+	fakeReturns := fake.jobsReturns
+	fake.recordInvocation("Jobs", []interface{}{})
+	fake.jobsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRelease) JobsCallCount() int {
+	fake.jobsMutex.RLock()
+	defer fake.jobsMutex.RUnlock()
+	return len(fake.jobsArgsForCall)
+}
+
+func (fake *FakeRelease) JobsCalls(stub func() ([]director.Job, error)) {
+	fake.jobsMutex.Lock()
+	defer fake.jobsMutex.Unlock()
+	fake.JobsStub = stub
+}
+
+func (fake *FakeRelease) JobsReturns(result1 []director.Job, result2 error) {
+	fake.jobsMutex.Lock()
+	defer fake.jobsMutex.Unlock()
+	fake.JobsStub = nil
+	fake.jobsReturns = struct {
+		result1 []director.Job
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRelease) JobsReturnsOnCall(i int, result1 []director.Job, result2 error) {
+	fake.jobsMutex.Lock()
+	defer fake.jobsMutex.Unlock()
+	fake.JobsStub = nil
+	if fake.jobsReturnsOnCall == nil {
+		fake.jobsReturnsOnCall = make(map[int]struct {
+			result1 []director.Job
+			result2 error
+		})
+	}
+	fake.jobsReturnsOnCall[i] = struct {
+		result1 []director.Job
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRelease) Name() string {
+	fake.nameMutex.Lock()
+	ret, specificReturn := fake.nameReturnsOnCall[len(fake.nameArgsForCall)]
+	fake.nameArgsForCall = append(fake.nameArgsForCall, struct {
+	}{})
+	stub := fake.NameStub
+	fakeReturns := fake.nameReturns
+	fake.recordInvocation("Name", []interface{}{})
+	fake.nameMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRelease) NameCallCount() int {
+	fake.nameMutex.RLock()
+	defer fake.nameMutex.RUnlock()
+	return len(fake.nameArgsForCall)
+}
+
+func (fake *FakeRelease) NameCalls(stub func() string) {
+	fake.nameMutex.Lock()
+	defer fake.nameMutex.Unlock()
+	fake.NameStub = stub
+}
+
+func (fake *FakeRelease) NameReturns(result1 string) {
+	fake.nameMutex.Lock()
+	defer fake.nameMutex.Unlock()
+	fake.NameStub = nil
+	fake.nameReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeRelease) NameReturnsOnCall(i int, result1 string) {
+	fake.nameMutex.Lock()
+	defer fake.nameMutex.Unlock()
+	fake.NameStub = nil
+	if fake.nameReturnsOnCall == nil {
+		fake.nameReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.nameReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeRelease) Packages() ([]director.Package, error) {
+	fake.packagesMutex.Lock()
+	ret, specificReturn := fake.packagesReturnsOnCall[len(fake.packagesArgsForCall)]
+	fake.packagesArgsForCall = append(fake.packagesArgsForCall, struct {
+	}{})
+	stub := fake.PackagesStub
+	fakeReturns := fake.packagesReturns
+	fake.recordInvocation("Packages", []interface{}{})
+	fake.packagesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRelease) PackagesCallCount() int {
+	fake.packagesMutex.RLock()
+	defer fake.packagesMutex.RUnlock()
+	return len(fake.packagesArgsForCall)
+}
+
+func (fake *FakeRelease) PackagesCalls(stub func() ([]director.Package, error)) {
+	fake.packagesMutex.Lock()
+	defer fake.packagesMutex.Unlock()
+	fake.PackagesStub = stub
+}
+
+func (fake *FakeRelease) PackagesReturns(result1 []director.Package, result2 error) {
+	fake.packagesMutex.Lock()
+	defer fake.packagesMutex.Unlock()
+	fake.PackagesStub = nil
+	fake.packagesReturns = struct {
+		result1 []director.Package
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRelease) PackagesReturnsOnCall(i int, result1 []director.Package, result2 error) {
+	fake.packagesMutex.Lock()
+	defer fake.packagesMutex.Unlock()
+	fake.PackagesStub = nil
+	if fake.packagesReturnsOnCall == nil {
+		fake.packagesReturnsOnCall = make(map[int]struct {
+			result1 []director.Package
+			result2 error
+		})
+	}
+	fake.packagesReturnsOnCall[i] = struct {
+		result1 []director.Package
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRelease) Version() version.Version {
+	fake.versionMutex.Lock()
+	ret, specificReturn := fake.versionReturnsOnCall[len(fake.versionArgsForCall)]
+	fake.versionArgsForCall = append(fake.versionArgsForCall, struct {
+	}{})
+	stub := fake.VersionStub
+	fakeReturns := fake.versionReturns
+	fake.recordInvocation("Version", []interface{}{})
+	fake.versionMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRelease) VersionCallCount() int {
+	fake.versionMutex.RLock()
+	defer fake.versionMutex.RUnlock()
+	return len(fake.versionArgsForCall)
+}
+
+func (fake *FakeRelease) VersionCalls(stub func() version.Version) {
+	fake.versionMutex.Lock()
+	defer fake.versionMutex.Unlock()
+	fake.VersionStub = stub
+}
+
+func (fake *FakeRelease) VersionReturns(result1 version.Version) {
+	fake.versionMutex.Lock()
+	defer fake.versionMutex.Unlock()
+	fake.VersionStub = nil
+	fake.versionReturns = struct {
+		result1 version.Version
+	}{result1}
+}
+
+func (fake *FakeRelease) VersionReturnsOnCall(i int, result1 version.Version) {
+	fake.versionMutex.Lock()
+	defer fake.versionMutex.Unlock()
+	fake.VersionStub = nil
+	if fake.versionReturnsOnCall == nil {
+		fake.versionReturnsOnCall = make(map[int]struct {
+			result1 version.Version
+		})
+	}
+	fake.versionReturnsOnCall[i] = struct {
+		result1 version.Version
+	}{result1}
+}
+
+func (fake *FakeRelease) VersionMark(arg1 string) string {
+	fake.versionMarkMutex.Lock()
+	ret, specificReturn := fake.versionMarkReturnsOnCall[len(fake.versionMarkArgsForCall)]
+	fake.versionMarkArgsForCall = append(fake.versionMarkArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.VersionMarkStub
+	fakeReturns := fake.versionMarkReturns
+	fake.recordInvocation("VersionMark", []interface{}{arg1})
+	fake.versionMarkMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRelease) VersionMarkCallCount() int {
+	fake.versionMarkMutex.RLock()
+	defer fake.versionMarkMutex.RUnlock()
+	return len(fake.versionMarkArgsForCall)
+}
+
+func (fake *FakeRelease) VersionMarkCalls(stub func(string) string) {
+	fake.versionMarkMutex.Lock()
+	defer fake.versionMarkMutex.Unlock()
+	fake.VersionMarkStub = stub
+}
+
+func (fake *FakeRelease) VersionMarkArgsForCall(i int) string {
+	fake.versionMarkMutex.RLock()
+	defer fake.versionMarkMutex.RUnlock()
+	argsForCall := fake.versionMarkArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRelease) VersionMarkReturns(result1 string) {
+	fake.versionMarkMutex.Lock()
+	defer fake.versionMarkMutex.Unlock()
+	fake.VersionMarkStub = nil
+	fake.versionMarkReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeRelease) VersionMarkReturnsOnCall(i int, result1 string) {
+	fake.versionMarkMutex.Lock()
+	defer fake.versionMarkMutex.Unlock()
+	fake.VersionMarkStub = nil
+	if fake.versionMarkReturnsOnCall == nil {
+		fake.versionMarkReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.versionMarkReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeRelease) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.commitHashWithMarkMutex.RLock()
+	defer fake.commitHashWithMarkMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.jobsMutex.RLock()
+	defer fake.jobsMutex.RUnlock()
+	fake.nameMutex.RLock()
+	defer fake.nameMutex.RUnlock()
+	fake.packagesMutex.RLock()
+	defer fake.packagesMutex.RUnlock()
+	fake.versionMutex.RLock()
+	defer fake.versionMutex.RUnlock()
+	fake.versionMarkMutex.RLock()
+	defer fake.versionMarkMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRelease) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ boshdirector.Release = new(FakeRelease)
@@ -31,6 +31,37 @@ func (c *Client) GetTasks(deploymentName string, logger *log.Logger) (BoshTasks,
 
 }
 
+// GetCurrentTasks lists the tasks of all deployments that have not finished
+// yet, that is the tasks that are queued, processing or being cancelled.
+func (c *Client) GetCurrentTasks(logger *log.Logger) (BoshTasks, error) {
+	logger.Println("getting current tasks from bosh")
+	d, err := c.Director(director.NewNoopTaskReporter())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to build director")
+	}
+
+	tasks, err := d.CurrentTasks(director.TasksFilter{All: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not fetch current tasks")
+	}
+
+	var boshTasks BoshTasks
+	for _, task := range tasks {
+		boshTasks = append(boshTasks, BoshTask{
+			ID:          task.ID(),
+			State:       task.State(),
+			Description: task.Description(),
+			Result:      task.Result(),
+			ContextID:   task.ContextID(),
+
+			DeploymentName: task.DeploymentName(),
+			StartedAt:      task.StartedAt(),
+			LastActivityAt: task.LastActivityAt(),
+		})
+	}
+	return boshTasks, nil
+}
+
 func (c *Client) GetNormalisedTasksByContext(deploymentName, contextID string, logger *log.Logger) (BoshTasks, error) {
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
@@ -57,6 +88,10 @@ func (c *Client) toBoshTasks(tasks []director.Task, deploymentName string, logge
 				Description: task.Description(),
 				Result:      task.Result(),
 				ContextID:   task.ContextID(),
+
+				DeploymentName: task.DeploymentName(),
+				StartedAt:      task.StartedAt(),
+				LastActivityAt: task.LastActivityAt(),
 			})
 		}
 	}
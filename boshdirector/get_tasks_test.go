@@ -11,6 +11,7 @@ import (
 	"math"
 
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry/bosh-cli/director"
 	. "github.com/onsi/ginkgo"
@@ -52,8 +53,8 @@ var _ = Describe("getting tasks", func() {
 		}
 
 		expectedTasks = boshdirector.BoshTasks{
-			{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: ""},
-			{ID: 2, State: boshdirector.TaskDone, Description: "snapshot deployment", Result: "result-2", ContextID: "some-context"},
+			{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: "", DeploymentName: deploymentName},
+			{ID: 2, State: boshdirector.TaskDone, Description: "snapshot deployment", Result: "result-2", ContextID: "some-context", DeploymentName: deploymentName},
 		}
 
 	})
@@ -72,6 +73,19 @@ var _ = Describe("getting tasks", func() {
 			Expect(actualTasks).To(Equal(expectedTasks))
 		})
 
+		It("includes when each task started and was last active", func() {
+			startedAt := time.Date(2019, 3, 12, 10, 0, 0, 0, time.UTC)
+			lastActivityAt := startedAt.Add(5 * time.Minute)
+			doneTask.StartedAtReturns(startedAt)
+			doneTask.LastActivityAtReturns(lastActivityAt)
+
+			actualTasks, err := c.GetTasks(deploymentName, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(actualTasks[1].StartedAt).To(Equal(startedAt))
+			Expect(actualTasks[1].LastActivityAt).To(Equal(lastActivityAt))
+		})
+
 		It("wraps the error when fetching the tasks fails", func() {
 			fakeDirector.RecentTasksReturns([]director.Task{}, errors.New("boom"))
 
@@ -80,6 +94,33 @@ var _ = Describe("getting tasks", func() {
 		})
 	})
 
+	Describe("GetCurrentTasks", func() {
+		It("lists the unfinished tasks of all deployments", func() {
+			otherDeploymentTask := new(fakes.FakeTask)
+			otherDeploymentTask.IDReturns(3)
+			otherDeploymentTask.StateReturns("queued")
+			otherDeploymentTask.DescriptionReturns("create deployment")
+			otherDeploymentTask.DeploymentNameReturns("other-deployment")
+			fakeDirector.CurrentTasksReturns([]director.Task{processingTask, otherDeploymentTask}, nil)
+
+			actualTasks, err := c.GetCurrentTasks(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDirector.CurrentTasksArgsForCall(0)).To(Equal(director.TasksFilter{All: true}))
+			Expect(actualTasks).To(Equal(boshdirector.BoshTasks{
+				{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", DeploymentName: deploymentName},
+				{ID: 3, State: boshdirector.TaskQueued, Description: "create deployment", DeploymentName: "other-deployment"},
+			}))
+		})
+
+		It("wraps the error when fetching the tasks fails", func() {
+			fakeDirector.CurrentTasksReturns(nil, errors.New("boom"))
+
+			_, err := c.GetCurrentTasks(logger)
+			Expect(err).To(MatchError("Could not fetch current tasks: boom"))
+		})
+	})
+
 	Describe("GetTasksByContextID", func() {
 		var (
 			multipleTaskContextID = "multiple-context-id"
@@ -124,7 +165,7 @@ var _ = Describe("getting tasks", func() {
 
 		It("returns one task when there is one task with the context id", func() {
 			processingTask.ContextIDReturns(singleTaskContextID)
-			expectedTask := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: singleTaskContextID}
+			expectedTask := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: singleTaskContextID, DeploymentName: deploymentName}
 
 			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, singleTaskContextID, logger)
 			Expect(actualTasks).To(HaveLen(1))
@@ -147,13 +188,13 @@ var _ = Describe("getting tasks", func() {
 			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, "some-context", logger)
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(actualTasks).To(Equal(expectedTasksWithID("some-context")))
+			Expect(actualTasks).To(Equal(expectedTasksWithID(deploymentName, "some-context")))
 		})
 
 		It("returns the correct tasks when there are many tasks with the context id", func() {
 			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, multipleTaskContextID, logger)
 			Expect(actualTasks).To(HaveLen(2))
-			Expect(actualTasks).To(Equal(expectedTasksWithID(multipleTaskContextID)))
+			Expect(actualTasks).To(Equal(expectedTasksWithID(deploymentName, multipleTaskContextID)))
 			Expect(err).To(Not(HaveOccurred()))
 		})
 
@@ -178,6 +219,8 @@ var _ = Describe("getting tasks", func() {
 				Description: "errand completed",
 				Result:      "result-1",
 				ContextID:   errandTaskContextID,
+
+				DeploymentName: deploymentName,
 			}
 
 			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, errandTaskContextID, logger)
@@ -195,9 +238,9 @@ var _ = Describe("getting tasks", func() {
 	})
 })
 
-func expectedTasksWithID(contextID string) boshdirector.BoshTasks {
+func expectedTasksWithID(deploymentName, contextID string) boshdirector.BoshTasks {
 	return boshdirector.BoshTasks{
-		{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: contextID},
-		{ID: 2, State: boshdirector.TaskDone, Description: "snapshot deployment", Result: "result-2", ContextID: contextID},
+		{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: contextID, DeploymentName: deploymentName},
+		{ID: 2, State: boshdirector.TaskDone, Description: "snapshot deployment", Result: "result-2", ContextID: contextID, DeploymentName: deploymentName},
 	}
 }
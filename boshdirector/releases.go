@@ -0,0 +1,70 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/cppforlife/go-semi-semantic/version"
+	"github.com/pkg/errors"
+)
+
+// ReleaseVersion names a version of a release.
+type ReleaseVersion struct {
+	Name    string
+	Version string
+}
+
+// GetReleaseJobs returns the names of the jobs of each of the release versions
+// that has been uploaded, listing the releases on the director once. Versions
+// are compared semantically, so that 1.0 finds an uploaded 1.0.0. Release
+// versions that have not been uploaded are absent from the result.
+func (c *Client) GetReleaseJobs(releaseVersions []ReleaseVersion, logger *log.Logger) (map[ReleaseVersion][]string, error) {
+	logger.Printf("getting jobs of releases %v\n", releaseVersions)
+	d, err := c.Director(director.NewNoopTaskReporter())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to build director")
+	}
+
+	releases, err := d.Releases()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get the list of releases")
+	}
+
+	releaseJobs := map[ReleaseVersion][]string{}
+	for _, releaseVersion := range releaseVersions {
+		for _, release := range releases {
+			if release.Name() != releaseVersion.Name || !sameVersion(release.Version(), releaseVersion.Version) {
+				continue
+			}
+
+			jobs, err := release.Jobs()
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("BOSH error getting jobs of release %s/%s", releaseVersion.Name, releaseVersion.Version))
+			}
+
+			jobNames := []string{}
+			for _, job := range jobs {
+				jobNames = append(jobNames, job.Name)
+			}
+			releaseJobs[releaseVersion] = jobNames
+			break
+		}
+	}
+
+	return releaseJobs, nil
+}
+
+func sameVersion(uploaded version.Version, requested string) bool {
+	requestedVersion, err := version.NewVersionFromString(requested)
+	if err != nil {
+		return uploaded.String() == requested
+	}
+	return uploaded.IsEq(requestedVersion)
+}
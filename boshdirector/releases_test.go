@@ -0,0 +1,90 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/cppforlife/go-semi-semantic/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector/fakes"
+)
+
+var _ = Describe("getting the jobs of a release", func() {
+	var redis, otherRedis *fakes.FakeRelease
+
+	BeforeEach(func() {
+		otherRedis = new(fakes.FakeRelease)
+		otherRedis.NameReturns("redis")
+		otherRedis.VersionReturns(version.MustNewVersionFromString("1.2.2"))
+
+		redis = new(fakes.FakeRelease)
+		redis.NameReturns("redis")
+		redis.VersionReturns(version.MustNewVersionFromString("1.2.3"))
+		redis.JobsReturns([]director.Job{{Name: "redis-server"}, {Name: "health-check"}}, nil)
+
+		fakeDirector.ReleasesReturns([]director.Release{otherRedis, redis}, nil)
+	})
+
+	It("returns the job names of the release versions", func() {
+		jobs, err := c.GetReleaseJobs([]boshdirector.ReleaseVersion{{Name: "redis", Version: "1.2.3"}}, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(Equal(map[boshdirector.ReleaseVersion][]string{
+			{Name: "redis", Version: "1.2.3"}: {"redis-server", "health-check"},
+		}))
+		Expect(otherRedis.JobsCallCount()).To(Equal(0))
+	})
+
+	It("lists the releases once for all the release versions", func() {
+		otherRedis.JobsReturns([]director.Job{{Name: "redis-server"}}, nil)
+
+		jobs, err := c.GetReleaseJobs([]boshdirector.ReleaseVersion{
+			{Name: "redis", Version: "1.2.3"},
+			{Name: "redis", Version: "1.2.2"},
+		}, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(HaveLen(2))
+		Expect(fakeDirector.ReleasesCallCount()).To(Equal(1))
+	})
+
+	It("compares release versions semantically", func() {
+		redis.VersionReturns(version.MustNewVersionFromString("1.0.0"))
+
+		jobs, err := c.GetReleaseJobs([]boshdirector.ReleaseVersion{{Name: "redis", Version: "1.0"}}, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(HaveKeyWithValue(boshdirector.ReleaseVersion{Name: "redis", Version: "1.0"}, []string{"redis-server", "health-check"}))
+	})
+
+	It("leaves out release versions that have not been uploaded", func() {
+		jobs, err := c.GetReleaseJobs([]boshdirector.ReleaseVersion{{Name: "redis", Version: "2.0.0"}}, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(BeEmpty())
+	})
+
+	It("returns an error when the releases cannot be listed", func() {
+		fakeDirector.ReleasesReturns(nil, errors.New("oops"))
+
+		_, err := c.GetReleaseJobs([]boshdirector.ReleaseVersion{{Name: "redis", Version: "1.2.3"}}, logger)
+
+		Expect(err).To(MatchError(ContainSubstring("Cannot get the list of releases")))
+	})
+
+	It("returns an error when the jobs of the release cannot be read", func() {
+		redis.JobsReturns(nil, errors.New("oops"))
+
+		_, err := c.GetReleaseJobs([]boshdirector.ReleaseVersion{{Name: "redis", Version: "1.2.3"}}, logger)
+
+		Expect(err).To(MatchError(ContainSubstring("BOSH error getting jobs of release redis/1.2.3")))
+	})
+})
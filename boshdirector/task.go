@@ -6,7 +6,10 @@
 
 package boshdirector
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type BoshTask struct {
 	ID          int
@@ -14,6 +17,10 @@ type BoshTask struct {
 	Description string
 	Result      string
 	ContextID   string `json:"context_id,omitempty"`
+
+	DeploymentName string    `json:"-"`
+	StartedAt      time.Time `json:"-"`
+	LastActivityAt time.Time `json:"-"`
 }
 
 type TaskStateType int
@@ -63,7 +63,7 @@ func (b *Broker) Bind(
 				fmt.Errorf("finding plan ID %s", details.PlanID),
 			), logger)
 		}
-		schemas, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
 				return brokerapi.Binding{}, b.processError(err, logger)
@@ -86,12 +86,20 @@ func (b *Broker) Bind(
 		}
 	}
 
+	postBindErrands := plan.PostBindErrands()
+	if len(postBindErrands) > 0 && !asyncAllowed {
+		return brokerapi.Binding{}, b.processError(NewDisplayableError(
+			brokerapi.ErrAsyncRequired,
+			fmt.Errorf("plan %s has post_bind errands, but the request does not accept an asynchronous binding", plan.ID),
+		), logger)
+	}
+
 	dnsAddresses, err := b.boshClient.GetDNSAddresses(deploymentName(instanceID), plan.BindingWithDNS)
 	if err != nil {
 		return brokerapi.Binding{}, b.processError(NewGenericError(ctx, fmt.Errorf("failed to get required DNS info: %s", err)), logger)
 	}
 
-	binding, createBindingErr := b.adapterClient.CreateBinding(bindingID, vms, manifest, mappedParams, secretsMap, dnsAddresses, logger)
+	binding, createBindingErr := b.adapterClient.CreateBinding(ctx, bindingID, vms, manifest, mappedParams, secretsMap, dnsAddresses, logger)
 	if createBindingErr != nil {
 		if !b.EnableSecureManifests {
 			logger.Printf("broker.resolve_secrets_at_bind was: false ")
@@ -103,7 +111,18 @@ func (b *Broker) Bind(
 		return brokerapi.Binding{}, b.processError(err, logger)
 	}
 
+	var operationData string
+	if len(postBindErrands) > 0 {
+		logger.Printf("running post_bind errands for binding with ID %s for instance %s\n", bindingID, instanceID)
+		operationData, err = b.startBindingErrands(instanceID, OperationTypeBind, postBindErrands, logger)
+		if err != nil {
+			return brokerapi.Binding{}, b.processError(NewBoshRequestError("bind", err), logger)
+		}
+	}
+
 	return brokerapi.Binding{
+		IsAsync:         operationData != "",
+		OperationData:   operationData,
 		Credentials:     binding.Credentials,
 		SyslogDrainURL:  binding.SyslogDrainURL,
 		RouteServiceURL: binding.RouteServiceURL,
@@ -20,6 +20,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	brokerfakes "github.com/pivotal-cf/on-demand-service-broker/broker/fakes"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/noopservicescontroller"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
@@ -113,7 +114,7 @@ var _ = Describe("Bind", func() {
 
 			bindResult, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, false)
 			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
-			passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
+			_, passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
 			Expect(passedBindingID).To(Equal(bindingID))
 			Expect(passedVms).To(Equal(boshVms))
 			Expect(passedManifest).To(Equal(actualManifest))
@@ -151,7 +152,7 @@ var _ = Describe("Bind", func() {
 
 		It("creates the binding using the bosh topology, admin credentials and bosh dns addresses", func() {
 			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
-			passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
+			_, passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
 			Expect(passedBindingID).To(Equal(bindingID))
 			Expect(passedVms).To(Equal(boshVms))
 			Expect(passedManifest).To(Equal(actualManifest))
@@ -389,6 +390,31 @@ var _ = Describe("Bind", func() {
 				})
 			})
 
+			Context("with a timeout error", func() {
+				BeforeEach(func() {
+					serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.NewTimeoutError("adapter timed out"))
+				})
+
+				It("returns an error telling the user to try again later", func() {
+					Expect(bindErr).To(MatchError(broker.AdapterTimeoutErrorMessage))
+				})
+
+				It("passes the request context to the service adapter", func() {
+					ctx, _, _, _, _, _, _, _ := serviceAdapter.CreateBindingArgsForCall(0)
+					Expect(brokercontext.GetReqID(ctx)).NotTo(BeEmpty())
+				})
+			})
+
+			Context("with a canceled error", func() {
+				BeforeEach(func() {
+					serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.NewCanceledError("adapter request cancelled"))
+				})
+
+				It("returns an error telling the user the request was cancelled", func() {
+					Expect(bindErr).To(MatchError(broker.RequestCanceledMessage))
+				})
+			})
+
 			Context("with a binding already exists error", func() {
 				BeforeEach(func() {
 					serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.BindingAlreadyExistsError{})
@@ -581,6 +607,60 @@ var _ = Describe("Bind", func() {
 			Expect(logBuffer.String()).To(ContainSubstring("secrets needed"))
 		})
 	})
+
+	Context("when the plan has post_bind errands", func() {
+		BeforeEach(func() {
+			serviceCatalog.Plans[0].BindingErrands = &config.BindingErrands{
+				PostBind: []sdk.Errand{{Name: "create-acls", Instances: []string{"kafka/0"}}, {Name: "check-acls"}},
+			}
+			bindRequest.PlanID = serviceCatalog.Plans[0].ID
+			boshClient.RunErrandReturns(42, nil)
+		})
+
+		It("creates the binding and runs the first errand asynchronously", func() {
+			b = createDefaultBroker()
+			bindResult, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+
+			Expect(bindErr).NotTo(HaveOccurred())
+			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
+			Expect(bindResult.IsAsync).To(BeTrue())
+			Expect(bindResult.Credentials).To(Equal(adapterBindingResponse.Credentials))
+
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+			actualDeploymentName, actualErrand, actualInstances, actualContextID, _, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(serviceDeploymentName))
+			Expect(actualErrand).To(Equal("create-acls"))
+			Expect(actualInstances).To(Equal([]string{"kafka/0"}))
+
+			var operationData broker.OperationData
+			Expect(json.Unmarshal([]byte(bindResult.OperationData), &operationData)).To(Succeed())
+			Expect(operationData).To(Equal(broker.OperationData{
+				BoshTaskID:    42,
+				BoshContextID: actualContextID,
+				OperationType: broker.OperationTypeBind,
+				Errands:       []config.Errand{{Name: "create-acls", Instances: []string{"kafka/0"}}, {Name: "check-acls"}},
+			}))
+		})
+
+		It("fails when the request does not accept an asynchronous binding", func() {
+			b = createDefaultBroker()
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, false)
+
+			Expect(bindErr).To(Equal(brokerapi.ErrAsyncRequired))
+			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(0))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(0))
+		})
+
+		It("returns a bosh request error when the errand cannot be started", func() {
+			boshClient.RunErrandReturns(0, errors.New("director unavailable"))
+
+			b = createDefaultBroker()
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+
+			Expect(bindErr).To(MatchError(ContainSubstring("Currently unable to bind service instance")))
+			Expect(logBuffer.String()).To(ContainSubstring("error running errand create-acls: director unavailable"))
+		})
+	})
 })
 
 func generateBindRequestWithParams(params map[string]interface{}) brokerapi.BindDetails {
@@ -7,6 +7,7 @@
 package broker
 
 import (
+	"context"
 	"log"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
@@ -31,13 +33,19 @@ type Broker struct {
 	secretManager  ManifestSecretManager
 	instanceLister service.InstanceLister
 	hasher         Hasher
+	operationStore OperationStore
 	deploymentLock *sync.Mutex
 
-	serviceOffering         config.ServiceOffering
-	ExposeOperationalErrors bool
-	EnablePlanSchemas       bool
-	EnableSecureManifests   bool
-	DisableBoshConfigs      bool
+	serviceOffering            config.ServiceOffering
+	OtherServiceOfferingIDs    []string
+	ExposeOperationalErrors    bool
+	EnablePlanSchemas          bool
+	EnableSecureManifests      bool
+	EnableBindingsRetrievable  bool
+	EnableInstancesRetrievable bool
+	DisableBoshConfigs         bool
+	RollbackFailedUpgrades     bool
+	ExposeUpgradePreviewValues bool
 
 	loggerFactory *loggerfactory.LoggerFactory
 	catalogLock   sync.Mutex
@@ -55,23 +63,30 @@ func New(
 	manifestSecretManager ManifestSecretManager,
 	instanceLister service.InstanceLister,
 	hasher Hasher,
+	operationStore OperationStore,
+	deploymentLock *sync.Mutex,
 	loggerFactory *loggerfactory.LoggerFactory,
 ) (*Broker, error) {
 	b := &Broker{
-		boshClient:              boshClient,
-		cfClient:                cfClient,
-		adapterClient:           serviceAdapter,
-		deployer:                deployer,
-		deploymentLock:          &sync.Mutex{},
-		serviceOffering:         serviceOffering,
-		ExposeOperationalErrors: brokerConfig.ExposeOperationalErrors,
-		EnablePlanSchemas:       brokerConfig.EnablePlanSchemas,
-		EnableSecureManifests:   brokerConfig.EnableSecureManifests,
-		DisableBoshConfigs:      brokerConfig.DisableBoshConfigs,
-		secretManager:           manifestSecretManager,
-		instanceLister:          instanceLister,
-		hasher:                  hasher,
-		loggerFactory:           loggerFactory,
+		boshClient:                 boshClient,
+		cfClient:                   cfClient,
+		adapterClient:              serviceAdapter,
+		deployer:                   deployer,
+		deploymentLock:             deploymentLock,
+		serviceOffering:            serviceOffering,
+		ExposeOperationalErrors:    brokerConfig.ExposeOperationalErrors,
+		EnablePlanSchemas:          brokerConfig.EnablePlanSchemas,
+		EnableSecureManifests:      brokerConfig.EnableSecureManifests,
+		EnableBindingsRetrievable:  brokerConfig.EnableBindingsRetrievable,
+		EnableInstancesRetrievable: brokerConfig.EnableInstancesRetrievable,
+		DisableBoshConfigs:         brokerConfig.DisableBoshConfigs,
+		RollbackFailedUpgrades:     brokerConfig.RollbackFailedUpgrades,
+		ExposeUpgradePreviewValues: brokerConfig.ExposeUpgradePreviewValues,
+		secretManager:              manifestSecretManager,
+		instanceLister:             instanceLister,
+		hasher:                     hasher,
+		operationStore:             operationStore,
+		loggerFactory:              loggerFactory,
 	}
 
 	var startupCheckErrMessages []string
@@ -121,13 +136,14 @@ const (
 type OperationType string
 
 type OperationData struct {
-	BoshTaskID       int
-	BoshContextID    string `json:",omitempty"`
-	OperationType    OperationType
-	PlanID           string           `json:",omitempty"`
-	PostDeployErrand PostDeployErrand // DEPRECATED: only needed for compatibility with ODB 0.20.x
-	PreDeleteErrand  PreDeleteErrand  // DEPRECATED: only needed for compatibility with ODB 0.20.x
-	Errands          []config.Errand  `json:",omitempty"`
+	BoshTaskID        int
+	BoshContextID     string `json:",omitempty"`
+	OperationType     OperationType
+	PlanID            string           `json:",omitempty"`
+	PostDeployErrand  PostDeployErrand // DEPRECATED: only needed for compatibility with ODB 0.20.x
+	PreDeleteErrand   PreDeleteErrand  // DEPRECATED: only needed for compatibility with ODB 0.20.x
+	Errands           []config.Errand  `json:",omitempty"`
+	PreUpgradeErrands []config.Errand  `json:",omitempty"`
 }
 
 type Errand struct {
@@ -168,24 +184,37 @@ type StartupChecker interface {
 
 //go:generate counterfeiter -o fakes/fake_deployer.go . Deployer
 type Deployer interface {
-	Create(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
-	Update(deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap map[string]string, logger *log.Logger) (int, []byte, error)
-	Upgrade(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Update(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap map[string]string, logger *log.Logger) (int, []byte, error)
+	Upgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
 	Recreate(deploymentName, planID, boshContextID string, logger *log.Logger) (int, error)
+	PreviewUpgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, logger *log.Logger) (UpgradePreview, error)
+}
+
+//go:generate counterfeiter -o fakes/fake_operation_store.go . OperationStore
+type OperationStore interface {
+	Record(operation operationstore.Operation) error
+	UpdateState(instanceID string, boshTaskID, latestBoshTaskID int, state string) (bool, error)
+	RecordRollbackTask(instanceID string, boshTaskID, rollbackTaskID int) error
+	ForInstance(instanceID string) ([]operationstore.Operation, error)
+	SaveSnapshot(instanceID string, snapshot operationstore.Snapshot) error
+	Snapshot(instanceID string) (*operationstore.Snapshot, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_service_adapter_client.go . ServiceAdapterClient
 type ServiceAdapterClient interface {
-	CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error)
-	DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, logger *log.Logger) error
-	GenerateDashboardUrl(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
-	GeneratePlanSchema(plan serviceadapter.Plan, logger *log.Logger) (brokerapi.ServiceSchemas, error)
+	CreateBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error)
+	GetBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error)
+	DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, logger *log.Logger) error
+	GenerateDashboardUrl(ctx context.Context, instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
+	GeneratePlanSchema(ctx context.Context, plan serviceadapter.Plan, logger *log.Logger) (brokerapi.ServiceSchemas, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_bosh_client.go . BoshClient
 type BoshClient interface {
 	GetTask(taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
 	GetTasks(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	GetCurrentTasks(logger *log.Logger) (boshdirector.BoshTasks, error)
 	GetNormalisedTasksByContext(deploymentName, contextID string, logger *log.Logger) (boshdirector.BoshTasks, error)
 	VMs(deploymentName string, logger *log.Logger) (bosh.BoshVMs, error)
 	GetDeployment(name string, logger *log.Logger) ([]byte, bool, error)
@@ -199,6 +228,7 @@ type BoshClient interface {
 	Deploy(manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error)
 	Recreate(deploymentName, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
 	GetConfigs(configName string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
+	UpdateConfig(configType, configName string, configContent []byte, logger *log.Logger) error
 	DeleteConfig(configType, configName string, logger *log.Logger) (bool, error)
 }
 
@@ -207,6 +237,8 @@ type CloudFoundryClient interface {
 	GetAPIVersion(logger *log.Logger) (string, error)
 	CountInstancesOfPlan(serviceOfferingID, planID string, logger *log.Logger) (int, error)
 	CountInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOfferingInOrg(serviceOfferingID, orgGUID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOfferingInSpace(serviceOfferingID, spaceGUID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
 	GetInstanceState(serviceInstanceGUID string, logger *log.Logger) (cf.InstanceState, error)
 	GetInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]service.Instance, error)
 	GetInstancesOfServiceOfferingByOrgSpace(serviceOfferingID, orgName, spaceName string, logger *log.Logger) ([]service.Instance, error)
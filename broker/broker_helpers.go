@@ -13,7 +13,10 @@ import (
 
 	"log"
 
+	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 )
 
@@ -71,3 +74,23 @@ func convertToMap(object interface{}) (map[string]interface{}, error) {
 	}
 	return genericMap, nil
 }
+
+func (b *Broker) startBindingErrands(instanceID string, operationType OperationType, errands []config.Errand, logger *log.Logger) (string, error) {
+	contextID := uuid.New()
+	taskID, err := b.boshClient.RunErrand(deploymentName(instanceID), errands[0].Name, errands[0].Instances, contextID, logger, boshdirector.NewAsyncTaskReporter())
+	if err != nil {
+		return "", fmt.Errorf("error running errand %s: %s", errands[0].Name, err)
+	}
+
+	operationData, err := json.Marshal(OperationData{
+		BoshTaskID:    taskID,
+		BoshContextID: contextID,
+		OperationType: operationType,
+		Errands:       errands,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(operationData), nil
+}
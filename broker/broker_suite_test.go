@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"sync"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
@@ -43,6 +44,7 @@ const (
 
 	postDeployErrandPlanID = "post-deploy-errand-plan-id"
 	preDeleteErrandPlanID  = "pre-delete-errand-plan-id"
+	upgradeErrandsPlanID   = "upgrade-errands-plan-id"
 )
 
 var (
@@ -55,10 +57,12 @@ var (
 	fakeInstanceLister *servicefakes.FakeInstanceLister
 	serviceCatalog     config.ServiceOffering
 	logBuffer          *bytes.Buffer
+	deploymentLock     *sync.Mutex
 	loggerFactory      *loggerfactory.LoggerFactory
 	brokerConfig       config.Broker
 	fakeSecretManager  *fakes.FakeManifestSecretManager
 	fakeMapHasher      *fakes.FakeHasher
+	fakeOperationStore *fakes.FakeOperationStore
 
 	existingPlanServiceInstanceLimit    = 3
 	serviceOfferingServiceInstanceLimit = 5
@@ -286,6 +290,18 @@ var _ = BeforeEach(func() {
 		InstanceGroups: []serviceadapter.InstanceGroup{},
 	}
 
+	upgradeErrandsPlan := config.Plan{
+		ID: upgradeErrandsPlanID,
+		LifecycleErrands: &serviceadapter.LifecycleErrands{
+			PostDeploy: []serviceadapter.Errand{{Name: "health-check"}},
+		},
+		UpgradeErrands: &config.UpgradeErrands{
+			PreUpgrade:  []serviceadapter.Errand{{Name: "pre-check", Instances: []string{"redis-server/0"}}},
+			PostUpgrade: []serviceadapter.Errand{{Name: "smoke-tests"}},
+		},
+		InstanceGroups: []serviceadapter.InstanceGroup{},
+	}
+
 	boshClient = new(fakes.FakeBoshClient)
 	serviceAdapter = new(fakes.FakeServiceAdapterClient)
 	fakeDeployer = new(fakes.FakeDeployer)
@@ -294,6 +310,8 @@ var _ = BeforeEach(func() {
 	cfClient = new(fakes.FakeCloudFoundryClient)
 	fakeMapHasher = new(fakes.FakeHasher)
 	fakeMapHasher.HashStub = ReturnSameValueHasher
+	fakeOperationStore = new(fakes.FakeOperationStore)
+	deploymentLock = &sync.Mutex{}
 	cfClient.GetAPIVersionReturns("2.57.0", nil)
 
 	serviceCatalog = config.ServiceOffering{
@@ -314,6 +332,7 @@ var _ = BeforeEach(func() {
 			secondPlan,
 			postDeployErrandPlan,
 			preDeleteErrandPlan,
+			upgradeErrandsPlan,
 		},
 	}
 
@@ -363,6 +382,8 @@ func createBrokerWithAdapter(serviceAdapter *fakes.FakeServiceAdapterClient) *br
 		fakeSecretManager,
 		fakeInstanceLister,
 		fakeMapHasher,
+		fakeOperationStore,
+		deploymentLock,
 		loggerFactory,
 	)
 
@@ -384,6 +405,8 @@ func createBrokerWithServiceCatalog(catalog config.ServiceOffering) *broker.Brok
 		fakeSecretManager,
 		fakeInstanceLister,
 		fakeMapHasher,
+		fakeOperationStore,
+		deploymentLock,
 		loggerFactory,
 	)
 
@@ -407,6 +430,8 @@ func createBroker(startupCheckers []broker.StartupChecker, overrideClient ...bro
 		fakeSecretManager,
 		fakeInstanceLister,
 		fakeMapHasher,
+		fakeOperationStore,
+		deploymentLock,
 		loggerFactory,
 	)
 }
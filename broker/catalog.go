@@ -58,7 +58,7 @@ func (b *Broker) Services(ctx context.Context) ([]brokerapi.Service, error) {
 		}
 
 		if b.EnablePlanSchemas {
-			planSchema, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+			planSchema, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
 			if err != nil {
 				if _, ok := err.(serviceadapter.NotImplementedError); !ok {
 					return []brokerapi.Service{}, err
@@ -90,12 +90,14 @@ func (b *Broker) Services(ctx context.Context) ([]brokerapi.Service, error) {
 
 	b.cachedCatalog = []brokerapi.Service{
 		{
-			ID:            b.serviceOffering.ID,
-			Name:          b.serviceOffering.Name,
-			Description:   b.serviceOffering.Description,
-			Bindable:      b.serviceOffering.Bindable,
-			PlanUpdatable: b.serviceOffering.PlanUpdatable,
-			Plans:         servicePlans,
+			ID:                   b.serviceOffering.ID,
+			Name:                 b.serviceOffering.Name,
+			Description:          b.serviceOffering.Description,
+			Bindable:             b.serviceOffering.Bindable,
+			PlanUpdatable:        b.serviceOffering.PlanUpdatable,
+			InstancesRetrievable: b.EnableInstancesRetrievable,
+			BindingsRetrievable:  b.EnableBindingsRetrievable,
+			Plans:                servicePlans,
 			Metadata: &brokerapi.ServiceMetadata{
 				DisplayName:         b.serviceOffering.Metadata.DisplayName,
 				ImageUrl:            b.serviceOffering.Metadata.ImageURL,
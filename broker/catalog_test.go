@@ -134,11 +134,12 @@ var _ = Describe("Catalog", func() {
 
 		Expect(services).To(Equal([]brokerapi.Service{
 			{
-				ID:            serviceCatalog.ID,
-				Name:          serviceCatalog.Name,
-				Description:   serviceCatalog.Description,
-				Bindable:      serviceCatalog.Bindable,
-				PlanUpdatable: serviceCatalog.PlanUpdatable,
+				ID:                   serviceCatalog.ID,
+				Name:                 serviceCatalog.Name,
+				Description:          serviceCatalog.Description,
+				Bindable:             serviceCatalog.Bindable,
+				PlanUpdatable:        serviceCatalog.PlanUpdatable,
+				InstancesRetrievable: false,
 				Metadata: &brokerapi.ServiceMetadata{
 					DisplayName:         serviceCatalog.Metadata.DisplayName,
 					ImageUrl:            serviceCatalog.Metadata.DisplayName,
@@ -175,6 +176,28 @@ var _ = Describe("Catalog", func() {
 		))
 	})
 
+	It("advertises bindings as retrievable when enabled", func() {
+		brokerConfig.EnableBindingsRetrievable = true
+		b, brokerCreationErr = createBroker([]broker.StartupChecker{}, noopservicescontroller.New())
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+		services, err := b.Services(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(services[0].BindingsRetrievable).To(BeTrue())
+	})
+
+	It("advertises instances as retrievable when enabled", func() {
+		brokerConfig.EnableInstancesRetrievable = true
+		b, brokerCreationErr = createBroker([]broker.StartupChecker{}, noopservicescontroller.New())
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+		services, err := b.Services(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(services[0].InstancesRetrievable).To(BeTrue())
+	})
+
 	It("includes the plan dashboard", func() {
 		serviceCatalog.DashboardClient = &config.DashboardClient{
 			ID:          "super-id",
@@ -0,0 +1,59 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+)
+
+var _ = Describe("Deployment lock", func() {
+	provisionDetails := brokerapi.ProvisionDetails{
+		PlanID:           existingPlanID,
+		OrganizationGUID: "some-org-guid",
+		SpaceGUID:        "some-space-guid",
+		ServiceID:        serviceOfferingID,
+	}
+
+	It("stops the broker built by a reload from provisioning while the broker it replaced is still provisioning", func() {
+		boshClient.GetDeploymentReturns(nil, false, nil)
+		releaseFirstCreate := make(chan struct{})
+		fakeDeployer.CreateStub = func(_ context.Context, deploymentName, _ string, _ map[string]interface{}, _ string, _ *log.Logger) (int, []byte, error) {
+			if deploymentName == "service-instance_first-instance" {
+				<-releaseFirstCreate
+			}
+			return 1, []byte("some-manifest"), nil
+		}
+
+		initialBroker := createDefaultBroker()
+		reloadedBroker := createDefaultBroker()
+
+		firstDone := make(chan error, 1)
+		go func() {
+			_, err := initialBroker.Provision(context.Background(), "first-instance", provisionDetails, true)
+			firstDone <- err
+		}()
+		Eventually(fakeDeployer.CreateCallCount).Should(Equal(1))
+
+		secondDone := make(chan error, 1)
+		go func() {
+			_, err := reloadedBroker.Provision(context.Background(), "second-instance", provisionDetails, true)
+			secondDone <- err
+		}()
+		Consistently(fakeDeployer.CreateCallCount, 200*time.Millisecond).Should(Equal(1))
+
+		close(releaseFirstCreate)
+		Eventually(firstDone).Should(Receive(BeNil()))
+		Eventually(secondDone).Should(Receive(BeNil()))
+		Expect(fakeDeployer.CreateCallCount()).To(Equal(2))
+	})
+})
@@ -76,7 +76,7 @@ func (b *Broker) Deprovision(
 	plan, found := b.serviceOffering.FindPlanByID(deprovisionDetails.PlanID)
 	if found {
 		if errands := plan.PreDeleteErrands(); len(errands) != 0 {
-			serviceSpec, err := b.runPreDeleteErrands(ctx, instanceID, errands, logger)
+			serviceSpec, err := b.runPreDeleteErrands(ctx, instanceID, plan.ID, errands, logger)
 			return serviceSpec, b.processError(err, logger)
 		}
 	}
@@ -170,6 +170,7 @@ func (b *Broker) assertNoOperationsInProgress(ctx context.Context, instanceID st
 func (b *Broker) runPreDeleteErrands(
 	ctx context.Context,
 	instanceID string,
+	planID string,
 	preDeleteErrands []config.Errand,
 	logger *log.Logger,
 ) (brokerapi.DeprovisionServiceSpec, error) {
@@ -189,12 +190,15 @@ func (b *Broker) runPreDeleteErrands(
 		return brokerapi.DeprovisionServiceSpec{IsAsync: true}, NewGenericError(ctx, err)
 	}
 
-	operationData, err := json.Marshal(OperationData{
+	operation := OperationData{
 		OperationType: OperationTypeDelete,
 		BoshTaskID:    taskID,
 		BoshContextID: boshContextID,
 		Errands:       preDeleteErrands,
-	})
+	}
+	b.recordOperation(ctx, instanceID, planID, operation, logger)
+
+	operationData, err := json.Marshal(operation)
 
 	if err != nil {
 		return brokerapi.DeprovisionServiceSpec{IsAsync: true}, NewGenericError(ctx, err)
@@ -224,10 +228,13 @@ func (b *Broker) deleteInstance(
 	logger.Printf("Bosh task id for Delete instance %s was %d\n", instanceID, taskID)
 	ctx = brokercontext.WithBoshTaskID(ctx, taskID)
 
-	operationData, err := json.Marshal(OperationData{
+	operation := OperationData{
 		OperationType: OperationTypeDelete,
 		BoshTaskID:    taskID,
-	})
+	}
+	b.recordOperation(ctx, instanceID, planConfig.ID, operation, logger)
+
+	operationData, err := json.Marshal(operation)
 
 	if err != nil {
 		return brokerapi.DeprovisionServiceSpec{IsAsync: true}, NewGenericError(ctx, err)
@@ -10,9 +10,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
@@ -20,6 +22,8 @@ const (
 	GenericErrorPrefix         = "There was a problem completing your request. Please contact your operations team providing the following information:"
 	PendingChangesErrorMessage = "The service broker has been updated, and this service instance is out of date. Please contact your operator."
 	OperationInProgressMessage = "An operation is in progress for your service instance. Please try again later."
+	AdapterTimeoutErrorMessage = "The service broker took too long to process your request. Please try again later, or contact your operations team if the problem persists."
+	RequestCanceledMessage     = "The request was cancelled before the service broker could complete it. Please try again."
 
 	UpdateLoggerAction = ""
 )
@@ -114,6 +118,10 @@ func adapterToAPIError(ctx context.Context, err error) error {
 		return brokerapi.ErrBindingDoesNotExist
 	case serviceadapter.AppGuidNotProvidedError:
 		return brokerapi.ErrAppGuidNotProvided
+	case serviceadapter.TimeoutError:
+		return NewDisplayableError(errors.New(AdapterTimeoutErrorMessage), err)
+	case serviceadapter.CanceledError:
+		return NewDisplayableError(errors.New(RequestCanceledMessage), err)
 	case serviceadapter.UnknownFailureError:
 		if err.Error() == "" {
 			//Adapter returns an unknown error with no message
@@ -160,8 +168,45 @@ func NewServiceError(e error) error {
 
 type PendingChangesNotAppliedError struct {
 	error
+	Changes []manifestdiff.Change
 }
 
-func NewPendingChangesNotAppliedError(e error) error {
-	return PendingChangesNotAppliedError{error: e}
+func NewPendingChangesNotAppliedError(e error, changes []manifestdiff.Change) error {
+	return PendingChangesNotAppliedError{error: e, Changes: changes}
+}
+
+// Summary lists which parts of the deployed manifest differ from the one the
+// adapter generates now. Values are left out, as they may contain secrets.
+func (e PendingChangesNotAppliedError) Summary() string {
+	summary := "There are pending changes"
+	if len(e.Changes) == 0 {
+		return summary
+	}
+
+	descriptions := make([]string, 0, len(e.Changes))
+	for _, change := range e.Changes {
+		descriptions = append(descriptions, describeManifestChange(change))
+	}
+	return summary + ": " + strings.Join(descriptions, "; ")
+}
+
+var manifestSectionNames = map[string]string{
+	"instance_groups": "instance group",
+	"releases":        "release",
+	"stemcells":       "stemcell",
+}
+
+func describeManifestChange(change manifestdiff.Change) string {
+	segments := strings.Split(strings.TrimPrefix(change.Path, "/"), "/")
+
+	sectionName, isSection := manifestSectionNames[segments[0]]
+	if !isSection || len(segments) < 2 {
+		return fmt.Sprintf("%s %s", change.Path, change.Type)
+	}
+
+	element := strings.TrimPrefix(segments[1], "name=")
+	if len(segments) == 2 {
+		return fmt.Sprintf("%s %s %s", sectionName, element, change.Type)
+	}
+	return fmt.Sprintf("%s %s: %s %s", sectionName, element, strings.Join(segments[2:], "/"), change.Type)
 }
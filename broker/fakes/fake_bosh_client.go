@@ -2,13 +2,13 @@
 package fakes
 
 import (
-	log "log"
-	sync "sync"
+	"log"
+	"sync"
 
-	boshdirector "github.com/pivotal-cf/on-demand-service-broker/boshdirector"
-	broker "github.com/pivotal-cf/on-demand-service-broker/broker"
-	config "github.com/pivotal-cf/on-demand-service-broker/config"
-	bosh "github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 )
 
 type FakeBoshClient struct {
@@ -73,6 +73,19 @@ type FakeBoshClient struct {
 		result1 []boshdirector.BoshConfig
 		result2 error
 	}
+	GetCurrentTasksStub        func(*log.Logger) (boshdirector.BoshTasks, error)
+	getCurrentTasksMutex       sync.RWMutex
+	getCurrentTasksArgsForCall []struct {
+		arg1 *log.Logger
+	}
+	getCurrentTasksReturns struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}
+	getCurrentTasksReturnsOnCall map[int]struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}
 	GetDNSAddressesStub        func(string, []config.BindingDNS) (map[string]string, error)
 	getDNSAddressesMutex       sync.RWMutex
 	getDNSAddressesArgsForCall []struct {
@@ -271,15 +284,16 @@ func (fake *FakeBoshClient) DeleteConfig(arg1 string, arg2 string, arg3 *log.Log
 		arg2 string
 		arg3 *log.Logger
 	}{arg1, arg2, arg3})
+	stub := fake.DeleteConfigStub
+	fakeReturns := fake.deleteConfigReturns
 	fake.recordInvocation("DeleteConfig", []interface{}{arg1, arg2, arg3})
 	fake.deleteConfigMutex.Unlock()
-	if fake.DeleteConfigStub != nil {
-		return fake.DeleteConfigStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.deleteConfigReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -337,15 +351,16 @@ func (fake *FakeBoshClient) DeleteDeployment(arg1 string, arg2 string, arg3 *log
 		arg3 *log.Logger
 		arg4 *boshdirector.AsyncTaskReporter
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.DeleteDeploymentStub
+	fakeReturns := fake.deleteDeploymentReturns
 	fake.recordInvocation("DeleteDeployment", []interface{}{arg1, arg2, arg3, arg4})
 	fake.deleteDeploymentMutex.Unlock()
-	if fake.DeleteDeploymentStub != nil {
-		return fake.DeleteDeploymentStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.deleteDeploymentReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -408,15 +423,16 @@ func (fake *FakeBoshClient) Deploy(arg1 []byte, arg2 string, arg3 *log.Logger, a
 		arg3 *log.Logger
 		arg4 *boshdirector.AsyncTaskReporter
 	}{arg1Copy, arg2, arg3, arg4})
+	stub := fake.DeployStub
+	fakeReturns := fake.deployReturns
 	fake.recordInvocation("Deploy", []interface{}{arg1Copy, arg2, arg3, arg4})
 	fake.deployMutex.Unlock()
-	if fake.DeployStub != nil {
-		return fake.DeployStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.deployReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -472,15 +488,16 @@ func (fake *FakeBoshClient) GetConfigs(arg1 string, arg2 *log.Logger) ([]boshdir
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetConfigsStub
+	fakeReturns := fake.getConfigsReturns
 	fake.recordInvocation("GetConfigs", []interface{}{arg1, arg2})
 	fake.getConfigsMutex.Unlock()
-	if fake.GetConfigsStub != nil {
-		return fake.GetConfigsStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getConfigsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -529,6 +546,70 @@ func (fake *FakeBoshClient) GetConfigsReturnsOnCall(i int, result1 []boshdirecto
 	}{result1, result2}
 }
 
+func (fake *FakeBoshClient) GetCurrentTasks(arg1 *log.Logger) (boshdirector.BoshTasks, error) {
+	fake.getCurrentTasksMutex.Lock()
+	ret, specificReturn := fake.getCurrentTasksReturnsOnCall[len(fake.getCurrentTasksArgsForCall)]
+	fake.getCurrentTasksArgsForCall = append(fake.getCurrentTasksArgsForCall, struct {
+		arg1 *log.Logger
+	}{arg1})
+	stub := fake.GetCurrentTasksStub
+	fakeReturns := fake.getCurrentTasksReturns
+	fake.recordInvocation("GetCurrentTasks", []interface{}{arg1})
+	fake.getCurrentTasksMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) GetCurrentTasksCallCount() int {
+	fake.getCurrentTasksMutex.RLock()
+	defer fake.getCurrentTasksMutex.RUnlock()
+	return len(fake.getCurrentTasksArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetCurrentTasksCalls(stub func(*log.Logger) (boshdirector.BoshTasks, error)) {
+	fake.getCurrentTasksMutex.Lock()
+	defer fake.getCurrentTasksMutex.Unlock()
+	fake.GetCurrentTasksStub = stub
+}
+
+func (fake *FakeBoshClient) GetCurrentTasksArgsForCall(i int) *log.Logger {
+	fake.getCurrentTasksMutex.RLock()
+	defer fake.getCurrentTasksMutex.RUnlock()
+	argsForCall := fake.getCurrentTasksArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBoshClient) GetCurrentTasksReturns(result1 boshdirector.BoshTasks, result2 error) {
+	fake.getCurrentTasksMutex.Lock()
+	defer fake.getCurrentTasksMutex.Unlock()
+	fake.GetCurrentTasksStub = nil
+	fake.getCurrentTasksReturns = struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetCurrentTasksReturnsOnCall(i int, result1 boshdirector.BoshTasks, result2 error) {
+	fake.getCurrentTasksMutex.Lock()
+	defer fake.getCurrentTasksMutex.Unlock()
+	fake.GetCurrentTasksStub = nil
+	if fake.getCurrentTasksReturnsOnCall == nil {
+		fake.getCurrentTasksReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.BoshTasks
+			result2 error
+		})
+	}
+	fake.getCurrentTasksReturnsOnCall[i] = struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBoshClient) GetDNSAddresses(arg1 string, arg2 []config.BindingDNS) (map[string]string, error) {
 	var arg2Copy []config.BindingDNS
 	if arg2 != nil {
@@ -541,15 +622,16 @@ func (fake *FakeBoshClient) GetDNSAddresses(arg1 string, arg2 []config.BindingDN
 		arg1 string
 		arg2 []config.BindingDNS
 	}{arg1, arg2Copy})
+	stub := fake.GetDNSAddressesStub
+	fakeReturns := fake.getDNSAddressesReturns
 	fake.recordInvocation("GetDNSAddresses", []interface{}{arg1, arg2Copy})
 	fake.getDNSAddressesMutex.Unlock()
-	if fake.GetDNSAddressesStub != nil {
-		return fake.GetDNSAddressesStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getDNSAddressesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -605,15 +687,16 @@ func (fake *FakeBoshClient) GetDeployment(arg1 string, arg2 *log.Logger) ([]byte
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetDeploymentStub
+	fakeReturns := fake.getDeploymentReturns
 	fake.recordInvocation("GetDeployment", []interface{}{arg1, arg2})
 	fake.getDeploymentMutex.Unlock()
-	if fake.GetDeploymentStub != nil {
-		return fake.GetDeploymentStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.getDeploymentReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -671,15 +754,16 @@ func (fake *FakeBoshClient) GetDeployments(arg1 *log.Logger) ([]boshdirector.Dep
 	fake.getDeploymentsArgsForCall = append(fake.getDeploymentsArgsForCall, struct {
 		arg1 *log.Logger
 	}{arg1})
+	stub := fake.GetDeploymentsStub
+	fakeReturns := fake.getDeploymentsReturns
 	fake.recordInvocation("GetDeployments", []interface{}{arg1})
 	fake.getDeploymentsMutex.Unlock()
-	if fake.GetDeploymentsStub != nil {
-		return fake.GetDeploymentsStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getDeploymentsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -734,15 +818,16 @@ func (fake *FakeBoshClient) GetInfo(arg1 *log.Logger) (boshdirector.Info, error)
 	fake.getInfoArgsForCall = append(fake.getInfoArgsForCall, struct {
 		arg1 *log.Logger
 	}{arg1})
+	stub := fake.GetInfoStub
+	fakeReturns := fake.getInfoReturns
 	fake.recordInvocation("GetInfo", []interface{}{arg1})
 	fake.getInfoMutex.Unlock()
-	if fake.GetInfoStub != nil {
-		return fake.GetInfoStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getInfoReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -799,15 +884,16 @@ func (fake *FakeBoshClient) GetNormalisedTasksByContext(arg1 string, arg2 string
 		arg2 string
 		arg3 *log.Logger
 	}{arg1, arg2, arg3})
+	stub := fake.GetNormalisedTasksByContextStub
+	fakeReturns := fake.getNormalisedTasksByContextReturns
 	fake.recordInvocation("GetNormalisedTasksByContext", []interface{}{arg1, arg2, arg3})
 	fake.getNormalisedTasksByContextMutex.Unlock()
-	if fake.GetNormalisedTasksByContextStub != nil {
-		return fake.GetNormalisedTasksByContextStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getNormalisedTasksByContextReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -863,15 +949,16 @@ func (fake *FakeBoshClient) GetTask(arg1 int, arg2 *log.Logger) (boshdirector.Bo
 		arg1 int
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetTaskStub
+	fakeReturns := fake.getTaskReturns
 	fake.recordInvocation("GetTask", []interface{}{arg1, arg2})
 	fake.getTaskMutex.Unlock()
-	if fake.GetTaskStub != nil {
-		return fake.GetTaskStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getTaskReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -927,15 +1014,16 @@ func (fake *FakeBoshClient) GetTasks(arg1 string, arg2 *log.Logger) (boshdirecto
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetTasksStub
+	fakeReturns := fake.getTasksReturns
 	fake.recordInvocation("GetTasks", []interface{}{arg1, arg2})
 	fake.getTasksMutex.Unlock()
-	if fake.GetTasksStub != nil {
-		return fake.GetTasksStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getTasksReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -993,15 +1081,16 @@ func (fake *FakeBoshClient) Recreate(arg1 string, arg2 string, arg3 *log.Logger,
 		arg3 *log.Logger
 		arg4 *boshdirector.AsyncTaskReporter
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecreateStub
+	fakeReturns := fake.recreateReturns
 	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
 	fake.recreateMutex.Unlock()
-	if fake.RecreateStub != nil {
-		return fake.RecreateStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.recreateReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -1066,15 +1155,16 @@ func (fake *FakeBoshClient) RunErrand(arg1 string, arg2 string, arg3 []string, a
 		arg5 *log.Logger
 		arg6 *boshdirector.AsyncTaskReporter
 	}{arg1, arg2, arg3Copy, arg4, arg5, arg6})
+	stub := fake.RunErrandStub
+	fakeReturns := fake.runErrandReturns
 	fake.recordInvocation("RunErrand", []interface{}{arg1, arg2, arg3Copy, arg4, arg5, arg6})
 	fake.runErrandMutex.Unlock()
-	if fake.RunErrandStub != nil {
-		return fake.RunErrandStub(arg1, arg2, arg3, arg4, arg5, arg6)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.runErrandReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -1137,15 +1227,16 @@ func (fake *FakeBoshClient) UpdateConfig(arg1 string, arg2 string, arg3 []byte,
 		arg3 []byte
 		arg4 *log.Logger
 	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.UpdateConfigStub
+	fakeReturns := fake.updateConfigReturns
 	fake.recordInvocation("UpdateConfig", []interface{}{arg1, arg2, arg3Copy, arg4})
 	fake.updateConfigMutex.Unlock()
-	if fake.UpdateConfigStub != nil {
-		return fake.UpdateConfigStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.updateConfigReturns
 	return fakeReturns.result1
 }
 
@@ -1198,15 +1289,16 @@ func (fake *FakeBoshClient) VMs(arg1 string, arg2 *log.Logger) (bosh.BoshVMs, er
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.VMsStub
+	fakeReturns := fake.vMsReturns
 	fake.recordInvocation("VMs", []interface{}{arg1, arg2})
 	fake.vMsMutex.Unlock()
-	if fake.VMsStub != nil {
-		return fake.VMsStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.vMsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -1262,15 +1354,16 @@ func (fake *FakeBoshClient) Variables(arg1 string, arg2 *log.Logger) ([]boshdire
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.VariablesStub
+	fakeReturns := fake.variablesReturns
 	fake.recordInvocation("Variables", []interface{}{arg1, arg2})
 	fake.variablesMutex.Unlock()
-	if fake.VariablesStub != nil {
-		return fake.VariablesStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.variablesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -1325,15 +1418,16 @@ func (fake *FakeBoshClient) VerifyAuth(arg1 *log.Logger) error {
 	fake.verifyAuthArgsForCall = append(fake.verifyAuthArgsForCall, struct {
 		arg1 *log.Logger
 	}{arg1})
+	stub := fake.VerifyAuthStub
+	fakeReturns := fake.verifyAuthReturns
 	fake.recordInvocation("VerifyAuth", []interface{}{arg1})
 	fake.verifyAuthMutex.Unlock()
-	if fake.VerifyAuthStub != nil {
-		return fake.VerifyAuthStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.verifyAuthReturns
 	return fakeReturns.result1
 }
 
@@ -1390,6 +1484,8 @@ func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	defer fake.deployMutex.RUnlock()
 	fake.getConfigsMutex.RLock()
 	defer fake.getConfigsMutex.RUnlock()
+	fake.getCurrentTasksMutex.RLock()
+	defer fake.getCurrentTasksMutex.RUnlock()
 	fake.getDNSAddressesMutex.RLock()
 	defer fake.getDNSAddressesMutex.RUnlock()
 	fake.getDeploymentMutex.RLock()
@@ -11,25 +11,12 @@ import (
 )
 
 type FakeCloudFoundryClient struct {
-	GetAPIVersionStub        func(logger *log.Logger) (string, error)
-	getAPIVersionMutex       sync.RWMutex
-	getAPIVersionArgsForCall []struct {
-		logger *log.Logger
-	}
-	getAPIVersionReturns struct {
-		result1 string
-		result2 error
-	}
-	getAPIVersionReturnsOnCall map[int]struct {
-		result1 string
-		result2 error
-	}
-	CountInstancesOfPlanStub        func(serviceOfferingID, planID string, logger *log.Logger) (int, error)
+	CountInstancesOfPlanStub        func(string, string, *log.Logger) (int, error)
 	countInstancesOfPlanMutex       sync.RWMutex
 	countInstancesOfPlanArgsForCall []struct {
-		serviceOfferingID string
-		planID            string
-		logger            *log.Logger
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
 	}
 	countInstancesOfPlanReturns struct {
 		result1 int
@@ -39,11 +26,11 @@ type FakeCloudFoundryClient struct {
 		result1 int
 		result2 error
 	}
-	CountInstancesOfServiceOfferingStub        func(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOfferingStub        func(string, *log.Logger) (map[cf.ServicePlan]int, error)
 	countInstancesOfServiceOfferingMutex       sync.RWMutex
 	countInstancesOfServiceOfferingArgsForCall []struct {
-		serviceOfferingID string
-		logger            *log.Logger
+		arg1 string
+		arg2 *log.Logger
 	}
 	countInstancesOfServiceOfferingReturns struct {
 		result1 map[cf.ServicePlan]int
@@ -53,11 +40,54 @@ type FakeCloudFoundryClient struct {
 		result1 map[cf.ServicePlan]int
 		result2 error
 	}
-	GetInstanceStateStub        func(serviceInstanceGUID string, logger *log.Logger) (cf.InstanceState, error)
+	CountInstancesOfServiceOfferingInOrgStub        func(string, string, *log.Logger) (map[cf.ServicePlan]int, error)
+	countInstancesOfServiceOfferingInOrgMutex       sync.RWMutex
+	countInstancesOfServiceOfferingInOrgArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
+	}
+	countInstancesOfServiceOfferingInOrgReturns struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}
+	countInstancesOfServiceOfferingInOrgReturnsOnCall map[int]struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}
+	CountInstancesOfServiceOfferingInSpaceStub        func(string, string, *log.Logger) (map[cf.ServicePlan]int, error)
+	countInstancesOfServiceOfferingInSpaceMutex       sync.RWMutex
+	countInstancesOfServiceOfferingInSpaceArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
+	}
+	countInstancesOfServiceOfferingInSpaceReturns struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}
+	countInstancesOfServiceOfferingInSpaceReturnsOnCall map[int]struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}
+	GetAPIVersionStub        func(*log.Logger) (string, error)
+	getAPIVersionMutex       sync.RWMutex
+	getAPIVersionArgsForCall []struct {
+		arg1 *log.Logger
+	}
+	getAPIVersionReturns struct {
+		result1 string
+		result2 error
+	}
+	getAPIVersionReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetInstanceStateStub        func(string, *log.Logger) (cf.InstanceState, error)
 	getInstanceStateMutex       sync.RWMutex
 	getInstanceStateArgsForCall []struct {
-		serviceInstanceGUID string
-		logger              *log.Logger
+		arg1 string
+		arg2 *log.Logger
 	}
 	getInstanceStateReturns struct {
 		result1 cf.InstanceState
@@ -67,11 +97,11 @@ type FakeCloudFoundryClient struct {
 		result1 cf.InstanceState
 		result2 error
 	}
-	GetInstancesOfServiceOfferingStub        func(serviceOfferingID string, logger *log.Logger) ([]service.Instance, error)
+	GetInstancesOfServiceOfferingStub        func(string, *log.Logger) ([]service.Instance, error)
 	getInstancesOfServiceOfferingMutex       sync.RWMutex
 	getInstancesOfServiceOfferingArgsForCall []struct {
-		serviceOfferingID string
-		logger            *log.Logger
+		arg1 string
+		arg2 *log.Logger
 	}
 	getInstancesOfServiceOfferingReturns struct {
 		result1 []service.Instance
@@ -81,13 +111,13 @@ type FakeCloudFoundryClient struct {
 		result1 []service.Instance
 		result2 error
 	}
-	GetInstancesOfServiceOfferingByOrgSpaceStub        func(serviceOfferingID, orgName, spaceName string, logger *log.Logger) ([]service.Instance, error)
+	GetInstancesOfServiceOfferingByOrgSpaceStub        func(string, string, string, *log.Logger) ([]service.Instance, error)
 	getInstancesOfServiceOfferingByOrgSpaceMutex       sync.RWMutex
 	getInstancesOfServiceOfferingByOrgSpaceArgsForCall []struct {
-		serviceOfferingID string
-		orgName           string
-		spaceName         string
-		logger            *log.Logger
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
 	}
 	getInstancesOfServiceOfferingByOrgSpaceReturns struct {
 		result1 []service.Instance
@@ -101,74 +131,25 @@ type FakeCloudFoundryClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeCloudFoundryClient) GetAPIVersion(logger *log.Logger) (string, error) {
-	fake.getAPIVersionMutex.Lock()
-	ret, specificReturn := fake.getAPIVersionReturnsOnCall[len(fake.getAPIVersionArgsForCall)]
-	fake.getAPIVersionArgsForCall = append(fake.getAPIVersionArgsForCall, struct {
-		logger *log.Logger
-	}{logger})
-	fake.recordInvocation("GetAPIVersion", []interface{}{logger})
-	fake.getAPIVersionMutex.Unlock()
-	if fake.GetAPIVersionStub != nil {
-		return fake.GetAPIVersionStub(logger)
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
-	}
-	return fake.getAPIVersionReturns.result1, fake.getAPIVersionReturns.result2
-}
-
-func (fake *FakeCloudFoundryClient) GetAPIVersionCallCount() int {
-	fake.getAPIVersionMutex.RLock()
-	defer fake.getAPIVersionMutex.RUnlock()
-	return len(fake.getAPIVersionArgsForCall)
-}
-
-func (fake *FakeCloudFoundryClient) GetAPIVersionArgsForCall(i int) *log.Logger {
-	fake.getAPIVersionMutex.RLock()
-	defer fake.getAPIVersionMutex.RUnlock()
-	return fake.getAPIVersionArgsForCall[i].logger
-}
-
-func (fake *FakeCloudFoundryClient) GetAPIVersionReturns(result1 string, result2 error) {
-	fake.GetAPIVersionStub = nil
-	fake.getAPIVersionReturns = struct {
-		result1 string
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *FakeCloudFoundryClient) GetAPIVersionReturnsOnCall(i int, result1 string, result2 error) {
-	fake.GetAPIVersionStub = nil
-	if fake.getAPIVersionReturnsOnCall == nil {
-		fake.getAPIVersionReturnsOnCall = make(map[int]struct {
-			result1 string
-			result2 error
-		})
-	}
-	fake.getAPIVersionReturnsOnCall[i] = struct {
-		result1 string
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *FakeCloudFoundryClient) CountInstancesOfPlan(serviceOfferingID string, planID string, logger *log.Logger) (int, error) {
+func (fake *FakeCloudFoundryClient) CountInstancesOfPlan(arg1 string, arg2 string, arg3 *log.Logger) (int, error) {
 	fake.countInstancesOfPlanMutex.Lock()
 	ret, specificReturn := fake.countInstancesOfPlanReturnsOnCall[len(fake.countInstancesOfPlanArgsForCall)]
 	fake.countInstancesOfPlanArgsForCall = append(fake.countInstancesOfPlanArgsForCall, struct {
-		serviceOfferingID string
-		planID            string
-		logger            *log.Logger
-	}{serviceOfferingID, planID, logger})
-	fake.recordInvocation("CountInstancesOfPlan", []interface{}{serviceOfferingID, planID, logger})
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.CountInstancesOfPlanStub
+	fakeReturns := fake.countInstancesOfPlanReturns
+	fake.recordInvocation("CountInstancesOfPlan", []interface{}{arg1, arg2, arg3})
 	fake.countInstancesOfPlanMutex.Unlock()
-	if fake.CountInstancesOfPlanStub != nil {
-		return fake.CountInstancesOfPlanStub(serviceOfferingID, planID, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.countInstancesOfPlanReturns.result1, fake.countInstancesOfPlanReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfPlanCallCount() int {
@@ -177,13 +158,22 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfPlanCallCount() int {
 	return len(fake.countInstancesOfPlanArgsForCall)
 }
 
+func (fake *FakeCloudFoundryClient) CountInstancesOfPlanCalls(stub func(string, string, *log.Logger) (int, error)) {
+	fake.countInstancesOfPlanMutex.Lock()
+	defer fake.countInstancesOfPlanMutex.Unlock()
+	fake.CountInstancesOfPlanStub = stub
+}
+
 func (fake *FakeCloudFoundryClient) CountInstancesOfPlanArgsForCall(i int) (string, string, *log.Logger) {
 	fake.countInstancesOfPlanMutex.RLock()
 	defer fake.countInstancesOfPlanMutex.RUnlock()
-	return fake.countInstancesOfPlanArgsForCall[i].serviceOfferingID, fake.countInstancesOfPlanArgsForCall[i].planID, fake.countInstancesOfPlanArgsForCall[i].logger
+	argsForCall := fake.countInstancesOfPlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfPlanReturns(result1 int, result2 error) {
+	fake.countInstancesOfPlanMutex.Lock()
+	defer fake.countInstancesOfPlanMutex.Unlock()
 	fake.CountInstancesOfPlanStub = nil
 	fake.countInstancesOfPlanReturns = struct {
 		result1 int
@@ -192,6 +182,8 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfPlanReturns(result1 int, res
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfPlanReturnsOnCall(i int, result1 int, result2 error) {
+	fake.countInstancesOfPlanMutex.Lock()
+	defer fake.countInstancesOfPlanMutex.Unlock()
 	fake.CountInstancesOfPlanStub = nil
 	if fake.countInstancesOfPlanReturnsOnCall == nil {
 		fake.countInstancesOfPlanReturnsOnCall = make(map[int]struct {
@@ -205,22 +197,24 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfPlanReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
-func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error) {
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOffering(arg1 string, arg2 *log.Logger) (map[cf.ServicePlan]int, error) {
 	fake.countInstancesOfServiceOfferingMutex.Lock()
 	ret, specificReturn := fake.countInstancesOfServiceOfferingReturnsOnCall[len(fake.countInstancesOfServiceOfferingArgsForCall)]
 	fake.countInstancesOfServiceOfferingArgsForCall = append(fake.countInstancesOfServiceOfferingArgsForCall, struct {
-		serviceOfferingID string
-		logger            *log.Logger
-	}{serviceOfferingID, logger})
-	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{serviceOfferingID, logger})
+		arg1 string
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.CountInstancesOfServiceOfferingStub
+	fakeReturns := fake.countInstancesOfServiceOfferingReturns
+	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{arg1, arg2})
 	fake.countInstancesOfServiceOfferingMutex.Unlock()
-	if fake.CountInstancesOfServiceOfferingStub != nil {
-		return fake.CountInstancesOfServiceOfferingStub(serviceOfferingID, logger)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.countInstancesOfServiceOfferingReturns.result1, fake.countInstancesOfServiceOfferingReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingCallCount() int {
@@ -229,13 +223,22 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingCallCount() i
 	return len(fake.countInstancesOfServiceOfferingArgsForCall)
 }
 
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingCalls(stub func(string, *log.Logger) (map[cf.ServicePlan]int, error)) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingStub = stub
+}
+
 func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingArgsForCall(i int) (string, *log.Logger) {
 	fake.countInstancesOfServiceOfferingMutex.RLock()
 	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
-	return fake.countInstancesOfServiceOfferingArgsForCall[i].serviceOfferingID, fake.countInstancesOfServiceOfferingArgsForCall[i].logger
+	argsForCall := fake.countInstancesOfServiceOfferingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingReturns(result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
 	fake.CountInstancesOfServiceOfferingStub = nil
 	fake.countInstancesOfServiceOfferingReturns = struct {
 		result1 map[cf.ServicePlan]int
@@ -244,6 +247,8 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingReturns(resul
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingReturnsOnCall(i int, result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
 	fake.CountInstancesOfServiceOfferingStub = nil
 	if fake.countInstancesOfServiceOfferingReturnsOnCall == nil {
 		fake.countInstancesOfServiceOfferingReturnsOnCall = make(map[int]struct {
@@ -257,22 +262,220 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingReturnsOnCall
 	}{result1, result2}
 }
 
-func (fake *FakeCloudFoundryClient) GetInstanceState(serviceInstanceGUID string, logger *log.Logger) (cf.InstanceState, error) {
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInOrg(arg1 string, arg2 string, arg3 *log.Logger) (map[cf.ServicePlan]int, error) {
+	fake.countInstancesOfServiceOfferingInOrgMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfServiceOfferingInOrgReturnsOnCall[len(fake.countInstancesOfServiceOfferingInOrgArgsForCall)]
+	fake.countInstancesOfServiceOfferingInOrgArgsForCall = append(fake.countInstancesOfServiceOfferingInOrgArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.CountInstancesOfServiceOfferingInOrgStub
+	fakeReturns := fake.countInstancesOfServiceOfferingInOrgReturns
+	fake.recordInvocation("CountInstancesOfServiceOfferingInOrg", []interface{}{arg1, arg2, arg3})
+	fake.countInstancesOfServiceOfferingInOrgMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInOrgCallCount() int {
+	fake.countInstancesOfServiceOfferingInOrgMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingInOrgMutex.RUnlock()
+	return len(fake.countInstancesOfServiceOfferingInOrgArgsForCall)
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInOrgCalls(stub func(string, string, *log.Logger) (map[cf.ServicePlan]int, error)) {
+	fake.countInstancesOfServiceOfferingInOrgMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingInOrgMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingInOrgStub = stub
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInOrgArgsForCall(i int) (string, string, *log.Logger) {
+	fake.countInstancesOfServiceOfferingInOrgMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingInOrgMutex.RUnlock()
+	argsForCall := fake.countInstancesOfServiceOfferingInOrgArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInOrgReturns(result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingInOrgMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingInOrgMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingInOrgStub = nil
+	fake.countInstancesOfServiceOfferingInOrgReturns = struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInOrgReturnsOnCall(i int, result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingInOrgMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingInOrgMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingInOrgStub = nil
+	if fake.countInstancesOfServiceOfferingInOrgReturnsOnCall == nil {
+		fake.countInstancesOfServiceOfferingInOrgReturnsOnCall = make(map[int]struct {
+			result1 map[cf.ServicePlan]int
+			result2 error
+		})
+	}
+	fake.countInstancesOfServiceOfferingInOrgReturnsOnCall[i] = struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInSpace(arg1 string, arg2 string, arg3 *log.Logger) (map[cf.ServicePlan]int, error) {
+	fake.countInstancesOfServiceOfferingInSpaceMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfServiceOfferingInSpaceReturnsOnCall[len(fake.countInstancesOfServiceOfferingInSpaceArgsForCall)]
+	fake.countInstancesOfServiceOfferingInSpaceArgsForCall = append(fake.countInstancesOfServiceOfferingInSpaceArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.CountInstancesOfServiceOfferingInSpaceStub
+	fakeReturns := fake.countInstancesOfServiceOfferingInSpaceReturns
+	fake.recordInvocation("CountInstancesOfServiceOfferingInSpace", []interface{}{arg1, arg2, arg3})
+	fake.countInstancesOfServiceOfferingInSpaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInSpaceCallCount() int {
+	fake.countInstancesOfServiceOfferingInSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingInSpaceMutex.RUnlock()
+	return len(fake.countInstancesOfServiceOfferingInSpaceArgsForCall)
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInSpaceCalls(stub func(string, string, *log.Logger) (map[cf.ServicePlan]int, error)) {
+	fake.countInstancesOfServiceOfferingInSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingInSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingInSpaceStub = stub
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInSpaceArgsForCall(i int) (string, string, *log.Logger) {
+	fake.countInstancesOfServiceOfferingInSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingInSpaceMutex.RUnlock()
+	argsForCall := fake.countInstancesOfServiceOfferingInSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInSpaceReturns(result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingInSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingInSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingInSpaceStub = nil
+	fake.countInstancesOfServiceOfferingInSpaceReturns = struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingInSpaceReturnsOnCall(i int, result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingInSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingInSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingInSpaceStub = nil
+	if fake.countInstancesOfServiceOfferingInSpaceReturnsOnCall == nil {
+		fake.countInstancesOfServiceOfferingInSpaceReturnsOnCall = make(map[int]struct {
+			result1 map[cf.ServicePlan]int
+			result2 error
+		})
+	}
+	fake.countInstancesOfServiceOfferingInSpaceReturnsOnCall[i] = struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) GetAPIVersion(arg1 *log.Logger) (string, error) {
+	fake.getAPIVersionMutex.Lock()
+	ret, specificReturn := fake.getAPIVersionReturnsOnCall[len(fake.getAPIVersionArgsForCall)]
+	fake.getAPIVersionArgsForCall = append(fake.getAPIVersionArgsForCall, struct {
+		arg1 *log.Logger
+	}{arg1})
+	stub := fake.GetAPIVersionStub
+	fakeReturns := fake.getAPIVersionReturns
+	fake.recordInvocation("GetAPIVersion", []interface{}{arg1})
+	fake.getAPIVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudFoundryClient) GetAPIVersionCallCount() int {
+	fake.getAPIVersionMutex.RLock()
+	defer fake.getAPIVersionMutex.RUnlock()
+	return len(fake.getAPIVersionArgsForCall)
+}
+
+func (fake *FakeCloudFoundryClient) GetAPIVersionCalls(stub func(*log.Logger) (string, error)) {
+	fake.getAPIVersionMutex.Lock()
+	defer fake.getAPIVersionMutex.Unlock()
+	fake.GetAPIVersionStub = stub
+}
+
+func (fake *FakeCloudFoundryClient) GetAPIVersionArgsForCall(i int) *log.Logger {
+	fake.getAPIVersionMutex.RLock()
+	defer fake.getAPIVersionMutex.RUnlock()
+	argsForCall := fake.getAPIVersionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCloudFoundryClient) GetAPIVersionReturns(result1 string, result2 error) {
+	fake.getAPIVersionMutex.Lock()
+	defer fake.getAPIVersionMutex.Unlock()
+	fake.GetAPIVersionStub = nil
+	fake.getAPIVersionReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) GetAPIVersionReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getAPIVersionMutex.Lock()
+	defer fake.getAPIVersionMutex.Unlock()
+	fake.GetAPIVersionStub = nil
+	if fake.getAPIVersionReturnsOnCall == nil {
+		fake.getAPIVersionReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getAPIVersionReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) GetInstanceState(arg1 string, arg2 *log.Logger) (cf.InstanceState, error) {
 	fake.getInstanceStateMutex.Lock()
 	ret, specificReturn := fake.getInstanceStateReturnsOnCall[len(fake.getInstanceStateArgsForCall)]
 	fake.getInstanceStateArgsForCall = append(fake.getInstanceStateArgsForCall, struct {
-		serviceInstanceGUID string
-		logger              *log.Logger
-	}{serviceInstanceGUID, logger})
-	fake.recordInvocation("GetInstanceState", []interface{}{serviceInstanceGUID, logger})
+		arg1 string
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.GetInstanceStateStub
+	fakeReturns := fake.getInstanceStateReturns
+	fake.recordInvocation("GetInstanceState", []interface{}{arg1, arg2})
 	fake.getInstanceStateMutex.Unlock()
-	if fake.GetInstanceStateStub != nil {
-		return fake.GetInstanceStateStub(serviceInstanceGUID, logger)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getInstanceStateReturns.result1, fake.getInstanceStateReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeCloudFoundryClient) GetInstanceStateCallCount() int {
@@ -281,13 +484,22 @@ func (fake *FakeCloudFoundryClient) GetInstanceStateCallCount() int {
 	return len(fake.getInstanceStateArgsForCall)
 }
 
+func (fake *FakeCloudFoundryClient) GetInstanceStateCalls(stub func(string, *log.Logger) (cf.InstanceState, error)) {
+	fake.getInstanceStateMutex.Lock()
+	defer fake.getInstanceStateMutex.Unlock()
+	fake.GetInstanceStateStub = stub
+}
+
 func (fake *FakeCloudFoundryClient) GetInstanceStateArgsForCall(i int) (string, *log.Logger) {
 	fake.getInstanceStateMutex.RLock()
 	defer fake.getInstanceStateMutex.RUnlock()
-	return fake.getInstanceStateArgsForCall[i].serviceInstanceGUID, fake.getInstanceStateArgsForCall[i].logger
+	argsForCall := fake.getInstanceStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeCloudFoundryClient) GetInstanceStateReturns(result1 cf.InstanceState, result2 error) {
+	fake.getInstanceStateMutex.Lock()
+	defer fake.getInstanceStateMutex.Unlock()
 	fake.GetInstanceStateStub = nil
 	fake.getInstanceStateReturns = struct {
 		result1 cf.InstanceState
@@ -296,6 +508,8 @@ func (fake *FakeCloudFoundryClient) GetInstanceStateReturns(result1 cf.InstanceS
 }
 
 func (fake *FakeCloudFoundryClient) GetInstanceStateReturnsOnCall(i int, result1 cf.InstanceState, result2 error) {
+	fake.getInstanceStateMutex.Lock()
+	defer fake.getInstanceStateMutex.Unlock()
 	fake.GetInstanceStateStub = nil
 	if fake.getInstanceStateReturnsOnCall == nil {
 		fake.getInstanceStateReturnsOnCall = make(map[int]struct {
@@ -309,22 +523,24 @@ func (fake *FakeCloudFoundryClient) GetInstanceStateReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]service.Instance, error) {
+func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOffering(arg1 string, arg2 *log.Logger) ([]service.Instance, error) {
 	fake.getInstancesOfServiceOfferingMutex.Lock()
 	ret, specificReturn := fake.getInstancesOfServiceOfferingReturnsOnCall[len(fake.getInstancesOfServiceOfferingArgsForCall)]
 	fake.getInstancesOfServiceOfferingArgsForCall = append(fake.getInstancesOfServiceOfferingArgsForCall, struct {
-		serviceOfferingID string
-		logger            *log.Logger
-	}{serviceOfferingID, logger})
-	fake.recordInvocation("GetInstancesOfServiceOffering", []interface{}{serviceOfferingID, logger})
+		arg1 string
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.GetInstancesOfServiceOfferingStub
+	fakeReturns := fake.getInstancesOfServiceOfferingReturns
+	fake.recordInvocation("GetInstancesOfServiceOffering", []interface{}{arg1, arg2})
 	fake.getInstancesOfServiceOfferingMutex.Unlock()
-	if fake.GetInstancesOfServiceOfferingStub != nil {
-		return fake.GetInstancesOfServiceOfferingStub(serviceOfferingID, logger)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getInstancesOfServiceOfferingReturns.result1, fake.getInstancesOfServiceOfferingReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingCallCount() int {
@@ -333,13 +549,22 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingCallCount() int
 	return len(fake.getInstancesOfServiceOfferingArgsForCall)
 }
 
+func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingCalls(stub func(string, *log.Logger) ([]service.Instance, error)) {
+	fake.getInstancesOfServiceOfferingMutex.Lock()
+	defer fake.getInstancesOfServiceOfferingMutex.Unlock()
+	fake.GetInstancesOfServiceOfferingStub = stub
+}
+
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingArgsForCall(i int) (string, *log.Logger) {
 	fake.getInstancesOfServiceOfferingMutex.RLock()
 	defer fake.getInstancesOfServiceOfferingMutex.RUnlock()
-	return fake.getInstancesOfServiceOfferingArgsForCall[i].serviceOfferingID, fake.getInstancesOfServiceOfferingArgsForCall[i].logger
+	argsForCall := fake.getInstancesOfServiceOfferingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingReturns(result1 []service.Instance, result2 error) {
+	fake.getInstancesOfServiceOfferingMutex.Lock()
+	defer fake.getInstancesOfServiceOfferingMutex.Unlock()
 	fake.GetInstancesOfServiceOfferingStub = nil
 	fake.getInstancesOfServiceOfferingReturns = struct {
 		result1 []service.Instance
@@ -348,6 +573,8 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingReturns(result1
 }
 
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingReturnsOnCall(i int, result1 []service.Instance, result2 error) {
+	fake.getInstancesOfServiceOfferingMutex.Lock()
+	defer fake.getInstancesOfServiceOfferingMutex.Unlock()
 	fake.GetInstancesOfServiceOfferingStub = nil
 	if fake.getInstancesOfServiceOfferingReturnsOnCall == nil {
 		fake.getInstancesOfServiceOfferingReturnsOnCall = make(map[int]struct {
@@ -361,24 +588,26 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingReturnsOnCall(i
 	}{result1, result2}
 }
 
-func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpace(serviceOfferingID string, orgName string, spaceName string, logger *log.Logger) ([]service.Instance, error) {
+func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpace(arg1 string, arg2 string, arg3 string, arg4 *log.Logger) ([]service.Instance, error) {
 	fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Lock()
 	ret, specificReturn := fake.getInstancesOfServiceOfferingByOrgSpaceReturnsOnCall[len(fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall)]
 	fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall = append(fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall, struct {
-		serviceOfferingID string
-		orgName           string
-		spaceName         string
-		logger            *log.Logger
-	}{serviceOfferingID, orgName, spaceName, logger})
-	fake.recordInvocation("GetInstancesOfServiceOfferingByOrgSpace", []interface{}{serviceOfferingID, orgName, spaceName, logger})
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetInstancesOfServiceOfferingByOrgSpaceStub
+	fakeReturns := fake.getInstancesOfServiceOfferingByOrgSpaceReturns
+	fake.recordInvocation("GetInstancesOfServiceOfferingByOrgSpace", []interface{}{arg1, arg2, arg3, arg4})
 	fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Unlock()
-	if fake.GetInstancesOfServiceOfferingByOrgSpaceStub != nil {
-		return fake.GetInstancesOfServiceOfferingByOrgSpaceStub(serviceOfferingID, orgName, spaceName, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getInstancesOfServiceOfferingByOrgSpaceReturns.result1, fake.getInstancesOfServiceOfferingByOrgSpaceReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceCallCount() int {
@@ -387,13 +616,22 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceCallC
 	return len(fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall)
 }
 
+func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceCalls(stub func(string, string, string, *log.Logger) ([]service.Instance, error)) {
+	fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Lock()
+	defer fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Unlock()
+	fake.GetInstancesOfServiceOfferingByOrgSpaceStub = stub
+}
+
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceArgsForCall(i int) (string, string, string, *log.Logger) {
 	fake.getInstancesOfServiceOfferingByOrgSpaceMutex.RLock()
 	defer fake.getInstancesOfServiceOfferingByOrgSpaceMutex.RUnlock()
-	return fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall[i].serviceOfferingID, fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall[i].orgName, fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall[i].spaceName, fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall[i].logger
+	argsForCall := fake.getInstancesOfServiceOfferingByOrgSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceReturns(result1 []service.Instance, result2 error) {
+	fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Lock()
+	defer fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Unlock()
 	fake.GetInstancesOfServiceOfferingByOrgSpaceStub = nil
 	fake.getInstancesOfServiceOfferingByOrgSpaceReturns = struct {
 		result1 []service.Instance
@@ -402,6 +640,8 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceRetur
 }
 
 func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceReturnsOnCall(i int, result1 []service.Instance, result2 error) {
+	fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Lock()
+	defer fake.getInstancesOfServiceOfferingByOrgSpaceMutex.Unlock()
 	fake.GetInstancesOfServiceOfferingByOrgSpaceStub = nil
 	if fake.getInstancesOfServiceOfferingByOrgSpaceReturnsOnCall == nil {
 		fake.getInstancesOfServiceOfferingByOrgSpaceReturnsOnCall = make(map[int]struct {
@@ -418,12 +658,16 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingByOrgSpaceRetur
 func (fake *FakeCloudFoundryClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.getAPIVersionMutex.RLock()
-	defer fake.getAPIVersionMutex.RUnlock()
 	fake.countInstancesOfPlanMutex.RLock()
 	defer fake.countInstancesOfPlanMutex.RUnlock()
 	fake.countInstancesOfServiceOfferingMutex.RLock()
 	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
+	fake.countInstancesOfServiceOfferingInOrgMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingInOrgMutex.RUnlock()
+	fake.countInstancesOfServiceOfferingInSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingInSpaceMutex.RUnlock()
+	fake.getAPIVersionMutex.RLock()
+	defer fake.getAPIVersionMutex.RUnlock()
 	fake.getInstanceStateMutex.RLock()
 	defer fake.getInstanceStateMutex.RUnlock()
 	fake.getInstancesOfServiceOfferingMutex.RLock()
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -9,14 +10,15 @@ import (
 )
 
 type FakeDeployer struct {
-	CreateStub        func(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
+	CreateStub        func(context.Context, string, string, map[string]interface{}, string, *log.Logger) (int, []byte, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
-		deploymentName string
-		planID         string
-		requestParams  map[string]interface{}
-		boshContextID  string
-		logger         *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 *log.Logger
 	}
 	createReturns struct {
 		result1 int
@@ -28,16 +30,50 @@ type FakeDeployer struct {
 		result2 []byte
 		result3 error
 	}
-	UpdateStub        func(deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap map[string]string, logger *log.Logger) (int, []byte, error)
+	PreviewUpgradeStub        func(context.Context, string, string, *string, *log.Logger) (broker.UpgradePreview, error)
+	previewUpgradeMutex       sync.RWMutex
+	previewUpgradeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *string
+		arg5 *log.Logger
+	}
+	previewUpgradeReturns struct {
+		result1 broker.UpgradePreview
+		result2 error
+	}
+	previewUpgradeReturnsOnCall map[int]struct {
+		result1 broker.UpgradePreview
+		result2 error
+	}
+	RecreateStub        func(string, string, string, *log.Logger) (int, error)
+	recreateMutex       sync.RWMutex
+	recreateArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}
+	recreateReturns struct {
+		result1 int
+		result2 error
+	}
+	recreateReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	UpdateStub        func(context.Context, string, string, map[string]interface{}, *string, string, map[string]string, *log.Logger) (int, []byte, error)
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
-		deploymentName string
-		planID         string
-		requestParams  map[string]interface{}
-		previousPlanID *string
-		boshContextID  string
-		secretsMap     map[string]string
-		logger         *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 *string
+		arg6 string
+		arg7 map[string]string
+		arg8 *log.Logger
 	}
 	updateReturns struct {
 		result1 int
@@ -49,14 +85,15 @@ type FakeDeployer struct {
 		result2 []byte
 		result3 error
 	}
-	UpgradeStub        func(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	UpgradeStub        func(context.Context, string, string, *string, string, *log.Logger) (int, []byte, error)
 	upgradeMutex       sync.RWMutex
 	upgradeArgsForCall []struct {
-		deploymentName string
-		planID         string
-		previousPlanID *string
-		boshContextID  string
-		logger         *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *string
+		arg5 string
+		arg6 *log.Logger
 	}
 	upgradeReturns struct {
 		result1 int
@@ -68,45 +105,32 @@ type FakeDeployer struct {
 		result2 []byte
 		result3 error
 	}
-	RecreateStub        func(deploymentName, planID, boshContextID string, logger *log.Logger) (int, error)
-	recreateMutex       sync.RWMutex
-	recreateArgsForCall []struct {
-		deploymentName string
-		planID         string
-		boshContextID  string
-		logger         *log.Logger
-	}
-	recreateReturns struct {
-		result1 int
-		result2 error
-	}
-	recreateReturnsOnCall map[int]struct {
-		result1 int
-		result2 error
-	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDeployer) Create(deploymentName string, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (fake *FakeDeployer) Create(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 string, arg6 *log.Logger) (int, []byte, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
-		deploymentName string
-		planID         string
-		requestParams  map[string]interface{}
-		boshContextID  string
-		logger         *log.Logger
-	}{deploymentName, planID, requestParams, boshContextID, logger})
-	fake.recordInvocation("Create", []interface{}{deploymentName, planID, requestParams, boshContextID, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.CreateStub
+	fakeReturns := fake.createReturns
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.createMutex.Unlock()
-	if fake.CreateStub != nil {
-		return fake.CreateStub(deploymentName, planID, requestParams, boshContextID, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	return fake.createReturns.result1, fake.createReturns.result2, fake.createReturns.result3
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeDeployer) CreateCallCount() int {
@@ -115,13 +139,22 @@ func (fake *FakeDeployer) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeDeployer) CreateArgsForCall(i int) (string, string, map[string]interface{}, string, *log.Logger) {
+func (fake *FakeDeployer) CreateCalls(stub func(context.Context, string, string, map[string]interface{}, string, *log.Logger) (int, []byte, error)) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = stub
+}
+
+func (fake *FakeDeployer) CreateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, string, *log.Logger) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
-	return fake.createArgsForCall[i].deploymentName, fake.createArgsForCall[i].planID, fake.createArgsForCall[i].requestParams, fake.createArgsForCall[i].boshContextID, fake.createArgsForCall[i].logger
+	argsForCall := fake.createArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeDeployer) CreateReturns(result1 int, result2 []byte, result3 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	fake.createReturns = struct {
 		result1 int
@@ -131,6 +164,8 @@ func (fake *FakeDeployer) CreateReturns(result1 int, result2 []byte, result3 err
 }
 
 func (fake *FakeDeployer) CreateReturnsOnCall(i int, result1 int, result2 []byte, result3 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	if fake.createReturnsOnCall == nil {
 		fake.createReturnsOnCall = make(map[int]struct {
@@ -146,27 +181,165 @@ func (fake *FakeDeployer) CreateReturnsOnCall(i int, result1 int, result2 []byte
 	}{result1, result2, result3}
 }
 
-func (fake *FakeDeployer) Update(deploymentName string, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap map[string]string, logger *log.Logger) (int, []byte, error) {
+func (fake *FakeDeployer) PreviewUpgrade(arg1 context.Context, arg2 string, arg3 string, arg4 *string, arg5 *log.Logger) (broker.UpgradePreview, error) {
+	fake.previewUpgradeMutex.Lock()
+	ret, specificReturn := fake.previewUpgradeReturnsOnCall[len(fake.previewUpgradeArgsForCall)]
+	fake.previewUpgradeArgsForCall = append(fake.previewUpgradeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *string
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.PreviewUpgradeStub
+	fakeReturns := fake.previewUpgradeReturns
+	fake.recordInvocation("PreviewUpgrade", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.previewUpgradeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployer) PreviewUpgradeCallCount() int {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	return len(fake.previewUpgradeArgsForCall)
+}
+
+func (fake *FakeDeployer) PreviewUpgradeCalls(stub func(context.Context, string, string, *string, *log.Logger) (broker.UpgradePreview, error)) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = stub
+}
+
+func (fake *FakeDeployer) PreviewUpgradeArgsForCall(i int) (context.Context, string, string, *string, *log.Logger) {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	argsForCall := fake.previewUpgradeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeDeployer) PreviewUpgradeReturns(result1 broker.UpgradePreview, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	fake.previewUpgradeReturns = struct {
+		result1 broker.UpgradePreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) PreviewUpgradeReturnsOnCall(i int, result1 broker.UpgradePreview, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	if fake.previewUpgradeReturnsOnCall == nil {
+		fake.previewUpgradeReturnsOnCall = make(map[int]struct {
+			result1 broker.UpgradePreview
+			result2 error
+		})
+	}
+	fake.previewUpgradeReturnsOnCall[i] = struct {
+		result1 broker.UpgradePreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) Recreate(arg1 string, arg2 string, arg3 string, arg4 *log.Logger) (int, error) {
+	fake.recreateMutex.Lock()
+	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
+	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecreateStub
+	fakeReturns := fake.recreateReturns
+	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recreateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployer) RecreateCallCount() int {
+	fake.recreateMutex.RLock()
+	defer fake.recreateMutex.RUnlock()
+	return len(fake.recreateArgsForCall)
+}
+
+func (fake *FakeDeployer) RecreateCalls(stub func(string, string, string, *log.Logger) (int, error)) {
+	fake.recreateMutex.Lock()
+	defer fake.recreateMutex.Unlock()
+	fake.RecreateStub = stub
+}
+
+func (fake *FakeDeployer) RecreateArgsForCall(i int) (string, string, string, *log.Logger) {
+	fake.recreateMutex.RLock()
+	defer fake.recreateMutex.RUnlock()
+	argsForCall := fake.recreateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeDeployer) RecreateReturns(result1 int, result2 error) {
+	fake.recreateMutex.Lock()
+	defer fake.recreateMutex.Unlock()
+	fake.RecreateStub = nil
+	fake.recreateReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) RecreateReturnsOnCall(i int, result1 int, result2 error) {
+	fake.recreateMutex.Lock()
+	defer fake.recreateMutex.Unlock()
+	fake.RecreateStub = nil
+	if fake.recreateReturnsOnCall == nil {
+		fake.recreateReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.recreateReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) Update(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 *string, arg6 string, arg7 map[string]string, arg8 *log.Logger) (int, []byte, error) {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
 	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
-		deploymentName string
-		planID         string
-		requestParams  map[string]interface{}
-		previousPlanID *string
-		boshContextID  string
-		secretsMap     map[string]string
-		logger         *log.Logger
-	}{deploymentName, planID, requestParams, previousPlanID, boshContextID, secretsMap, logger})
-	fake.recordInvocation("Update", []interface{}{deploymentName, planID, requestParams, previousPlanID, boshContextID, secretsMap, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 *string
+		arg6 string
+		arg7 map[string]string
+		arg8 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.updateMutex.Unlock()
-	if fake.UpdateStub != nil {
-		return fake.UpdateStub(deploymentName, planID, requestParams, previousPlanID, boshContextID, secretsMap, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	return fake.updateReturns.result1, fake.updateReturns.result2, fake.updateReturns.result3
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeDeployer) UpdateCallCount() int {
@@ -175,13 +348,22 @@ func (fake *FakeDeployer) UpdateCallCount() int {
 	return len(fake.updateArgsForCall)
 }
 
-func (fake *FakeDeployer) UpdateArgsForCall(i int) (string, string, map[string]interface{}, *string, string, map[string]string, *log.Logger) {
+func (fake *FakeDeployer) UpdateCalls(stub func(context.Context, string, string, map[string]interface{}, *string, string, map[string]string, *log.Logger) (int, []byte, error)) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *FakeDeployer) UpdateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, *string, string, map[string]string, *log.Logger) {
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
-	return fake.updateArgsForCall[i].deploymentName, fake.updateArgsForCall[i].planID, fake.updateArgsForCall[i].requestParams, fake.updateArgsForCall[i].previousPlanID, fake.updateArgsForCall[i].boshContextID, fake.updateArgsForCall[i].secretsMap, fake.updateArgsForCall[i].logger
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeDeployer) UpdateReturns(result1 int, result2 []byte, result3 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
 	fake.UpdateStub = nil
 	fake.updateReturns = struct {
 		result1 int
@@ -191,6 +373,8 @@ func (fake *FakeDeployer) UpdateReturns(result1 int, result2 []byte, result3 err
 }
 
 func (fake *FakeDeployer) UpdateReturnsOnCall(i int, result1 int, result2 []byte, result3 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
 	fake.UpdateStub = nil
 	if fake.updateReturnsOnCall == nil {
 		fake.updateReturnsOnCall = make(map[int]struct {
@@ -206,25 +390,28 @@ func (fake *FakeDeployer) UpdateReturnsOnCall(i int, result1 int, result2 []byte
 	}{result1, result2, result3}
 }
 
-func (fake *FakeDeployer) Upgrade(deploymentName string, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (fake *FakeDeployer) Upgrade(arg1 context.Context, arg2 string, arg3 string, arg4 *string, arg5 string, arg6 *log.Logger) (int, []byte, error) {
 	fake.upgradeMutex.Lock()
 	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
 	fake.upgradeArgsForCall = append(fake.upgradeArgsForCall, struct {
-		deploymentName string
-		planID         string
-		previousPlanID *string
-		boshContextID  string
-		logger         *log.Logger
-	}{deploymentName, planID, previousPlanID, boshContextID, logger})
-	fake.recordInvocation("Upgrade", []interface{}{deploymentName, planID, previousPlanID, boshContextID, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *string
+		arg5 string
+		arg6 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.UpgradeStub
+	fakeReturns := fake.upgradeReturns
+	fake.recordInvocation("Upgrade", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.upgradeMutex.Unlock()
-	if fake.UpgradeStub != nil {
-		return fake.UpgradeStub(deploymentName, planID, previousPlanID, boshContextID, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	return fake.upgradeReturns.result1, fake.upgradeReturns.result2, fake.upgradeReturns.result3
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeDeployer) UpgradeCallCount() int {
@@ -233,13 +420,22 @@ func (fake *FakeDeployer) UpgradeCallCount() int {
 	return len(fake.upgradeArgsForCall)
 }
 
-func (fake *FakeDeployer) UpgradeArgsForCall(i int) (string, string, *string, string, *log.Logger) {
+func (fake *FakeDeployer) UpgradeCalls(stub func(context.Context, string, string, *string, string, *log.Logger) (int, []byte, error)) {
+	fake.upgradeMutex.Lock()
+	defer fake.upgradeMutex.Unlock()
+	fake.UpgradeStub = stub
+}
+
+func (fake *FakeDeployer) UpgradeArgsForCall(i int) (context.Context, string, string, *string, string, *log.Logger) {
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
-	return fake.upgradeArgsForCall[i].deploymentName, fake.upgradeArgsForCall[i].planID, fake.upgradeArgsForCall[i].previousPlanID, fake.upgradeArgsForCall[i].boshContextID, fake.upgradeArgsForCall[i].logger
+	argsForCall := fake.upgradeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeDeployer) UpgradeReturns(result1 int, result2 []byte, result3 error) {
+	fake.upgradeMutex.Lock()
+	defer fake.upgradeMutex.Unlock()
 	fake.UpgradeStub = nil
 	fake.upgradeReturns = struct {
 		result1 int
@@ -249,6 +445,8 @@ func (fake *FakeDeployer) UpgradeReturns(result1 int, result2 []byte, result3 er
 }
 
 func (fake *FakeDeployer) UpgradeReturnsOnCall(i int, result1 int, result2 []byte, result3 error) {
+	fake.upgradeMutex.Lock()
+	defer fake.upgradeMutex.Unlock()
 	fake.UpgradeStub = nil
 	if fake.upgradeReturnsOnCall == nil {
 		fake.upgradeReturnsOnCall = make(map[int]struct {
@@ -264,71 +462,19 @@ func (fake *FakeDeployer) UpgradeReturnsOnCall(i int, result1 int, result2 []byt
 	}{result1, result2, result3}
 }
 
-func (fake *FakeDeployer) Recreate(deploymentName string, planID string, boshContextID string, logger *log.Logger) (int, error) {
-	fake.recreateMutex.Lock()
-	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
-	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-		deploymentName string
-		planID         string
-		boshContextID  string
-		logger         *log.Logger
-	}{deploymentName, planID, boshContextID, logger})
-	fake.recordInvocation("Recreate", []interface{}{deploymentName, planID, boshContextID, logger})
-	fake.recreateMutex.Unlock()
-	if fake.RecreateStub != nil {
-		return fake.RecreateStub(deploymentName, planID, boshContextID, logger)
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
-	}
-	return fake.recreateReturns.result1, fake.recreateReturns.result2
-}
-
-func (fake *FakeDeployer) RecreateCallCount() int {
-	fake.recreateMutex.RLock()
-	defer fake.recreateMutex.RUnlock()
-	return len(fake.recreateArgsForCall)
-}
-
-func (fake *FakeDeployer) RecreateArgsForCall(i int) (string, string, string, *log.Logger) {
-	fake.recreateMutex.RLock()
-	defer fake.recreateMutex.RUnlock()
-	return fake.recreateArgsForCall[i].deploymentName, fake.recreateArgsForCall[i].planID, fake.recreateArgsForCall[i].boshContextID, fake.recreateArgsForCall[i].logger
-}
-
-func (fake *FakeDeployer) RecreateReturns(result1 int, result2 error) {
-	fake.RecreateStub = nil
-	fake.recreateReturns = struct {
-		result1 int
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *FakeDeployer) RecreateReturnsOnCall(i int, result1 int, result2 error) {
-	fake.RecreateStub = nil
-	if fake.recreateReturnsOnCall == nil {
-		fake.recreateReturnsOnCall = make(map[int]struct {
-			result1 int
-			result2 error
-		})
-	}
-	fake.recreateReturnsOnCall[i] = struct {
-		result1 int
-		result2 error
-	}{result1, result2}
-}
-
 func (fake *FakeDeployer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	fake.recreateMutex.RLock()
+	defer fake.recreateMutex.RUnlock()
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
-	fake.recreateMutex.RLock()
-	defer fake.recreateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,509 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+type FakeOperationStore struct {
+	ForInstanceStub        func(string) ([]operationstore.Operation, error)
+	forInstanceMutex       sync.RWMutex
+	forInstanceArgsForCall []struct {
+		arg1 string
+	}
+	forInstanceReturns struct {
+		result1 []operationstore.Operation
+		result2 error
+	}
+	forInstanceReturnsOnCall map[int]struct {
+		result1 []operationstore.Operation
+		result2 error
+	}
+	RecordStub        func(operationstore.Operation) error
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		arg1 operationstore.Operation
+	}
+	recordReturns struct {
+		result1 error
+	}
+	recordReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RecordRollbackTaskStub        func(string, int, int) error
+	recordRollbackTaskMutex       sync.RWMutex
+	recordRollbackTaskArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 int
+	}
+	recordRollbackTaskReturns struct {
+		result1 error
+	}
+	recordRollbackTaskReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SaveSnapshotStub        func(string, operationstore.Snapshot) error
+	saveSnapshotMutex       sync.RWMutex
+	saveSnapshotArgsForCall []struct {
+		arg1 string
+		arg2 operationstore.Snapshot
+	}
+	saveSnapshotReturns struct {
+		result1 error
+	}
+	saveSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SnapshotStub        func(string) (*operationstore.Snapshot, error)
+	snapshotMutex       sync.RWMutex
+	snapshotArgsForCall []struct {
+		arg1 string
+	}
+	snapshotReturns struct {
+		result1 *operationstore.Snapshot
+		result2 error
+	}
+	snapshotReturnsOnCall map[int]struct {
+		result1 *operationstore.Snapshot
+		result2 error
+	}
+	UpdateStateStub        func(string, int, int, string) (bool, error)
+	updateStateMutex       sync.RWMutex
+	updateStateArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 int
+		arg4 string
+	}
+	updateStateReturns struct {
+		result1 bool
+		result2 error
+	}
+	updateStateReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeOperationStore) ForInstance(arg1 string) ([]operationstore.Operation, error) {
+	fake.forInstanceMutex.Lock()
+	ret, specificReturn := fake.forInstanceReturnsOnCall[len(fake.forInstanceArgsForCall)]
+	fake.forInstanceArgsForCall = append(fake.forInstanceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ForInstanceStub
+	fakeReturns := fake.forInstanceReturns
+	fake.recordInvocation("ForInstance", []interface{}{arg1})
+	fake.forInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOperationStore) ForInstanceCallCount() int {
+	fake.forInstanceMutex.RLock()
+	defer fake.forInstanceMutex.RUnlock()
+	return len(fake.forInstanceArgsForCall)
+}
+
+func (fake *FakeOperationStore) ForInstanceCalls(stub func(string) ([]operationstore.Operation, error)) {
+	fake.forInstanceMutex.Lock()
+	defer fake.forInstanceMutex.Unlock()
+	fake.ForInstanceStub = stub
+}
+
+func (fake *FakeOperationStore) ForInstanceArgsForCall(i int) string {
+	fake.forInstanceMutex.RLock()
+	defer fake.forInstanceMutex.RUnlock()
+	argsForCall := fake.forInstanceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOperationStore) ForInstanceReturns(result1 []operationstore.Operation, result2 error) {
+	fake.forInstanceMutex.Lock()
+	defer fake.forInstanceMutex.Unlock()
+	fake.ForInstanceStub = nil
+	fake.forInstanceReturns = struct {
+		result1 []operationstore.Operation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOperationStore) ForInstanceReturnsOnCall(i int, result1 []operationstore.Operation, result2 error) {
+	fake.forInstanceMutex.Lock()
+	defer fake.forInstanceMutex.Unlock()
+	fake.ForInstanceStub = nil
+	if fake.forInstanceReturnsOnCall == nil {
+		fake.forInstanceReturnsOnCall = make(map[int]struct {
+			result1 []operationstore.Operation
+			result2 error
+		})
+	}
+	fake.forInstanceReturnsOnCall[i] = struct {
+		result1 []operationstore.Operation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOperationStore) Record(arg1 operationstore.Operation) error {
+	fake.recordMutex.Lock()
+	ret, specificReturn := fake.recordReturnsOnCall[len(fake.recordArgsForCall)]
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		arg1 operationstore.Operation
+	}{arg1})
+	stub := fake.RecordStub
+	fakeReturns := fake.recordReturns
+	fake.recordInvocation("Record", []interface{}{arg1})
+	fake.recordMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOperationStore) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeOperationStore) RecordCalls(stub func(operationstore.Operation) error) {
+	fake.recordMutex.Lock()
+	defer fake.recordMutex.Unlock()
+	fake.RecordStub = stub
+}
+
+func (fake *FakeOperationStore) RecordArgsForCall(i int) operationstore.Operation {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	argsForCall := fake.recordArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOperationStore) RecordReturns(result1 error) {
+	fake.recordMutex.Lock()
+	defer fake.recordMutex.Unlock()
+	fake.RecordStub = nil
+	fake.recordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOperationStore) RecordReturnsOnCall(i int, result1 error) {
+	fake.recordMutex.Lock()
+	defer fake.recordMutex.Unlock()
+	fake.RecordStub = nil
+	if fake.recordReturnsOnCall == nil {
+		fake.recordReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOperationStore) RecordRollbackTask(arg1 string, arg2 int, arg3 int) error {
+	fake.recordRollbackTaskMutex.Lock()
+	ret, specificReturn := fake.recordRollbackTaskReturnsOnCall[len(fake.recordRollbackTaskArgsForCall)]
+	fake.recordRollbackTaskArgsForCall = append(fake.recordRollbackTaskArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.RecordRollbackTaskStub
+	fakeReturns := fake.recordRollbackTaskReturns
+	fake.recordInvocation("RecordRollbackTask", []interface{}{arg1, arg2, arg3})
+	fake.recordRollbackTaskMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOperationStore) RecordRollbackTaskCallCount() int {
+	fake.recordRollbackTaskMutex.RLock()
+	defer fake.recordRollbackTaskMutex.RUnlock()
+	return len(fake.recordRollbackTaskArgsForCall)
+}
+
+func (fake *FakeOperationStore) RecordRollbackTaskCalls(stub func(string, int, int) error) {
+	fake.recordRollbackTaskMutex.Lock()
+	defer fake.recordRollbackTaskMutex.Unlock()
+	fake.RecordRollbackTaskStub = stub
+}
+
+func (fake *FakeOperationStore) RecordRollbackTaskArgsForCall(i int) (string, int, int) {
+	fake.recordRollbackTaskMutex.RLock()
+	defer fake.recordRollbackTaskMutex.RUnlock()
+	argsForCall := fake.recordRollbackTaskArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOperationStore) RecordRollbackTaskReturns(result1 error) {
+	fake.recordRollbackTaskMutex.Lock()
+	defer fake.recordRollbackTaskMutex.Unlock()
+	fake.RecordRollbackTaskStub = nil
+	fake.recordRollbackTaskReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOperationStore) RecordRollbackTaskReturnsOnCall(i int, result1 error) {
+	fake.recordRollbackTaskMutex.Lock()
+	defer fake.recordRollbackTaskMutex.Unlock()
+	fake.RecordRollbackTaskStub = nil
+	if fake.recordRollbackTaskReturnsOnCall == nil {
+		fake.recordRollbackTaskReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordRollbackTaskReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOperationStore) SaveSnapshot(arg1 string, arg2 operationstore.Snapshot) error {
+	fake.saveSnapshotMutex.Lock()
+	ret, specificReturn := fake.saveSnapshotReturnsOnCall[len(fake.saveSnapshotArgsForCall)]
+	fake.saveSnapshotArgsForCall = append(fake.saveSnapshotArgsForCall, struct {
+		arg1 string
+		arg2 operationstore.Snapshot
+	}{arg1, arg2})
+	stub := fake.SaveSnapshotStub
+	fakeReturns := fake.saveSnapshotReturns
+	fake.recordInvocation("SaveSnapshot", []interface{}{arg1, arg2})
+	fake.saveSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOperationStore) SaveSnapshotCallCount() int {
+	fake.saveSnapshotMutex.RLock()
+	defer fake.saveSnapshotMutex.RUnlock()
+	return len(fake.saveSnapshotArgsForCall)
+}
+
+func (fake *FakeOperationStore) SaveSnapshotCalls(stub func(string, operationstore.Snapshot) error) {
+	fake.saveSnapshotMutex.Lock()
+	defer fake.saveSnapshotMutex.Unlock()
+	fake.SaveSnapshotStub = stub
+}
+
+func (fake *FakeOperationStore) SaveSnapshotArgsForCall(i int) (string, operationstore.Snapshot) {
+	fake.saveSnapshotMutex.RLock()
+	defer fake.saveSnapshotMutex.RUnlock()
+	argsForCall := fake.saveSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOperationStore) SaveSnapshotReturns(result1 error) {
+	fake.saveSnapshotMutex.Lock()
+	defer fake.saveSnapshotMutex.Unlock()
+	fake.SaveSnapshotStub = nil
+	fake.saveSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOperationStore) SaveSnapshotReturnsOnCall(i int, result1 error) {
+	fake.saveSnapshotMutex.Lock()
+	defer fake.saveSnapshotMutex.Unlock()
+	fake.SaveSnapshotStub = nil
+	if fake.saveSnapshotReturnsOnCall == nil {
+		fake.saveSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOperationStore) Snapshot(arg1 string) (*operationstore.Snapshot, error) {
+	fake.snapshotMutex.Lock()
+	ret, specificReturn := fake.snapshotReturnsOnCall[len(fake.snapshotArgsForCall)]
+	fake.snapshotArgsForCall = append(fake.snapshotArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SnapshotStub
+	fakeReturns := fake.snapshotReturns
+	fake.recordInvocation("Snapshot", []interface{}{arg1})
+	fake.snapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOperationStore) SnapshotCallCount() int {
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	return len(fake.snapshotArgsForCall)
+}
+
+func (fake *FakeOperationStore) SnapshotCalls(stub func(string) (*operationstore.Snapshot, error)) {
+	fake.snapshotMutex.Lock()
+	defer fake.snapshotMutex.Unlock()
+	fake.SnapshotStub = stub
+}
+
+func (fake *FakeOperationStore) SnapshotArgsForCall(i int) string {
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	argsForCall := fake.snapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOperationStore) SnapshotReturns(result1 *operationstore.Snapshot, result2 error) {
+	fake.snapshotMutex.Lock()
+	defer fake.snapshotMutex.Unlock()
+	fake.SnapshotStub = nil
+	fake.snapshotReturns = struct {
+		result1 *operationstore.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOperationStore) SnapshotReturnsOnCall(i int, result1 *operationstore.Snapshot, result2 error) {
+	fake.snapshotMutex.Lock()
+	defer fake.snapshotMutex.Unlock()
+	fake.SnapshotStub = nil
+	if fake.snapshotReturnsOnCall == nil {
+		fake.snapshotReturnsOnCall = make(map[int]struct {
+			result1 *operationstore.Snapshot
+			result2 error
+		})
+	}
+	fake.snapshotReturnsOnCall[i] = struct {
+		result1 *operationstore.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOperationStore) UpdateState(arg1 string, arg2 int, arg3 int, arg4 string) (bool, error) {
+	fake.updateStateMutex.Lock()
+	ret, specificReturn := fake.updateStateReturnsOnCall[len(fake.updateStateArgsForCall)]
+	fake.updateStateArgsForCall = append(fake.updateStateArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 int
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UpdateStateStub
+	fakeReturns := fake.updateStateReturns
+	fake.recordInvocation("UpdateState", []interface{}{arg1, arg2, arg3, arg4})
+	fake.updateStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOperationStore) UpdateStateCallCount() int {
+	fake.updateStateMutex.RLock()
+	defer fake.updateStateMutex.RUnlock()
+	return len(fake.updateStateArgsForCall)
+}
+
+func (fake *FakeOperationStore) UpdateStateCalls(stub func(string, int, int, string) (bool, error)) {
+	fake.updateStateMutex.Lock()
+	defer fake.updateStateMutex.Unlock()
+	fake.UpdateStateStub = stub
+}
+
+func (fake *FakeOperationStore) UpdateStateArgsForCall(i int) (string, int, int, string) {
+	fake.updateStateMutex.RLock()
+	defer fake.updateStateMutex.RUnlock()
+	argsForCall := fake.updateStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeOperationStore) UpdateStateReturns(result1 bool, result2 error) {
+	fake.updateStateMutex.Lock()
+	defer fake.updateStateMutex.Unlock()
+	fake.UpdateStateStub = nil
+	fake.updateStateReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOperationStore) UpdateStateReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.updateStateMutex.Lock()
+	defer fake.updateStateMutex.Unlock()
+	fake.UpdateStateStub = nil
+	if fake.updateStateReturnsOnCall == nil {
+		fake.updateStateReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.updateStateReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOperationStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.forInstanceMutex.RLock()
+	defer fake.forInstanceMutex.RUnlock()
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	fake.recordRollbackTaskMutex.RLock()
+	defer fake.recordRollbackTaskMutex.RUnlock()
+	fake.saveSnapshotMutex.RLock()
+	defer fake.saveSnapshotMutex.RUnlock()
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	fake.updateStateMutex.RLock()
+	defer fake.updateStateMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeOperationStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ broker.OperationStore = new(FakeOperationStore)
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -12,16 +13,17 @@ import (
 )
 
 type FakeServiceAdapterClient struct {
-	CreateBindingStub        func(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error)
+	CreateBindingStub        func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)
 	createBindingMutex       sync.RWMutex
 	createBindingArgsForCall []struct {
-		bindingID          string
-		deploymentTopology bosh.BoshVMs
-		manifest           []byte
-		requestParams      map[string]interface{}
-		secretsMap         map[string]string
-		dnsAddresses       map[string]string
-		logger             *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
+		arg6 map[string]string
+		arg7 map[string]string
+		arg8 *log.Logger
 	}
 	createBindingReturns struct {
 		result1 serviceadapter.Binding
@@ -31,15 +33,16 @@ type FakeServiceAdapterClient struct {
 		result1 serviceadapter.Binding
 		result2 error
 	}
-	DeleteBindingStub        func(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, logger *log.Logger) error
+	DeleteBindingStub        func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, *log.Logger) error
 	deleteBindingMutex       sync.RWMutex
 	deleteBindingArgsForCall []struct {
-		bindingID          string
-		deploymentTopology bosh.BoshVMs
-		manifest           []byte
-		requestParams      map[string]interface{}
-		secretsMap         map[string]string
-		logger             *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
+		arg6 map[string]string
+		arg7 *log.Logger
 	}
 	deleteBindingReturns struct {
 		result1 error
@@ -47,13 +50,14 @@ type FakeServiceAdapterClient struct {
 	deleteBindingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GenerateDashboardUrlStub        func(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
+	GenerateDashboardUrlStub        func(context.Context, string, serviceadapter.Plan, []byte, *log.Logger) (string, error)
 	generateDashboardUrlMutex       sync.RWMutex
 	generateDashboardUrlArgsForCall []struct {
-		instanceID string
-		plan       serviceadapter.Plan
-		manifest   []byte
-		logger     *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 serviceadapter.Plan
+		arg4 []byte
+		arg5 *log.Logger
 	}
 	generateDashboardUrlReturns struct {
 		result1 string
@@ -63,11 +67,12 @@ type FakeServiceAdapterClient struct {
 		result1 string
 		result2 error
 	}
-	GeneratePlanSchemaStub        func(plan serviceadapter.Plan, logger *log.Logger) (brokerapi.ServiceSchemas, error)
+	GeneratePlanSchemaStub        func(context.Context, serviceadapter.Plan, *log.Logger) (brokerapi.ServiceSchemas, error)
 	generatePlanSchemaMutex       sync.RWMutex
 	generatePlanSchemaArgsForCall []struct {
-		plan   serviceadapter.Plan
-		logger *log.Logger
+		arg1 context.Context
+		arg2 serviceadapter.Plan
+		arg3 *log.Logger
 	}
 	generatePlanSchemaReturns struct {
 		result1 brokerapi.ServiceSchemas
@@ -77,36 +82,58 @@ type FakeServiceAdapterClient struct {
 		result1 brokerapi.ServiceSchemas
 		result2 error
 	}
+	GetBindingStub        func(context.Context, string, bosh.BoshVMs, []byte, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)
+	getBindingMutex       sync.RWMutex
+	getBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]string
+		arg6 map[string]string
+		arg7 *log.Logger
+	}
+	getBindingReturns struct {
+		result1 serviceadapter.Binding
+		result2 error
+	}
+	getBindingReturnsOnCall map[int]struct {
+		result1 serviceadapter.Binding
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeServiceAdapterClient) CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error) {
-	var manifestCopy []byte
-	if manifest != nil {
-		manifestCopy = make([]byte, len(manifest))
-		copy(manifestCopy, manifest)
+func (fake *FakeServiceAdapterClient) CreateBinding(arg1 context.Context, arg2 string, arg3 bosh.BoshVMs, arg4 []byte, arg5 map[string]interface{}, arg6 map[string]string, arg7 map[string]string, arg8 *log.Logger) (serviceadapter.Binding, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.createBindingMutex.Lock()
 	ret, specificReturn := fake.createBindingReturnsOnCall[len(fake.createBindingArgsForCall)]
 	fake.createBindingArgsForCall = append(fake.createBindingArgsForCall, struct {
-		bindingID          string
-		deploymentTopology bosh.BoshVMs
-		manifest           []byte
-		requestParams      map[string]interface{}
-		secretsMap         map[string]string
-		dnsAddresses       map[string]string
-		logger             *log.Logger
-	}{bindingID, deploymentTopology, manifestCopy, requestParams, secretsMap, dnsAddresses, logger})
-	fake.recordInvocation("CreateBinding", []interface{}{bindingID, deploymentTopology, manifestCopy, requestParams, secretsMap, dnsAddresses, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
+		arg6 map[string]string
+		arg7 map[string]string
+		arg8 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
+	stub := fake.CreateBindingStub
+	fakeReturns := fake.createBindingReturns
+	fake.recordInvocation("CreateBinding", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	fake.createBindingMutex.Unlock()
-	if fake.CreateBindingStub != nil {
-		return fake.CreateBindingStub(bindingID, deploymentTopology, manifest, requestParams, secretsMap, dnsAddresses, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.createBindingReturns.result1, fake.createBindingReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeServiceAdapterClient) CreateBindingCallCount() int {
@@ -115,13 +142,22 @@ func (fake *FakeServiceAdapterClient) CreateBindingCallCount() int {
 	return len(fake.createBindingArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) CreateBindingArgsForCall(i int) (string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) {
+func (fake *FakeServiceAdapterClient) CreateBindingCalls(stub func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)) {
+	fake.createBindingMutex.Lock()
+	defer fake.createBindingMutex.Unlock()
+	fake.CreateBindingStub = stub
+}
+
+func (fake *FakeServiceAdapterClient) CreateBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) {
 	fake.createBindingMutex.RLock()
 	defer fake.createBindingMutex.RUnlock()
-	return fake.createBindingArgsForCall[i].bindingID, fake.createBindingArgsForCall[i].deploymentTopology, fake.createBindingArgsForCall[i].manifest, fake.createBindingArgsForCall[i].requestParams, fake.createBindingArgsForCall[i].secretsMap, fake.createBindingArgsForCall[i].dnsAddresses, fake.createBindingArgsForCall[i].logger
+	argsForCall := fake.createBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeServiceAdapterClient) CreateBindingReturns(result1 serviceadapter.Binding, result2 error) {
+	fake.createBindingMutex.Lock()
+	defer fake.createBindingMutex.Unlock()
 	fake.CreateBindingStub = nil
 	fake.createBindingReturns = struct {
 		result1 serviceadapter.Binding
@@ -130,6 +166,8 @@ func (fake *FakeServiceAdapterClient) CreateBindingReturns(result1 serviceadapte
 }
 
 func (fake *FakeServiceAdapterClient) CreateBindingReturnsOnCall(i int, result1 serviceadapter.Binding, result2 error) {
+	fake.createBindingMutex.Lock()
+	defer fake.createBindingMutex.Unlock()
 	fake.CreateBindingStub = nil
 	if fake.createBindingReturnsOnCall == nil {
 		fake.createBindingReturnsOnCall = make(map[int]struct {
@@ -143,31 +181,34 @@ func (fake *FakeServiceAdapterClient) CreateBindingReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, logger *log.Logger) error {
-	var manifestCopy []byte
-	if manifest != nil {
-		manifestCopy = make([]byte, len(manifest))
-		copy(manifestCopy, manifest)
+func (fake *FakeServiceAdapterClient) DeleteBinding(arg1 context.Context, arg2 string, arg3 bosh.BoshVMs, arg4 []byte, arg5 map[string]interface{}, arg6 map[string]string, arg7 *log.Logger) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.deleteBindingMutex.Lock()
 	ret, specificReturn := fake.deleteBindingReturnsOnCall[len(fake.deleteBindingArgsForCall)]
 	fake.deleteBindingArgsForCall = append(fake.deleteBindingArgsForCall, struct {
-		bindingID          string
-		deploymentTopology bosh.BoshVMs
-		manifest           []byte
-		requestParams      map[string]interface{}
-		secretsMap         map[string]string
-		logger             *log.Logger
-	}{bindingID, deploymentTopology, manifestCopy, requestParams, secretsMap, logger})
-	fake.recordInvocation("DeleteBinding", []interface{}{bindingID, deploymentTopology, manifestCopy, requestParams, secretsMap, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
+		arg6 map[string]string
+		arg7 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7})
+	stub := fake.DeleteBindingStub
+	fakeReturns := fake.deleteBindingReturns
+	fake.recordInvocation("DeleteBinding", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7})
 	fake.deleteBindingMutex.Unlock()
-	if fake.DeleteBindingStub != nil {
-		return fake.DeleteBindingStub(bindingID, deploymentTopology, manifest, requestParams, secretsMap, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.deleteBindingReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *FakeServiceAdapterClient) DeleteBindingCallCount() int {
@@ -176,13 +217,22 @@ func (fake *FakeServiceAdapterClient) DeleteBindingCallCount() int {
 	return len(fake.deleteBindingArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBindingArgsForCall(i int) (string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, *log.Logger) {
+func (fake *FakeServiceAdapterClient) DeleteBindingCalls(stub func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, *log.Logger) error) {
+	fake.deleteBindingMutex.Lock()
+	defer fake.deleteBindingMutex.Unlock()
+	fake.DeleteBindingStub = stub
+}
+
+func (fake *FakeServiceAdapterClient) DeleteBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, *log.Logger) {
 	fake.deleteBindingMutex.RLock()
 	defer fake.deleteBindingMutex.RUnlock()
-	return fake.deleteBindingArgsForCall[i].bindingID, fake.deleteBindingArgsForCall[i].deploymentTopology, fake.deleteBindingArgsForCall[i].manifest, fake.deleteBindingArgsForCall[i].requestParams, fake.deleteBindingArgsForCall[i].secretsMap, fake.deleteBindingArgsForCall[i].logger
+	argsForCall := fake.deleteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
 func (fake *FakeServiceAdapterClient) DeleteBindingReturns(result1 error) {
+	fake.deleteBindingMutex.Lock()
+	defer fake.deleteBindingMutex.Unlock()
 	fake.DeleteBindingStub = nil
 	fake.deleteBindingReturns = struct {
 		result1 error
@@ -190,6 +240,8 @@ func (fake *FakeServiceAdapterClient) DeleteBindingReturns(result1 error) {
 }
 
 func (fake *FakeServiceAdapterClient) DeleteBindingReturnsOnCall(i int, result1 error) {
+	fake.deleteBindingMutex.Lock()
+	defer fake.deleteBindingMutex.Unlock()
 	fake.DeleteBindingStub = nil
 	if fake.deleteBindingReturnsOnCall == nil {
 		fake.deleteBindingReturnsOnCall = make(map[int]struct {
@@ -201,29 +253,32 @@ func (fake *FakeServiceAdapterClient) DeleteBindingReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error) {
-	var manifestCopy []byte
-	if manifest != nil {
-		manifestCopy = make([]byte, len(manifest))
-		copy(manifestCopy, manifest)
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(arg1 context.Context, arg2 string, arg3 serviceadapter.Plan, arg4 []byte, arg5 *log.Logger) (string, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.generateDashboardUrlMutex.Lock()
 	ret, specificReturn := fake.generateDashboardUrlReturnsOnCall[len(fake.generateDashboardUrlArgsForCall)]
 	fake.generateDashboardUrlArgsForCall = append(fake.generateDashboardUrlArgsForCall, struct {
-		instanceID string
-		plan       serviceadapter.Plan
-		manifest   []byte
-		logger     *log.Logger
-	}{instanceID, plan, manifestCopy, logger})
-	fake.recordInvocation("GenerateDashboardUrl", []interface{}{instanceID, plan, manifestCopy, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 serviceadapter.Plan
+		arg4 []byte
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5})
+	stub := fake.GenerateDashboardUrlStub
+	fakeReturns := fake.generateDashboardUrlReturns
+	fake.recordInvocation("GenerateDashboardUrl", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
 	fake.generateDashboardUrlMutex.Unlock()
-	if fake.GenerateDashboardUrlStub != nil {
-		return fake.GenerateDashboardUrlStub(instanceID, plan, manifest, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.generateDashboardUrlReturns.result1, fake.generateDashboardUrlReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCallCount() int {
@@ -232,13 +287,22 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCallCount() int {
 	return len(fake.generateDashboardUrlArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrlArgsForCall(i int) (string, serviceadapter.Plan, []byte, *log.Logger) {
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCalls(stub func(context.Context, string, serviceadapter.Plan, []byte, *log.Logger) (string, error)) {
+	fake.generateDashboardUrlMutex.Lock()
+	defer fake.generateDashboardUrlMutex.Unlock()
+	fake.GenerateDashboardUrlStub = stub
+}
+
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrlArgsForCall(i int) (context.Context, string, serviceadapter.Plan, []byte, *log.Logger) {
 	fake.generateDashboardUrlMutex.RLock()
 	defer fake.generateDashboardUrlMutex.RUnlock()
-	return fake.generateDashboardUrlArgsForCall[i].instanceID, fake.generateDashboardUrlArgsForCall[i].plan, fake.generateDashboardUrlArgsForCall[i].manifest, fake.generateDashboardUrlArgsForCall[i].logger
+	argsForCall := fake.generateDashboardUrlArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturns(result1 string, result2 error) {
+	fake.generateDashboardUrlMutex.Lock()
+	defer fake.generateDashboardUrlMutex.Unlock()
 	fake.GenerateDashboardUrlStub = nil
 	fake.generateDashboardUrlReturns = struct {
 		result1 string
@@ -247,6 +311,8 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturns(result1 string
 }
 
 func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturnsOnCall(i int, result1 string, result2 error) {
+	fake.generateDashboardUrlMutex.Lock()
+	defer fake.generateDashboardUrlMutex.Unlock()
 	fake.GenerateDashboardUrlStub = nil
 	if fake.generateDashboardUrlReturnsOnCall == nil {
 		fake.generateDashboardUrlReturnsOnCall = make(map[int]struct {
@@ -260,22 +326,25 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
-func (fake *FakeServiceAdapterClient) GeneratePlanSchema(plan serviceadapter.Plan, logger *log.Logger) (brokerapi.ServiceSchemas, error) {
+func (fake *FakeServiceAdapterClient) GeneratePlanSchema(arg1 context.Context, arg2 serviceadapter.Plan, arg3 *log.Logger) (brokerapi.ServiceSchemas, error) {
 	fake.generatePlanSchemaMutex.Lock()
 	ret, specificReturn := fake.generatePlanSchemaReturnsOnCall[len(fake.generatePlanSchemaArgsForCall)]
 	fake.generatePlanSchemaArgsForCall = append(fake.generatePlanSchemaArgsForCall, struct {
-		plan   serviceadapter.Plan
-		logger *log.Logger
-	}{plan, logger})
-	fake.recordInvocation("GeneratePlanSchema", []interface{}{plan, logger})
+		arg1 context.Context
+		arg2 serviceadapter.Plan
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.GeneratePlanSchemaStub
+	fakeReturns := fake.generatePlanSchemaReturns
+	fake.recordInvocation("GeneratePlanSchema", []interface{}{arg1, arg2, arg3})
 	fake.generatePlanSchemaMutex.Unlock()
-	if fake.GeneratePlanSchemaStub != nil {
-		return fake.GeneratePlanSchemaStub(plan, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.generatePlanSchemaReturns.result1, fake.generatePlanSchemaReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeServiceAdapterClient) GeneratePlanSchemaCallCount() int {
@@ -284,13 +353,22 @@ func (fake *FakeServiceAdapterClient) GeneratePlanSchemaCallCount() int {
 	return len(fake.generatePlanSchemaArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) GeneratePlanSchemaArgsForCall(i int) (serviceadapter.Plan, *log.Logger) {
+func (fake *FakeServiceAdapterClient) GeneratePlanSchemaCalls(stub func(context.Context, serviceadapter.Plan, *log.Logger) (brokerapi.ServiceSchemas, error)) {
+	fake.generatePlanSchemaMutex.Lock()
+	defer fake.generatePlanSchemaMutex.Unlock()
+	fake.GeneratePlanSchemaStub = stub
+}
+
+func (fake *FakeServiceAdapterClient) GeneratePlanSchemaArgsForCall(i int) (context.Context, serviceadapter.Plan, *log.Logger) {
 	fake.generatePlanSchemaMutex.RLock()
 	defer fake.generatePlanSchemaMutex.RUnlock()
-	return fake.generatePlanSchemaArgsForCall[i].plan, fake.generatePlanSchemaArgsForCall[i].logger
+	argsForCall := fake.generatePlanSchemaArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeServiceAdapterClient) GeneratePlanSchemaReturns(result1 brokerapi.ServiceSchemas, result2 error) {
+	fake.generatePlanSchemaMutex.Lock()
+	defer fake.generatePlanSchemaMutex.Unlock()
 	fake.GeneratePlanSchemaStub = nil
 	fake.generatePlanSchemaReturns = struct {
 		result1 brokerapi.ServiceSchemas
@@ -299,6 +377,8 @@ func (fake *FakeServiceAdapterClient) GeneratePlanSchemaReturns(result1 brokerap
 }
 
 func (fake *FakeServiceAdapterClient) GeneratePlanSchemaReturnsOnCall(i int, result1 brokerapi.ServiceSchemas, result2 error) {
+	fake.generatePlanSchemaMutex.Lock()
+	defer fake.generatePlanSchemaMutex.Unlock()
 	fake.GeneratePlanSchemaStub = nil
 	if fake.generatePlanSchemaReturnsOnCall == nil {
 		fake.generatePlanSchemaReturnsOnCall = make(map[int]struct {
@@ -312,6 +392,81 @@ func (fake *FakeServiceAdapterClient) GeneratePlanSchemaReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
+func (fake *FakeServiceAdapterClient) GetBinding(arg1 context.Context, arg2 string, arg3 bosh.BoshVMs, arg4 []byte, arg5 map[string]string, arg6 map[string]string, arg7 *log.Logger) (serviceadapter.Binding, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.getBindingMutex.Lock()
+	ret, specificReturn := fake.getBindingReturnsOnCall[len(fake.getBindingArgsForCall)]
+	fake.getBindingArgsForCall = append(fake.getBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]string
+		arg6 map[string]string
+		arg7 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7})
+	stub := fake.GetBindingStub
+	fakeReturns := fake.getBindingReturns
+	fake.recordInvocation("GetBinding", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7})
+	fake.getBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceAdapterClient) GetBindingCallCount() int {
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
+	return len(fake.getBindingArgsForCall)
+}
+
+func (fake *FakeServiceAdapterClient) GetBindingCalls(stub func(context.Context, string, bosh.BoshVMs, []byte, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = stub
+}
+
+func (fake *FakeServiceAdapterClient) GetBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]string, map[string]string, *log.Logger) {
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
+	argsForCall := fake.getBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+}
+
+func (fake *FakeServiceAdapterClient) GetBindingReturns(result1 serviceadapter.Binding, result2 error) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = nil
+	fake.getBindingReturns = struct {
+		result1 serviceadapter.Binding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceAdapterClient) GetBindingReturnsOnCall(i int, result1 serviceadapter.Binding, result2 error) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = nil
+	if fake.getBindingReturnsOnCall == nil {
+		fake.getBindingReturnsOnCall = make(map[int]struct {
+			result1 serviceadapter.Binding
+			result2 error
+		})
+	}
+	fake.getBindingReturnsOnCall[i] = struct {
+		result1 serviceadapter.Binding
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceAdapterClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -323,6 +478,8 @@ func (fake *FakeServiceAdapterClient) Invocations() map[string][][]interface{} {
 	defer fake.generateDashboardUrlMutex.RUnlock()
 	fake.generatePlanSchemaMutex.RLock()
 	defer fake.generatePlanSchemaMutex.RUnlock()
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -3,11 +3,80 @@ package broker
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 func (b *Broker) GetBinding(ctx context.Context, instanceID, bindingID string) (brokerapi.GetBindingSpec, error) {
-	err := brokerapi.NewFailureResponse(errors.New("GetBinding Not Implemented"), 404, "")
-	return brokerapi.GetBindingSpec{}, err
+	if !b.EnableBindingsRetrievable {
+		err := brokerapi.NewFailureResponse(errors.New("GetBinding Not Implemented"), 404, "")
+		return brokerapi.GetBindingSpec{}, err
+	}
+
+	requestID := uuid.New()
+	if len(brokercontext.GetReqID(ctx)) > 0 {
+		requestID = brokercontext.GetReqID(ctx)
+	}
+
+	ctx = brokercontext.New(ctx, "get-binding", requestID, b.serviceOffering.Name, instanceID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	manifest, vms, deploymentErr := b.getDeploymentInfo(instanceID, ctx, "fetch", logger)
+	if deploymentErr != nil {
+		return brokerapi.GetBindingSpec{}, b.processError(deploymentErr, logger)
+	}
+
+	deploymentVariables, err := b.boshClient.Variables(deploymentName(instanceID), logger)
+	if err != nil {
+		logger.Printf("failed to retrieve deployment variables for deployment '%s': %s", deploymentName(instanceID), err)
+	}
+
+	secretsMap, err := b.secretManager.ResolveManifestSecrets(manifest, deploymentVariables, logger)
+	if err != nil {
+		logger.Printf("failed to resolve manifest secrets: %s", err.Error())
+	}
+
+	var bindingDNS []config.BindingDNS
+	details, found, err := InstanceDetailsFromManifest(manifest)
+	if err != nil {
+		logger.Printf("failed to read instance details from manifest: %s", err)
+	}
+	if plan, planFound := b.serviceOffering.FindPlanByID(details.PlanID); found && planFound {
+		bindingDNS = plan.BindingWithDNS
+	}
+
+	dnsAddresses, err := b.boshClient.GetDNSAddresses(deploymentName(instanceID), bindingDNS)
+	if err != nil {
+		return brokerapi.GetBindingSpec{}, b.processError(NewGenericError(ctx, fmt.Errorf("failed to get required DNS info: %s", err)), logger)
+	}
+
+	logger.Printf("service adapter will get binding with ID %s for instance %s\n", bindingID, instanceID)
+	binding, err := b.adapterClient.GetBinding(ctx, bindingID, vms, manifest, secretsMap, dnsAddresses, logger)
+	if err != nil {
+		logger.Printf("getting binding: %v\n", err)
+		switch err.(type) {
+		case serviceadapter.NotImplementedError:
+			return brokerapi.GetBindingSpec{}, b.processError(fmt.Errorf("enable_bindings_retrievable is set to true, but the service adapter does not implement get-binding"), logger)
+		case serviceadapter.BindingNotFoundError:
+			return brokerapi.GetBindingSpec{}, b.processError(NewDisplayableError(
+				brokerapi.ErrBindingNotFound,
+				fmt.Errorf("binding %s not found for instance %s", bindingID, instanceID),
+			), logger)
+		}
+	}
+
+	if err := adapterToAPIError(ctx, err); err != nil {
+		return brokerapi.GetBindingSpec{}, b.processError(err, logger)
+	}
+
+	return brokerapi.GetBindingSpec{
+		Credentials:     binding.Credentials,
+		SyslogDrainURL:  binding.SyslogDrainURL,
+		RouteServiceURL: binding.RouteServiceURL,
+	}, nil
 }
@@ -2,19 +2,144 @@ package broker_test
 
 import (
 	"context"
+	"errors"
 
 	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 var _ = Describe("GetBinding", func() {
-	It("returns an error", func() {
+	const (
+		instanceID = "some-instance-id"
+		bindingID  = "some-binding-id"
+	)
+
+	var (
+		manifest      []byte
+		boshVMs       bosh.BoshVMs
+		bindingSpec   brokerapi.GetBindingSpec
+		getBindingErr error
+	)
+
+	It("returns an error when bindings are not retrievable", func() {
+		b = createDefaultBroker()
 		_, err := b.GetBinding(context.Background(), "instanceID", "bID")
 		fresp, ok := err.(*brokerapi.FailureResponse)
 		Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
 		logger := lager.NewLogger("test")
 		Expect(fresp.ValidatedStatusCode(logger)).To(Equal(404))
+		Expect(serviceAdapter.GetBindingCallCount()).To(Equal(0))
+	})
+
+	Context("when bindings are retrievable", func() {
+		BeforeEach(func() {
+			brokerConfig.EnableBindingsRetrievable = true
+
+			var err error
+			manifest, err = broker.AddInstanceDetailsToManifest([]byte("name: "+deploymentName(instanceID)), broker.InstanceDetails{PlanID: existingPlanID})
+			Expect(err).NotTo(HaveOccurred())
+			boshVMs = bosh.BoshVMs{"redis-server": []string{"an.ip"}}
+
+			boshClient.GetDeploymentReturns(manifest, true, nil)
+			boshClient.VMsReturns(boshVMs, nil)
+			boshClient.VariablesReturns([]boshdirector.Variable{{Path: "/foo/bar", ID: "123asd"}}, nil)
+			boshClient.GetDNSAddressesReturns(map[string]string{"config1": "some.dns.bosh"}, nil)
+			fakeSecretManager.ResolveManifestSecretsReturns(map[string]string{"/foo/bar": "secret"}, nil)
+			serviceAdapter.GetBindingReturns(sdk.Binding{
+				Credentials:     map[string]interface{}{"foo": "bar"},
+				SyslogDrainURL:  "syslog",
+				RouteServiceURL: "route",
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			b = createDefaultBroker()
+			bindingSpec, getBindingErr = b.GetBinding(context.Background(), instanceID, bindingID)
+		})
+
+		It("returns the binding generated by the adapter", func() {
+			Expect(getBindingErr).NotTo(HaveOccurred())
+			Expect(bindingSpec).To(Equal(brokerapi.GetBindingSpec{
+				Credentials:     map[string]interface{}{"foo": "bar"},
+				SyslogDrainURL:  "syslog",
+				RouteServiceURL: "route",
+			}))
+		})
+
+		It("calls the adapter with the deployment topology, manifest, secrets and dns addresses", func() {
+			Expect(serviceAdapter.GetBindingCallCount()).To(Equal(1))
+			_, actualBindingID, actualVMs, actualManifest, actualSecrets, actualDNSAddresses, _ := serviceAdapter.GetBindingArgsForCall(0)
+			Expect(actualBindingID).To(Equal(bindingID))
+			Expect(actualVMs).To(Equal(boshVMs))
+			Expect(actualManifest).To(Equal(manifest))
+			Expect(actualSecrets).To(Equal(map[string]string{"/foo/bar": "secret"}))
+			Expect(actualDNSAddresses).To(Equal(map[string]string{"config1": "some.dns.bosh"}))
+
+			actualDeploymentName, actualBindingDNS := boshClient.GetDNSAddressesArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+			Expect(actualBindingDNS).To(Equal(existingPlan.BindingWithDNS))
+
+			_, actualVariables, _ := fakeSecretManager.ResolveManifestSecretsArgsForCall(0)
+			Expect(actualVariables).To(Equal([]boshdirector.Variable{{Path: "/foo/bar", ID: "123asd"}}))
+		})
+
+		Context("when the deployment does not exist", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentReturns(nil, false, nil)
+			})
+
+			It("returns an instance does not exist error", func() {
+				Expect(getBindingErr).To(Equal(brokerapi.ErrInstanceDoesNotExist))
+			})
+		})
+
+		Context("when the adapter does not implement get-binding", func() {
+			BeforeEach(func() {
+				serviceAdapter.GetBindingReturns(sdk.Binding{}, serviceadapter.NewNotImplementedError("not implemented"))
+			})
+
+			It("returns an error", func() {
+				Expect(getBindingErr).To(MatchError("enable_bindings_retrievable is set to true, but the service adapter does not implement get-binding"))
+			})
+		})
+
+		Context("when the adapter cannot find the binding", func() {
+			BeforeEach(func() {
+				serviceAdapter.GetBindingReturns(sdk.Binding{}, serviceadapter.BindingNotFoundError{})
+			})
+
+			It("returns a 404", func() {
+				Expect(getBindingErr).To(Equal(brokerapi.ErrBindingNotFound))
+			})
+		})
+
+		Context("when the adapter fails", func() {
+			BeforeEach(func() {
+				serviceAdapter.GetBindingReturns(sdk.Binding{}, errors.New("oops"))
+			})
+
+			It("returns a generic error", func() {
+				Expect(getBindingErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			})
+		})
+
+		Context("when getting the dns addresses fails", func() {
+			BeforeEach(func() {
+				boshClient.GetDNSAddressesReturns(nil, errors.New("no dns"))
+			})
+
+			It("returns a generic error without calling the adapter", func() {
+				Expect(getBindingErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+				Expect(logBuffer.String()).To(ContainSubstring("failed to get required DNS info: no dns"))
+				Expect(serviceAdapter.GetBindingCallCount()).To(Equal(0))
+			})
+		})
 	})
 })
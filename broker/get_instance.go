@@ -1,13 +1,103 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
 package broker
 
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 
+	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
+var ErrInstanceNotFound = brokerapi.NewFailureResponseBuilder(
+	errors.New("instance does not exist"), http.StatusNotFound, "instance-not-found",
+).WithEmptyResponse().Build()
+
 func (b *Broker) GetInstance(ctx context.Context, instanceID string) (brokerapi.GetInstanceDetailsSpec, error) {
-	err := brokerapi.NewFailureResponse(errors.New("GetInstance Not Implemented"), 404, "")
-	return brokerapi.GetInstanceDetailsSpec{}, err
+	if !b.EnableInstancesRetrievable {
+		err := brokerapi.NewFailureResponse(errors.New("GetInstance Not Implemented"), http.StatusNotFound, "")
+		return brokerapi.GetInstanceDetailsSpec{}, err
+	}
+
+	requestID := uuid.New()
+	ctx = brokercontext.New(ctx, "get-instance", requestID, b.serviceOffering.Name, instanceID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	manifest, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	if err != nil {
+		return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewBoshRequestError("get", fmt.Errorf("could not get manifest: %s", err)), logger)
+	}
+	if !found {
+		return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewDisplayableError(
+			ErrInstanceNotFound,
+			fmt.Errorf("error getting instance: instance %s, not found", instanceID),
+		), logger)
+	}
+
+	tasks, err := b.boshClient.GetTasks(deploymentName(instanceID), logger)
+	if err != nil {
+		return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewBoshRequestError("get", fmt.Errorf("could not get tasks: %s", err)), logger)
+	}
+	if incompleteTasks := tasks.IncompleteTasks(); len(incompleteTasks) != 0 {
+		return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewDisplayableError(
+			brokerapi.ErrConcurrentInstanceAccess.Build(),
+			fmt.Errorf("deployment %s is still in progress: tasks %s", deploymentName(instanceID), incompleteTasks.ToLog()),
+		), logger)
+	}
+
+	details, found, err := InstanceDetailsFromManifest(manifest)
+	if err != nil {
+		return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+	if !found {
+		logger.Printf("instance details not recorded in manifest for instance %s, looking up plan", instanceID)
+		details.PlanID, err = b.lookupPlanID(instanceID)
+		if err != nil {
+			return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewGenericError(ctx, err), logger)
+		}
+	}
+
+	plan, found := b.serviceOffering.FindPlanByID(details.PlanID)
+	if !found {
+		return brokerapi.GetInstanceDetailsSpec{}, b.processError(NewGenericError(ctx, PlanNotFoundError{PlanGUID: details.PlanID}), logger)
+	}
+
+	dashboardURL, err := b.adapterClient.GenerateDashboardUrl(ctx, instanceID, plan.AdapterPlan(b.serviceOffering.GlobalProperties), manifest, logger)
+	if err != nil {
+		if _, ok := err.(serviceadapter.NotImplementedError); !ok {
+			logger.Printf("generating dashboard: %v\n", err)
+			return brokerapi.GetInstanceDetailsSpec{}, b.processError(adapterToAPIError(ctx, err), logger)
+		}
+	}
+
+	return brokerapi.GetInstanceDetailsSpec{
+		ServiceID:    b.serviceOffering.ID,
+		PlanID:       details.PlanID,
+		DashboardURL: dashboardURL,
+		Parameters:   details.Parameters,
+	}, nil
+}
+
+func (b *Broker) lookupPlanID(instanceID string) (string, error) {
+	instances, err := b.instanceLister.Instances()
+	if err != nil {
+		return "", fmt.Errorf("error listing service instances: %s", err)
+	}
+
+	for _, instance := range instances {
+		if instance.GUID == instanceID {
+			return instance.PlanUniqueID, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find plan for instance %s", instanceID)
 }
@@ -1,21 +1,192 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
 package broker_test
 
 import (
 	"context"
+	"errors"
 
 	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 var _ = Describe("GetInstance", func() {
-	It("returns an appropriate error", func() {
-		_, err := b.GetInstance(context.Background(), "some-instance-id")
-		fresp, ok := err.(*brokerapi.FailureResponse)
-		Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
-		logger := lager.NewLogger("test")
-		Expect(fresp.ValidatedStatusCode(logger)).To(Equal(404))
+	const instanceID = "some-instance-id"
+
+	var (
+		manifest        []byte
+		instanceDetails brokerapi.GetInstanceDetailsSpec
+		getInstanceErr  error
+	)
+
+	BeforeEach(func() {
+		brokerConfig.EnableInstancesRetrievable = true
+
+		var err error
+		manifest, err = broker.AddInstanceDetailsToManifest([]byte("name: "+deploymentName(instanceID)), broker.InstanceDetails{
+			PlanID:     existingPlanID,
+			Parameters: map[string]interface{}{"foo": "bar"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		boshClient.GetDeploymentReturns(manifest, true, nil)
+		boshClient.GetTasksReturns(boshdirector.BoshTasks{{State: boshdirector.TaskDone}}, nil)
+		serviceAdapter.GenerateDashboardUrlReturns("http://dashboard.example.com", nil)
+	})
+
+	JustBeforeEach(func() {
+		b = createDefaultBroker()
+		instanceDetails, getInstanceErr = b.GetInstance(context.Background(), instanceID)
+	})
+
+	It("returns the plan and parameters recorded in the deployment manifest", func() {
+		Expect(getInstanceErr).NotTo(HaveOccurred())
+		Expect(instanceDetails).To(Equal(brokerapi.GetInstanceDetailsSpec{
+			ServiceID:    serviceOfferingID,
+			PlanID:       existingPlanID,
+			DashboardURL: "http://dashboard.example.com",
+			Parameters:   map[string]interface{}{"foo": "bar"},
+		}))
+
+		Expect(boshClient.GetDeploymentCallCount()).To(Equal(1))
+		actualDeploymentName, _ := boshClient.GetDeploymentArgsForCall(0)
+		Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+	})
+
+	It("generates the dashboard URL from the deployed manifest", func() {
+		Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
+		_, actualInstanceID, actualPlan, actualManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+		Expect(actualInstanceID).To(Equal(instanceID))
+		Expect(actualPlan).To(Equal(existingPlan.AdapterPlan(serviceCatalog.GlobalProperties)))
+		Expect(actualManifest).To(Equal(manifest))
+	})
+
+	It("does not look up the instance in the platform", func() {
+		Expect(fakeInstanceLister.InstancesCallCount()).To(Equal(0))
+	})
+
+	Context("when the adapter does not implement generate-dashboard-url", func() {
+		BeforeEach(func() {
+			serviceAdapter.GenerateDashboardUrlReturns("", serviceadapter.NewNotImplementedError("not implemented"))
+		})
+
+		It("returns the instance details without a dashboard URL", func() {
+			Expect(getInstanceErr).NotTo(HaveOccurred())
+			Expect(instanceDetails.DashboardURL).To(BeEmpty())
+			Expect(instanceDetails.PlanID).To(Equal(existingPlanID))
+		})
+	})
+
+	Context("when generating the dashboard URL fails", func() {
+		BeforeEach(func() {
+			serviceAdapter.GenerateDashboardUrlReturns("", errors.New("oops"))
+		})
+
+		It("returns a generic error", func() {
+			Expect(getInstanceErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+		})
+	})
+
+	Context("when the manifest does not record the instance details", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns([]byte("name: "+deploymentName(instanceID)), true, nil)
+			fakeInstanceLister.InstancesReturns([]service.Instance{
+				{GUID: "another-instance-id", PlanUniqueID: secondPlanID},
+				{GUID: instanceID, PlanUniqueID: existingPlanID},
+			}, nil)
+		})
+
+		It("looks up the plan of the instance and returns no parameters", func() {
+			Expect(getInstanceErr).NotTo(HaveOccurred())
+			Expect(fakeInstanceLister.InstancesCallCount()).To(Equal(1))
+			Expect(instanceDetails.PlanID).To(Equal(existingPlanID))
+			Expect(instanceDetails.Parameters).To(BeNil())
+		})
+
+		Context("and listing the instances fails", func() {
+			BeforeEach(func() {
+				fakeInstanceLister.InstancesReturns(nil, errors.New("cf is down"))
+			})
+
+			It("returns a generic error", func() {
+				Expect(getInstanceErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+				Expect(logBuffer.String()).To(ContainSubstring("cf is down"))
+			})
+		})
+	})
+
+	Context("when the deployment does not exist", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+		})
+
+		It("returns a 404", func() {
+			fresp, ok := getInstanceErr.(*brokerapi.FailureResponse)
+			Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
+			Expect(fresp.ValidatedStatusCode(lager.NewLogger("test"))).To(Equal(404))
+		})
+	})
+
+	Context("when getting the deployment fails", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns(nil, false, errors.New("bosh is down"))
+		})
+
+		It("returns a bosh request error", func() {
+			Expect(getInstanceErr).To(MatchError(ContainSubstring("Currently unable to get service instance")))
+		})
+	})
+
+	Context("when an operation is in progress for the instance", func() {
+		BeforeEach(func() {
+			boshClient.GetTasksReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+		})
+
+		It("returns a concurrency error", func() {
+			fresp, ok := getInstanceErr.(*brokerapi.FailureResponse)
+			Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
+			Expect(fresp.ValidatedStatusCode(lager.NewLogger("test"))).To(Equal(422))
+			Expect(fresp.ErrorResponse()).To(Equal(brokerapi.ErrorResponse{
+				Error:       "ConcurrencyError",
+				Description: "instance is being updated and cannot be retrieved",
+			}))
+		})
+	})
+
+	Context("when instances are not retrievable", func() {
+		BeforeEach(func() {
+			brokerConfig.EnableInstancesRetrievable = false
+		})
+
+		It("returns not found without looking up the deployment", func() {
+			fresp, ok := getInstanceErr.(*brokerapi.FailureResponse)
+			Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
+			Expect(fresp.ValidatedStatusCode(lager.NewLogger("test"))).To(Equal(404))
+			Expect(boshClient.GetDeploymentCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the recorded plan no longer exists", func() {
+		BeforeEach(func() {
+			var err error
+			manifest, err = broker.AddInstanceDetailsToManifest([]byte("name: foo"), broker.InstanceDetails{PlanID: "deleted-plan"})
+			Expect(err).NotTo(HaveOccurred())
+			boshClient.GetDeploymentReturns(manifest, true, nil)
+		})
+
+		It("returns a generic error", func() {
+			Expect(getInstanceErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(ContainSubstring("plan deleted-plan does not exist"))
+		})
 	})
 })
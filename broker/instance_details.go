@@ -0,0 +1,76 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// InstanceDetailsManifestKey is the top-level key under which the broker records
+// the plan a service instance was last deployed with, and those of its
+// arbitrary parameters that the plan allows to be retrieved. BOSH ignores
+// top-level manifest keys it does not recognise.
+const InstanceDetailsManifestKey = "odb_instance_details"
+
+type InstanceDetails struct {
+	PlanID     string
+	Parameters map[string]interface{}
+}
+
+type manifestInstanceDetails struct {
+	PlanID     string `yaml:"plan_id"`
+	Parameters string `yaml:"parameters,omitempty"`
+}
+
+func InstanceDetailsFromManifest(manifest []byte) (InstanceDetails, bool, error) {
+	var wrapper struct {
+		Details *manifestInstanceDetails `yaml:"odb_instance_details"`
+	}
+	if err := yaml.Unmarshal(manifest, &wrapper); err != nil {
+		return InstanceDetails{}, false, fmt.Errorf("error reading instance details from manifest: %s", err)
+	}
+	if wrapper.Details == nil {
+		return InstanceDetails{}, false, nil
+	}
+
+	details := InstanceDetails{PlanID: wrapper.Details.PlanID, Parameters: map[string]interface{}{}}
+	if wrapper.Details.Parameters != "" {
+		if err := json.Unmarshal([]byte(wrapper.Details.Parameters), &details.Parameters); err != nil {
+			return InstanceDetails{}, false, fmt.Errorf("error reading instance parameters from manifest: %s", err)
+		}
+	}
+	return details, true, nil
+}
+
+func AddInstanceDetailsToManifest(manifest []byte, details InstanceDetails) ([]byte, error) {
+	var manifestContent yaml.MapSlice
+	if err := yaml.Unmarshal(manifest, &manifestContent); err != nil {
+		return nil, fmt.Errorf("error recording instance details in manifest: %s", err)
+	}
+
+	recordedDetails := manifestInstanceDetails{PlanID: details.PlanID}
+	if len(details.Parameters) > 0 {
+		parameters, err := json.Marshal(details.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("error recording instance parameters in manifest: %s", err)
+		}
+		recordedDetails.Parameters = string(parameters)
+	}
+
+	var updatedContent yaml.MapSlice
+	for _, item := range manifestContent {
+		if item.Key != InstanceDetailsManifestKey {
+			updatedContent = append(updatedContent, item)
+		}
+	}
+	updatedContent = append(updatedContent, yaml.MapItem{Key: InstanceDetailsManifestKey, Value: recordedDetails})
+
+	return yaml.Marshal(updatedContent)
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+type InstanceOperation struct {
+	BoshTaskID     int           `json:"bosh_task_id"`
+	OperationType  OperationType `json:"operation_type,omitempty"`
+	State          string        `json:"state"`
+	Description    string        `json:"description"`
+	Errand         string        `json:"errand,omitempty"`
+	StartedAt      time.Time     `json:"started_at"`
+	LastActivityAt time.Time     `json:"last_activity_at"`
+}
+
+var errandTaskDescription = regexp.MustCompile(`^run errand (\S+)`)
+
+// InstanceOperations lists the BOSH tasks of the instance's deployment, oldest
+// first. Tasks started by the broker are labelled with the type of the
+// operation they belong to, as recorded in the operation store. An instance
+// whose deployment has neither tasks nor a manifest is not known to BOSH.
+func (b *Broker) InstanceOperations(instanceID string, logger *log.Logger) ([]InstanceOperation, error) {
+	tasks, err := b.boshClient.GetTasks(deploymentName(instanceID), logger)
+	if err != nil {
+		logger.Printf("error getting tasks for deployment %s: %s", deploymentName(instanceID), err)
+		return nil, b.processError(err, logger)
+	}
+
+	if len(tasks) == 0 {
+		_, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+		if err != nil {
+			logger.Printf("error getting deployment %s: %s", deploymentName(instanceID), err)
+			return nil, b.processError(err, logger)
+		}
+		if !found {
+			return nil, NewDeploymentNotFoundError(fmt.Errorf("bosh deployment '%s' not found", deploymentName(instanceID)))
+		}
+	}
+
+	recordedOperations, err := b.operationStore.ForInstance(instanceID)
+	if err != nil {
+		logger.Printf("error reading operation store for instance %s: %s", instanceID, err)
+		return nil, b.processError(fmt.Errorf("error reading operation store: %s", err), logger)
+	}
+
+	operations := []InstanceOperation{}
+	for _, task := range tasks {
+		operation := InstanceOperation{
+			BoshTaskID:     task.ID,
+			OperationType:  recordedOperationType(recordedOperations, task.ID, task.ContextID),
+			State:          task.State,
+			Description:    task.Description,
+			StartedAt:      task.StartedAt,
+			LastActivityAt: task.LastActivityAt,
+		}
+		if match := errandTaskDescription.FindStringSubmatch(task.Description); match != nil {
+			operation.Errand = match[1]
+		}
+		operations = append(operations, operation)
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].BoshTaskID < operations[j].BoshTaskID
+	})
+
+	return operations, nil
+}
+
+func recordedOperationType(recordedOperations []operationstore.Operation, taskID int, contextID string) OperationType {
+	for _, recorded := range recordedOperations {
+		if contextID != "" && recorded.BoshContextID == contextID {
+			return OperationType(recorded.Type)
+		}
+		for _, recordedTaskID := range recorded.BoshTaskIDs {
+			if recordedTaskID == taskID {
+				return OperationType(recorded.Type)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+var _ = Describe("InstanceOperations", func() {
+	const instanceID = "some-instance-id"
+
+	var startedAt time.Time
+
+	BeforeEach(func() {
+		startedAt = time.Date(2019, 3, 12, 10, 0, 0, 0, time.UTC)
+		b = createDefaultBroker()
+	})
+
+	It("lists the bosh tasks of the instance oldest first, labelled with their operation", func() {
+		boshClient.GetTasksReturns(boshdirector.BoshTasks{
+			{ID: 7, State: boshdirector.TaskProcessing, Description: "run errand health-check from deployment service-instance_some-instance-id", ContextID: "some-context-id", StartedAt: startedAt.Add(time.Hour)},
+			{ID: 6, State: boshdirector.TaskDone, Description: "create deployment", ContextID: "some-context-id", StartedAt: startedAt.Add(time.Minute), LastActivityAt: startedAt.Add(time.Hour)},
+			{ID: 3, State: boshdirector.TaskDone, Description: "create deployment", StartedAt: startedAt},
+		}, nil)
+		fakeOperationStore.ForInstanceReturns([]operationstore.Operation{
+			{InstanceID: instanceID, Type: "create", BoshTaskIDs: []int{3}},
+			{InstanceID: instanceID, Type: "upgrade", BoshTaskIDs: []int{6}, BoshContextID: "some-context-id"},
+		}, nil)
+
+		operations, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(boshClient.GetTasksCallCount()).To(Equal(1))
+		actualDeploymentName, _ := boshClient.GetTasksArgsForCall(0)
+		Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+		Expect(fakeOperationStore.ForInstanceArgsForCall(0)).To(Equal(instanceID))
+
+		Expect(operations).To(Equal([]broker.InstanceOperation{
+			{BoshTaskID: 3, OperationType: broker.OperationTypeCreate, State: boshdirector.TaskDone, Description: "create deployment", StartedAt: startedAt},
+			{BoshTaskID: 6, OperationType: broker.OperationTypeUpgrade, State: boshdirector.TaskDone, Description: "create deployment", StartedAt: startedAt.Add(time.Minute), LastActivityAt: startedAt.Add(time.Hour)},
+			{BoshTaskID: 7, OperationType: broker.OperationTypeUpgrade, State: boshdirector.TaskProcessing, Description: "run errand health-check from deployment service-instance_some-instance-id", Errand: "health-check", StartedAt: startedAt.Add(time.Hour)},
+		}))
+	})
+
+	It("lists tasks that were not started by the broker without an operation type", func() {
+		boshClient.GetTasksReturns(boshdirector.BoshTasks{{ID: 3, State: boshdirector.TaskDone, Description: "create deployment"}}, nil)
+
+		operations, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(operations).To(Equal([]broker.InstanceOperation{{BoshTaskID: 3, State: boshdirector.TaskDone, Description: "create deployment"}}))
+	})
+
+	It("returns an empty list when the deployment has no tasks", func() {
+		boshClient.GetDeploymentReturns([]byte("name: some-deployment"), true, nil)
+
+		operations, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations).To(BeEmpty())
+		Expect(operations).NotTo(BeNil())
+	})
+
+	It("returns a deployment not found error when the deployment has neither tasks nor a manifest", func() {
+		boshClient.GetDeploymentReturns(nil, false, nil)
+
+		_, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+		Expect(err).To(MatchError("bosh deployment 'service-instance_some-instance-id' not found"))
+	})
+
+	It("returns an error when the deployment cannot be retrieved", func() {
+		boshClient.GetDeploymentReturns(nil, false, errors.New("director unreachable"))
+
+		_, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).To(MatchError("director unreachable"))
+	})
+
+	It("returns an error when the tasks cannot be retrieved", func() {
+		boshClient.GetTasksReturns(nil, errors.New("director unreachable"))
+
+		_, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).To(MatchError("director unreachable"))
+	})
+
+	It("returns an error when the operation store cannot be read", func() {
+		boshClient.GetDeploymentReturns([]byte("name: some-deployment"), true, nil)
+		fakeOperationStore.ForInstanceReturns(nil, errors.New("corrupt store"))
+
+		_, err := b.InstanceOperations(instanceID, loggerFactory.NewWithRequestID())
+		Expect(err).To(MatchError("error reading operation store: corrupt store"))
+	})
+})
@@ -2,12 +2,57 @@ package broker
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
+	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 )
 
 func (b *Broker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details brokerapi.PollDetails) (brokerapi.LastOperation, error) {
-	err := brokerapi.NewFailureResponse(errors.New("LastBindingOperation Not Implemented"), 404, "")
-	return brokerapi.LastOperation{}, err
+	requestID := uuid.New()
+	ctx = brokercontext.New(ctx, "", requestID, b.serviceOffering.Name, instanceID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	if details.OperationData == "" {
+		return brokerapi.LastOperation{}, b.processError(NewGenericError(ctx, errors.New("request missing operation data")), logger)
+	}
+
+	var operationData OperationData
+	if err := json.Unmarshal([]byte(details.OperationData), &operationData); err != nil {
+		return brokerapi.LastOperation{}, b.processError(NewGenericError(ctx, fmt.Errorf("operation data cannot be parsed: %s", err)), logger)
+	}
+
+	ctx = brokercontext.WithOperation(ctx, string(operationData.OperationType))
+
+	if !validBindingOpType(operationData.OperationType) {
+		return brokerapi.LastOperation{}, b.processError(NewGenericError(ctx, fmt.Errorf("unexpected operation type %q for binding %s", operationData.OperationType, bindingID)), logger)
+	}
+
+	if operationData.BoshTaskID == 0 {
+		return brokerapi.LastOperation{}, b.processError(NewGenericError(ctx, errors.New("no task ID found in operation data")), logger)
+	}
+
+	ctx = brokercontext.WithBoshTaskID(ctx, operationData.BoshTaskID)
+
+	lifeCycleRunner := NewLifeCycleRunner(b.boshClient, b.serviceOffering.Plans)
+
+	// if the next errand isn't already running, GetTask will start it
+	lastBoshTask, err := lifeCycleRunner.GetTask(deploymentName(instanceID), operationData, logger)
+	if err != nil {
+		return brokerapi.LastOperation{}, b.processError(
+			NewGenericError(ctx, fmt.Errorf("error retrieving tasks from bosh, for deployment '%s': %s", deploymentName(instanceID), err)),
+			logger,
+		)
+	}
+
+	ctx = brokercontext.WithBoshTaskID(ctx, lastBoshTask.ID)
+
+	taskState := lastOperationState(lastBoshTask, logger)
+	lastOperation := constructLastOperation(ctx, taskState, lastBoshTask, operationData, b.ExposeOperationalErrors)
+	logLastOperation(instanceID, lastBoshTask, operationData, logger)
+
+	return lastOperation, nil
 }
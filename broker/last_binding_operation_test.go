@@ -2,20 +2,201 @@ package broker_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 
-	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 var _ = Describe("LastBindingOperation", func() {
-	It("returns an appropriate error", func() {
-		_, err := b.LastBindingOperation(context.Background(), "instanceID", "bID", brokerapi.PollDetails{})
-		fresp, ok := err.(*brokerapi.FailureResponse)
-		Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
-		logger := lager.NewLogger("test")
-		Expect(fresp.ValidatedStatusCode(logger)).To(Equal(404))
+	const (
+		instanceID = "some-instance-id"
+		bindingID  = "some-binding-id"
+		contextID  = "some-context-id"
+	)
+
+	var (
+		operationData string
+		opResult      brokerapi.LastOperation
+		lastOpErr     error
+	)
+
+	toOperationData := func(operationType broker.OperationType) string {
+		data, err := json.Marshal(broker.OperationData{
+			BoshTaskID:    42,
+			BoshContextID: contextID,
+			OperationType: operationType,
+			Errands:       []config.Errand{{Name: "create-acls"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		operationData = toOperationData(broker.OperationTypeBind)
+	})
+
+	JustBeforeEach(func() {
+		b = createDefaultBroker()
+		opResult, lastOpErr = b.LastBindingOperation(context.Background(), instanceID, bindingID, brokerapi.PollDetails{OperationData: operationData})
+	})
+
+	Context("while the binding errand is running", func() {
+		BeforeEach(func() {
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 42, State: boshdirector.TaskProcessing}}, nil)
+		})
+
+		It("returns in progress", func() {
+			Expect(lastOpErr).NotTo(HaveOccurred())
+			Expect(opResult).To(Equal(brokerapi.LastOperation{State: brokerapi.InProgress, Description: "Binding creation in progress"}))
+
+			actualDeploymentName, actualContextID, _ := boshClient.GetNormalisedTasksByContextArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+			Expect(actualContextID).To(Equal(contextID))
+		})
+	})
+
+	Context("when the unbind errands have completed", func() {
+		BeforeEach(func() {
+			operationData = toOperationData(broker.OperationTypeUnbind)
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 42, State: boshdirector.TaskDone}}, nil)
+		})
+
+		It("returns succeeded", func() {
+			Expect(lastOpErr).NotTo(HaveOccurred())
+			Expect(opResult).To(Equal(brokerapi.LastOperation{State: brokerapi.Succeeded, Description: "Binding deletion completed"}))
+		})
+	})
+
+	Context("when the binding errand fails", func() {
+		BeforeEach(func() {
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 42, State: boshdirector.TaskError}}, nil)
+		})
+
+		It("returns failed", func() {
+			Expect(lastOpErr).NotTo(HaveOccurred())
+			Expect(opResult.State).To(Equal(brokerapi.Failed))
+			Expect(opResult.Description).To(ContainSubstring("Binding creation failed"))
+		})
+	})
+
+	Context("when the tasks cannot be retrieved from BOSH", func() {
+		BeforeEach(func() {
+			boshClient.GetNormalisedTasksByContextReturns(nil, errors.New("something went wrong!"))
+		})
+
+		It("returns a generic error", func() {
+			Expect(lastOpErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(ContainSubstring("error retrieving tasks from bosh, for deployment 'service-instance_some-instance-id': something went wrong!"))
+		})
+	})
+
+	Context("when there is no operation data present in the request", func() {
+		BeforeEach(func() {
+			operationData = ""
+		})
+
+		It("returns a generic error", func() {
+			Expect(lastOpErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(ContainSubstring("request missing operation data"))
+		})
+	})
+
+	Context("when the operation data cannot be parsed", func() {
+		BeforeEach(func() {
+			operationData = "not-json"
+		})
+
+		It("returns a generic error", func() {
+			Expect(lastOpErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(ContainSubstring("operation data cannot be parsed"))
+		})
+	})
+
+	Context("when the operation data is not for a binding operation", func() {
+		BeforeEach(func() {
+			operationData = toOperationData(broker.OperationTypeCreate)
+		})
+
+		It("returns a generic error", func() {
+			Expect(lastOpErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(ContainSubstring(`unexpected operation type "create" for binding some-binding-id`))
+		})
+	})
+
+	Context("counting the outcome of binding errands", func() {
+		var failedBefore, succeededBefore float64
+
+		outcomes := func(state string) float64 {
+			return testutil.ToFloat64(metrics.BoshTaskOutcomes.WithLabelValues("bind", state))
+		}
+
+		BeforeEach(func() {
+			failedBefore = outcomes("failed")
+			succeededBefore = outcomes("succeeded")
+		})
+
+		Context("when the last binding errand has completed", func() {
+			BeforeEach(func() {
+				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 4201, State: boshdirector.TaskDone}}, nil)
+			})
+
+			It("counts the outcome once however many times the operation is polled", func() {
+				Expect(outcomes("succeeded")).To(Equal(succeededBefore + 1))
+
+				_, err := b.LastBindingOperation(context.Background(), instanceID, bindingID, brokerapi.PollDetails{OperationData: operationData})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(outcomes("succeeded")).To(Equal(succeededBefore + 1))
+			})
+		})
+
+		Context("when a binding errand fails", func() {
+			BeforeEach(func() {
+				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 4202, State: boshdirector.TaskError}}, nil)
+			})
+
+			It("counts the failure", func() {
+				Expect(outcomes("failed")).To(Equal(failedBefore + 1))
+			})
+		})
+
+		Context("when the binding errand is still running", func() {
+			BeforeEach(func() {
+				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 4203, State: boshdirector.TaskProcessing}}, nil)
+			})
+
+			It("does not count an outcome", func() {
+				Expect(outcomes("succeeded")).To(Equal(succeededBefore))
+				Expect(outcomes("failed")).To(Equal(failedBefore))
+			})
+		})
+
+		Context("when another binding errand is still to run", func() {
+			BeforeEach(func() {
+				data, err := json.Marshal(broker.OperationData{
+					BoshTaskID:    42,
+					BoshContextID: contextID,
+					OperationType: broker.OperationTypeBind,
+					Errands:       []config.Errand{{Name: "create-acls"}, {Name: "warm-cache"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				operationData = string(data)
+				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 4204, State: boshdirector.TaskDone}}, nil)
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 4205, State: boshdirector.TaskProcessing}, nil)
+			})
+
+			It("does not count an outcome until the last errand finishes", func() {
+				Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+				Expect(outcomes("succeeded")).To(Equal(succeededBefore))
+			})
+		})
 	})
 })
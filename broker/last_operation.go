@@ -26,6 +26,8 @@ var descriptions = map[brokerapi.LastOperationState]map[OperationType]string{
 		OperationTypeUpgrade:  "Instance upgrade in progress",
 		OperationTypeDelete:   "Instance deletion in progress",
 		OperationTypeRecreate: "Instance recreate in progress",
+		OperationTypeBind:     "Binding creation in progress",
+		OperationTypeUnbind:   "Binding deletion in progress",
 	},
 	brokerapi.Succeeded: {
 		OperationTypeCreate:   "Instance provisioning completed",
@@ -33,6 +35,8 @@ var descriptions = map[brokerapi.LastOperationState]map[OperationType]string{
 		OperationTypeUpgrade:  "Instance upgrade completed",
 		OperationTypeDelete:   "Instance deletion completed",
 		OperationTypeRecreate: "Instance recreate completed",
+		OperationTypeBind:     "Binding creation completed",
+		OperationTypeUnbind:   "Binding deletion completed",
 	},
 	brokerapi.Failed: {
 		OperationTypeCreate:   "Instance provisioning failed",
@@ -40,6 +44,8 @@ var descriptions = map[brokerapi.LastOperationState]map[OperationType]string{
 		OperationTypeUpgrade:  "Failed for bosh task",
 		OperationTypeDelete:   "Instance deletion failed",
 		OperationTypeRecreate: "Instance recreate failed",
+		OperationTypeBind:     "Binding creation failed",
+		OperationTypeUnbind:   "Binding deletion failed",
 	},
 }
 
@@ -72,7 +78,20 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID string, pollDetai
 	lifeCycleRunner := NewLifeCycleRunner(b.boshClient, b.serviceOffering.Plans)
 
 	// if the errand isn't already running, or delete deployment wasn't triggered, GetTask will start it!
-	lastBoshTask, err := lifeCycleRunner.GetTask(deploymentName(instanceID), operationData, logger)
+	var lastBoshTask boshdirector.BoshTask
+	var err error
+	deployed := true
+	if len(operationData.PreUpgradeErrands) > 0 {
+		lastBoshTask, deployed, err = b.processPreUpgrade(ctx, instanceID, operationData, lifeCycleRunner, logger)
+	} else {
+		lastBoshTask, err = lifeCycleRunner.GetTask(deploymentName(instanceID), operationData, logger)
+	}
+	if upgradeErr, ok := err.(upgradeAfterErrandsError); ok {
+		lastOperation := constructLastOperation(ctx, brokerapi.Failed, lastBoshTask, operationData, false)
+		lastOperation.Description = fmt.Sprintf("%s, error-message: %s", lastOperation.Description, b.upgradeError(ctx, upgradeErr.error, logger))
+		b.updateOperationState(instanceID, operationData, lastBoshTask.ID, brokerapi.Failed, logger)
+		return lastOperation, nil
+	}
 	if err != nil {
 		return brokerapi.LastOperation{}, b.processError(
 			NewGenericError(ctx, fmt.Errorf("error retrieving tasks from bosh, for deployment '%s': %s", deploymentName(instanceID), err)),
@@ -86,6 +105,7 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID string, pollDetai
 				ctx = brokercontext.WithBoshTaskID(ctx, 0)
 				lastOperation := constructLastOperation(ctx, brokerapi.Failed, lastBoshTask, operationData, b.ExposeOperationalErrors)
 				logger.Printf("Failed to delete configs for service instance %s: %s\n", instanceID, err.Error())
+				b.updateOperationState(instanceID, operationData, lastBoshTask.ID, brokerapi.Failed, logger)
 				return lastOperation, nil
 			}
 		}
@@ -94,6 +114,7 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID string, pollDetai
 			ctx = brokercontext.WithBoshTaskID(ctx, 0)
 			lastOperation := constructLastOperation(ctx, brokerapi.Failed, lastBoshTask, operationData, b.ExposeOperationalErrors)
 			logger.Printf("Failed to delete credhub secrets for service instance %s. Credhub error: %s\n", instanceID, err.Error())
+			b.updateOperationState(instanceID, operationData, lastBoshTask.ID, brokerapi.Failed, logger)
 			return lastOperation, nil
 		}
 	}
@@ -101,8 +122,15 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID string, pollDetai
 	ctx = brokercontext.WithBoshTaskID(ctx, lastBoshTask.ID)
 
 	taskState := lastOperationState(lastBoshTask, logger)
+	if taskState == brokerapi.Failed && operationData.OperationType == OperationTypeUpgrade && b.RollbackFailedUpgrades && deployed {
+		if lastOperation, rollingBack := b.rollBackUpgrade(ctx, instanceID, operationData, lastBoshTask, logger); rollingBack {
+			return lastOperation, nil
+		}
+	}
+
 	lastOperation := constructLastOperation(ctx, taskState, lastBoshTask, operationData, b.ExposeOperationalErrors)
 	logLastOperation(instanceID, lastBoshTask, operationData, logger)
+	b.updateOperationState(instanceID, operationData, lastBoshTask.ID, taskState, logger)
 
 	return lastOperation, nil
 }
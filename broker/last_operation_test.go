@@ -17,6 +17,8 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 var _ = Describe("LastOperation", func() {
@@ -1147,4 +1149,66 @@ var _ = Describe("LastOperation", func() {
 			)
 		})
 	})
+
+	Context("when the bosh task has finished", func() {
+		outcomes := func(operationType, state string) float64 {
+			return testutil.ToFloat64(metrics.BoshTaskOutcomes.WithLabelValues(operationType, state))
+		}
+
+		It("counts the outcome of the operation when it is first recorded", func() {
+			outcomesBefore := outcomes("recreate", "failed")
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskError}, nil)
+			fakeOperationStore.UpdateStateReturns(true, nil)
+
+			b = createDefaultBroker()
+			_, err := b.LastOperation(context.Background(), "some-instance-id", brokerapi.PollDetails{
+				OperationData: `{"BoshTaskID": 42, "OperationType": "recreate"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outcomes("recreate", "failed")).To(Equal(outcomesBefore + 1))
+		})
+
+		It("does not count the outcome again when the finished operation is polled again", func() {
+			outcomesBefore := outcomes("recreate", "failed")
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskError}, nil)
+			fakeOperationStore.UpdateStateReturns(false, nil)
+
+			b = createDefaultBroker()
+			_, err := b.LastOperation(context.Background(), "some-instance-id", brokerapi.PollDetails{
+				OperationData: `{"BoshTaskID": 42, "OperationType": "recreate"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outcomes("recreate", "failed")).To(Equal(outcomesBefore))
+		})
+
+		It("does not count the outcome when it cannot be recorded", func() {
+			outcomesBefore := outcomes("recreate", "failed")
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskError}, nil)
+			fakeOperationStore.UpdateStateReturns(false, errors.New("disk full"))
+
+			b = createDefaultBroker()
+			_, err := b.LastOperation(context.Background(), "some-instance-id", brokerapi.PollDetails{
+				OperationData: `{"BoshTaskID": 42, "OperationType": "recreate"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outcomes("recreate", "failed")).To(Equal(outcomesBefore))
+		})
+
+		It("does not count tasks that are still in progress", func() {
+			outcomesBefore := outcomes("recreate", "in progress")
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskProcessing}, nil)
+			fakeOperationStore.UpdateStateReturns(true, nil)
+
+			b = createDefaultBroker()
+			_, err := b.LastOperation(context.Background(), "some-instance-id", brokerapi.PollDetails{
+				OperationData: `{"BoshTaskID": 42, "OperationType": "recreate"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outcomes("recreate", "in progress")).To(Equal(outcomesBefore))
+		})
+	})
 })
@@ -38,6 +38,8 @@ func (l LifeCycleRunner) GetTask(deploymentName string, operationData OperationD
 		return l.processPostDeployment(deploymentName, operationData, logger)
 	case validPreDeleteOpType(operationData.OperationType):
 		return l.processPreDelete(deploymentName, operationData, logger)
+	case validBindingOpType(operationData.OperationType):
+		return l.processBindingErrands(deploymentName, operationData, logger)
 	default:
 		return l.boshClient.GetTask(operationData.BoshTaskID, logger)
 	}
@@ -53,6 +55,11 @@ func validPreDeleteOpType(op OperationType) bool {
 	return op == OperationTypeDelete
 }
 
+func validBindingOpType(op OperationType) bool {
+	return op == OperationTypeBind ||
+		op == OperationTypeUnbind
+}
+
 func (l LifeCycleRunner) processPostDeployment(
 	deploymentName string,
 	operationData OperationData,
@@ -78,14 +85,14 @@ func (l LifeCycleRunner) processPostDeployment(
 		return l.runErrand(deploymentName, operationData.PostDeployErrand.Name, operationData.PostDeployErrand.Instances, operationData.BoshContextID, logger)
 	}
 
-	nextErrandIndex := len(boshTasks) - 1
+	nextErrandIndex := len(boshTasks) - 1 - len(operationData.PreUpgradeErrands)
 	if nextErrandIndex < len(operationData.Errands) {
 		errand := operationData.Errands[nextErrandIndex].Name
 		instances := operationData.Errands[nextErrandIndex].Instances
 		return l.runErrand(deploymentName, errand, instances, operationData.BoshContextID, logger)
 	}
 
-	if len(operationData.Errands) == 0 && operationData.PostDeployErrand.Name == "" {
+	if len(operationData.Errands) == 0 && operationData.PostDeployErrand.Name == "" && len(operationData.PreUpgradeErrands) == 0 {
 		logger.Println("can't determine lifecycle errands, neither PlanID nor PostDeployErrand.Name is present")
 	}
 	return task, nil
@@ -127,6 +134,35 @@ func (l LifeCycleRunner) processPreDelete(
 	return l.runErrand(deploymentName, errand.Name, errand.Instances, operationData.BoshContextID, logger)
 }
 
+func (l LifeCycleRunner) processBindingErrands(
+	deploymentName string,
+	operationData OperationData,
+	logger *log.Logger,
+) (boshdirector.BoshTask, error) {
+	boshTasks, err := l.boshClient.GetNormalisedTasksByContext(deploymentName, operationData.BoshContextID, logger)
+	if err != nil {
+		return boshdirector.BoshTask{}, err
+	}
+
+	if len(boshTasks) == 0 {
+		return boshdirector.BoshTask{}, fmt.Errorf("no tasks found for context id: %s", operationData.BoshContextID)
+	}
+
+	task := boshTasks[0]
+	switch task.StateType() {
+	case boshdirector.TaskIncomplete:
+		return task, nil
+	case boshdirector.TaskComplete:
+		if len(boshTasks) < len(operationData.Errands) {
+			errand := operationData.Errands[len(boshTasks)]
+			return l.runErrand(deploymentName, errand.Name, errand.Instances, operationData.BoshContextID, logger)
+		}
+	}
+
+	countBindingTaskOutcome(operationData.OperationType, task)
+	return task, nil
+}
+
 func isOldStylePreDeleteOperationData(boshTasks boshdirector.BoshTasks, operationData OperationData) bool {
 	return len(boshTasks) == 1 && operationData.PreDeleteErrand.Name != ""
 }
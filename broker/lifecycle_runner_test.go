@@ -465,6 +465,59 @@ var _ = Describe("Lifecycle runner", func() {
 		})
 	})
 
+	DescribeTable("binding errands",
+		func(operationType broker.OperationType) {
+			operationData = broker.OperationData{
+				BoshTaskID:    taskProcessing.ID,
+				BoshContextID: contextID,
+				OperationType: operationType,
+				Errands:       []config.Errand{{Name: errand1, Instances: errandInstances}, {Name: errand2}},
+			}
+
+			By("returning the first errand task while it is running")
+			boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{taskProcessing}, nil)
+			task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task).To(Equal(taskProcessing))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(0))
+
+			By("running the next errand once the first one is complete")
+			boshClient.GetNormalisedTasksByContextReturnsOnCall(1, boshdirector.BoshTasks{taskComplete}, nil)
+			boshClient.RunErrandReturns(taskProcessing.ID, nil)
+			boshClient.GetTaskReturns(taskProcessing, nil)
+			task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task).To(Equal(taskProcessing))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+			actualDeploymentName, actualErrand, actualInstances, actualContextID, _, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualErrand).To(Equal(errand2))
+			Expect(actualInstances).To(BeEmpty())
+			Expect(actualContextID).To(Equal(contextID))
+
+			By("returning the last task once all errands have run")
+			boshClient.GetNormalisedTasksByContextReturnsOnCall(2, boshdirector.BoshTasks{taskComplete, taskComplete}, nil)
+			task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task).To(Equal(taskComplete))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+		},
+		Entry("bind", broker.OperationTypeBind),
+		Entry("unbind", broker.OperationTypeUnbind),
+	)
+
+	It("returns an error when there are no tasks for the binding errands", func() {
+		operationData = broker.OperationData{
+			BoshContextID: contextID,
+			OperationType: broker.OperationTypeBind,
+			Errands:       []config.Errand{{Name: errand1}},
+		}
+		boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{}, nil)
+
+		_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+		Expect(err).To(MatchError("no tasks found for context id: " + contextID))
+	})
+
 	When("BoshContextID is not set", func() {
 		It("returns the task using the task id", func() {
 			operationData = broker.OperationData{
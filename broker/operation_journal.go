@@ -0,0 +1,111 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+// The operation journal is an audit trail only: failing to write to it is
+// logged and never fails the request.
+
+func (b *Broker) recordOperation(ctx context.Context, instanceID, planID string, operationData OperationData, logger *log.Logger) {
+	b.recordOperationWithRollback(ctx, instanceID, planID, operationData, nil, logger)
+}
+
+// recordOperationWithRollback also saves the snapshot to redeploy if the
+// operation fails. Without it the operation cannot be rolled back.
+func (b *Broker) recordOperationWithRollback(ctx context.Context, instanceID, planID string, operationData OperationData, snapshot *operationstore.Snapshot, logger *log.Logger) {
+	var rollback *operationstore.Rollback
+	if snapshot != nil {
+		if err := b.operationStore.SaveSnapshot(instanceID, *snapshot); err != nil {
+			logger.Printf("failed to save rollback snapshot for instance %s, the upgrade cannot be rolled back: %s\n", instanceID, err)
+		} else {
+			rollback = &operationstore.Rollback{}
+		}
+	}
+
+	var errands []string
+	for _, errand := range operationData.Errands {
+		errands = append(errands, errand.Name)
+	}
+
+	now := time.Now()
+	err := b.operationStore.Record(operationstore.Operation{
+		RequestID:     brokercontext.GetReqID(ctx),
+		InstanceID:    instanceID,
+		Type:          string(operationData.OperationType),
+		PlanID:        planID,
+		BoshTaskIDs:   []int{operationData.BoshTaskID},
+		BoshContextID: operationData.BoshContextID,
+		Errands:       errands,
+		State:         string(brokerapi.InProgress),
+		StartedAt:     now,
+		UpdatedAt:     now,
+		Rollback:      rollback,
+	})
+	if err != nil {
+		logger.Printf("failed to record %s operation for instance %s in the operation store: %s\n", operationData.OperationType, instanceID, err)
+	}
+}
+
+// updateOperationState records the state of the operation in the operation
+// store. The outcome of the operation is counted when it is first recorded,
+// so that polling a finished operation again does not count it twice.
+func (b *Broker) updateOperationState(instanceID string, operationData OperationData, latestBoshTaskID int, state brokerapi.LastOperationState, logger *log.Logger) {
+	changed, err := b.operationStore.UpdateState(instanceID, operationData.BoshTaskID, latestBoshTaskID, string(state))
+	if err != nil {
+		logger.Printf("failed to update %s operation for instance %s in the operation store: %s\n", operationData.OperationType, instanceID, err)
+		return
+	}
+	if changed && state != brokerapi.InProgress {
+		metrics.BoshTaskOutcomes.WithLabelValues(string(operationData.OperationType), string(state)).Inc()
+	}
+}
+
+// Binding operations are not recorded in the operation store, so the final
+// tasks of binding errands whose outcome has been counted are remembered
+// instead. Only the most recent of them are kept.
+const countedBindingTasksLimit = 1000
+
+var countedBindingTasks = struct {
+	sync.Mutex
+	ids   map[int]bool
+	order []int
+}{ids: map[int]bool{}}
+
+// countBindingTaskOutcome counts the outcome of the task that ends a binding
+// operation, once however many times the operation is polled.
+func countBindingTaskOutcome(operationType OperationType, task boshdirector.BoshTask) {
+	countedBindingTasks.Lock()
+	defer countedBindingTasks.Unlock()
+
+	if countedBindingTasks.ids[task.ID] {
+		return
+	}
+	countedBindingTasks.ids[task.ID] = true
+	countedBindingTasks.order = append(countedBindingTasks.order, task.ID)
+	if len(countedBindingTasks.order) > countedBindingTasksLimit {
+		delete(countedBindingTasks.ids, countedBindingTasks.order[0])
+		countedBindingTasks.order = countedBindingTasks.order[1:]
+	}
+
+	state := brokerapi.Failed
+	if task.StateType() == boshdirector.TaskComplete {
+		state = brokerapi.Succeeded
+	}
+	metrics.BoshTaskOutcomes.WithLabelValues(string(operationType), string(state)).Inc()
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+)
+
+var _ = Describe("Operation journal", func() {
+	const (
+		instanceID       = "some-instance-id"
+		organizationGUID = "some-org-guid"
+		spaceGUID        = "some-space-guid"
+	)
+
+	Describe("recording operations", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+			fakeDeployer.CreateReturns(123, []byte("some-manifest"), nil)
+		})
+
+		It("records a provision when it has been started", func() {
+			b = createDefaultBroker()
+			ctx := brokercontext.WithReqID(context.Background(), "some-request-id")
+
+			_, err := b.Provision(ctx, instanceID, brokerapi.ProvisionDetails{
+				PlanID:           existingPlanID,
+				OrganizationGUID: organizationGUID,
+				SpaceGUID:        spaceGUID,
+				ServiceID:        serviceOfferingID,
+			}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeOperationStore.RecordCallCount()).To(Equal(1))
+			operation := fakeOperationStore.RecordArgsForCall(0)
+			Expect(operation.InstanceID).To(Equal(instanceID))
+			Expect(operation.Type).To(Equal("create"))
+			Expect(operation.PlanID).To(Equal(existingPlanID))
+			Expect(operation.BoshTaskIDs).To(Equal([]int{123}))
+			Expect(operation.State).To(Equal(string(brokerapi.InProgress)))
+			Expect(operation.StartedAt).NotTo(BeZero())
+		})
+
+		It("records the errands that will run after an upgrade", func() {
+			b = createDefaultBroker()
+			fakeDeployer.UpgradeReturns(876, []byte("some-manifest"), nil)
+
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: postDeployErrandPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeOperationStore.RecordCallCount()).To(Equal(1))
+			operation := fakeOperationStore.RecordArgsForCall(0)
+			Expect(operation.Type).To(Equal("upgrade"))
+			Expect(operation.BoshTaskIDs).To(Equal([]int{876}))
+			Expect(operation.BoshContextID).NotTo(BeEmpty())
+			Expect(operation.Errands).To(Equal([]string{"health-check"}))
+		})
+
+		It("does not record an operation that failed to start", func() {
+			b = createDefaultBroker()
+			fakeDeployer.CreateReturns(0, nil, errors.New("oops"))
+
+			_, err := b.Provision(context.Background(), instanceID, brokerapi.ProvisionDetails{
+				PlanID:           existingPlanID,
+				OrganizationGUID: organizationGUID,
+				SpaceGUID:        spaceGUID,
+				ServiceID:        serviceOfferingID,
+			}, true)
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeOperationStore.RecordCallCount()).To(Equal(0))
+		})
+
+		It("only logs when the operation cannot be recorded", func() {
+			b = createDefaultBroker()
+			fakeOperationStore.RecordReturns(errors.New("disk full"))
+
+			_, err := b.Provision(context.Background(), instanceID, brokerapi.ProvisionDetails{
+				PlanID:           existingPlanID,
+				OrganizationGUID: organizationGUID,
+				SpaceGUID:        spaceGUID,
+				ServiceID:        serviceOfferingID,
+			}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logBuffer.String()).To(ContainSubstring("failed to record create operation for instance " + instanceID + " in the operation store: disk full"))
+		})
+	})
+
+	Describe("updating operations from LastOperation", func() {
+		It("records the state of the latest bosh task", func() {
+			b = createDefaultBroker()
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskDone}, nil)
+
+			_, err := b.LastOperation(context.Background(), instanceID, brokerapi.PollDetails{
+				OperationData: `{"BoshTaskID": 42, "OperationType": "update"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeOperationStore.UpdateStateCallCount()).To(Equal(1))
+			actualInstanceID, actualTaskID, actualLatestTaskID, actualState := fakeOperationStore.UpdateStateArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualTaskID).To(Equal(42))
+			Expect(actualLatestTaskID).To(Equal(42))
+			Expect(actualState).To(Equal(string(brokerapi.Succeeded)))
+		})
+
+		It("only logs when the operation cannot be updated", func() {
+			b = createDefaultBroker()
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskProcessing}, nil)
+			fakeOperationStore.UpdateStateReturns(false, errors.New("disk full"))
+
+			lastOperation, err := b.LastOperation(context.Background(), instanceID, brokerapi.PollDetails{
+				OperationData: `{"BoshTaskID": 42, "OperationType": "update"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastOperation.State).To(Equal(brokerapi.InProgress))
+
+			Expect(logBuffer.String()).To(ContainSubstring("failed to update update operation for instance " + instanceID + " in the operation store: disk full"))
+		})
+	})
+})
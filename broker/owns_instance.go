@@ -0,0 +1,40 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"log"
+)
+
+// OwnsInstance reports whether the service instance belongs to the service
+// offering of the broker. The plan is read from the instance details in the
+// manifest when they are recorded, and looked up in the instances of the
+// offering otherwise.
+func (b *Broker) OwnsInstance(instanceID string, logger *log.Logger) (bool, error) {
+	manifest, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	if err != nil {
+		return false, fmt.Errorf("error getting deployment %s: %s", deploymentName(instanceID), err)
+	}
+	if found {
+		if details, found, err := InstanceDetailsFromManifest(manifest); err == nil && found {
+			_, owned := b.serviceOffering.FindPlanByID(details.PlanID)
+			return owned, nil
+		}
+	}
+
+	instances, err := b.instanceLister.Instances()
+	if err != nil {
+		return false, fmt.Errorf("error listing service instances: %s", err)
+	}
+	for _, instance := range instances {
+		if instance.GUID == instanceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
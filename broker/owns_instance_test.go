@@ -0,0 +1,70 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("OwnsInstance", func() {
+	BeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("owns the instance when the plan recorded in the manifest is one of the offering's", func() {
+		boshClient.GetDeploymentReturns([]byte("odb_instance_details:\n  plan_id: "+existingPlanID), true, nil)
+
+		owned, err := b.OwnsInstance("some-instance", loggerFactory.NewWithRequestID())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owned).To(BeTrue())
+		deploymentName, _ := boshClient.GetDeploymentArgsForCall(0)
+		Expect(deploymentName).To(Equal("service-instance_some-instance"))
+		Expect(fakeInstanceLister.InstancesCallCount()).To(Equal(0))
+	})
+
+	It("does not own the instance when the plan recorded in the manifest belongs to another offering", func() {
+		boshClient.GetDeploymentReturns([]byte("odb_instance_details:\n  plan_id: other-offering-plan"), true, nil)
+
+		owned, err := b.OwnsInstance("some-instance", loggerFactory.NewWithRequestID())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owned).To(BeFalse())
+	})
+
+	It("looks the instance up in the instances of the offering when the manifest does not record the plan", func() {
+		boshClient.GetDeploymentReturns([]byte("name: some-deployment"), true, nil)
+		fakeInstanceLister.InstancesReturns([]service.Instance{{GUID: "some-instance"}}, nil)
+
+		owned, err := b.OwnsInstance("some-instance", loggerFactory.NewWithRequestID())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owned).To(BeTrue())
+	})
+
+	It("does not own an instance that has no deployment and is not listed", func() {
+		boshClient.GetDeploymentReturns(nil, false, nil)
+		fakeInstanceLister.InstancesReturns([]service.Instance{{GUID: "other-instance"}}, nil)
+
+		owned, err := b.OwnsInstance("some-instance", loggerFactory.NewWithRequestID())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owned).To(BeFalse())
+	})
+
+	It("returns an error when the deployment cannot be retrieved", func() {
+		boshClient.GetDeploymentReturns(nil, false, errors.New("director unavailable"))
+
+		_, err := b.OwnsInstance("some-instance", loggerFactory.NewWithRequestID())
+
+		Expect(err).To(MatchError("error getting deployment service-instance_some-instance: director unavailable"))
+	})
+})
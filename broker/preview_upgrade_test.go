@@ -0,0 +1,122 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+)
+
+var _ = Describe("PreviewUpgrade", func() {
+	const instanceID = "some-instance"
+
+	var (
+		details brokerapi.UpdateDetails
+		logger  *log.Logger
+	)
+
+	BeforeEach(func() {
+		details = brokerapi.UpdateDetails{PlanID: existingPlanID}
+		logger = loggerFactory.NewWithRequestID()
+		b = createDefaultBroker()
+	})
+
+	It("returns the paths and types of the changes the upgrade would make without deploying", func() {
+		fakeDeployer.PreviewUpgradeReturns(broker.UpgradePreview{
+			ManifestChanges: []manifestdiff.Change{{Path: "/releases/name=redis/version", Type: manifestdiff.Changed, Old: "1", New: "2"}},
+		}, nil)
+
+		preview, err := b.PreviewUpgrade(context.Background(), instanceID, details, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview).To(Equal(broker.UpgradePreview{
+			ManifestChanges: []manifestdiff.Change{{Path: "/releases/name=redis/version", Type: manifestdiff.Changed}},
+		}))
+
+		Expect(fakeDeployer.PreviewUpgradeCallCount()).To(Equal(1))
+		_, actualDeploymentName, actualPlanID, actualPreviousPlanID, _ := fakeDeployer.PreviewUpgradeArgsForCall(0)
+		Expect(actualDeploymentName).To(Equal(broker.InstancePrefix + instanceID))
+		Expect(actualPlanID).To(Equal(existingPlanID))
+		Expect(*actualPreviousPlanID).To(Equal(existingPlanID))
+
+		Expect(fakeDeployer.UpgradeCallCount()).To(BeZero())
+		Expect(fakeOperationStore.RecordCallCount()).To(BeZero())
+	})
+
+	It("leaves out the values of manifest and config changes, as they may contain secrets", func() {
+		fakeDeployer.PreviewUpgradeReturns(broker.UpgradePreview{
+			ManifestChanges: []manifestdiff.Change{
+				{Path: "/instance_groups/name=redis/jobs/name=redis/properties/password", Type: manifestdiff.Changed, Old: "old-secret", New: "new-secret"},
+				{Path: "/variables/name=admin/options", Type: manifestdiff.Added, New: map[interface{}]interface{}{"ca": "some-ca"}},
+			},
+			ConfigChanges: map[string][]manifestdiff.Change{
+				"cloud": {{Path: "/vm_extensions/name=lb/cloud_properties/key", Type: manifestdiff.Removed, Old: "old-key"}},
+			},
+		}, nil)
+
+		preview, err := b.PreviewUpgrade(context.Background(), instanceID, details, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview).To(Equal(broker.UpgradePreview{
+			ManifestChanges: []manifestdiff.Change{
+				{Path: "/instance_groups/name=redis/jobs/name=redis/properties/password", Type: manifestdiff.Changed},
+				{Path: "/variables/name=admin/options", Type: manifestdiff.Added},
+			},
+			ConfigChanges: map[string][]manifestdiff.Change{
+				"cloud": {{Path: "/vm_extensions/name=lb/cloud_properties/key", Type: manifestdiff.Removed}},
+			},
+		}))
+	})
+
+	It("returns the values of the changes when expose_upgrade_preview_values is set", func() {
+		brokerConfig.ExposeUpgradePreviewValues = true
+		b = createDefaultBroker()
+		expectedPreview := broker.UpgradePreview{
+			ManifestChanges: []manifestdiff.Change{{Path: "/properties/password", Type: manifestdiff.Changed, Old: "old-secret", New: "new-secret"}},
+		}
+		fakeDeployer.PreviewUpgradeReturns(expectedPreview, nil)
+
+		preview, err := b.PreviewUpgrade(context.Background(), instanceID, details, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview).To(Equal(expectedPreview))
+	})
+
+	It("returns an error when no plan ID is provided", func() {
+		_, err := b.PreviewUpgrade(context.Background(), instanceID, brokerapi.UpdateDetails{}, logger)
+
+		Expect(err).To(MatchError(ContainSubstring("no plan ID provided in upgrade request body")))
+		Expect(fakeDeployer.PreviewUpgradeCallCount()).To(BeZero())
+	})
+
+	It("returns an error when the plan cannot be found", func() {
+		_, err := b.PreviewUpgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: "not-a-plan"}, logger)
+
+		Expect(err).To(MatchError(ContainSubstring("plan not-a-plan not found")))
+		Expect(fakeDeployer.PreviewUpgradeCallCount()).To(BeZero())
+	})
+
+	It("returns the adapter's message when the adapter fails", func() {
+		adapterErr := serviceadapter.NewUnknownFailureError("error for cf user")
+		fakeDeployer.PreviewUpgradeReturns(broker.UpgradePreview{}, adapterErr)
+
+		_, err := b.PreviewUpgrade(context.Background(), instanceID, details, logger)
+		Expect(err).To(Equal(adapterErr))
+	})
+
+	It("returns a deployment not found error when the deployment does not exist", func() {
+		fakeDeployer.PreviewUpgradeReturns(broker.UpgradePreview{}, broker.NewDeploymentNotFoundError(errors.New("not found")))
+
+		_, err := b.PreviewUpgrade(context.Background(), instanceID, details, logger)
+		Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+	})
+})
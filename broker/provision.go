@@ -83,6 +83,8 @@ func (b *Broker) Provision(ctx context.Context, instanceID string, details broke
 		return brokerapi.ProvisionedServiceSpec{}, b.processError(err, logger)
 	}
 
+	b.recordOperation(ctx, instanceID, details.PlanID, operationData, logger)
+
 	operationDataJSON, err := json.Marshal(operationData)
 	if err != nil {
 		return brokerapi.ProvisionedServiceSpec{}, b.processError(err, logger)
@@ -125,12 +127,14 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 		))
 	}
 
-	cfPlanCounts, err := b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+	orgGUID, _ := requestParams["organization_guid"].(string)
+	spaceGUID, _ := requestParams["space_guid"].(string)
+	planCounts, pendingCount, err := b.instanceCountsForQuotas(instanceID, plan, orgGUID, spaceGUID, logger)
 	if err != nil {
 		return errs(NewGenericError(ctx, err))
 	}
 
-	quotasErrors, ok := b.checkQuotas(ctx, plan, cfPlanCounts, b.serviceOffering.ID, logger)
+	quotasErrors, ok := b.checkQuotas(ctx, plan, planCounts, pendingCount, b.serviceOffering.ID, orgGUID, spaceGUID, logger)
 	if !ok {
 		return errs(quotasErrors)
 	}
@@ -145,13 +149,13 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 		boshContextID = uuid.New()
 	}
 
-	boshTaskID, manifest, err := b.deployer.Create(deploymentName(instanceID), plan.ID, requestParams, boshContextID, logger)
+	boshTaskID, manifest, err := b.deployer.Create(ctx, deploymentName(instanceID), plan.ID, requestParams, boshContextID, logger)
 	switch err := err.(type) {
 	case boshdirector.RequestError:
 		return errs(NewBoshRequestError("create", err))
 	case DisplayableError:
 		return errs(err)
-	case serviceadapter.UnknownFailureError:
+	case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError, serviceadapter.CanceledError:
 		return errs(adapterToAPIError(ctx, err))
 	case error:
 		return errs(NewGenericError(ctx, err))
@@ -161,7 +165,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 
 	abridgedPlan := plan.AdapterPlan(b.serviceOffering.GlobalProperties)
 
-	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(instanceID, abridgedPlan, manifest, logger)
+	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(ctx, instanceID, abridgedPlan, manifest, logger)
 	if err != nil {
 		logger.Printf("generating dashboard: %v\n", err)
 	}
@@ -188,7 +192,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 func (b *Broker) checkPlanSchemas(ctx context.Context, requestParams map[string]interface{}, plan config.Plan, logger *log.Logger) error {
 	if b.EnablePlanSchemas {
 		var schemas brokerapi.ServiceSchemas
-		schemas, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
 				return err
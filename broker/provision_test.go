@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 
@@ -24,6 +25,7 @@ import (
 	brokerfakes "github.com/pivotal-cf/on-demand-service-broker/broker/fakes"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/noopservicescontroller"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
@@ -109,7 +111,7 @@ var _ = Describe("Provisioning", func() {
 
 		It("invokes the deployer", func() {
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
-			actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
+			_, actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualRequestParams).To(Equal(map[string]interface{}{
 				"plan_id":           planID,
 				"context":           arbContext,
@@ -140,7 +142,7 @@ var _ = Describe("Provisioning", func() {
 
 		It("invokes the adapter for the dashboard url, merging global and plan properties", func() {
 			Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
-			instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+			_, instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
 			Expect(instanceID).To(Equal(instanceID))
 			expectedProperties := sdk.Properties{"super": "no", "a_global_property": "global_value", "some_other_global_property": "other_global_value"}
 			Expect(plan).To(Equal(sdk.Plan{
@@ -302,7 +304,7 @@ var _ = Describe("Provisioning", func() {
 
 		It("calls the deployer with a bosh context id", func() {
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
-			_, _, _, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
+			_, _, _, _, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualBoshContextID).NotTo(BeEmpty())
 		})
 
@@ -332,10 +334,10 @@ var _ = Describe("Provisioning", func() {
 
 			It("calls the deployer with a different bosh context id", func() {
 				Expect(fakeDeployer.CreateCallCount()).To(Equal(2))
-				_, _, _, firstBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
+				_, _, _, _, firstBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
 				Expect(firstBoshContextID).NotTo(BeNil())
 
-				_, _, _, secondBoshContextID, _ := fakeDeployer.CreateArgsForCall(1)
+				_, _, _, _, secondBoshContextID, _ := fakeDeployer.CreateArgsForCall(1)
 				Expect(secondBoshContextID).NotTo(Equal(firstBoshContextID))
 			})
 		})
@@ -404,7 +406,7 @@ var _ = Describe("Provisioning", func() {
 		})
 
 		It("no arbitrary params are passed to the adapter", func() {
-			_, _, actualRequestParams, _, _ := fakeDeployer.CreateArgsForCall(0)
+			_, _, _, actualRequestParams, _, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualRequestParams["parameters"]).To(HaveLen(0))
 		})
 
@@ -533,6 +535,34 @@ var _ = Describe("Provisioning", func() {
 		})
 	})
 
+	Context("when the deploy returns an adapter canceled error", func() {
+		BeforeEach(func() {
+			fakeDeployer.CreateReturns(0, nil, serviceadapter.NewCanceledError("adapter request cancelled"))
+		})
+
+		It("returns an error telling the user the request was cancelled", func() {
+			Expect(provisionErr).To(MatchError(broker.RequestCanceledMessage))
+		})
+
+		It("logs the error", func() {
+			Expect(logBuffer.String()).To(ContainSubstring("adapter request cancelled"))
+		})
+	})
+
+	Context("when the deploy returns an adapter timeout error", func() {
+		BeforeEach(func() {
+			fakeDeployer.CreateReturns(0, nil, serviceadapter.NewTimeoutError("adapter timed out"))
+		})
+
+		It("returns an error telling the user to try again later", func() {
+			Expect(provisionErr).To(MatchError(broker.AdapterTimeoutErrorMessage))
+		})
+
+		It("logs the error", func() {
+			Expect(logBuffer.String()).To(ContainSubstring("adapter timed out"))
+		})
+	})
+
 	Context("when a provision of an already provisioned instance is triggered", func() {
 		BeforeEach(func() {
 			boshClient.GetDeploymentReturns([]byte(`manifest: true`), true, nil)
@@ -950,6 +980,53 @@ var _ = Describe("Provisioning", func() {
 				Expect(provisionErr.Error()).To(ContainSubstring("global quotas [ips: (limit 5, used 5, requires 1)] would be exceeded by this deployment"))
 			})
 
+			Context("when instances of several plans with different costs exist", func() {
+				deployWithMixedPlans := func(existingPlanInstances, secondPlanInstances int) error {
+					cfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{
+						cfServicePlan("1234", existingPlanID, "url", "name"): existingPlanInstances,
+						cfServicePlan("5678", secondPlanID, "url", "name"):   secondPlanInstances,
+					}, nil)
+
+					cheapPlan := existingPlan
+					cheapPlan.Quotas = config.Quotas{}
+					cheapPlan.ResourceCosts = map[string]int{"ips": 1}
+					expensivePlan := secondPlan
+					expensivePlan.ResourceCosts = map[string]int{"ips": 4}
+
+					catalogWithResourceQuotas := serviceCatalog
+					catalogWithResourceQuotas.Plans = config.Plans{cheapPlan, expensivePlan}
+					catalogWithResourceQuotas.GlobalQuotas = config.Quotas{ResourceLimits: map[string]int{"ips": 10}}
+					b = createBrokerWithServiceCatalog(catalogWithResourceQuotas)
+
+					_, err := b.Provision(
+						context.Background(),
+						instanceID,
+						brokerapi.ProvisionDetails{
+							PlanID:           existingPlanID,
+							RawContext:       jsonContext,
+							RawParameters:    jsonParams,
+							OrganizationGUID: organizationGUID,
+							SpaceGUID:        spaceGUID,
+							ServiceID:        serviceOfferingID,
+						},
+						asyncAllowed,
+					)
+					return err
+				}
+
+				It("counts the instances of each plan at that plan's cost against the global resource limit", func() {
+					provisionErr = deployWithMixedPlans(1, 3)
+
+					Expect(provisionErr).To(MatchError(ContainSubstring("global quotas [ips: (limit 10, used 13, requires 1)] would be exceeded by this deployment")))
+				})
+
+				It("does not count the instances of the requested plan at the cost of other plans", func() {
+					provisionErr = deployWithMixedPlans(3, 0)
+
+					Expect(provisionErr).NotTo(HaveOccurred())
+				})
+			})
+
 			It("succeeds when plan resource quota is set and has been reached but there is no instance count limit", func() {
 				planResourceLimits := map[string]int{"ips": 5} // plan costs 1 IP per instance
 				provisionErr = deployWithQuotas(
@@ -961,6 +1038,147 @@ var _ = Describe("Provisioning", func() {
 			})
 		})
 
+		Context("pending provisions", func() {
+			var planInstanceLimit int
+
+			BeforeEach(func() {
+				planInstanceLimit = 2
+			})
+
+			It("counts deployments that are in flight but unknown to CF", func() {
+				boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+					{ID: 5, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_pending-instance"},
+				}, nil)
+
+				provisionErr = deployWithQuotas(quotaCase{nil, nil, nil, &planInstanceLimit}, existingPlanID, 1)
+
+				Expect(provisionErr).To(MatchError(ContainSubstring("plan instance limit exceeded for service ID: service-id. Total instances: 2")))
+				Expect(cfClient.GetInstancesOfServiceOfferingCallCount()).To(Equal(1))
+				Expect(logBuffer.String()).To(ContainSubstring("counting 1 pending service instances against the quotas of plan " + existingPlanID))
+			})
+
+			It("lists the in-flight bosh tasks once, and the CF instances only when a service instance is being deployed", func() {
+				tasksCallsBefore := boshClient.GetCurrentTasksCallCount()
+				instancesCallsBefore := cfClient.GetInstancesOfServiceOfferingCallCount()
+				provisionErr = deployWithQuotas(quotaCase{nil, nil, nil, &planInstanceLimit}, existingPlanID, 0)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+				Expect(boshClient.GetCurrentTasksCallCount() - tasksCallsBefore).To(Equal(1))
+				Expect(cfClient.GetInstancesOfServiceOfferingCallCount() - instancesCallsBefore).To(Equal(0))
+
+				boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+					{ID: 5, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_pending-instance"},
+					{ID: 6, State: boshdirector.TaskQueued, Description: "create deployment", DeploymentName: "service-instance_another-pending-instance"},
+				}, nil)
+				provisionErr = deployWithQuotas(quotaCase{nil, nil, nil, &planInstanceLimit}, existingPlanID, 0)
+
+				Expect(provisionErr).To(MatchError(ContainSubstring("plan instance limit exceeded for service ID: service-id. Total instances: 2")))
+				Expect(boshClient.GetCurrentTasksCallCount() - tasksCallsBefore).To(Equal(2))
+				Expect(cfClient.GetInstancesOfServiceOfferingCallCount() - instancesCallsBefore).To(Equal(1))
+			})
+
+			It("does not look for pending deployments when no quota applies", func() {
+				catalog := serviceCatalog
+				catalog.GlobalQuotas = config.Quotas{}
+				plan := existingPlan
+				plan.Quotas = config.Quotas{}
+				catalog.Plans = config.Plans{plan, secondPlan}
+				cfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{}, nil)
+				b = createBrokerWithServiceCatalog(catalog)
+				tasksCallsBefore := boshClient.GetCurrentTasksCallCount()
+
+				_, provisionErr = b.Provision(
+					context.Background(),
+					instanceID,
+					brokerapi.ProvisionDetails{
+						PlanID:           existingPlanID,
+						OrganizationGUID: organizationGUID,
+						SpaceGUID:        spaceGUID,
+						ServiceID:        serviceOfferingID,
+					},
+					asyncAllowed,
+				)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+				Expect(boshClient.GetCurrentTasksCallCount()).To(Equal(tasksCallsBefore))
+			})
+
+			It("does not count deployments of instances that CF already knows about", func() {
+				boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+					{ID: 5, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_known-instance"},
+				}, nil)
+				cfClient.GetInstancesOfServiceOfferingReturns([]service.Instance{{GUID: "known-instance", PlanUniqueID: existingPlanID}}, nil)
+
+				provisionErr = deployWithQuotas(quotaCase{nil, nil, nil, &planInstanceLimit}, existingPlanID, 1)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+			})
+
+			It("does not count deployments of instances that CF knows under the other offerings of the broker", func() {
+				boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+					{ID: 5, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_other-offering-instance"},
+					{ID: 6, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_pending-instance"},
+				}, nil)
+				cfClient.GetInstancesOfServiceOfferingStub = func(offeringID string, _ *log.Logger) ([]service.Instance, error) {
+					if offeringID == "other-service-id" {
+						return []service.Instance{{GUID: "other-offering-instance", PlanUniqueID: "other-plan-id"}}, nil
+					}
+					return nil, nil
+				}
+				catalog := serviceCatalog
+				plan := existingPlan
+				plan.Quotas = config.Quotas{ServiceInstanceLimit: &planInstanceLimit}
+				catalog.Plans = config.Plans{plan, secondPlan}
+				cfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{
+					cfServicePlan("1234", existingPlanID, "url", "name"): 0,
+				}, nil)
+				b = createBrokerWithServiceCatalog(catalog)
+				b.OtherServiceOfferingIDs = []string{"other-service-id"}
+				instancesCallsBefore := cfClient.GetInstancesOfServiceOfferingCallCount()
+
+				_, provisionErr = b.Provision(
+					context.Background(),
+					instanceID,
+					brokerapi.ProvisionDetails{
+						PlanID:           existingPlanID,
+						OrganizationGUID: organizationGUID,
+						SpaceGUID:        spaceGUID,
+						ServiceID:        serviceOfferingID,
+					},
+					asyncAllowed,
+				)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+				Expect(logBuffer.String()).To(ContainSubstring("counting 1 pending service instances against the quotas of plan " + existingPlanID))
+				Expect(cfClient.GetInstancesOfServiceOfferingCallCount() - instancesCallsBefore).To(Equal(2))
+				offeringID, _ := cfClient.GetInstancesOfServiceOfferingArgsForCall(instancesCallsBefore)
+				Expect(offeringID).To(Equal(serviceOfferingID))
+				offeringID, _ = cfClient.GetInstancesOfServiceOfferingArgsForCall(instancesCallsBefore + 1)
+				Expect(offeringID).To(Equal("other-service-id"))
+			})
+
+			It("ignores deletions and deployments that are not service instances", func() {
+				boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+					{ID: 5, State: boshdirector.TaskProcessing, Description: "delete deployment service-instance_deleted-instance", DeploymentName: "service-instance_deleted-instance"},
+					{ID: 6, State: boshdirector.TaskQueued, Description: "create deployment", DeploymentName: "cf"},
+				}, nil)
+
+				provisionErr = deployWithQuotas(quotaCase{nil, nil, nil, &planInstanceLimit}, existingPlanID, 1)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+				Expect(cfClient.GetInstancesOfServiceOfferingCallCount()).To(Equal(0))
+			})
+
+			It("fails when the current bosh tasks cannot be retrieved", func() {
+				boshClient.GetCurrentTasksReturns(nil, errors.New("director unreachable"))
+
+				provisionErr = deployWithQuotas(quotaCase{nil, nil, nil, &planInstanceLimit}, existingPlanID, 1)
+
+				Expect(provisionErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+				Expect(fakeDeployer.CreateCallCount()).To(Equal(0))
+			})
+		})
+
 		Describe("when all quotas are reached simultaneously", func() {
 			var deployErr error
 			BeforeEach(func() {
@@ -1003,6 +1221,127 @@ var _ = Describe("Provisioning", func() {
 		})
 	})
 
+	Describe("org and space quotas", func() {
+		var provisionErr error
+
+		provisionInOrgAndSpace := func(catalog config.ServiceOffering) error {
+			fakeDeployer = new(brokerfakes.FakeDeployer)
+			boshClient.GetDeploymentReturns(nil, false, nil)
+			b = createBrokerWithServiceCatalog(catalog)
+
+			_, err := b.Provision(
+				context.Background(),
+				instanceID,
+				brokerapi.ProvisionDetails{
+					PlanID:           existingPlanID,
+					OrganizationGUID: organizationGUID,
+					SpaceGUID:        spaceGUID,
+					ServiceID:        serviceOfferingID,
+				},
+				true,
+			)
+			return err
+		}
+
+		It("fails when the org instance limit is reached", func() {
+			orgInstanceLimit := 2
+			catalog := serviceCatalog
+			catalog.OrgQuotas = map[string]config.Quotas{
+				organizationGUID: {ServiceInstanceLimit: &orgInstanceLimit},
+			}
+			cfClient.CountInstancesOfServiceOfferingInOrgReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", existingPlanID, "url", "name"): 1,
+				cfServicePlan("5678", secondPlanID, "url", "name"):   1,
+			}, nil)
+
+			provisionErr = provisionInOrgAndSpace(catalog)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("org instance limit exceeded for org: a-cf-org. Total instances: 2")))
+			Expect(cfClient.CountInstancesOfServiceOfferingInOrgCallCount()).To(Equal(1))
+			actualServiceOfferingID, actualOrgGUID, _ := cfClient.CountInstancesOfServiceOfferingInOrgArgsForCall(0)
+			Expect(actualServiceOfferingID).To(Equal(serviceOfferingID))
+			Expect(actualOrgGUID).To(Equal(organizationGUID))
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(0))
+		})
+
+		It("fails when a space resource limit would be exceeded", func() {
+			catalog := serviceCatalog
+			plan := existingPlan
+			plan.ResourceCosts = map[string]int{"ips": 2}
+			catalog.Plans = config.Plans{plan, secondPlan}
+			catalog.SpaceQuotas = map[string]config.Quotas{
+				spaceGUID: {ResourceLimits: map[string]int{"ips": 5}},
+			}
+			cfClient.CountInstancesOfServiceOfferingInSpaceReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", existingPlanID, "url", "name"): 2,
+			}, nil)
+
+			provisionErr = provisionInOrgAndSpace(catalog)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("space quotas [ips: (limit 5, used 4, requires 2)] would be exceeded by this deployment")))
+			_, actualSpaceGUID, _ := cfClient.CountInstancesOfServiceOfferingInSpaceArgsForCall(0)
+			Expect(actualSpaceGUID).To(Equal(spaceGUID))
+		})
+
+		It("counts deployments that are in flight but unknown to CF against the org and space quotas", func() {
+			limit := 2
+			catalog := serviceCatalog
+			catalog.OrgQuotas = map[string]config.Quotas{organizationGUID: {ServiceInstanceLimit: &limit}}
+			catalog.SpaceQuotas = map[string]config.Quotas{spaceGUID: {ServiceInstanceLimit: &limit}}
+			cfClient.CountInstancesOfServiceOfferingInOrgReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", existingPlanID, "url", "name"): 1,
+			}, nil)
+			boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+				{ID: 5, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_pending-instance"},
+			}, nil)
+
+			provisionErr = provisionInOrgAndSpace(catalog)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("org instance limit exceeded for org: a-cf-org. Total instances: 2")))
+			Expect(provisionErr).NotTo(MatchError(ContainSubstring("space instance limit exceeded")))
+		})
+
+		It("succeeds when the org and space quotas are not reached", func() {
+			limit := 3
+			catalog := serviceCatalog
+			catalog.OrgQuotas = map[string]config.Quotas{organizationGUID: {ServiceInstanceLimit: &limit}}
+			catalog.SpaceQuotas = map[string]config.Quotas{spaceGUID: {ServiceInstanceLimit: &limit}}
+			cfClient.CountInstancesOfServiceOfferingInOrgReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", existingPlanID, "url", "name"): 2,
+			}, nil)
+
+			provisionErr = provisionInOrgAndSpace(catalog)
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(cfClient.CountInstancesOfServiceOfferingInSpaceCallCount()).To(Equal(1))
+		})
+
+		It("does not count instances when there are no quotas for the org or space", func() {
+			limit := 1
+			catalog := serviceCatalog
+			catalog.OrgQuotas = map[string]config.Quotas{"another-org": {ServiceInstanceLimit: &limit}}
+
+			provisionErr = provisionInOrgAndSpace(catalog)
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(cfClient.CountInstancesOfServiceOfferingInOrgCallCount()).To(Equal(0))
+			Expect(cfClient.CountInstancesOfServiceOfferingInSpaceCallCount()).To(Equal(0))
+		})
+
+		It("fails with a generic error when the org instances cannot be counted", func() {
+			limit := 1
+			catalog := serviceCatalog
+			catalog.OrgQuotas = map[string]config.Quotas{organizationGUID: {ServiceInstanceLimit: &limit}}
+			cfClient.CountInstancesOfServiceOfferingInOrgReturns(nil, errors.New("cf unreachable"))
+
+			provisionErr = provisionInOrgAndSpace(catalog)
+
+			Expect(provisionErr).To(HaveOccurred())
+			Expect(provisionErr.Error()).To(ContainSubstring("There was a problem completing your request"))
+			Expect(logBuffer.String()).To(ContainSubstring("cf unreachable"))
+		})
+	})
+
 	Context("when maintenance info is passed", func() {
 		BeforeEach(func() {
 			requestMaintenanceInfo = brokerapi.MaintenanceInfo{
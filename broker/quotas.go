@@ -11,11 +11,9 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 )
 
-func (b *Broker) checkQuotas(ctx context.Context, plan config.Plan, cfPlanCounts map[cf.ServicePlan]int, serviceOffering string, logger *log.Logger) (error, bool) {
+func (b *Broker) checkQuotas(ctx context.Context, plan config.Plan, planCounts map[string]int, pendingCount int, serviceOffering, orgGUID, spaceGUID string, logger *log.Logger) (error, bool) {
 	var quotasErrors []error
 
-	planCounts := convertCfPlanCounts(cfPlanCounts)
-
 	if instanceLimit := plan.Quotas.ServiceInstanceLimit; instanceLimit != nil {
 		if err := checkPlanServiceCount(plan, planCounts, *instanceLimit, serviceOffering); err != nil {
 			quotasErrors = append(quotasErrors, err)
@@ -29,17 +27,35 @@ func (b *Broker) checkQuotas(ctx context.Context, plan config.Plan, cfPlanCounts
 	}
 
 	if globalResourceLimits := b.serviceOffering.GlobalQuotas.ResourceLimits; globalResourceLimits != nil {
-		if err := checkGlobalResourceQuotaNotExceeded(plan, b.serviceOffering.Plans, planCounts, globalResourceLimits); err != nil {
+		usage := GlobalResourceUsage(b.serviceOffering.Plans, planCounts)
+		if err := checkResourceQuotaNotExceeded("global", plan, usage, globalResourceLimits); err != nil {
 			quotasErrors = append(quotasErrors, err)
 		}
 	}
 
 	if planResourceLimits := plan.Quotas.ResourceLimits; planResourceLimits != nil {
-		if err := checkPlanResourceQuotaNotExceeded(plan, planCounts, plan.Quotas.ResourceLimits); err != nil {
+		usage := PlanResourceUsage(plan, planCounts[plan.ID])
+		if err := checkResourceQuotaNotExceeded("plan", plan, usage, planResourceLimits); err != nil {
 			quotasErrors = append(quotasErrors, err)
 		}
 	}
 
+	if quotas, found := b.serviceOffering.OrgQuotas[orgGUID]; found && orgGUID != "" {
+		orgPlanCounts, err := b.cfClient.CountInstancesOfServiceOfferingInOrg(b.serviceOffering.ID, orgGUID, logger)
+		if err != nil {
+			return NewGenericError(ctx, err), false
+		}
+		quotasErrors = append(quotasErrors, b.checkScopedQuotas("org", orgGUID, plan, withPending(convertCfPlanCounts(orgPlanCounts), plan, pendingCount), quotas)...)
+	}
+
+	if quotas, found := b.serviceOffering.SpaceQuotas[spaceGUID]; found && spaceGUID != "" {
+		spacePlanCounts, err := b.cfClient.CountInstancesOfServiceOfferingInSpace(b.serviceOffering.ID, spaceGUID, logger)
+		if err != nil {
+			return NewGenericError(ctx, err), false
+		}
+		quotasErrors = append(quotasErrors, b.checkScopedQuotas("space", spaceGUID, plan, withPending(convertCfPlanCounts(spacePlanCounts), plan, pendingCount), quotas)...)
+	}
+
 	if len(quotasErrors) > 0 {
 		errorStrings := []string{}
 		for _, e := range quotasErrors {
@@ -51,6 +67,113 @@ func (b *Broker) checkQuotas(ctx context.Context, plan config.Plan, cfPlanCounts
 	return nil, true
 }
 
+// instanceCountsForQuotas counts the instances of each plan of the service
+// offering, and the instances that are still being provisioned by another
+// broker and that CF does not list yet. The plan, org and space of those
+// pending instances are not known, so they are counted against the plan,
+// org and space being requested: a concurrent provision may then be refused
+// when it would not have exceeded a quota.
+//
+// Counting pending instances only narrows the window in which concurrent
+// provisions can exceed a quota. Two brokers that check the quotas before
+// either of them has started its BOSH task can still both admit their
+// provision.
+//
+// Pending instances are only looked for when a quota applies to the request.
+// Each lookup lists the director's in-flight tasks, and lists the instances
+// of the service offering in CF whenever one of those tasks deploys a
+// service instance.
+func (b *Broker) instanceCountsForQuotas(instanceID string, plan config.Plan, orgGUID, spaceGUID string, logger *log.Logger) (map[string]int, int, error) {
+	cfPlanCounts, err := b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+	if err != nil {
+		return nil, 0, err
+	}
+	planCounts := convertCfPlanCounts(cfPlanCounts)
+
+	if !b.quotasApply(plan, orgGUID, spaceGUID) {
+		return planCounts, 0, nil
+	}
+
+	pendingCount, err := b.countPendingInstances(instanceID, logger)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pendingCount > 0 {
+		logger.Printf("counting %d pending service instances against the quotas of plan %s\n", pendingCount, plan.ID)
+	}
+
+	return withPending(planCounts, plan, pendingCount), pendingCount, nil
+}
+
+// quotasApply reports whether any quota applies to an instance of plan in
+// the given org and space.
+func (b *Broker) quotasApply(plan config.Plan, orgGUID, spaceGUID string) bool {
+	if plan.Quotas.ServiceInstanceLimit != nil || plan.Quotas.ResourceLimits != nil {
+		return true
+	}
+	globalQuotas := b.serviceOffering.GlobalQuotas
+	if globalQuotas.ServiceInstanceLimit != nil || globalQuotas.ResourceLimits != nil {
+		return true
+	}
+	if _, found := b.serviceOffering.OrgQuotas[orgGUID]; found && orgGUID != "" {
+		return true
+	}
+	if _, found := b.serviceOffering.SpaceQuotas[spaceGUID]; found && spaceGUID != "" {
+		return true
+	}
+	return false
+}
+
+// withPending adds the pending instances to the instance count of plan.
+func withPending(planCounts map[string]int, plan config.Plan, pendingCount int) map[string]int {
+	if pendingCount > 0 {
+		planCounts[plan.ID] += pendingCount
+	}
+	return planCounts
+}
+
+// countPendingInstances counts the service instance deployments that have a
+// task in flight but that CF does not know about, other than the one being
+// requested.
+// CF only records a service instance once the broker has accepted the
+// provision, so these are provisions that other brokers are submitting.
+// Deployments of every offering share the same name prefix, so instances
+// that CF knows under any of the offerings this broker serves are left out.
+func (b *Broker) countPendingInstances(requestedInstanceID string, logger *log.Logger) (int, error) {
+	tasks, err := b.boshClient.GetCurrentTasks(logger)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := map[string]bool{}
+	for _, task := range tasks {
+		if !strings.HasPrefix(task.DeploymentName, InstancePrefix) || strings.HasPrefix(task.Description, "delete deployment") {
+			continue
+		}
+		if id := instanceID(task.DeploymentName); id != requestedInstanceID {
+			pending[id] = true
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	for _, offeringID := range append([]string{b.serviceOffering.ID}, b.OtherServiceOfferingIDs...) {
+		instances, err := b.cfClient.GetInstancesOfServiceOffering(offeringID, logger)
+		if err != nil {
+			return 0, err
+		}
+		for _, instance := range instances {
+			delete(pending, instance.GUID)
+		}
+		if len(pending) == 0 {
+			return 0, nil
+		}
+	}
+
+	return len(pending), nil
+}
+
 func convertCfPlanCounts(cfPlanCounts map[cf.ServicePlan]int) map[string]int {
 	var brokerPlanCounts = make(map[string]int)
 
@@ -83,58 +206,68 @@ func checkGlobalServiceCount(planCounts map[string]int, instanceLimit int, servi
 	return nil
 }
 
-type exceededQuota struct {
-	name     string
-	limit    int
-	usage    int
-	required int
-}
-
-func checkGlobalResourceQuotaNotExceeded(plan config.Plan, plans []config.Plan, planCounts map[string]int, globalResourceLimits map[string]int) error {
-	var exceededQuotas []exceededQuota
-
-	for kind, limit := range globalResourceLimits {
-		var currentUsage int
+// checkScopedQuotas checks the quotas of a single org or space, given the
+// instance counts of the service offering within that org or space.
+func (b *Broker) checkScopedQuotas(scope, guid string, plan config.Plan, planCounts map[string]int, quotas config.Quotas) []error {
+	var quotasErrors []error
 
-		for _, p := range plans {
-			instanceCount := planCounts[plan.ID]
-			cost, ok := p.ResourceCosts[kind]
-			if ok {
-				currentUsage += cost * instanceCount
-			}
+	if instanceLimit := quotas.ServiceInstanceLimit; instanceLimit != nil {
+		totalServiceInstances := 0
+		for _, count := range planCounts {
+			totalServiceInstances += count
 		}
-		required := plan.ResourceCosts[kind]
-		if (currentUsage + required) > limit {
-			exceededQuotas = append(exceededQuotas, exceededQuota{kind, limit, currentUsage, required})
+		if totalServiceInstances >= *instanceLimit {
+			quotasErrors = append(quotasErrors, fmt.Errorf("%s instance limit exceeded for %s: %s. Total instances: %d", scope, scope, guid, totalServiceInstances))
 		}
 	}
 
-	if exceededQuotas == nil {
-		return nil
+	if resourceLimits := quotas.ResourceLimits; resourceLimits != nil {
+		usage := GlobalResourceUsage(b.serviceOffering.Plans, planCounts)
+		if err := checkResourceQuotaNotExceeded(scope, plan, usage, resourceLimits); err != nil {
+			quotasErrors = append(quotasErrors, err)
+		}
 	}
 
-	errorDetails := []string{}
-	for _, q := range exceededQuotas {
-		errorDetails = append(errorDetails, fmt.Sprintf("%s: (limit %d, used %d, requires %d)", q.name, q.limit, q.usage, q.required))
-	}
+	return quotasErrors
+}
 
-	return fmt.Errorf("global quotas [%s] would be exceeded by this deployment", strings.Join(errorDetails, ", "))
+// PlanResourceUsage returns the amount of each kind of resource consumed by
+// instanceCount instances of plan, according to the plan's resource costs.
+func PlanResourceUsage(plan config.Plan, instanceCount int) map[string]int {
+	usage := map[string]int{}
+	for kind, cost := range plan.ResourceCosts {
+		usage[kind] = cost * instanceCount
+	}
+	return usage
 }
 
-func checkPlanResourceQuotaNotExceeded(plan config.Plan, planCounts map[string]int, planResourceLimits map[string]int) error {
-	var exceededQuotas []exceededQuota
+// GlobalResourceUsage returns the amount of each kind of resource consumed by
+// the instances of all plans, keyed by plan ID in planCounts.
+func GlobalResourceUsage(plans []config.Plan, planCounts map[string]int) map[string]int {
+	usage := map[string]int{}
+	for _, plan := range plans {
+		for kind, used := range PlanResourceUsage(plan, planCounts[plan.ID]) {
+			usage[kind] += used
+		}
+	}
+	return usage
+}
 
-	for kind, limit := range planResourceLimits {
-		var currentUsage int
+type exceededQuota struct {
+	name     string
+	limit    int
+	usage    int
+	required int
+}
 
-		instanceCount := planCounts[plan.ID]
-		cost, ok := plan.ResourceCosts[kind]
-		if ok {
-			currentUsage += cost * instanceCount
-		}
+func checkResourceQuotaNotExceeded(scope string, plan config.Plan, usage map[string]int, resourceLimits map[string]int) error {
+	var exceededQuotas []exceededQuota
 
-		if (currentUsage + cost) > limit {
-			exceededQuotas = append(exceededQuotas, exceededQuota{kind, limit, currentUsage, cost})
+	for kind, limit := range resourceLimits {
+		currentUsage := usage[kind]
+		required := plan.ResourceCosts[kind]
+		if (currentUsage + required) > limit {
+			exceededQuotas = append(exceededQuotas, exceededQuota{kind, limit, currentUsage, required})
 		}
 	}
 
@@ -147,5 +280,5 @@ func checkPlanResourceQuotaNotExceeded(plan config.Plan, planCounts map[string]i
 		errorDetails = append(errorDetails, fmt.Sprintf("%s: (limit %d, used %d, requires %d)", q.name, q.limit, q.usage, q.required))
 	}
 
-	return fmt.Errorf("plan quotas [%s] would be exceeded by this deployment", strings.Join(errorDetails, ", "))
+	return fmt.Errorf("%s quotas [%s] would be exceeded by this deployment", scope, strings.Join(errorDetails, ", "))
 }
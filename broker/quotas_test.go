@@ -0,0 +1,35 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+var _ = Describe("Resource usage", func() {
+	var (
+		smallPlan = config.Plan{ID: "small", ResourceCosts: map[string]int{"ips": 1, "memory": 2}}
+		largePlan = config.Plan{ID: "large", ResourceCosts: map[string]int{"ips": 3}}
+		freePlan  = config.Plan{ID: "free"}
+	)
+
+	It("computes the usage of a plan from its resource costs", func() {
+		Expect(broker.PlanResourceUsage(smallPlan, 4)).To(Equal(map[string]int{"ips": 4, "memory": 8}))
+		Expect(broker.PlanResourceUsage(freePlan, 4)).To(BeEmpty())
+	})
+
+	It("computes the usage of all plans from the instance count of each plan", func() {
+		usage := broker.GlobalResourceUsage(
+			[]config.Plan{smallPlan, largePlan, freePlan},
+			map[string]int{"small": 2, "large": 5, "free": 10},
+		)
+		Expect(usage).To(Equal(map[string]int{"ips": 17, "memory": 4}))
+	})
+})
@@ -50,10 +50,13 @@ func (b *Broker) Recreate(ctx context.Context, instanceID string, details broker
 		}
 	}
 
-	return OperationData{
+	operationData := OperationData{
 		BoshContextID: boshContextID,
 		BoshTaskID:    taskID,
 		OperationType: OperationTypeRecreate,
 		Errands:       plan.PostDeployErrands(),
-	}, nil
+	}
+	b.recordOperation(ctx, instanceID, plan.ID, operationData, logger)
+
+	return operationData, nil
 }
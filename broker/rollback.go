@@ -0,0 +1,165 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+// When rollback_failed_upgrades is set, the manifest and BOSH configs that
+// were deployed before an upgrade are saved as a snapshot next to the
+// operation journal, which only records that the upgrade can be rolled back.
+// If the upgrade or one of its post-deploy errands fails, the first poll for
+// the last operation redeploys the snapshot, and later polls report on that
+// redeploy.
+
+// rollbackSnapshot returns what is deployed for the instance, so that it can
+// be redeployed if the upgrade fails.
+func (b *Broker) rollbackSnapshot(instanceID string, logger *log.Logger) (*operationstore.Snapshot, error) {
+	manifest, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the manifest to roll back to: %s", err)
+	}
+	if !found {
+		return nil, NewDeploymentNotFoundError(fmt.Errorf("bosh deployment '%s' not found", deploymentName(instanceID)))
+	}
+
+	snapshot := &operationstore.Snapshot{Manifest: string(manifest)}
+	if b.DisableBoshConfigs {
+		return snapshot, nil
+	}
+
+	configs, err := b.boshClient.GetConfigs(deploymentName(instanceID), logger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the configs to roll back to: %s", err)
+	}
+	for _, config := range configs {
+		if snapshot.Configs == nil {
+			snapshot.Configs = map[string]string{}
+		}
+		snapshot.Configs[config.Type] = config.Content
+	}
+	return snapshot, nil
+}
+
+// rollBackUpgrade rolls back the upgrade described by operationData, which
+// failed in failedTask. It returns false when the upgrade cannot be rolled
+// back, so that the failure is reported as it is.
+func (b *Broker) rollBackUpgrade(ctx context.Context, instanceID string, operationData OperationData, failedTask boshdirector.BoshTask, logger *log.Logger) (brokerapi.LastOperation, bool) {
+	b.deploymentLock.Lock()
+	defer b.deploymentLock.Unlock()
+
+	rollback, err := b.findRollback(instanceID, operationData)
+	if err != nil {
+		logger.Printf("unable to roll back upgrade of instance %s: %s\n", instanceID, err)
+		return brokerapi.LastOperation{}, false
+	}
+	if rollback == nil {
+		logger.Printf("unable to roll back upgrade of instance %s: the deployment before the upgrade was not recorded\n", instanceID)
+		return brokerapi.LastOperation{}, false
+	}
+
+	rollbackTaskID := rollback.BoshTaskID
+	if rollbackTaskID == 0 {
+		snapshot, err := b.operationStore.Snapshot(instanceID)
+		if err != nil {
+			logger.Printf("unable to roll back upgrade of instance %s: %s\n", instanceID, err)
+			return brokerapi.LastOperation{}, false
+		}
+		if snapshot == nil {
+			logger.Printf("unable to roll back upgrade of instance %s: the deployment before the upgrade was not recorded\n", instanceID)
+			return brokerapi.LastOperation{}, false
+		}
+
+		rollbackTaskID, err = b.redeploy(instanceID, *snapshot, logger)
+		if err != nil {
+			logger.Printf("error rolling back upgrade of instance %s: %s\n", instanceID, err)
+			return brokerapi.LastOperation{}, false
+		}
+		logger.Printf("Rolling back upgrade of instance %s after failure of BOSH task ID %d: BOSH task ID %d\n", instanceID, failedTask.ID, rollbackTaskID)
+
+		if err := b.operationStore.RecordRollbackTask(instanceID, operationData.BoshTaskID, rollbackTaskID); err != nil {
+			logger.Printf("failed to record rollback of instance %s in the operation store: %s\n", instanceID, err)
+		}
+	}
+
+	rollbackTask, err := b.boshClient.GetTask(rollbackTaskID, logger)
+	if err != nil {
+		logger.Printf("error retrieving rollback task %d for instance %s: %s\n", rollbackTaskID, instanceID, err)
+		return brokerapi.LastOperation{}, false
+	}
+
+	ctx = brokercontext.WithBoshTaskID(ctx, rollbackTask.ID)
+	rollbackState := lastOperationState(rollbackTask, logger)
+	logLastOperation(instanceID, rollbackTask, operationData, logger)
+
+	lastOperation := constructLastOperation(ctx, brokerapi.Failed, failedTask, operationData, b.ExposeOperationalErrors)
+	switch rollbackState {
+	case brokerapi.InProgress:
+		lastOperation.State = brokerapi.InProgress
+		lastOperation.Description = fmt.Sprintf("%s, rolling back in bosh task: %d", lastOperation.Description, rollbackTask.ID)
+		b.updateOperationState(instanceID, operationData, rollbackTask.ID, brokerapi.InProgress, logger)
+	case brokerapi.Succeeded:
+		lastOperation.Description = fmt.Sprintf("%s, rolled back by bosh task: %d", lastOperation.Description, rollbackTask.ID)
+		b.updateOperationState(instanceID, operationData, rollbackTask.ID, brokerapi.Failed, logger)
+	default:
+		lastOperation.Description = fmt.Sprintf("%s, rollback failed for bosh task: %d", lastOperation.Description, rollbackTask.ID)
+		b.updateOperationState(instanceID, operationData, rollbackTask.ID, brokerapi.Failed, logger)
+	}
+	return lastOperation, true
+}
+
+func (b *Broker) findRollback(instanceID string, operationData OperationData) (*operationstore.Rollback, error) {
+	operations, err := b.operationStore.ForInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(operations) - 1; i >= 0; i-- {
+		operation := operations[i]
+		if len(operation.BoshTaskIDs) > 0 && operation.BoshTaskIDs[0] == operationData.BoshTaskID {
+			return operation.Rollback, nil
+		}
+	}
+	return nil, nil
+}
+
+// redeploy restores the configs of the deployment, removing any that the
+// upgrade added, and deploys the manifest from before the upgrade.
+func (b *Broker) redeploy(instanceID string, snapshot operationstore.Snapshot, logger *log.Logger) (int, error) {
+	if !b.DisableBoshConfigs {
+		configs, err := b.boshClient.GetConfigs(deploymentName(instanceID), logger)
+		if err != nil {
+			return 0, fmt.Errorf("error getting configs: %s", err)
+		}
+		for _, config := range configs {
+			if _, ok := snapshot.Configs[config.Type]; ok {
+				continue
+			}
+			if _, err := b.boshClient.DeleteConfig(config.Type, config.Name, logger); err != nil {
+				return 0, fmt.Errorf("error deleting config: %s", err)
+			}
+		}
+		for configType, configContent := range snapshot.Configs {
+			if err := b.boshClient.UpdateConfig(configType, deploymentName(instanceID), []byte(configContent), logger); err != nil {
+				return 0, fmt.Errorf("error updating config: %s", err)
+			}
+		}
+	}
+
+	taskID, err := b.boshClient.Deploy([]byte(snapshot.Manifest), "", logger, boshdirector.NewAsyncTaskReporter())
+	if err != nil {
+		return 0, fmt.Errorf("error deploying instance: %s", err)
+	}
+	return taskID, nil
+}
@@ -0,0 +1,288 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+var _ = Describe("Rolling back failed upgrades", func() {
+	const instanceID = "some-instance"
+
+	BeforeEach(func() {
+		brokerConfig.RollbackFailedUpgrades = true
+	})
+
+	Describe("Upgrade", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns([]byte("name: old-manifest"), true, nil)
+			boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{Type: "cloud", Name: "service-instance_some-instance", Content: "old-cloud-config"}}, nil)
+			fakeDeployer.UpgradeReturns(876, []byte("new-manifest"), nil)
+		})
+
+		It("saves the deployed manifest and configs as a snapshot, apart from the operation", func() {
+			b = createDefaultBroker()
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: existingPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			deploymentName, _ := boshClient.GetDeploymentArgsForCall(0)
+			Expect(deploymentName).To(Equal("service-instance_some-instance"))
+
+			Expect(fakeOperationStore.SaveSnapshotCallCount()).To(Equal(1))
+			snapshotInstanceID, snapshot := fakeOperationStore.SaveSnapshotArgsForCall(0)
+			Expect(snapshotInstanceID).To(Equal(instanceID))
+			Expect(snapshot).To(Equal(operationstore.Snapshot{
+				Manifest: "name: old-manifest",
+				Configs:  map[string]string{"cloud": "old-cloud-config"},
+			}))
+
+			Expect(fakeOperationStore.RecordCallCount()).To(Equal(1))
+			Expect(fakeOperationStore.RecordArgsForCall(0).Rollback).To(Equal(&operationstore.Rollback{}))
+		})
+
+		It("records that the upgrade cannot be rolled back when the snapshot cannot be saved", func() {
+			fakeOperationStore.SaveSnapshotReturns(errors.New("disk full"))
+			b = createDefaultBroker()
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: existingPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeOperationStore.RecordCallCount()).To(Equal(1))
+			Expect(fakeOperationStore.RecordArgsForCall(0).Rollback).To(BeNil())
+			Expect(logBuffer.String()).To(ContainSubstring("failed to save rollback snapshot for instance some-instance, the upgrade cannot be rolled back: disk full"))
+		})
+
+		It("does not record configs when bosh configs are disabled", func() {
+			brokerConfig.DisableBoshConfigs = true
+			b = createDefaultBroker()
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: existingPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClient.GetConfigsCallCount()).To(Equal(0))
+			_, snapshot := fakeOperationStore.SaveSnapshotArgsForCall(0)
+			Expect(snapshot).To(Equal(operationstore.Snapshot{Manifest: "name: old-manifest"}))
+		})
+
+		It("does not upgrade when the deployed manifest cannot be retrieved", func() {
+			boshClient.GetDeploymentReturns(nil, false, errors.New("director unavailable"))
+			b = createDefaultBroker()
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: existingPlanID}, loggerFactory.NewWithRequestID())
+
+			Expect(err).To(MatchError("error getting the manifest to roll back to: director unavailable"))
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(0))
+		})
+
+		It("does not get the deployed manifest when rollback is not enabled", func() {
+			brokerConfig.RollbackFailedUpgrades = false
+			b = createDefaultBroker()
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: existingPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClient.GetDeploymentCallCount()).To(Equal(0))
+			Expect(fakeOperationStore.SaveSnapshotCallCount()).To(Equal(0))
+			Expect(fakeOperationStore.RecordArgsForCall(0).Rollback).To(BeNil())
+		})
+	})
+
+	Describe("LastOperation", func() {
+		var (
+			upgradeOperation operationstore.Operation
+			operationData    string
+			lastOperation    brokerapi.LastOperation
+			lastOpErr        error
+			tasks            map[int]boshdirector.BoshTask
+		)
+
+		BeforeEach(func() {
+			operationData = `{"BoshTaskID": 42, "OperationType": "upgrade"}`
+			upgradeOperation = operationstore.Operation{
+				InstanceID:  instanceID,
+				Type:        "upgrade",
+				BoshTaskIDs: []int{42},
+				Rollback:    &operationstore.Rollback{},
+			}
+			fakeOperationStore.SnapshotReturns(&operationstore.Snapshot{
+				Manifest: "name: old-manifest",
+				Configs:  map[string]string{"cloud": "old-cloud-config"},
+			}, nil)
+			tasks = map[int]boshdirector.BoshTask{
+				42: {ID: 42, State: boshdirector.TaskError, Result: "upgrade went wrong"},
+				43: {ID: 43, State: boshdirector.TaskProcessing},
+			}
+			boshClient.GetTaskStub = func(taskID int, _ *log.Logger) (boshdirector.BoshTask, error) {
+				return tasks[taskID], nil
+			}
+			boshClient.DeployReturns(43, nil)
+			boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+				{Type: "cloud", Name: "service-instance_some-instance", Content: "new-cloud-config"},
+				{Type: "runtime", Name: "service-instance_some-instance", Content: "new-runtime-config"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			fakeOperationStore.ForInstanceReturns([]operationstore.Operation{upgradeOperation}, nil)
+			b = createDefaultBroker()
+			lastOperation, lastOpErr = b.LastOperation(context.Background(), instanceID, brokerapi.PollDetails{
+				OperationData: operationData,
+			})
+		})
+
+		It("redeploys the manifest and configs from before the upgrade", func() {
+			Expect(lastOpErr).NotTo(HaveOccurred())
+
+			Expect(fakeOperationStore.SnapshotCallCount()).To(Equal(1))
+			Expect(fakeOperationStore.SnapshotArgsForCall(0)).To(Equal(instanceID))
+
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+			configType, configName, _ := boshClient.DeleteConfigArgsForCall(0)
+			Expect(configType).To(Equal("runtime"))
+			Expect(configName).To(Equal("service-instance_some-instance"))
+
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+			configType, configName, configContent, _ := boshClient.UpdateConfigArgsForCall(0)
+			Expect(configType).To(Equal("cloud"))
+			Expect(configName).To(Equal("service-instance_some-instance"))
+			Expect(string(configContent)).To(Equal("old-cloud-config"))
+
+			Expect(boshClient.DeployCallCount()).To(Equal(1))
+			manifest, contextID, _, _ := boshClient.DeployArgsForCall(0)
+			Expect(string(manifest)).To(Equal("name: old-manifest"))
+			Expect(contextID).To(BeEmpty())
+
+			Expect(fakeOperationStore.RecordRollbackTaskCallCount()).To(Equal(1))
+			recordedInstanceID, boshTaskID, rollbackTaskID := fakeOperationStore.RecordRollbackTaskArgsForCall(0)
+			Expect(recordedInstanceID).To(Equal(instanceID))
+			Expect(boshTaskID).To(Equal(42))
+			Expect(rollbackTaskID).To(Equal(43))
+		})
+
+		It("reports the upgrade as in progress while rolling back", func() {
+			Expect(lastOperation).To(Equal(brokerapi.LastOperation{
+				State:       brokerapi.InProgress,
+				Description: "Failed for bosh task: 42, rolling back in bosh task: 43",
+			}))
+		})
+
+		Context("when the rollback has already been triggered", func() {
+			BeforeEach(func() {
+				upgradeOperation.BoshTaskIDs = []int{42, 43}
+				upgradeOperation.Rollback = &operationstore.Rollback{BoshTaskID: 43}
+				tasks[43] = boshdirector.BoshTask{ID: 43, State: boshdirector.TaskDone}
+			})
+
+			It("does not redeploy again", func() {
+				Expect(fakeOperationStore.SnapshotCallCount()).To(Equal(0))
+				Expect(boshClient.DeployCallCount()).To(Equal(0))
+				Expect(fakeOperationStore.RecordRollbackTaskCallCount()).To(Equal(0))
+			})
+
+			It("reports the upgrade as failed and rolled back once the rollback is done", func() {
+				Expect(lastOperation).To(Equal(brokerapi.LastOperation{
+					State:       brokerapi.Failed,
+					Description: "Failed for bosh task: 42, rolled back by bosh task: 43",
+				}))
+
+				instanceID, boshTaskID, latestBoshTaskID, state := fakeOperationStore.UpdateStateArgsForCall(0)
+				Expect(instanceID).To(Equal("some-instance"))
+				Expect(boshTaskID).To(Equal(42))
+				Expect(latestBoshTaskID).To(Equal(43))
+				Expect(state).To(Equal(string(brokerapi.Failed)))
+			})
+
+			Context("and the rollback failed", func() {
+				BeforeEach(func() {
+					tasks[43] = boshdirector.BoshTask{ID: 43, State: boshdirector.TaskError}
+				})
+
+				It("reports that the rollback failed", func() {
+					Expect(lastOperation).To(Equal(brokerapi.LastOperation{
+						State:       brokerapi.Failed,
+						Description: "Failed for bosh task: 42, rollback failed for bosh task: 43",
+					}))
+				})
+			})
+		})
+
+		Context("when the manifest before the upgrade was not recorded", func() {
+			BeforeEach(func() {
+				upgradeOperation.Rollback = nil
+			})
+
+			It("reports the failure without rolling back", func() {
+				Expect(boshClient.DeployCallCount()).To(Equal(0))
+				Expect(lastOperation).To(Equal(brokerapi.LastOperation{State: brokerapi.Failed, Description: "Failed for bosh task: 42"}))
+				Expect(logBuffer.String()).To(ContainSubstring("unable to roll back upgrade of instance some-instance: the deployment before the upgrade was not recorded"))
+			})
+		})
+
+		Context("when the snapshot of the deployment before the upgrade is missing", func() {
+			BeforeEach(func() {
+				fakeOperationStore.SnapshotReturns(nil, nil)
+			})
+
+			It("reports the failure without rolling back", func() {
+				Expect(boshClient.DeployCallCount()).To(Equal(0))
+				Expect(lastOperation).To(Equal(brokerapi.LastOperation{State: brokerapi.Failed, Description: "Failed for bosh task: 42"}))
+				Expect(logBuffer.String()).To(ContainSubstring("unable to roll back upgrade of instance some-instance: the deployment before the upgrade was not recorded"))
+			})
+		})
+
+		Context("when the snapshot cannot be read", func() {
+			BeforeEach(func() {
+				fakeOperationStore.SnapshotReturns(nil, errors.New("error reading rollback snapshot"))
+			})
+
+			It("reports the failure without rolling back", func() {
+				Expect(boshClient.DeployCallCount()).To(Equal(0))
+				Expect(lastOperation).To(Equal(brokerapi.LastOperation{State: brokerapi.Failed, Description: "Failed for bosh task: 42"}))
+				Expect(logBuffer.String()).To(ContainSubstring("unable to roll back upgrade of instance some-instance: error reading rollback snapshot"))
+			})
+		})
+
+		Context("when the rollback cannot be deployed", func() {
+			BeforeEach(func() {
+				boshClient.DeployReturns(0, errors.New("director unavailable"))
+			})
+
+			It("reports the failure of the upgrade", func() {
+				Expect(lastOperation).To(Equal(brokerapi.LastOperation{State: brokerapi.Failed, Description: "Failed for bosh task: 42"}))
+				Expect(logBuffer.String()).To(ContainSubstring("error rolling back upgrade of instance some-instance: error deploying instance: director unavailable"))
+			})
+		})
+
+		Context("when rollback is not enabled", func() {
+			BeforeEach(func() {
+				brokerConfig.RollbackFailedUpgrades = false
+			})
+
+			It("does not roll back", func() {
+				Expect(fakeOperationStore.ForInstanceCallCount()).To(Equal(0))
+				Expect(boshClient.DeployCallCount()).To(Equal(0))
+				Expect(lastOperation.State).To(Equal(brokerapi.Failed))
+			})
+		})
+
+		Context("when the operation is not an upgrade", func() {
+			BeforeEach(func() {
+				operationData = `{"BoshTaskID": 42, "OperationType": "update"}`
+			})
+
+			It("does not roll back", func() {
+				Expect(boshClient.DeployCallCount()).To(Equal(0))
+				Expect(lastOperation.State).To(Equal(brokerapi.Failed))
+				Expect(lastOperation.Description).To(HavePrefix("Instance update failed"))
+			})
+		})
+	})
+})
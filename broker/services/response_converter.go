@@ -33,6 +33,7 @@ const (
 	OperationInProgress BOSHOperationType = "busy"
 	OperationPending    BOSHOperationType = "not-started"
 	OperationSucceeded  BOSHOperationType = "succeeded"
+	OperationDeferred   BOSHOperationType = "deferred"
 )
 
 type ResponseConverter struct{}
@@ -73,6 +74,21 @@ func (r ResponseConverter) ExtractOperationFrom(response *http.Response) (BOSHOp
 	}
 }
 
+func (r ResponseConverter) UpgradePreviewFrom(response *http.Response) (BOSHOperation, broker.UpgradePreview, error) {
+	if response.StatusCode != http.StatusOK {
+		operation, err := r.ExtractOperationFrom(response)
+		return operation, broker.UpgradePreview{}, err
+	}
+
+	defer response.Body.Close()
+
+	var preview broker.UpgradePreview
+	if err := json.NewDecoder(response.Body).Decode(&preview); err != nil {
+		return BOSHOperation{}, broker.UpgradePreview{}, fmt.Errorf("cannot parse upgrade preview response: %s", err)
+	}
+	return BOSHOperation{Type: OperationSucceeded}, preview, nil
+}
+
 func (r ResponseConverter) LastOperationFrom(response *http.Response) (brokerapi.LastOperation, error) {
 	var lastOperation brokerapi.LastOperation
 	err := decodeBodyInto(response, &lastOperation)
@@ -93,6 +109,16 @@ func (r ResponseConverter) OrphanDeploymentsFrom(response *http.Response) ([]mgm
 	return orphans, nil
 }
 
+func (r ResponseConverter) CatalogFrom(response *http.Response) (brokerapi.CatalogResponse, error) {
+	var catalog brokerapi.CatalogResponse
+	err := decodeBodyInto(response, &catalog)
+	if err != nil {
+		return brokerapi.CatalogResponse{}, err
+	}
+
+	return catalog, nil
+}
+
 func decodeBodyInto(response *http.Response, contents interface{}) error {
 	defer response.Body.Close()
 
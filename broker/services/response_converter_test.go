@@ -18,6 +18,7 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 )
 
@@ -190,6 +191,49 @@ var _ = Describe("Response Converter", func() {
 		})
 	})
 
+	Context("upgrade preview", func() {
+		It("returns the preview when the broker responds with 200", func() {
+			response := http.Response{
+				StatusCode: http.StatusOK,
+				Body:       asBody(`{"manifest_changes": [], "config_changes": {"cloud": [{"path": "/vm_types/0", "type": "added", "new": "small"}]}}`),
+			}
+
+			result, preview, err := converter.UpgradePreviewFrom(&response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Type).To(Equal(services.OperationSucceeded))
+			Expect(preview).To(Equal(broker.UpgradePreview{
+				ManifestChanges: []manifestdiff.Change{},
+				ConfigChanges: map[string][]manifestdiff.Change{
+					"cloud": {{Path: "/vm_types/0", Type: manifestdiff.Added, New: "small"}},
+				},
+			}))
+		})
+
+		It("returns the operation result when the broker responds with any other status", func() {
+			response := http.Response{
+				StatusCode: http.StatusGone,
+				Body:       asBody(""),
+			}
+
+			result, _, err := converter.UpgradePreviewFrom(&response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Type).To(Equal(services.OrphanDeployment))
+		})
+
+		It("returns an error when the preview cannot be parsed", func() {
+			response := http.Response{
+				StatusCode: http.StatusOK,
+				Body:       asBody("not json"),
+			}
+
+			_, _, err := converter.UpgradePreviewFrom(&response)
+
+			Expect(err).To(MatchError(ContainSubstring("cannot parse upgrade preview response")))
+		})
+	})
+
 	Context("orphan deployments", func() {
 		It("returns orphan deployments", func() {
 			response := http.Response{
@@ -57,6 +57,20 @@ func (b *BrokerServices) ProcessInstance(instance service.Instance, operationTyp
 	return b.converter.ExtractOperationFrom(response)
 }
 
+// PreviewUpgrade asks the broker which changes upgrading the instance would
+// make, without upgrading it.
+func (b *BrokerServices) PreviewUpgrade(instance service.Instance) (BOSHOperation, broker.UpgradePreview, error) {
+	body := strings.NewReader(fmt.Sprintf(`{"plan_id": "%s"}`, instance.PlanUniqueID))
+	response, err := b.doRequest(
+		http.MethodPatch,
+		fmt.Sprintf("/mgmt/service_instances/%s?operation_type=upgrade&dry_run=true", instance.GUID),
+		body)
+	if err != nil {
+		return BOSHOperation{}, broker.UpgradePreview{}, err
+	}
+	return b.converter.UpgradePreviewFrom(response)
+}
+
 func (b *BrokerServices) LastOperation(instanceGUID string, operationData broker.OperationData) (brokerapi.LastOperation, error) {
 	asJSON, err := json.Marshal(operationData)
 	if err != nil {
@@ -84,6 +98,15 @@ func (b *BrokerServices) OrphanDeployments() ([]mgmtapi.Deployment, error) {
 	return b.converter.OrphanDeploymentsFrom(response)
 }
 
+func (b *BrokerServices) Catalog() (brokerapi.CatalogResponse, error) {
+	response, err := b.doRequest(http.MethodGet, "/v2/catalog", nil)
+	if err != nil {
+		return brokerapi.CatalogResponse{}, err
+	}
+
+	return b.converter.CatalogFrom(response)
+}
+
 func (b *BrokerServices) doRequest(method, path string, body io.Reader) (*http.Response, error) {
 	request, err := http.NewRequest(method, b.buildURL(path), body)
 	if err != nil {
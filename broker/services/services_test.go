@@ -23,6 +23,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 
@@ -124,6 +125,41 @@ var _ = Describe("Broker Services", func() {
 		})
 	})
 
+	Describe("PreviewUpgrade", func() {
+		It("requests an upgrade dry run and returns the preview", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(response(http.StatusOK, `{"manifest_changes": [{"path": "/name", "type": "changed", "old": "a", "new": "b"}]}`), nil)
+
+			operation, preview, err := brokerServices.PreviewUpgrade(service.Instance{
+				GUID:         serviceInstanceGUID,
+				PlanUniqueID: "unique_plan_id",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodPatch))
+			Expect(request.URL.Path).To(Equal("/mgmt/service_instances/" + serviceInstanceGUID))
+			Expect(request.URL.Query()).To(Equal(url.Values{"operation_type": {"upgrade"}, "dry_run": {"true"}}))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal(`{"plan_id": "unique_plan_id"}`))
+
+			Expect(operation.Type).To(Equal(services.OperationSucceeded))
+			Expect(preview.ManifestChanges).To(Equal([]manifestdiff.Change{{Path: "/name", Type: manifestdiff.Changed, Old: "a", New: "b"}}))
+		})
+
+		Context("when the request fails", func() {
+			It("returns an error", func() {
+				brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+				client.DoReturns(nil, errors.New("connection error"))
+
+				_, _, err := brokerServices.PreviewUpgrade(service.Instance{GUID: serviceInstanceGUID})
+
+				Expect(err).To(MatchError("connection error"))
+			})
+		})
+	})
+
 	Describe("LastOperation", func() {
 		It("returns a last operation", func() {
 			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
@@ -248,6 +284,45 @@ var _ = Describe("Broker Services", func() {
 			})
 		})
 	})
+
+	Describe("Catalog", func() {
+		It("returns the broker catalog", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			catalog := `{"services":[{"id":"service-id","plans":[{"id":"plan-id","maintenance_info":{"private":"secret"}}]}]}`
+			client.DoReturns(response(http.StatusOK, catalog), nil)
+
+			catalogResponse, err := brokerServices.Catalog()
+
+			Expect(err).NotTo(HaveOccurred())
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodGet))
+			Expect(request.URL.Path).To(Equal("/v2/catalog"))
+			Expect(catalogResponse.Services).To(HaveLen(1))
+			Expect(catalogResponse.Services[0].Plans[0].MaintenanceInfo).To(Equal(&brokerapi.MaintenanceInfo{Private: "secret"}))
+		})
+
+		Context("when the request fails", func() {
+			It("returns an error", func() {
+				brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+				client.DoReturns(nil, errors.New("connection error"))
+
+				_, err := brokerServices.Catalog()
+
+				Expect(err).To(MatchError("connection error"))
+			})
+		})
+
+		Context("when the broker responds with an error", func() {
+			It("returns an error", func() {
+				brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+				client.DoReturns(response(http.StatusInternalServerError, ""), nil)
+
+				_, err := brokerServices.Catalog()
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
 })
 
 func response(statusCode int, body string) *http.Response {
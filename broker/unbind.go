@@ -8,6 +8,7 @@ package broker
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
@@ -36,6 +37,15 @@ func (b *Broker) Unbind(
 		return emptyUnbindSpec, b.processError(deploymentErr, logger)
 	}
 
+	plan, _ := b.serviceOffering.FindPlanByID(details.PlanID)
+	postUnbindErrands := plan.PostUnbindErrands()
+	if len(postUnbindErrands) > 0 && !asyncAllowed {
+		return emptyUnbindSpec, b.processError(NewDisplayableError(
+			brokerapi.ErrAsyncRequired,
+			fmt.Errorf("plan %s has post_unbind errands, but the request does not accept an asynchronous unbinding", plan.ID),
+		), logger)
+	}
+
 	requestParams := map[string]interface{}{
 		"plan_id":    details.PlanID,
 		"service_id": details.ServiceID,
@@ -51,7 +61,7 @@ func (b *Broker) Unbind(
 		logger.Printf("failed to resolve manifest secrets: %s", err.Error())
 	}
 	logger.Printf("service adapter will delete binding with ID %s for instance %s\n", bindingID, instanceID)
-	err = b.adapterClient.DeleteBinding(bindingID, vms, manifest, requestParams, secretsMap, logger)
+	err = b.adapterClient.DeleteBinding(ctx, bindingID, vms, manifest, requestParams, secretsMap, logger)
 
 	if err != nil {
 		logger.Printf("delete binding: %v\n", err)
@@ -61,5 +71,14 @@ func (b *Broker) Unbind(
 		return emptyUnbindSpec, b.processError(err, logger)
 	}
 
+	if len(postUnbindErrands) > 0 {
+		logger.Printf("running post_unbind errands for binding with ID %s for instance %s\n", bindingID, instanceID)
+		operationData, err := b.startBindingErrands(instanceID, OperationTypeUnbind, postUnbindErrands, logger)
+		if err != nil {
+			return emptyUnbindSpec, b.processError(NewBoshRequestError("unbind", err), logger)
+		}
+		return brokerapi.UnbindSpec{IsAsync: true, OperationData: operationData}, nil
+	}
+
 	return emptyUnbindSpec, nil
 }
@@ -8,6 +8,7 @@ package broker_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -18,8 +19,10 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 var _ = Describe("Unbind", func() {
@@ -58,7 +61,7 @@ var _ = Describe("Unbind", func() {
 
 	It("calls delete-binding on the service adapter client with all expected arguments", func() {
 		Expect(serviceAdapter.DeleteBindingCallCount()).To(Equal(1))
-		passedBindingID, passedVms, passedManifest, passedRequestParams, passedSecretsMap, _ := serviceAdapter.DeleteBindingArgsForCall(0)
+		_, passedBindingID, passedVms, passedManifest, passedRequestParams, passedSecretsMap, _ := serviceAdapter.DeleteBindingArgsForCall(0)
 		Expect(passedBindingID).To(Equal(bindingID))
 		Expect(passedVms).To(Equal(boshVms))
 		Expect(passedManifest).To(Equal(actualManifest))
@@ -289,4 +292,58 @@ var _ = Describe("Unbind", func() {
 			Expect(unbindErr).To(Equal(brokerapi.ErrBindingDoesNotExist))
 		})
 	})
+
+	Context("when the plan has post_unbind errands", func() {
+		BeforeEach(func() {
+			serviceCatalog.Plans = append(serviceCatalog.Plans, config.Plan{
+				ID: planID,
+				BindingErrands: &config.BindingErrands{
+					PostUnbind: []sdk.Errand{{Name: "delete-acls"}},
+				},
+			})
+			boshClient.RunErrandReturns(42, nil)
+			asyncAllowed = true
+		})
+
+		It("deletes the binding and runs the errand asynchronously", func() {
+			Expect(unbindErr).NotTo(HaveOccurred())
+			Expect(serviceAdapter.DeleteBindingCallCount()).To(Equal(1))
+			Expect(unbindResponse.IsAsync).To(BeTrue())
+
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+			actualDeploymentName, actualErrand, _, actualContextID, _, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualErrand).To(Equal("delete-acls"))
+
+			var operationData broker.OperationData
+			Expect(json.Unmarshal([]byte(unbindResponse.OperationData), &operationData)).To(Succeed())
+			Expect(operationData).To(Equal(broker.OperationData{
+				BoshTaskID:    42,
+				BoshContextID: actualContextID,
+				OperationType: broker.OperationTypeUnbind,
+				Errands:       []config.Errand{{Name: "delete-acls"}},
+			}))
+		})
+
+		Context("and the request does not accept an asynchronous unbinding", func() {
+			BeforeEach(func() {
+				asyncAllowed = false
+			})
+
+			It("fails without deleting the binding", func() {
+				Expect(unbindErr).To(Equal(brokerapi.ErrAsyncRequired))
+				Expect(serviceAdapter.DeleteBindingCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("and the errand cannot be started", func() {
+			BeforeEach(func() {
+				boshClient.RunErrandReturns(0, errors.New("director unavailable"))
+			})
+
+			It("returns a bosh request error", func() {
+				Expect(unbindErr).To(MatchError(ContainSubstring("Currently unable to unbind service instance")))
+			})
+		})
+	})
 })
@@ -69,6 +69,7 @@ func (b *Broker) Update(
 
 		operationType = OperationTypeUpgrade
 		boshTaskID, _, err = b.deployer.Upgrade(
+			ctx,
 			deploymentName(instanceID),
 			details.PlanID,
 			&details.PreviousValues.PlanID,
@@ -76,12 +77,12 @@ func (b *Broker) Update(
 			logger,
 		)
 	} else {
-		err = b.validateQuotasForUpdate(plan, details, logger, ctx)
+		err = b.validateQuotasForUpdate(instanceID, plan, details, logger, ctx)
 		if err != nil {
 			return brokerapi.UpdateServiceSpec{}, b.processError(err, logger)
 		}
 
-		err = b.validatePlanSchemas(plan, details, logger)
+		err = b.validatePlanSchemas(ctx, plan, details, logger)
 		if err != nil {
 			return brokerapi.UpdateServiceSpec{}, b.processError(err, logger)
 		}
@@ -96,6 +97,7 @@ func (b *Broker) Update(
 
 		operationType = OperationTypeUpdate
 		boshTaskID, _, err = b.deployer.Update(
+			ctx,
 			deploymentName(instanceID),
 			details.PlanID,
 			detailsMap,
@@ -110,12 +112,15 @@ func (b *Broker) Update(
 		return b.handleUpdateError(err, logger, ctx)
 	}
 
-	operationData, err := json.Marshal(OperationData{
+	operation := OperationData{
 		BoshTaskID:    boshTaskID,
 		OperationType: operationType,
 		BoshContextID: boshContextID,
 		Errands:       plan.PostDeployErrands(),
-	})
+	}
+	b.recordOperation(ctx, instanceID, plan.ID, operation, logger)
+
+	operationData, err := json.Marshal(operation)
 	if err != nil {
 		return brokerapi.UpdateServiceSpec{}, b.processError(NewGenericError(brokercontext.WithBoshTaskID(ctx, boshTaskID), err), logger)
 	}
@@ -128,16 +133,21 @@ func (b *Broker) handleUpdateError(err error, logger *log.Logger, ctx context.Co
 	case ServiceError:
 		return brokerapi.UpdateServiceSpec{}, b.processError(NewBoshRequestError("update", fmt.Errorf("error deploying instance: %s", err)), logger)
 	case PendingChangesNotAppliedError:
-		return brokerapi.UpdateServiceSpec{}, b.processError(brokerapi.NewFailureResponse(
-			errors.New(PendingChangesErrorMessage),
-			http.StatusUnprocessableEntity,
-			UpdateLoggerAction,
+		return brokerapi.UpdateServiceSpec{}, b.processError(NewDisplayableError(
+			brokerapi.NewFailureResponse(
+				errors.New(PendingChangesErrorMessage),
+				http.StatusUnprocessableEntity,
+				UpdateLoggerAction,
+			),
+			errors.New(err.Summary()),
 		), logger)
 	case TaskInProgressError:
 		return brokerapi.UpdateServiceSpec{}, b.processError(errors.New(OperationInProgressMessage), logger)
 	case PlanNotFoundError:
 		return brokerapi.UpdateServiceSpec{}, b.processError(err, logger)
-	case serviceadapter.UnknownFailureError:
+	case DisplayableError:
+		return brokerapi.UpdateServiceSpec{}, b.processError(err, logger)
+	case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError, serviceadapter.CanceledError:
 		return brokerapi.UpdateServiceSpec{}, b.processError(adapterToAPIError(ctx, err), logger)
 	case error:
 		return brokerapi.UpdateServiceSpec{}, b.processError(NewGenericError(ctx, fmt.Errorf("error deploying instance: %s", err)), logger)
@@ -195,14 +205,15 @@ func (b *Broker) validateMaintenanceInfo(details brokerapi.UpdateDetails, ctx co
 	return nil
 }
 
-func (b *Broker) validateQuotasForUpdate(plan config.Plan, details brokerapi.UpdateDetails, logger *log.Logger, ctx context.Context) error {
+func (b *Broker) validateQuotasForUpdate(instanceID string, plan config.Plan, details brokerapi.UpdateDetails, logger *log.Logger, ctx context.Context) error {
 	if details.PreviousValues.PlanID != plan.ID {
-		cfPlanCounts, err := b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+		orgGUID, spaceGUID := details.PreviousValues.OrgID, details.PreviousValues.SpaceID
+		planCounts, pendingCount, err := b.instanceCountsForQuotas(instanceID, plan, orgGUID, spaceGUID, logger)
 		if err != nil {
 			return NewGenericError(ctx, err)
 		}
 
-		quotasErrors, ok := b.checkQuotas(ctx, plan, cfPlanCounts, b.serviceOffering.ID, logger)
+		quotasErrors, ok := b.checkQuotas(ctx, plan, planCounts, pendingCount, b.serviceOffering.ID, orgGUID, spaceGUID, logger)
 		if !ok {
 			return quotasErrors
 		}
@@ -211,11 +222,11 @@ func (b *Broker) validateQuotasForUpdate(plan config.Plan, details brokerapi.Upd
 	return nil
 }
 
-func (b *Broker) validatePlanSchemas(plan config.Plan, details brokerapi.UpdateDetails, logger *log.Logger) error {
+func (b *Broker) validatePlanSchemas(ctx context.Context, plan config.Plan, details brokerapi.UpdateDetails, logger *log.Logger) error {
 
 	if b.EnablePlanSchemas {
 		var schemas brokerapi.ServiceSchemas
-		schemas, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
 				return err
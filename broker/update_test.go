@@ -14,10 +14,12 @@ import (
 	"net/http"
 
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	brokerfakes "github.com/pivotal-cf/on-demand-service-broker/broker/fakes"
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
@@ -97,7 +99,7 @@ var _ = Describe("Update", func() {
 
 		It("invokes the deployer with the correct arguments", func() {
 			Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-			_, planID, actualRequestParams, _, _, actualSecretsMap, _ := fakeDeployer.UpdateArgsForCall(0)
+			_, _, planID, actualRequestParams, _, _, actualSecretsMap, _ := fakeDeployer.UpdateArgsForCall(0)
 
 			Expect(actualRequestParams).To(Equal(map[string]interface{}{
 				"plan_id":    planID,
@@ -124,7 +126,7 @@ var _ = Describe("Update", func() {
 
 				It("calls the deployer without a bosh context id", func() {
 					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-					_, _, _, _, actualBoshContextID, _, _ := fakeDeployer.UpdateArgsForCall(0)
+					_, _, _, _, _, actualBoshContextID, _, _ := fakeDeployer.UpdateArgsForCall(0)
 					Expect(actualBoshContextID).To(BeEmpty())
 				})
 
@@ -180,7 +182,7 @@ var _ = Describe("Update", func() {
 
 				It("calls the deployer with a bosh context id", func() {
 					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-					_, _, _, _, actualBoshContextID, _, _ := fakeDeployer.UpdateArgsForCall(0)
+					_, _, _, _, _, actualBoshContextID, _, _ := fakeDeployer.UpdateArgsForCall(0)
 					Expect(actualBoshContextID).NotTo(BeEmpty())
 				})
 			})
@@ -213,6 +215,41 @@ var _ = Describe("Update", func() {
 					)
 					Expect(updateError).To(Equal(expectedFailureResponse))
 				})
+
+				It("logs which parts of the manifest differ", func() {
+					fakeDeployer.UpdateReturns(boshTaskID, nil, broker.NewPendingChangesNotAppliedError(
+						errors.New("There are pending changes"),
+						[]manifestdiff.Change{{Path: "/releases/name=redis/version", Type: manifestdiff.Changed}},
+					))
+
+					_, updateError = b.Update(context.Background(), instanceID, updateDetails, async)
+
+					Expect(updateError).To(MatchError(broker.PendingChangesErrorMessage))
+					Expect(logBuffer.String()).To(ContainSubstring("There are pending changes: release redis: version changed"))
+				})
+
+				Context("and the broker is configured to expose operational errors", func() {
+					BeforeEach(func() {
+						brokerConfig.ExposeOperationalErrors = true
+						b = createDefaultBroker()
+						fakeDeployer.UpdateReturns(boshTaskID, nil, broker.NewPendingChangesNotAppliedError(
+							errors.New("There are pending changes"),
+							[]manifestdiff.Change{
+								{Path: "/instance_groups/name=redis/jobs/name=redis-server/properties/port", Type: manifestdiff.Changed},
+								{Path: "/stemcells/0/version", Type: manifestdiff.Changed},
+							},
+						))
+					})
+
+					It("includes the pending changes in the failure response", func() {
+						failureResponse, ok := updateError.(*brokerapi.FailureResponse)
+						Expect(ok).To(BeTrue(), "expected a failure response")
+						Expect(failureResponse.ValidatedStatusCode(nil)).To(Equal(http.StatusUnprocessableEntity))
+						Expect(failureResponse.Error()).To(Equal(broker.PendingChangesErrorMessage +
+							" - error-message: There are pending changes: " +
+							"instance group redis: jobs/name=redis-server/properties/port changed; stemcell 0: version changed"))
+					})
+				})
 			})
 		})
 
@@ -309,7 +346,7 @@ var _ = Describe("Update", func() {
 
 					It("calls the deployer with a bosh context id", func() {
 						Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-						_, _, _, _, actualBoshContextID, _, _ := fakeDeployer.UpdateArgsForCall(0)
+						_, _, _, _, _, actualBoshContextID, _, _ := fakeDeployer.UpdateArgsForCall(0)
 						Expect(actualBoshContextID).NotTo(BeEmpty())
 					})
 				})
@@ -674,6 +711,58 @@ var _ = Describe("Update", func() {
 					ContainSubstring("plan instance limit exceeded for service ID: service-id. Total instances: 4"),
 				))
 			})
+
+			It("lists the in-flight bosh tasks once when the plan changes", func() {
+				boshClient.GetCurrentTasksReturns(boshdirector.BoshTasks{
+					{ID: 5, State: boshdirector.TaskProcessing, Description: "create deployment", DeploymentName: "service-instance_pending-instance"},
+				}, nil)
+				count := 4
+				tasksCallsBefore := boshClient.GetCurrentTasksCallCount()
+				instancesCallsBefore := cfClient.GetInstancesOfServiceOfferingCallCount()
+
+				updateErr := updateWithQuotas(
+					quotaCase{nil, nil, nil, &count},
+					0,
+					3,
+					map[string]interface{}{}, map[string]interface{}{},
+				)
+
+				Expect(updateErr).To(MatchError(ContainSubstring("plan instance limit exceeded for service ID: service-id. Total instances: 4")))
+				Expect(boshClient.GetCurrentTasksCallCount() - tasksCallsBefore).To(Equal(1))
+				Expect(cfClient.GetInstancesOfServiceOfferingCallCount() - instancesCallsBefore).To(Equal(1))
+			})
+
+			It("checks the quotas of the org and space the instance belongs to", func() {
+				orgResourceLimits := map[string]int{"ips": 3}
+				spaceInstanceLimit := 10
+				newPlan := existingPlan
+				newPlan.ResourceCosts = map[string]int{"ips": 1}
+				catalog := serviceCatalog
+				catalog.Plans = config.Plans{newPlan, secondPlan}
+				catalog.OrgQuotas = map[string]config.Quotas{"some-org-guid": {ResourceLimits: orgResourceLimits}}
+				catalog.SpaceQuotas = map[string]config.Quotas{"some-space-guid": {ServiceInstanceLimit: &spaceInstanceLimit}}
+				cfClient.CountInstancesOfServiceOfferingInOrgReturns(map[cf.ServicePlan]int{
+					cfServicePlan("guid_1234", newPlan.ID, "url", "name"): 3,
+				}, nil)
+				fakeDeployer = new(brokerfakes.FakeDeployer)
+				b = createBrokerWithServiceCatalog(catalog)
+
+				_, updateErr := b.Update(context.Background(), instanceID, brokerapi.UpdateDetails{
+					PlanID:    newPlan.ID,
+					ServiceID: "serviceID",
+					PreviousValues: brokerapi.PreviousValues{
+						PlanID:  secondPlan.ID,
+						OrgID:   "some-org-guid",
+						SpaceID: "some-space-guid",
+					},
+				}, true)
+
+				Expect(updateErr).To(MatchError("org quotas [ips: (limit 3, used 3, requires 1)] would be exceeded by this deployment"))
+				_, actualOrgGUID, _ := cfClient.CountInstancesOfServiceOfferingInOrgArgsForCall(0)
+				Expect(actualOrgGUID).To(Equal("some-org-guid"))
+				_, actualSpaceGUID, _ := cfClient.CountInstancesOfServiceOfferingInSpaceArgsForCall(0)
+				Expect(actualSpaceGUID).To(Equal("some-space-guid"))
+			})
 		})
 
 	})
@@ -16,6 +16,8 @@ import (
 
 	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
@@ -37,12 +39,12 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, details brokera
 
 	var boshContextID string
 
-	if plan.LifecycleErrands != nil {
+	if plan.LifecycleErrands != nil || plan.UpgradeErrands != nil {
 		boshContextID = uuid.New()
 	}
 
 	if b.EnablePlanSchemas {
-		schemas, _ := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, _ := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
 		instanceUpgradeSchema := schemas.Instance.Update
 
 		validator := NewValidator(instanceUpgradeSchema.Parameters)
@@ -63,31 +65,111 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, details brokera
 		}
 	}
 
-	taskID, _, err := b.deployer.Upgrade(
-		deploymentName(instanceID),
-		details.PlanID,
-		&details.PlanID,
-		boshContextID,
-		logger,
-	)
+	var err error
+	var snapshot *operationstore.Snapshot
+	if b.RollbackFailedUpgrades {
+		snapshot, err = b.rollbackSnapshot(instanceID, logger)
+		if err != nil {
+			logger.Printf("error upgrading instance %s: %s", instanceID, err)
+			return OperationData{}, b.processError(err, logger)
+		}
+	}
+
+	var taskID int
+	preUpgradeErrands := plan.PreUpgradeErrands()
+	if len(preUpgradeErrands) > 0 {
+		taskID, err = b.runPreUpgradeErrand(ctx, instanceID, details.PlanID, preUpgradeErrands[0], boshContextID, logger)
+	} else {
+		taskID, _, err = b.deployer.Upgrade(
+			ctx,
+			deploymentName(instanceID),
+			details.PlanID,
+			&details.PlanID,
+			boshContextID,
+			logger,
+		)
+	}
 
 	if err != nil {
 		logger.Printf("error upgrading instance %s: %s", instanceID, err)
+		return OperationData{}, b.upgradeError(ctx, err, logger)
+	}
 
-		switch err := err.(type) {
-		case serviceadapter.UnknownFailureError:
-			return OperationData{}, b.processError(adapterToAPIError(ctx, err), logger)
-		case TaskInProgressError:
-			return OperationData{}, b.processError(NewOperationInProgressError(err), logger)
-		default:
-			return OperationData{}, b.processError(err, logger)
+	operationData := OperationData{
+		BoshContextID:     boshContextID,
+		BoshTaskID:        taskID,
+		OperationType:     OperationTypeUpgrade,
+		Errands:           append(plan.PostDeployErrands(), plan.PostUpgradeErrands()...),
+		PreUpgradeErrands: preUpgradeErrands,
+	}
+	if len(preUpgradeErrands) > 0 {
+		operationData.PlanID = plan.ID
+	}
+	b.recordOperationWithRollback(ctx, instanceID, plan.ID, operationData, snapshot, logger)
+
+	return operationData, nil
+}
+
+// upgradeError returns the error to report to the platform when an upgrade
+// cannot be started.
+func (b *Broker) upgradeError(ctx context.Context, err error, logger *log.Logger) error {
+	switch err := err.(type) {
+	case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError, serviceadapter.CanceledError:
+		return b.processError(adapterToAPIError(ctx, err), logger)
+	case TaskInProgressError:
+		return b.processError(NewOperationInProgressError(err), logger)
+	default:
+		return b.processError(err, logger)
+	}
+}
+
+type UpgradePreview struct {
+	ManifestChanges []manifestdiff.Change            `json:"manifest_changes" yaml:"manifest_changes"`
+	ConfigChanges   map[string][]manifestdiff.Change `json:"config_changes,omitempty" yaml:"config_changes,omitempty"`
+}
+
+// PreviewUpgrade returns the changes that upgrading the instance would make
+// to its manifest and BOSH configs, without deploying them. Only the paths
+// and types of the changes are returned, as the values may contain secrets,
+// unless expose_upgrade_preview_values is set.
+func (b *Broker) PreviewUpgrade(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (UpgradePreview, error) {
+	logger.Printf("previewing upgrade of instance %s", instanceID)
+
+	if details.PlanID == "" {
+		return UpgradePreview{}, b.processError(errors.New("no plan ID provided in upgrade request body"), logger)
+	}
+
+	if _, found := b.serviceOffering.FindPlanByID(details.PlanID); !found {
+		logger.Printf("error: finding plan ID %s", details.PlanID)
+		return UpgradePreview{}, b.processError(fmt.Errorf("plan %s not found", details.PlanID), logger)
+	}
+
+	preview, err := b.deployer.PreviewUpgrade(ctx, deploymentName(instanceID), details.PlanID, &details.PlanID, logger)
+	if err != nil {
+		logger.Printf("error previewing upgrade of instance %s: %s", instanceID, err)
+
+		switch err.(type) {
+		case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError, serviceadapter.CanceledError:
+			return UpgradePreview{}, b.processError(adapterToAPIError(ctx, err), logger)
 		}
+		return UpgradePreview{}, b.processError(err, logger)
+	}
+
+	if b.ExposeUpgradePreviewValues {
+		return preview, nil
 	}
+	return preview.Redacted(), nil
+}
 
-	return OperationData{
-		BoshContextID: boshContextID,
-		BoshTaskID:    taskID,
-		OperationType: OperationTypeUpgrade,
-		Errands:       plan.PostDeployErrands(),
-	}, nil
+// Redacted returns the preview with the old and new values of every change
+// left out.
+func (p UpgradePreview) Redacted() UpgradePreview {
+	redacted := UpgradePreview{ManifestChanges: manifestdiff.Redact(p.ManifestChanges)}
+	if p.ConfigChanges != nil {
+		redacted.ConfigChanges = map[string][]manifestdiff.Change{}
+		for configType, changes := range p.ConfigChanges {
+			redacted.ConfigChanges[configType] = manifestdiff.Redact(changes)
+		}
+	}
+	return redacted
 }
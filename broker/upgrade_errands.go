@@ -0,0 +1,105 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+// runPreUpgradeErrand starts an upgrade with pre-upgrade errands by running
+// the first of them. The upgrade is deployed by a later poll for the last
+// operation, once all of them have succeeded, so the manifest for it is
+// generated first, to fail the request rather than the errands' work when the
+// adapter cannot generate it.
+func (b *Broker) runPreUpgradeErrand(ctx context.Context, instanceID, planID string, errand config.Errand, boshContextID string, logger *log.Logger) (int, error) {
+	logger.Printf("running pre-upgrade errand for instance %s\n", instanceID)
+
+	tasks, err := b.boshClient.GetTasks(deploymentName(instanceID), logger)
+	if err != nil {
+		return 0, NewServiceError(fmt.Errorf("error getting tasks for deployment %s: %s\n", deploymentName(instanceID), err))
+	}
+	if incompleteTasks := tasks.IncompleteTasks(); len(incompleteTasks) != 0 {
+		logger.Printf("deployment %s is still in progress: tasks %s\n", deploymentName(instanceID), incompleteTasks.ToLog())
+		return 0, TaskInProgressError{Message: "task in progress"}
+	}
+
+	if _, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger); err != nil {
+		return 0, err
+	} else if !found {
+		return 0, NewDeploymentNotFoundError(fmt.Errorf("bosh deployment '%s' not found", deploymentName(instanceID)))
+	}
+
+	if _, err := b.deployer.PreviewUpgrade(ctx, deploymentName(instanceID), planID, &planID, logger); err != nil {
+		return 0, err
+	}
+
+	return b.boshClient.RunErrand(deploymentName(instanceID), errand.Name, errand.Instances, boshContextID, logger, boshdirector.NewAsyncTaskReporter())
+}
+
+// upgradeAfterErrandsError is returned by processPreUpgrade when the upgrade
+// cannot be deployed after the pre-upgrade errands have succeeded. The upgrade
+// has then failed, rather than the poll for its last operation.
+type upgradeAfterErrandsError struct {
+	error
+}
+
+// processPreUpgrade runs the remaining pre-upgrade errands of the upgrade one
+// at a time and deploys the upgrade once they have all succeeded. From then
+// on the lifecycle runner runs the post-deploy and post-upgrade errands. It
+// returns the latest task, and whether the upgrade has been deployed. When
+// the upgrade cannot be deployed, the latest task is the last errand's.
+func (b *Broker) processPreUpgrade(ctx context.Context, instanceID string, operationData OperationData, lifeCycleRunner LifeCycleRunner, logger *log.Logger) (boshdirector.BoshTask, bool, error) {
+	b.deploymentLock.Lock()
+	defer b.deploymentLock.Unlock()
+
+	boshTasks, err := b.boshClient.GetNormalisedTasksByContext(deploymentName(instanceID), operationData.BoshContextID, logger)
+	if err != nil {
+		return boshdirector.BoshTask{}, false, err
+	}
+
+	if len(boshTasks) == 0 {
+		return boshdirector.BoshTask{}, false, fmt.Errorf("no tasks found for context id: %s", operationData.BoshContextID)
+	}
+
+	if len(boshTasks) > len(operationData.PreUpgradeErrands) {
+		task, err := lifeCycleRunner.GetTask(deploymentName(instanceID), operationData, logger)
+		return task, true, err
+	}
+
+	task := boshTasks[0]
+	if task.StateType() != boshdirector.TaskComplete {
+		return task, false, nil
+	}
+
+	if len(boshTasks) < len(operationData.PreUpgradeErrands) {
+		errand := operationData.PreUpgradeErrands[len(boshTasks)]
+		task, err := lifeCycleRunner.runErrand(deploymentName(instanceID), errand.Name, errand.Instances, operationData.BoshContextID, logger)
+		return task, false, err
+	}
+
+	logger.Printf("pre-upgrade errands succeeded, upgrading instance %s\n", instanceID)
+	taskID, _, err := b.deployer.Upgrade(
+		ctx,
+		deploymentName(instanceID),
+		operationData.PlanID,
+		&operationData.PlanID,
+		operationData.BoshContextID,
+		logger,
+	)
+	if err != nil {
+		logger.Printf("error upgrading instance %s after pre-upgrade errands: %s\n", instanceID, err)
+		return task, false, upgradeAfterErrandsError{err}
+	}
+
+	task, err = b.boshClient.GetTask(taskID, logger)
+	return task, true, err
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+)
+
+var _ = Describe("Upgrade errands", func() {
+	const instanceID = "some-instance"
+
+	Describe("Upgrade", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns([]byte("name: some-manifest"), true, nil)
+			boshClient.RunErrandReturns(42, nil)
+			b = createDefaultBroker()
+		})
+
+		It("runs the first pre-upgrade errand instead of deploying", func() {
+			operationData, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: upgradeErrandsPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(0))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+			deploymentName, errandName, errandInstances, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(deploymentName).To(Equal("service-instance_some-instance"))
+			Expect(errandName).To(Equal("pre-check"))
+			Expect(errandInstances).To(Equal([]string{"redis-server/0"}))
+			Expect(contextID).NotTo(BeEmpty())
+
+			Expect(operationData).To(Equal(broker.OperationData{
+				BoshTaskID:        42,
+				BoshContextID:     contextID,
+				OperationType:     broker.OperationTypeUpgrade,
+				PlanID:            upgradeErrandsPlanID,
+				Errands:           []config.Errand{{Name: "health-check"}, {Name: "smoke-tests"}},
+				PreUpgradeErrands: []config.Errand{{Name: "pre-check", Instances: []string{"redis-server/0"}}},
+			}))
+		})
+
+		It("generates the upgraded manifest before running the errand", func() {
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: upgradeErrandsPlanID}, loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDeployer.PreviewUpgradeCallCount()).To(Equal(1))
+			_, deploymentName, planID, previousPlanID, _ := fakeDeployer.PreviewUpgradeArgsForCall(0)
+			Expect(deploymentName).To(Equal("service-instance_some-instance"))
+			Expect(planID).To(Equal(upgradeErrandsPlanID))
+			Expect(*previousPlanID).To(Equal(upgradeErrandsPlanID))
+		})
+
+		It("returns the adapter's error without running the errand when the upgraded manifest cannot be generated", func() {
+			fakeDeployer.PreviewUpgradeReturns(broker.UpgradePreview{}, serviceadapter.NewUnknownFailureError("error for cf user"))
+
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: upgradeErrandsPlanID}, loggerFactory.NewWithRequestID())
+
+			Expect(err).To(MatchError("error for cf user"))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(0))
+		})
+
+		It("returns an OperationInProgressError when there is a task in progress on the instance", func() {
+			boshClient.GetTasksReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: upgradeErrandsPlanID}, loggerFactory.NewWithRequestID())
+
+			Expect(err).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(0))
+		})
+
+		It("returns a DeploymentNotFoundError when the instance has no deployment", func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+
+			_, err := b.Upgrade(context.Background(), instanceID, brokerapi.UpdateDetails{PlanID: upgradeErrandsPlanID}, loggerFactory.NewWithRequestID())
+
+			Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("LastOperation", func() {
+		var (
+			operationData   broker.OperationData
+			preCheckTask    boshdirector.BoshTask
+			deployTask      boshdirector.BoshTask
+			healthCheckTask boshdirector.BoshTask
+			tasks           map[int]boshdirector.BoshTask
+		)
+
+		lastOperation := func() brokerapi.LastOperation {
+			rawOperationData, err := json.Marshal(operationData)
+			Expect(err).NotTo(HaveOccurred())
+
+			b = createDefaultBroker()
+			lastOperation, err := b.LastOperation(context.Background(), instanceID, brokerapi.PollDetails{OperationData: string(rawOperationData)})
+			Expect(err).NotTo(HaveOccurred())
+			return lastOperation
+		}
+
+		BeforeEach(func() {
+			operationData = broker.OperationData{
+				BoshTaskID:        1,
+				BoshContextID:     "some-context-id",
+				OperationType:     broker.OperationTypeUpgrade,
+				PlanID:            upgradeErrandsPlanID,
+				Errands:           []config.Errand{{Name: "health-check"}, {Name: "smoke-tests"}},
+				PreUpgradeErrands: []config.Errand{{Name: "pre-check", Instances: []string{"redis-server/0"}}},
+			}
+			preCheckTask = boshdirector.BoshTask{ID: 1, State: boshdirector.TaskDone}
+			deployTask = boshdirector.BoshTask{ID: 2, State: boshdirector.TaskProcessing}
+			healthCheckTask = boshdirector.BoshTask{ID: 3, State: boshdirector.TaskProcessing}
+			tasks = map[int]boshdirector.BoshTask{2: deployTask, 3: healthCheckTask}
+			boshClient.GetTaskStub = func(taskID int, _ *log.Logger) (boshdirector.BoshTask, error) {
+				return tasks[taskID], nil
+			}
+		})
+
+		It("reports the pre-upgrade errand while it runs", func() {
+			preCheckTask.State = boshdirector.TaskProcessing
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+
+			Expect(lastOperation()).To(Equal(brokerapi.LastOperation{State: brokerapi.InProgress, Description: "Instance upgrade in progress"}))
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(0))
+		})
+
+		It("fails the upgrade without deploying when a pre-upgrade errand fails", func() {
+			preCheckTask.State = boshdirector.TaskError
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+
+			Expect(lastOperation()).To(Equal(brokerapi.LastOperation{State: brokerapi.Failed, Description: "Failed for bosh task: 1"}))
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(0))
+		})
+
+		It("does not roll back when a pre-upgrade errand fails", func() {
+			brokerConfig.RollbackFailedUpgrades = true
+			preCheckTask.State = boshdirector.TaskError
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+
+			Expect(lastOperation().State).To(Equal(brokerapi.Failed))
+			Expect(fakeOperationStore.ForInstanceCallCount()).To(Equal(0))
+			Expect(boshClient.DeployCallCount()).To(Equal(0))
+		})
+
+		It("runs the next pre-upgrade errand when there is one", func() {
+			operationData.PreUpgradeErrands = append(operationData.PreUpgradeErrands, config.Errand{Name: "backup"})
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+			boshClient.RunErrandReturns(2, nil)
+
+			Expect(lastOperation().State).To(Equal(brokerapi.InProgress))
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(0))
+			_, errandName, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(errandName).To(Equal("backup"))
+			Expect(contextID).To(Equal("some-context-id"))
+		})
+
+		It("deploys the upgrade once the pre-upgrade errands have succeeded", func() {
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+			fakeDeployer.UpgradeReturns(2, []byte("new-manifest"), nil)
+
+			Expect(lastOperation()).To(Equal(brokerapi.LastOperation{State: brokerapi.InProgress, Description: "Instance upgrade in progress"}))
+
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
+			_, deploymentName, planID, previousPlanID, contextID, _ := fakeDeployer.UpgradeArgsForCall(0)
+			Expect(deploymentName).To(Equal("service-instance_some-instance"))
+			Expect(planID).To(Equal(upgradeErrandsPlanID))
+			Expect(*previousPlanID).To(Equal(upgradeErrandsPlanID))
+			Expect(contextID).To(Equal("some-context-id"))
+		})
+
+		It("fails the upgrade with the error when it cannot be deployed", func() {
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+			fakeDeployer.UpgradeReturns(0, nil, errors.New("manifest is invalid"))
+
+			Expect(lastOperation()).To(Equal(brokerapi.LastOperation{
+				State:       brokerapi.Failed,
+				Description: "Failed for bosh task: 1, error-message: manifest is invalid",
+			}))
+			Expect(logBuffer.String()).To(ContainSubstring("error upgrading instance some-instance after pre-upgrade errands: manifest is invalid"))
+
+			Expect(fakeOperationStore.UpdateStateCallCount()).To(Equal(1))
+			_, boshTaskID, latestBoshTaskID, state := fakeOperationStore.UpdateStateArgsForCall(0)
+			Expect(boshTaskID).To(Equal(1))
+			Expect(latestBoshTaskID).To(Equal(1))
+			Expect(state).To(Equal(string(brokerapi.Failed)))
+		})
+
+		It("fails the upgrade with the adapter's message for the user when the adapter fails", func() {
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{preCheckTask}, nil)
+			fakeDeployer.UpgradeReturns(0, nil, serviceadapter.NewUnknownFailureError("error for cf user"))
+
+			Expect(lastOperation()).To(Equal(brokerapi.LastOperation{
+				State:       brokerapi.Failed,
+				Description: "Failed for bosh task: 1, error-message: error for cf user",
+			}))
+		})
+
+		It("runs the post-deploy and then the post-upgrade errands once the upgrade is deployed", func() {
+			deployTask.State = boshdirector.TaskDone
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{deployTask, preCheckTask}, nil)
+			boshClient.RunErrandReturns(3, nil)
+
+			Expect(lastOperation().State).To(Equal(brokerapi.InProgress))
+			_, errandName, _, _, _, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(errandName).To(Equal("health-check"))
+
+			healthCheckTask.State = boshdirector.TaskDone
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{healthCheckTask, deployTask, preCheckTask}, nil)
+			boshClient.RunErrandReturns(4, nil)
+			tasks[4] = boshdirector.BoshTask{ID: 4, State: boshdirector.TaskProcessing}
+
+			Expect(lastOperation().State).To(Equal(brokerapi.InProgress))
+			_, errandName, _, _, _, _ = boshClient.RunErrandArgsForCall(1)
+			Expect(errandName).To(Equal("smoke-tests"))
+
+			smokeTestsTask := boshdirector.BoshTask{ID: 4, State: boshdirector.TaskError}
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{smokeTestsTask, healthCheckTask, deployTask, preCheckTask}, nil)
+
+			Expect(lastOperation()).To(Equal(brokerapi.LastOperation{State: brokerapi.Failed, Description: "Failed for bosh task: 4"}))
+			Expect(boshClient.RunErrandCallCount()).To(Equal(2))
+			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -48,7 +48,7 @@ var _ = Describe("Upgrade", func() {
 		Expect(fakeDeployer.CreateCallCount()).To(Equal(0))
 		Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
 		Expect(fakeDeployer.UpdateCallCount()).To(Equal(0))
-		actualDeploymentName, actualPlanID, actualPreviousPlanID, actualBoshContextID, _ := fakeDeployer.UpgradeArgsForCall(0)
+		_, actualDeploymentName, actualPlanID, actualPreviousPlanID, actualBoshContextID, _ := fakeDeployer.UpgradeArgsForCall(0)
 		Expect(actualPlanID).To(Equal(existingPlanID))
 		Expect(actualDeploymentName).To(Equal(broker.InstancePrefix + instanceID))
 		oldPlanIDCopy := existingPlanID
@@ -77,7 +77,7 @@ var _ = Describe("Upgrade", func() {
 
 			upgradeOperationData, _ = b.Upgrade(context.Background(), instanceID, details, logger)
 
-			_, _, _, contextID, _ := fakeDeployer.UpgradeArgsForCall(0)
+			_, _, _, _, contextID, _ := fakeDeployer.UpgradeArgsForCall(0)
 			Expect(contextID).NotTo(BeEmpty())
 			Expect(upgradeOperationData.BoshContextID).NotTo(BeEmpty())
 			Expect(upgradeOperationData).To(Equal(
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/pivotal-cf/on-demand-service-broker/hasher"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
@@ -18,12 +21,16 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/manifestsecrets"
 	"github.com/pivotal-cf/on-demand-service-broker/network"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+	"github.com/pivotal-cf/on-demand-service-broker/reloadablebroker"
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/startupchecker"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
 func Initiate(conf config.Config,
+	loadConfig reloadablebroker.ConfigLoader,
 	brokerBoshClient broker.BoshClient,
 	taskBoshClient task.BoshClient,
 	cfClient broker.CloudFoundryClient,
@@ -32,39 +39,136 @@ func Initiate(conf config.Config,
 	loggerFactory *loggerfactory.LoggerFactory) {
 
 	logger := loggerFactory.New()
-	var err error
-	startupChecks := buildStartupChecks(conf, cfClient, logger, brokerBoshClient)
+	boshCredhubStore := buildCredhubStore(conf, logger)
+	operationStore := buildOperationStore(conf)
+
+	var runtimeCredentialStore *credhub.Store
+	if conf.HasRuntimeCredHub() {
+		runtimeCredentialStore = buildRuntimeCredentialStore(conf, logger)
+	}
+
+	// the clients and stores are built once, so a reload only changes the
+	// brokers of the service offerings. The brokers of every offering, before
+	// and after a reload, share one lock, so that no two of them change
+	// deployments at the same time.
+	deploymentLock := &sync.Mutex{}
+	buildOnDemandBroker := func(conf config.Config) (apiserver.CombinedBroker, error) {
+		startupChecks, err := buildStartupChecks(conf, cfClient, logger, brokerBoshClient)
+		if err != nil {
+			return nil, err
+		}
+
+		var offerings []routingbroker.Offering
+		for _, serviceOffering := range conf.ServiceOfferings() {
+			offeringBroker, err := buildBroker(
+				conf,
+				serviceOffering,
+				brokerBoshClient,
+				taskBoshClient,
+				cfClient,
+				commandRunner,
+				startupChecks,
+				boshCredhubStore,
+				operationStore,
+				deploymentLock,
+				loggerFactory,
+			)
+			if err != nil {
+				return nil, err
+			}
+			// the startup checks cover every offering, and only need to run once
+			startupChecks = nil
+
+			var combinedBroker apiserver.CombinedBroker = offeringBroker
+			if runtimeCredentialStore != nil {
+				combinedBroker = credhubbroker.New(offeringBroker, runtimeCredentialStore, serviceOffering.Name, serviceOffering.ID, loggerFactory)
+			}
+			offerings = append(offerings, routingbroker.Offering{
+				ServiceOffering: serviceOffering,
+				Broker:          combinedBroker,
+				Owner:           offeringBroker,
+			})
+		}
+
+		if len(offerings) > 1 {
+			return routingbroker.New(offerings, loggerFactory), nil
+		}
+		return offerings[0].Broker, nil
+	}
+
+	onDemandBroker, err := buildOnDemandBroker(conf)
+	if err != nil {
+		logger.Fatalf("error starting broker: %s", err)
+	}
+	reloadableBroker := reloadablebroker.New(conf, onDemandBroker, loadConfig, buildOnDemandBroker)
+
+	reloadSignal := make(chan os.Signal, 1)
+	signal.Notify(reloadSignal, syscall.SIGHUP)
+	go reloadableBroker.ReloadOnSignal(reloadSignal, logger)
+
+	server := apiserver.New(
+		conf,
+		reloadableBroker,
+		broker.ComponentName,
+		loggerFactory,
+		logger,
+	)
+
+	displayBanner(conf)
+	apiserver.StartAndWait(conf, server, logger, stopServer)
+}
+
+// buildBroker builds the broker of one service offering, with the service
+// adapter, releases and stemcell of that offering.
+func buildBroker(
+	conf config.Config,
+	serviceOffering config.ServiceOffering,
+	brokerBoshClient broker.BoshClient,
+	taskBoshClient task.BoshClient,
+	cfClient broker.CloudFoundryClient,
+	commandRunner serviceadapter.CommandRunner,
+	startupChecks []broker.StartupChecker,
+	boshCredhubStore *credhub.Store,
+	operationStore broker.OperationStore,
+	deploymentLock *sync.Mutex,
+	loggerFactory *loggerfactory.LoggerFactory,
+) (*broker.Broker, error) {
+	logger := loggerFactory.New()
+	serviceDeployment := conf.ServiceDeploymentFor(serviceOffering)
 
+	adapterConfig := conf.ServiceAdapterFor(serviceOffering)
 	serviceAdapter := &serviceadapter.Client{
-		ExternalBinPath: conf.ServiceAdapter.Path,
+		ExternalBinPath: adapterConfig.Path,
 		CommandRunner:   commandRunner,
 		UsingStdin:      conf.Broker.UsingStdin,
+		Timeouts:        adapterConfig.Timeouts(),
 	}
 
 	manifestGenerator := task.NewManifestGenerator(
 		serviceAdapter,
-		conf.ServiceCatalog,
-		conf.ServiceDeployment.Stemcell,
-		conf.ServiceDeployment.Releases,
+		serviceOffering,
+		serviceDeployment.Stemcell,
+		serviceDeployment.Releases,
 	)
-	odbSecrets := manifestsecrets.ODBSecrets{ServiceOfferingID: conf.ServiceCatalog.ID}
-	boshCredhubStore := buildCredhubStore(conf, logger)
+	odbSecrets := manifestsecrets.ODBSecrets{ServiceOfferingID: serviceOffering.ID}
 
 	deploymentManager := task.NewDeployer(taskBoshClient, manifestGenerator, odbSecrets, boshCredhubStore)
 	deploymentManager.DisableBoshConfigs = conf.Broker.DisableBoshConfigs
+	deploymentManager.ValidateManifests = conf.Broker.ValidateManifests
+	deploymentManager.RecordInstanceDetails = conf.Broker.EnableInstancesRetrievable
+	deploymentManager.RetrievableParameters = serviceOffering.RetrievableParameters()
 
 	manifestSecretManager := manifestsecrets.BuildManager(conf.Broker.EnableSecureManifests, new(manifestsecrets.CredHubPathMatcher), boshCredhubStore)
 
-	instanceLister, err := service.BuildInstanceLister(cfClient, conf.ServiceCatalog.ID, conf.ServiceInstancesAPI, logger)
+	instanceLister, err := service.BuildInstanceLister(cfClient, serviceOffering.ID, conf.ServiceInstancesAPI, logger)
 	if err != nil {
-		logger.Fatalf("error building instance lister: %s", err)
+		return nil, fmt.Errorf("error building instance lister: %s", err)
 	}
 
-	var onDemandBroker apiserver.CombinedBroker
-	onDemandBroker, err = broker.New(
+	offeringBroker, err := broker.New(
 		brokerBoshClient,
 		cfClient,
-		conf.ServiceCatalog,
+		serviceOffering,
 		conf.Broker,
 		startupChecks,
 		serviceAdapter,
@@ -72,28 +176,23 @@ func Initiate(conf config.Config,
 		manifestSecretManager,
 		instanceLister,
 		&hasher.MapHasher{},
+		operationStore,
+		deploymentLock,
 		loggerFactory,
 	)
 	if err != nil {
-		logger.Fatalf("error starting broker: %s", err)
+		return nil, err
 	}
-	if conf.HasRuntimeCredHub() {
-		onDemandBroker = wrapWithCredHubBroker(conf, logger, onDemandBroker, loggerFactory)
-	}
-
-	server := apiserver.New(
-		conf,
-		onDemandBroker,
-		broker.ComponentName,
-		loggerFactory,
-		logger,
-	)
 
-	displayBanner(conf)
-	apiserver.StartAndWait(conf, server, logger, stopServer)
+	for _, otherOffering := range conf.ServiceOfferings() {
+		if otherOffering.ID != serviceOffering.ID {
+			offeringBroker.OtherServiceOfferingIDs = append(offeringBroker.OtherServiceOfferingIDs, otherOffering.ID)
+		}
+	}
+	return offeringBroker, nil
 }
 
-func wrapWithCredHubBroker(conf config.Config, logger *log.Logger, onDemandBroker apiserver.CombinedBroker, loggerFactory *loggerfactory.LoggerFactory) apiserver.CombinedBroker {
+func buildRuntimeCredentialStore(conf config.Config, logger *log.Logger) *credhub.Store {
 	err := network.NewHostWaiter().Wait(conf.CredHub.APIURL, 16, 10)
 	if err != nil {
 		logger.Fatalf("error connecting to runtime credhub: %s", err)
@@ -108,7 +207,7 @@ func wrapWithCredHubBroker(conf config.Config, logger *log.Logger, onDemandBroke
 	if err != nil {
 		logger.Fatalf("error creating runtime credhub client: %s", err)
 	}
-	return credhubbroker.New(onDemandBroker, runtimeCredentialStore, conf.ServiceCatalog.Name, loggerFactory)
+	return runtimeCredentialStore
 }
 
 func buildCredhubStore(conf config.Config, logger *log.Logger) *credhub.Store {
@@ -130,19 +229,28 @@ func buildCredhubStore(conf config.Config, logger *log.Logger) *credhub.Store {
 	return boshCredhubStore
 }
 
-func buildStartupChecks(conf config.Config, cfClient broker.CloudFoundryClient, logger *log.Logger, boshClient broker.BoshClient) []broker.StartupChecker {
+func buildOperationStore(conf config.Config) broker.OperationStore {
+	if conf.Broker.OperationStorePath == "" {
+		return operationstore.NoopStore{}
+	}
+	return operationstore.NewFileStore(conf.Broker.OperationStorePath, conf.Broker.OperationStoreMaxOps)
+}
+
+func buildStartupChecks(conf config.Config, cfClient broker.CloudFoundryClient, logger *log.Logger, boshClient broker.BoshClient) ([]broker.StartupChecker, error) {
 	var startupChecks []broker.StartupChecker
 	if !conf.Broker.DisableCFStartupChecks {
 		startupChecks = append(
 			startupChecks,
 			startupchecker.NewCFAPIVersionChecker(cfClient, broker.MinimumCFVersion, logger),
-			startupchecker.NewCFPlanConsistencyChecker(cfClient, conf.ServiceCatalog, logger),
 		)
+		for _, serviceOffering := range conf.ServiceOfferings() {
+			startupChecks = append(startupChecks, startupchecker.NewCFPlanConsistencyChecker(cfClient, serviceOffering, logger))
+		}
 
 	}
 	boshInfo, err := boshClient.GetInfo(logger)
 	if err != nil {
-		logger.Fatalf("error starting broker: %s", err)
+		return nil, err
 	}
 	startupChecks = append(startupChecks,
 		startupchecker.NewBOSHDirectorVersionChecker(
@@ -153,7 +261,7 @@ func buildStartupChecks(conf config.Config, cfClient broker.CloudFoundryClient,
 		),
 		startupchecker.NewBOSHAuthChecker(boshClient, logger),
 	)
-	return startupChecks
+	return startupChecks, nil
 }
 
 func displayBanner(conf config.Config) {
@@ -39,6 +39,18 @@ func New(
 }
 
 func (c Client) CountInstancesOfServiceOffering(serviceID string, logger *log.Logger) (map[ServicePlan]int, error) {
+	return c.countInstancesOfServiceOffering(serviceID, "", logger)
+}
+
+func (c Client) CountInstancesOfServiceOfferingInOrg(serviceID, orgGUID string, logger *log.Logger) (map[ServicePlan]int, error) {
+	return c.countInstancesOfServiceOffering(serviceID, fmt.Sprintf("&q=organization_guid:%s", orgGUID), logger)
+}
+
+func (c Client) CountInstancesOfServiceOfferingInSpace(serviceID, spaceGUID string, logger *log.Logger) (map[ServicePlan]int, error) {
+	return c.countInstancesOfServiceOffering(serviceID, fmt.Sprintf("&q=space_guid:%s", spaceGUID), logger)
+}
+
+func (c Client) countInstancesOfServiceOffering(serviceID, query string, logger *log.Logger) (map[ServicePlan]int, error) {
 	plans, err := c.getPlansForServiceID(serviceID, logger)
 	if err != nil {
 		return map[ServicePlan]int{}, err
@@ -46,7 +58,7 @@ func (c Client) CountInstancesOfServiceOffering(serviceID string, logger *log.Lo
 
 	output := map[ServicePlan]int{}
 	for _, plan := range plans {
-		count, err := c.countServiceInstancesOfServicePlan(plan.ServicePlanEntity.ServiceInstancesUrl, logger)
+		count, err := c.countServiceInstancesOfServicePlan(plan.ServicePlanEntity.ServiceInstancesUrl, query, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -91,7 +103,7 @@ func (c Client) CountInstancesOfPlan(serviceID, servicePlanID string, logger *lo
 
 	for _, plan := range plans {
 		if plan.ServicePlanEntity.UniqueID == servicePlanID {
-			count, err := c.countServiceInstancesOfServicePlan(plan.ServicePlanEntity.ServiceInstancesUrl, logger)
+			count, err := c.countServiceInstancesOfServicePlan(plan.ServicePlanEntity.ServiceInstancesUrl, "", logger)
 			if err != nil {
 				return 0, err
 			}
@@ -407,9 +419,9 @@ func (c Client) listPlans(path string, logger *log.Logger) (ServicePlanResponse,
 	return servicePlanResponse, c.get(fmt.Sprintf("%s%s", c.url, path), &servicePlanResponse, logger)
 }
 
-func (c Client) countServiceInstancesOfServicePlan(path string, logger *log.Logger) (int, error) {
+func (c Client) countServiceInstancesOfServicePlan(path, query string, logger *log.Logger) (int, error) {
 	resp := serviceInstancesResponse{}
-	err := c.get(fmt.Sprintf("%s%s?results-per-page=%d", c.url, path, defaultPerPage), &resp, logger)
+	err := c.get(fmt.Sprintf("%s%s?results-per-page=%d%s", c.url, path, defaultPerPage, query), &resp, logger)
 	if err != nil {
 		return 0, err
 	}
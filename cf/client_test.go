@@ -404,6 +404,78 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("CountInstancesOfServiceOfferingInOrg", func() {
+		It("fetches instance counts per plan within the org", func() {
+			server.VerifyAndMock(
+				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
+				mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
+				mockcfapi.ListServiceInstancesByOrg("ff717e7c-afd5-4d0a-bafe-16c7eff546ec", "some-org-guid").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
+				mockcfapi.ListServiceInstancesByOrg("2777ad05-8114-4169-8188-2ef5f39e0c6b", "some-org-guid").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.CountInstancesOfServiceOfferingInOrg("D94A086D-203D-4966-A6F1-60A9E2300F72", "some-org-guid", testLogger)).To(Equal(map[cf.ServicePlan]int{
+				servicePlan(
+					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
+					"11789210-D743-4C65-9D38-C80B29F4D9C8",
+					"/v2/service_plans/ff717e7c-afd5-4d0a-bafe-16c7eff546ec/service_instances",
+					"small",
+				): 1,
+				servicePlan(
+					"2777ad05-8114-4169-8188-2ef5f39e0c6b",
+					"22789210-D743-4C65-9D38-C80B29F4D9C8",
+					"/v2/service_plans/2777ad05-8114-4169-8188-2ef5f39e0c6b/service_instances",
+					"big",
+				): 2,
+			}))
+		})
+
+		It("fails if counting the instances fails", func() {
+			server.VerifyAndMock(
+				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
+				mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
+				mockcfapi.ListServiceInstancesByOrg("ff717e7c-afd5-4d0a-bafe-16c7eff546ec", "some-org-guid").WithAuthorizationHeader(cfAuthorizationHeader).RespondsInternalServerErrorWith("niet goed"),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.CountInstancesOfServiceOfferingInOrg("D94A086D-203D-4966-A6F1-60A9E2300F72", "some-org-guid", testLogger)
+			Expect(err).To(MatchError(ContainSubstring("niet goed")))
+		})
+	})
+
+	Describe("CountInstancesOfServiceOfferingInSpace", func() {
+		It("fetches instance counts per plan within the space", func() {
+			server.VerifyAndMock(
+				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
+				mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
+				mockcfapi.ListServiceInstancesBySpace("ff717e7c-afd5-4d0a-bafe-16c7eff546ec", "some-space-guid").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
+				mockcfapi.ListServiceInstancesBySpace("2777ad05-8114-4169-8188-2ef5f39e0c6b", "some-space-guid").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.CountInstancesOfServiceOfferingInSpace("D94A086D-203D-4966-A6F1-60A9E2300F72", "some-space-guid", testLogger)).To(Equal(map[cf.ServicePlan]int{
+				servicePlan(
+					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
+					"11789210-D743-4C65-9D38-C80B29F4D9C8",
+					"/v2/service_plans/ff717e7c-afd5-4d0a-bafe-16c7eff546ec/service_instances",
+					"small",
+				): 1,
+				servicePlan(
+					"2777ad05-8114-4169-8188-2ef5f39e0c6b",
+					"22789210-D743-4C65-9D38-C80B29F4D9C8",
+					"/v2/service_plans/2777ad05-8114-4169-8188-2ef5f39e0c6b/service_instances",
+					"big",
+				): 2,
+			}))
+		})
+	})
+
 	Describe("CountInstancesOfPlan", func() {
 		It("fetches instance counts for the plan", func() {
 			server.VerifyAndMock(
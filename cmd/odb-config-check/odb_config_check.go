@@ -0,0 +1,53 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const ProblemsFoundExitCode = 10
+
+func main() {
+	loggerFactory := loggerfactory.New(os.Stderr, "odb-config-check", loggerfactory.Flags)
+	logger := loggerFactory.New()
+
+	configFilePath := flag.String("configFilePath", "", "path to broker config file")
+	flag.Parse()
+
+	if *configFilePath == "" {
+		logger.Fatalln("-configFilePath must be given as argument")
+	}
+
+	contents, err := ioutil.ReadFile(*configFilePath)
+	if err != nil {
+		logger.Fatalf("error reading config file: %s\n", err)
+	}
+
+	var conf config.Config
+	if err := yaml.Unmarshal(contents, &conf); err != nil {
+		logger.Fatalf("error parsing config file: %s\n", err)
+	}
+
+	problems := conf.Lint()
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stdout, problem)
+	}
+
+	if len(problems) > 0 {
+		logger.Printf("found %d problems in %s\n", len(problems), *configFilePath)
+		os.Exit(ProblemsFoundExitCode)
+	}
+	logger.Printf("found no problems in %s\n", *configFilePath)
+}
@@ -32,26 +32,25 @@ func main() {
 	logger := loggerFactory.New()
 	logger.Println("Starting broker")
 
-	config := configParser(logger)
-	boshClient := createBoshClient(logger, config)
-	commandRunner := serviceadapter.NewCommandRunner()
-	stopServer := make(chan os.Signal, 1)
-	cfClient := createCfClient(config, logger)
-
-	brokerinitiator.Initiate(config, boshClient, boshClient, cfClient, commandRunner, stopServer, loggerFactory)
-}
-
-func configParser(logger *log.Logger) config.Config {
 	configFilePath := flag.String("configFilePath", "", "path to config file")
 	flag.Parse()
 	if *configFilePath == "" {
 		logger.Fatal("must supply -configFilePath")
 	}
-	config, err := config.Parse(*configFilePath)
+	loadConfig := func() (config.Config, error) {
+		return config.Parse(*configFilePath)
+	}
+
+	config, err := loadConfig()
 	if err != nil {
 		logger.Fatalf("error parsing config: %s", err)
 	}
-	return config
+	boshClient := createBoshClient(logger, config)
+	commandRunner := serviceadapter.NewCommandRunner()
+	stopServer := make(chan os.Signal, 1)
+	cfClient := createCfClient(config, logger)
+
+	brokerinitiator.Initiate(config, loadConfig, boshClient, boshClient, cfClient, commandRunner, stopServer, loggerFactory)
 }
 
 func createCfClient(conf config.Config, logger *log.Logger) broker.CloudFoundryClient {
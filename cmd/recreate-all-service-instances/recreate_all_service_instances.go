@@ -40,6 +40,10 @@ func main() {
 		logger.Fatalln(err.Error())
 	}
 
+	if conf.JSONReportPath == config.JSONReportToStdout {
+		logger = loggerfactory.New(os.Stderr, "recreate-all-service-instances", loggerfactory.Flags).New()
+	}
+
 	builder, err := instanceiterator.NewBuilder(conf, logger, "recreate-all")
 	if err != nil {
 		logger.Fatalln(err.Error())
@@ -22,7 +22,11 @@ func main() {
 	logger := loggerFactory.New()
 
 	var configPath string
+	var dryRun bool
+	var resume bool
 	flag.StringVar(&configPath, "configPath", "", "path to upgrade-all-service-instances config")
+	flag.BoolVar(&dryRun, "dryRun", false, "report the changes an upgrade would make to each instance without upgrading")
+	flag.BoolVar(&resume, "resume", false, "skip the instances upgraded by the previous run, as recorded in the configured state_file")
 	flag.Parse()
 
 	if configPath == "" {
@@ -40,11 +44,24 @@ func main() {
 		logger.Fatalln(err.Error())
 	}
 
+	if conf.JSONReportPath == config.JSONReportToStdout {
+		logger = loggerfactory.New(os.Stderr, "upgrade-all-service-instances", loggerfactory.Flags).New()
+	}
+
 	builder, err := instanceiterator.NewBuilder(conf, logger, "upgrade-all")
 	if err != nil {
 		logger.Fatalln(err.Error())
 	}
-	builder.SetUpgradeTriggerer()
+	if resume {
+		if err := builder.EnableResume(); err != nil {
+			logger.Fatalln(err.Error())
+		}
+	}
+	if dryRun {
+		builder.SetUpgradePreviewTriggerer()
+	} else {
+		builder.SetUpgradeTriggerer()
+	}
 	upgradeTool := instanceiterator.New(builder)
 
 	err = upgradeTool.Iterate()
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 	"syscall"
 
 	"github.com/pivotal-cf/on-demand-service-broker/manifestsecrets"
@@ -29,6 +30,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/credhubbroker"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
 
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
@@ -70,6 +72,9 @@ func StartServer(
 	odbSecrets := manifestsecrets.ODBSecrets{ServiceOfferingID: conf.ServiceCatalog.ID}
 	deployer := task.NewDeployer(fakeTaskBoshClient, taskManifestGenerator, odbSecrets, fakeTaskBulkSetter)
 	deployer.DisableBoshConfigs = conf.Broker.DisableBoshConfigs
+	deployer.ValidateManifests = conf.Broker.ValidateManifests
+	deployer.RecordInstanceDetails = conf.Broker.EnableInstancesRetrievable
+	deployer.RetrievableParameters = conf.ServiceCatalog.RetrievableParameters()
 
 	loggerFactory := loggerfactory.New(loggerBuffer, "collaboration-tests", loggerfactory.Flags)
 	logger := loggerFactory.New()
@@ -91,12 +96,14 @@ func StartServer(
 		secretManager,
 		instanceLister,
 		fakeMapHasher,
+		operationstore.NoopStore{},
+		&sync.Mutex{},
 		loggerFactory,
 	)
 	Expect(err).NotTo(HaveOccurred())
 	var fakeBroker apiserver.CombinedBroker
 	if conf.HasRuntimeCredHub() {
-		fakeBroker = credhubbroker.New(fakeOnDemandBroker, fakeCredentialStore, conf.ServiceCatalog.Name, loggerFactory)
+		fakeBroker = credhubbroker.New(fakeOnDemandBroker, fakeCredentialStore, conf.ServiceCatalog.Name, conf.ServiceCatalog.ID, loggerFactory)
 	} else {
 		fakeBroker = fakeOnDemandBroker
 	}
@@ -62,7 +62,7 @@ var _ = Describe("Catalog", func() {
 			conf := brokerConfig.Config{
 				Broker: brokerConfig.Broker{
 					Port: serverPort, Username: brokerUsername, Password: brokerPassword,
-					EnablePlanSchemas: true,
+					EnablePlanSchemas: true, EnableInstancesRetrievable: true,
 				},
 				ServiceCatalog: serviceCatalogConfig,
 			}
@@ -84,11 +84,12 @@ var _ = Describe("Catalog", func() {
 			Expect(catalog).To(Equal(map[string][]brokerapi.Service{
 				"services": {
 					{
-						ID:            serviceID,
-						Name:          serviceName,
-						Description:   serviceDescription,
-						Bindable:      serviceBindable,
-						PlanUpdatable: servicePlanUpdatable,
+						ID:                   serviceID,
+						Name:                 serviceName,
+						Description:          serviceDescription,
+						Bindable:             serviceBindable,
+						PlanUpdatable:        servicePlanUpdatable,
+						InstancesRetrievable: true,
 						Metadata: &brokerapi.ServiceMetadata{
 							DisplayName:         serviceMetadataDisplayName,
 							ImageUrl:            serviceMetadataImageURL,
@@ -222,11 +223,12 @@ var _ = Describe("Catalog", func() {
 			Expect(catalog).To(Equal(map[string][]brokerapi.Service{
 				"services": {
 					{
-						ID:            serviceID,
-						Name:          serviceName,
-						Description:   serviceDescription,
-						Bindable:      serviceBindable,
-						PlanUpdatable: servicePlanUpdatable,
+						ID:                   serviceID,
+						Name:                 serviceName,
+						Description:          serviceDescription,
+						Bindable:             serviceBindable,
+						PlanUpdatable:        servicePlanUpdatable,
+						InstancesRetrievable: false,
 						Metadata: &brokerapi.ServiceMetadata{
 							DisplayName:         serviceMetadataDisplayName,
 							ImageUrl:            serviceMetadataImageURL,
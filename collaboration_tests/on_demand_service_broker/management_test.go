@@ -273,6 +273,18 @@ var _ = Describe("Management API", func() {
 			})
 		})
 
+		It("responds with metrics in the prometheus format", func() {
+			catalogResp, _ := doCatalogRequest()
+			Expect(catalogResp.StatusCode).To(Equal(http.StatusOK))
+
+			metricsResp, bodyContent := doGetRequest(metricsPath + "?format=prometheus")
+			Expect(metricsResp.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(string(bodyContent)).To(ContainSubstring(`odb_plan_service_instances{plan="dedicated-plan-name",service="service-name"} 1`))
+			Expect(string(bodyContent)).To(ContainSubstring(`odb_service_instances_quota_remaining{service="service-name"} 7`))
+			Expect(string(bodyContent)).To(MatchRegexp(`odb_osbapi_request_duration_seconds_count{method="GET",route="/v2/catalog",status="200"} [1-9]`))
+		})
+
 		It("fails when the broker is not registered with CF", func() {
 			fakeCfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{}, nil)
 
@@ -314,7 +326,7 @@ var _ = Describe("Management API", func() {
 				Expect(response.StatusCode).To(Equal(http.StatusAccepted))
 
 				By("upgrades the correct instance")
-				input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+				_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 				actualInput, ok := input.(sdk.InputParams)
 				Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 				Expect(actualOthers[1]).To(Equal("generate-manifest"))
@@ -356,7 +368,7 @@ var _ = Describe("Management API", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusAccepted))
 
 					By("upgrades the correct instance")
-					input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+					_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 					actualInput, ok := input.(sdk.InputParams)
 					Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 					Expect(actualOthers[1]).To(Equal("generate-manifest"))
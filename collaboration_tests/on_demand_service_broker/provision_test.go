@@ -70,6 +70,7 @@ var _ = Describe("Provision service instance", func() {
 		conf = brokerConfig.Config{
 			Broker: brokerConfig.Broker{
 				Port: serverPort, Username: brokerUsername, Password: brokerPassword,
+				EnableInstancesRetrievable: true,
 			},
 			ServiceCatalog: brokerConfig.ServiceOffering{
 				GlobalQuotas: brokerConfig.Quotas{ServiceInstanceLimit: &globalQuota},
@@ -77,11 +78,12 @@ var _ = Describe("Provision service instance", func() {
 				ID:           serviceID,
 				Plans: brokerConfig.Plans{
 					{
-						Name:       "some-other-plan",
-						ID:         planWithQuotaID,
-						Quotas:     brokerConfig.Quotas{ServiceInstanceLimit: &planQuota},
-						Properties: sdk.Properties{"type": "plan-with-quota", "global_property": "global_value"},
-						Update:     dedicatedPlanUpdateBlock,
+						Name:                  "some-other-plan",
+						ID:                    planWithQuotaID,
+						Quotas:                brokerConfig.Quotas{ServiceInstanceLimit: &planQuota},
+						RetrievableParameters: []string{"some"},
+						Properties:            sdk.Properties{"type": "plan-with-quota", "global_property": "global_value"},
+						Update:                dedicatedPlanUpdateBlock,
 						InstanceGroups: []sdk.InstanceGroup{
 							{
 								Name:               "instance-group-name",
@@ -213,7 +215,7 @@ password: ((odb_secret:foo))`,
 
 			By("calling the deployer with the correct parameters")
 
-			input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+			_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 			actualInput, ok := input.(sdk.InputParams)
 			Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 			Expect(actualOthers[1]).To(Equal("generate-manifest"))
@@ -265,7 +267,7 @@ password: ((odb_secret:foo))`,
 			Expect(json.Unmarshal(bodyContent, &provisionResponseBody)).To(Succeed())
 
 			By("calling the adapter with the correct arguments")
-			id, plan, manifest, _ := fakeServiceAdapter.GenerateDashboardUrlArgsForCall(0)
+			_, id, plan, manifest, _ := fakeServiceAdapter.GenerateDashboardUrlArgsForCall(0)
 			Expect(id).To(Equal(instanceID))
 			Expect(err).NotTo(HaveOccurred())
 			Expect(plan).To(Equal(sdk.Plan{
@@ -295,7 +297,11 @@ password: ((odb_secret:foo))`,
 					},
 				},
 			}))
-			Expect(manifest).To(Equal(boshManifest))
+			Expect(manifest).To(MatchYAML(string(boshManifest) + `
+odb_instance_details:
+  plan_id: ` + planWithQuotaID + `
+  parameters: '{"some":"prop"}'
+`))
 
 			By("including the dashboard url in the response")
 			Expect(provisionResponseBody.DashboardURL).To(Equal("http://dashboard.example.com"))
@@ -81,7 +81,7 @@ var _ = Describe("Unbind", func() {
 		Expect(fakeCredhubOperator.BulkGetCallCount()).To(Equal(1))
 
 		By("calling the adapter with the correct arguments")
-		id, deploymentTopology, manifest, requestParams, actualSecretsMap, _ := fakeServiceAdapter.DeleteBindingArgsForCall(0)
+		_, id, deploymentTopology, manifest, requestParams, actualSecretsMap, _ := fakeServiceAdapter.DeleteBindingArgsForCall(0)
 
 		Expect(id).To(Equal(bindingID))
 		Expect(deploymentTopology).To(Equal(boshVMs))
@@ -287,7 +287,7 @@ var _ = Describe("Update a service instance", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 
 			By("calling the adapter with the correct arguments", func() {
-				input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(1)
+				_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(1)
 				actualInput, ok := input.(sdk.InputParams)
 				Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 				Expect(actualOthers[1]).To(Equal("generate-manifest"))
@@ -540,6 +540,29 @@ properties:
 			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
 		})
 
+		Context("when manifest validation is enabled", func() {
+			BeforeEach(func() {
+				conf.Broker.ValidateManifests = true
+				invalidManifest := "name: service-instance_some-instance-id\nreleases: [{name: redis, version: 1.2.3}]"
+				manifestBytes, err := json.Marshal(sdk.MarshalledGenerateManifest{Manifest: invalidManifest})
+				Expect(err).NotTo(HaveOccurred())
+				zero := 0
+				fakeCommandRunner.RunWithInputParamsReturns(manifestBytes, []byte{}, &zero, nil)
+				fakeTaskBoshClient.GetDeploymentReturns([]byte(invalidManifest), true, nil)
+				fakeTaskBoshClient.GetReleaseJobsReturns(map[boshdirector.ReleaseVersion][]string{}, nil)
+			})
+
+			It("responds with 500 and describes the problem with the generated manifest", func() {
+				resp, bodyContent := doUpdateRequest(requestBody, instanceID)
+
+				Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+				var body brokerapi.ErrorResponse
+				Expect(json.Unmarshal(bodyContent, &body)).To(Succeed())
+				Expect(body.Description).To(Equal("The service adapter generated an invalid manifest: release redis version 1.2.3 has not been uploaded"))
+				Expect(fakeTaskBoshClient.DeployCallCount()).To(Equal(0))
+			})
+		})
+
 		It("responds with 500 if BOSH deployment cannot be found", func() {
 			fakeTaskBoshClient.GetDeploymentReturns(nil, false, nil)
 
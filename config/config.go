@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"log"
 	"strings"
+	"time"
 
 	"net/http"
 
@@ -30,21 +31,124 @@ type Config struct {
 	ServiceDeployment   ServiceDeployment   `yaml:"service_deployment"`
 	ServiceCatalog      ServiceOffering     `yaml:"service_catalog"`
 	BoshCredhub         BoshCredhub         `yaml:"bosh_credhub"`
+
+	// AdditionalServiceOfferings holds the offerings after the first one
+	// when service_catalog is a list of offerings.
+	AdditionalServiceOfferings []ServiceOffering `yaml:"-"`
+}
+
+// UnmarshalYAML accepts either a single service offering or a list of
+// service offerings as the service_catalog.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plainConfig Config
+
+	var fields yaml.MapSlice
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+
+	for i, field := range fields {
+		if field.Key != "service_catalog" {
+			continue
+		}
+		if _, isList := field.Value.([]interface{}); !isList {
+			break
+		}
+
+		var catalog struct {
+			Offerings []ServiceOffering `yaml:"service_catalog"`
+		}
+		if err := unmarshal(&catalog); err != nil {
+			return err
+		}
+		if len(catalog.Offerings) == 0 {
+			return errors.New("service_catalog must contain at least one service offering")
+		}
+
+		otherFields := append(append(yaml.MapSlice{}, fields[:i]...), fields[i+1:]...)
+		rawOtherFields, err := yaml.Marshal(otherFields)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(rawOtherFields, (*plainConfig)(c)); err != nil {
+			return err
+		}
+		c.ServiceCatalog = catalog.Offerings[0]
+		c.AdditionalServiceOfferings = catalog.Offerings[1:]
+		return nil
+	}
+
+	return unmarshal((*plainConfig)(c))
+}
+
+// MarshalYAML writes the service_catalog as a list when there is more than
+// one service offering.
+func (c Config) MarshalYAML() (interface{}, error) {
+	type plainConfig Config
+	if len(c.AdditionalServiceOfferings) == 0 {
+		return plainConfig(c), nil
+	}
+
+	rawConfig, err := yaml.Marshal(plainConfig(c))
+	if err != nil {
+		return nil, err
+	}
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal(rawConfig, &fields); err != nil {
+		return nil, err
+	}
+	for i := range fields {
+		if fields[i].Key == "service_catalog" {
+			fields[i].Value = c.ServiceOfferings()
+		}
+	}
+	return fields, nil
+}
+
+// ServiceOfferings returns every service offering of the broker, starting
+// with the ServiceCatalog.
+func (c Config) ServiceOfferings() []ServiceOffering {
+	return append([]ServiceOffering{c.ServiceCatalog}, c.AdditionalServiceOfferings...)
+}
+
+// ServiceAdapterFor returns the service adapter of the offering, which
+// defaults to the service_adapter of the broker.
+func (c Config) ServiceAdapterFor(offering ServiceOffering) ServiceAdapter {
+	if offering.ServiceAdapter != nil {
+		return *offering.ServiceAdapter
+	}
+	return c.ServiceAdapter
+}
+
+// ServiceDeploymentFor returns the releases and stemcell of the offering,
+// which default to the service_deployment of the broker.
+func (c Config) ServiceDeploymentFor(offering ServiceOffering) ServiceDeployment {
+	if offering.ServiceDeployment != nil {
+		return *offering.ServiceDeployment
+	}
+	return c.ServiceDeployment
 }
 
 type Broker struct {
 	Port                       int
 	Username                   string
 	Password                   string
-	DisableSSLCertVerification bool `yaml:"disable_ssl_cert_verification"`
-	DisableBoshConfigs         bool `yaml:"disable_bosh_configs"`
-	StartUpBanner              bool `yaml:"startup_banner"`
-	ShutdownTimeoutSecs        int  `yaml:"shutdown_timeout_in_seconds"`
-	DisableCFStartupChecks     bool `yaml:"disable_cf_startup_checks"`
-	ExposeOperationalErrors    bool `yaml:"expose_operational_errors"`
-	EnablePlanSchemas          bool `yaml:"enable_plan_schemas"`
-	UsingStdin                 bool `yaml:"use_stdin"`
-	EnableSecureManifests      bool `yaml:"enable_secure_manifests"`
+	DisableSSLCertVerification bool   `yaml:"disable_ssl_cert_verification"`
+	DisableBoshConfigs         bool   `yaml:"disable_bosh_configs"`
+	StartUpBanner              bool   `yaml:"startup_banner"`
+	ShutdownTimeoutSecs        int    `yaml:"shutdown_timeout_in_seconds"`
+	DisableCFStartupChecks     bool   `yaml:"disable_cf_startup_checks"`
+	ExposeOperationalErrors    bool   `yaml:"expose_operational_errors"`
+	EnablePlanSchemas          bool   `yaml:"enable_plan_schemas"`
+	UsingStdin                 bool   `yaml:"use_stdin"`
+	EnableSecureManifests      bool   `yaml:"enable_secure_manifests"`
+	EnableBindingsRetrievable  bool   `yaml:"enable_bindings_retrievable"`
+	EnableInstancesRetrievable bool   `yaml:"enable_instances_retrievable"`
+	OperationStorePath         string `yaml:"operation_store_path"`
+	OperationStoreMaxOps       int    `yaml:"operation_store_max_operations"`
+	RollbackFailedUpgrades     bool   `yaml:"rollback_failed_upgrades"`
+	ExposeUpgradePreviewValues bool   `yaml:"expose_upgrade_preview_values"`
+	ValidateManifests          bool   `yaml:"validate_manifests"`
 	TLS                        TLSConfig
 }
 
@@ -106,29 +210,93 @@ func (c Config) Validate() error {
 		}
 	}
 
-	if err := checkIsExecutableFile(c.ServiceAdapter.Path); err != nil {
-		return fmt.Errorf("checking for executable service adapter file: %s", err)
+	for _, offering := range c.ServiceOfferings() {
+		if problems := c.serviceAdapterProblems(offering); len(problems) > 0 {
+			return problems[0]
+		}
+
+		if err := c.ServiceDeploymentFor(offering).Validate(); err != nil {
+			return err
+		}
+
+		if err := offering.Validate(); err != nil {
+			return err
+		}
+
+		for _, plan := range offering.Plans {
+			if err := c.postBindErrandsProblem(plan); err != nil {
+				return fmt.Errorf("plan %s: %s", plan.Name, err)
+			}
+		}
 	}
 
-	if err := c.ServiceDeployment.Validate(); err != nil {
-		return err
+	if len(c.AdditionalServiceOfferings) > 0 {
+		if problems := uniqueOfferingProblems(c.ServiceOfferings()); len(problems) > 0 {
+			return problems[0]
+		}
 	}
+	return nil
+}
 
-	if err := c.ServiceCatalog.Validate(); err != nil {
-		return err
+// The checks below are shared by Validate, which stops at the first problem
+// they find, and Lint, which reports all of them.
+
+func (c Config) serviceAdapterProblems(offering ServiceOffering) []error {
+	var problems []error
+	serviceAdapter := c.ServiceAdapterFor(offering)
+	if err := checkIsExecutableFile(serviceAdapter.Path); err != nil {
+		problems = append(problems, fmt.Errorf("checking for executable service adapter file: %s", err))
+	}
+	if err := serviceAdapter.Validate(); err != nil {
+		problems = append(problems, err)
 	}
+	return problems
+}
 
+func (c Config) postBindErrandsProblem(plan Plan) error {
+	if len(plan.PostBindErrands()) > 0 && !c.Broker.EnableBindingsRetrievable {
+		return errors.New("post_bind errands require enable_bindings_retrievable to be set so that asynchronously created bindings can be fetched")
+	}
 	return nil
 }
 
+// uniqueOfferingProblems checks that requests can be routed to a single
+// service offering by the service ID, or by the plan ID.
+func uniqueOfferingProblems(offerings []ServiceOffering) []error {
+	offeringIDs := map[string]int{}
+	offeringNames := map[string]int{}
+	planIDs := map[string]int{}
+	for _, offering := range offerings {
+		offeringIDs[offering.ID]++
+		offeringNames[offering.Name]++
+		for _, plan := range offering.Plans {
+			planIDs[plan.ID]++
+		}
+	}
+
+	var problems []error
+	for _, id := range repeatedKeys(offeringIDs) {
+		problems = append(problems, fmt.Errorf("service offering ID %s is used by more than one service offering", id))
+	}
+	for _, name := range repeatedKeys(offeringNames) {
+		problems = append(problems, fmt.Errorf("service offering name %s is used by more than one service offering", name))
+	}
+	for _, id := range repeatedKeys(planIDs) {
+		problems = append(problems, fmt.Errorf("plan ID %s is used by more than one plan", id))
+	}
+	return problems
+}
+
 func (c Config) HasRuntimeCredHub() bool {
 	return c.CredHub != CredHub{}
 }
 
 func (c Config) HasBindingWithDNSConfigured() bool {
-	for _, plan := range c.ServiceCatalog.Plans {
-		if len(plan.BindingWithDNS) > 0 {
-			return true
+	for _, offering := range c.ServiceOfferings() {
+		for _, plan := range offering.Plans {
+			if len(plan.BindingWithDNS) > 0 {
+				return true
+			}
 		}
 	}
 	return false
@@ -148,6 +316,12 @@ func (b Broker) Validate() error {
 	if b.Password == "" {
 		return errors.New("broker.password can't be empty")
 	}
+	if b.RollbackFailedUpgrades && b.OperationStorePath == "" {
+		return errors.New("broker.rollback_failed_upgrades requires broker.operation_store_path to keep the manifests to roll back to")
+	}
+	if b.OperationStoreMaxOps < 0 {
+		return errors.New("broker.operation_store_max_operations can't be negative")
+	}
 
 	return nil
 }
@@ -158,24 +332,27 @@ type ServiceDeployment struct {
 }
 
 func (s ServiceDeployment) Validate() error {
+	if len(s.latestVersionProblems()) > 0 {
+		return errors.New("You must configure the exact release and stemcell versions in broker.service_deployment. ODB requires exact versions to detect pending changes as part of the 'cf update-service' workflow. For example, latest and 3112.latest are not supported.")
+	}
+	return nil
+}
+
+func (s ServiceDeployment) latestVersionProblems() []error {
+	var problems []error
 	for _, release := range s.Releases {
-		if err := assertVersion(release.Version); err != nil {
-			return err
+		if isLatestVersion(release.Version) {
+			problems = append(problems, fmt.Errorf("release %s must have an exact version, not '%s'", release.Name, release.Version))
 		}
 	}
-
-	if err := assertVersion(s.Stemcell.Version); err != nil {
-		return err
+	if isLatestVersion(s.Stemcell.Version) {
+		problems = append(problems, fmt.Errorf("stemcell must have an exact version, not '%s'", s.Stemcell.Version))
 	}
-
-	return nil
+	return problems
 }
 
-func assertVersion(version string) error {
-	if strings.HasSuffix(version, "latest") {
-		return errors.New("You must configure the exact release and stemcell versions in broker.service_deployment. ODB requires exact versions to detect pending changes as part of the 'cf update-service' workflow. For example, latest and 3112.latest are not supported.")
-	}
-	return nil
+func isLatestVersion(version string) bool {
+	return strings.HasSuffix(version, "latest")
 }
 
 func (boshConfig Bosh) NewAuthHeaderBuilder(UAAURL string, disableSSLCertVerification bool) (AuthHeaderBuilder, error) {
@@ -349,6 +526,61 @@ type CredHub struct {
 
 type ServiceAdapter struct {
 	Path string
+	// TimeoutSecs bounds how long any invocation of the adapter may run. When
+	// it is zero, an invocation is only stopped if its request is cancelled.
+	TimeoutSecs int `yaml:"timeout_in_seconds,omitempty"`
+	// SubcommandTimeoutSecs overrides TimeoutSecs for individual subcommands,
+	// such as generate-manifest.
+	SubcommandTimeoutSecs map[string]int `yaml:"subcommand_timeouts_in_seconds,omitempty"`
+}
+
+var serviceAdapterSubcommands = []string{
+	"generate-manifest",
+	"create-binding",
+	"delete-binding",
+	"get-binding",
+	"dashboard-url",
+	"generate-plan-schemas",
+}
+
+func (a ServiceAdapter) Validate() error {
+	if a.TimeoutSecs < 0 {
+		return fmt.Errorf("service_adapter.timeout_in_seconds can't be negative")
+	}
+	for subcommand, timeoutSecs := range a.SubcommandTimeoutSecs {
+		if !isServiceAdapterSubcommand(subcommand) {
+			return fmt.Errorf("service_adapter.subcommand_timeouts_in_seconds has unknown subcommand %s, must be one of %s", subcommand, strings.Join(serviceAdapterSubcommands, ", "))
+		}
+		if timeoutSecs < 0 {
+			return fmt.Errorf("service_adapter.subcommand_timeouts_in_seconds of %s can't be negative", subcommand)
+		}
+	}
+	return nil
+}
+
+// Timeouts returns how long each subcommand of the adapter may run. Those
+// without a timeout are left out.
+func (a ServiceAdapter) Timeouts() map[string]time.Duration {
+	timeouts := map[string]time.Duration{}
+	for _, subcommand := range serviceAdapterSubcommands {
+		timeoutSecs, overridden := a.SubcommandTimeoutSecs[subcommand]
+		if !overridden {
+			timeoutSecs = a.TimeoutSecs
+		}
+		if timeoutSecs > 0 {
+			timeouts[subcommand] = time.Duration(timeoutSecs) * time.Second
+		}
+	}
+	return timeouts
+}
+
+func isServiceAdapterSubcommand(name string) bool {
+	for _, subcommand := range serviceAdapterSubcommands {
+		if subcommand == name {
+			return true
+		}
+	}
+	return false
 }
 
 func Parse(configFilePath string) (Config, error) {
@@ -386,14 +618,31 @@ type ServiceOffering struct {
 	Tags             []string
 	GlobalProperties serviceadapter.Properties `yaml:"global_properties"`
 	GlobalQuotas     Quotas                    `yaml:"global_quotas"`
+	OrgQuotas        map[string]Quotas         `yaml:"org_quotas,omitempty"`
+	SpaceQuotas      map[string]Quotas         `yaml:"space_quotas,omitempty"`
 	Plans            Plans
 	MaintenanceInfo  *MaintenanceInfo `yaml:"maintenance_info,omitempty"`
+
+	// ServiceAdapter and ServiceDeployment override those of the broker for
+	// this offering.
+	ServiceAdapter    *ServiceAdapter    `yaml:"service_adapter,omitempty"`
+	ServiceDeployment *ServiceDeployment `yaml:"service_deployment,omitempty"`
 }
 
 func (s ServiceOffering) FindPlanByID(id string) (Plan, bool) {
 	return s.Plans.FindByID(id)
 }
 
+// RetrievableParameters returns the retrievable parameters of each plan, by
+// plan ID.
+func (s ServiceOffering) RetrievableParameters() map[string][]string {
+	parameters := map[string][]string{}
+	for _, plan := range s.Plans {
+		parameters[plan.ID] = plan.RetrievableParameters
+	}
+	return parameters
+}
+
 func (s ServiceOffering) HasLifecycleErrands() bool {
 	for _, plan := range s.Plans {
 		if plan.LifecycleErrands != nil {
@@ -401,6 +650,9 @@ func (s ServiceOffering) HasLifecycleErrands() bool {
 				return true
 			}
 		}
+		if len(plan.PreUpgradeErrands()) > 0 || len(plan.PostUpgradeErrands()) > 0 {
+			return true
+		}
 	}
 
 	return false
@@ -408,23 +660,38 @@ func (s ServiceOffering) HasLifecycleErrands() bool {
 
 func (s ServiceOffering) Validate() error {
 	for _, plan := range s.Plans {
-		if plan.LifecycleErrands != nil {
-			for _, errand := range plan.LifecycleErrands.PostDeploy {
-				if err := s.validateLifecycleErrands(errand); err != nil {
-					return err
-				}
-			}
-			for _, errand := range plan.LifecycleErrands.PreDelete {
-				if err := s.validateLifecycleErrands(errand); err != nil {
-					return err
-				}
-			}
+		if problems := s.errandProblems(plan); len(problems) > 0 {
+			return fmt.Errorf("plan %s: %s", plan.Name, problems[0])
 		}
 	}
 
 	return nil
 }
 
+func (s ServiceOffering) errandProblems(plan Plan) []error {
+	errands := []struct {
+		kind    string
+		errands []Errand
+	}{
+		{"post_deploy", plan.PostDeployErrands()},
+		{"pre_delete", plan.PreDeleteErrands()},
+		{"post_bind", plan.PostBindErrands()},
+		{"post_unbind", plan.PostUnbindErrands()},
+		{"pre_upgrade", plan.PreUpgradeErrands()},
+		{"post_upgrade", plan.PostUpgradeErrands()},
+	}
+
+	var problems []error
+	for _, kind := range errands {
+		for _, errand := range kind.errands {
+			if err := s.validateLifecycleErrands(serviceadapter.Errand(errand)); err != nil {
+				problems = append(problems, fmt.Errorf("%s errand %s: %s", kind.kind, errand.Name, err))
+			}
+		}
+	}
+	return problems
+}
+
 func (s ServiceOffering) validateLifecycleErrands(errands serviceadapter.Errand) error {
 	for _, instanceName := range errands.Instances {
 		pieces := strings.Split(instanceName, "/")
@@ -468,9 +735,15 @@ type Plan struct {
 	InstanceGroups   []serviceadapter.InstanceGroup   `yaml:"instance_groups,omitempty"`
 	Update           *serviceadapter.Update           `yaml:"update,omitempty"`
 	LifecycleErrands *serviceadapter.LifecycleErrands `yaml:"lifecycle_errands,omitempty"`
+	BindingErrands   *BindingErrands                  `yaml:"binding_errands,omitempty"`
+	UpgradeErrands   *UpgradeErrands                  `yaml:"upgrade_errands,omitempty"`
 	ResourceCosts    map[string]int                   `yaml:"resource_costs,omitempty"`
 	BindingWithDNS   []BindingDNS                     `yaml:"binding_with_dns"`
 	MaintenanceInfo  *MaintenanceInfo                 `yaml:"maintenance_info,omitempty"`
+	// RetrievableParameters are the arbitrary parameters recorded in the
+	// deployment manifest, so that they are returned when the instance is
+	// retrieved. Other parameters are not recorded, as they may be secrets.
+	RetrievableParameters []string `yaml:"retrievable_parameters,omitempty"`
 }
 
 func (p Plan) AdapterPlan(globalProperties serviceadapter.Properties) serviceadapter.Plan {
@@ -515,6 +788,70 @@ type Errand struct {
 	Instances []string
 }
 
+// BindingErrands are run after the service adapter has created or deleted a
+// binding, making the bind or unbind operation asynchronous.
+type BindingErrands struct {
+	PostBind   []serviceadapter.Errand `yaml:"post_bind,omitempty"`
+	PostUnbind []serviceadapter.Errand `yaml:"post_unbind,omitempty"`
+}
+
+func (p Plan) PostBindErrands() []Errand {
+	var errands []Errand
+
+	if p.BindingErrands != nil {
+		for _, errand := range p.BindingErrands.PostBind {
+			errands = append(errands, Errand(errand))
+		}
+	}
+
+	return errands
+}
+
+func (p Plan) PostUnbindErrands() []Errand {
+	var errands []Errand
+
+	if p.BindingErrands != nil {
+		for _, errand := range p.BindingErrands.PostUnbind {
+			errands = append(errands, Errand(errand))
+		}
+	}
+
+	return errands
+}
+
+// UpgradeErrands are only run when an instance is upgraded through the
+// management API, as upgrade-all does. Pre-upgrade errands run before the
+// deployment, and a failure stops the upgrade of the instance. Post-upgrade
+// errands run after the post-deploy errands.
+type UpgradeErrands struct {
+	PreUpgrade  []serviceadapter.Errand `yaml:"pre_upgrade,omitempty"`
+	PostUpgrade []serviceadapter.Errand `yaml:"post_upgrade,omitempty"`
+}
+
+func (p Plan) PreUpgradeErrands() []Errand {
+	var errands []Errand
+
+	if p.UpgradeErrands != nil {
+		for _, errand := range p.UpgradeErrands.PreUpgrade {
+			errands = append(errands, Errand(errand))
+		}
+	}
+
+	return errands
+}
+
+func (p Plan) PostUpgradeErrands() []Errand {
+	var errands []Errand
+
+	if p.UpgradeErrands != nil {
+		for _, errand := range p.UpgradeErrands.PostUpgrade {
+			errands = append(errands, Errand(errand))
+		}
+	}
+
+	return errands
+}
+
 func (p Plan) PreDeleteErrands() []Errand {
 	var errands []Errand
 
@@ -579,8 +916,91 @@ type InstanceIteratorConfig struct {
 	AttemptLimit          int                   `yaml:"attempt_limit"`
 	RequestTimeout        int                   `yaml:"request_timeout"`
 	MaxInFlight           int                   `yaml:"max_in_flight"`
+	MaxFailures           int                   `yaml:"max_failures"`
+	MaxFailurePercentage  int                   `yaml:"max_failure_percentage"`
+	PlanMaxInFlight       map[string]int        `yaml:"plan_max_in_flight"`
+	ConcurrencyLimits     []ConcurrencyLimit    `yaml:"concurrency_limits"`
 	Canaries              int                   `yaml:"canaries"`
 	CanarySelectionParams CanarySelectionParams `yaml:"canary_selection_params"`
+	CanaryStages          []CanaryStage         `yaml:"canary_stages"`
+	ApprovalFile          string                `yaml:"approval_file"`
+	StateFile             string                `yaml:"state_file"`
+	MaintenanceWindows    MaintenanceWindows    `yaml:"maintenance_windows"`
+	InstanceFilters       InstanceFilters       `yaml:"instance_filters"`
+	// JSONReportPath is where a JSON report of the run is written, or "-"
+	// for stdout. No report is written when it is empty.
+	JSONReportPath string `yaml:"json_report_path"`
+}
+
+// JSONReportToStdout is the json_report_path that writes the report to
+// stdout. Errands log to stderr instead in that case, so that stdout holds
+// nothing but the report.
+const JSONReportToStdout = "-"
+
+// CanaryStage is one of several canary phases run in order before all the
+// remaining instances are processed. A stage processes a number or a
+// percentage of the instances matching its selection params, or all of them,
+// and can pause or wait for approval once complete.
+type CanaryStage struct {
+	Canaries         int                   `yaml:"canaries"`
+	CanaryPercentage int                   `yaml:"canary_percentage"`
+	SelectionParams  CanarySelectionParams `yaml:"selection_params"`
+	Pause            int                   `yaml:"pause"`
+	RequireApproval  bool                  `yaml:"require_approval"`
+	// ApprovalTimeout is how many seconds to wait for approval before failing
+	// the run. The run waits indefinitely when it is zero.
+	ApprovalTimeout int `yaml:"approval_timeout"`
+}
+
+// ConcurrencyLimit bounds the number of concurrent operations on the instances
+// selected by the selection params, such as cf_org and cf_space. The selected
+// instances share one limit, so a limit selecting only a cf_org caps all of its
+// spaces together. Grouping by org, space or AZ is not supported: to cap
+// several orgs or spaces separately, configure a limit for each of them.
+type ConcurrencyLimit struct {
+	SelectionParams map[string]string `yaml:"selection_params"`
+	MaxInFlight     int               `yaml:"max_in_flight"`
+}
+
+// InstanceFilters limits which service instances are processed. When any
+// include criteria are set only instances matching at least one of them are
+// processed, and instances matching any exclude criteria are never processed.
+type InstanceFilters struct {
+	Include InstanceFilter `yaml:"include"`
+	Exclude InstanceFilter `yaml:"exclude"`
+}
+
+// InstanceFilter selects instances by plan ID, by selection params such as
+// cf_org and cf_space, or by GUID. InstanceGUIDsFile names a file listing one
+// GUID per line.
+type InstanceFilter struct {
+	PlanIDs           []string            `yaml:"plan_ids"`
+	SelectionParams   []map[string]string `yaml:"selection_params"`
+	InstanceGUIDs     []string            `yaml:"instance_guids"`
+	InstanceGUIDsFile string              `yaml:"instance_guids_file"`
+}
+
+// MaintenanceWindows restricts when operations may be triggered on service
+// instances. Overrides are matched in order against the instances selected by
+// their selection params, such as cf_org and cf_space; instances matching no
+// override use the default window. Without any window, instances are always
+// open to operations.
+type MaintenanceWindows struct {
+	Default   *MaintenanceWindow          `yaml:"default"`
+	Overrides []MaintenanceWindowOverride `yaml:"overrides"`
+}
+
+// MaintenanceWindow is a daily period, given as HH:MM times of day in the
+// timezone. A window whose end is before its start spans midnight.
+type MaintenanceWindow struct {
+	Start    string `yaml:"start"`
+	End      string `yaml:"end"`
+	Timezone string `yaml:"timezone"`
+}
+
+type MaintenanceWindowOverride struct {
+	MaintenanceWindow `yaml:",inline"`
+	SelectionParams   map[string]string `yaml:"selection_params"`
 }
 
 type BrokerAPI struct {
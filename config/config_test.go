@@ -12,6 +12,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"net/http"
 
@@ -215,6 +216,24 @@ var _ = Describe("Broker Config", func() {
 			})
 		})
 
+		Context("and the config has org and space quotas", func() {
+			BeforeEach(func() {
+				configFileName = "good_config_with_org_and_space_quotas.yml"
+			})
+
+			It("returns config object", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				orgInstanceLimit := 5
+				spaceInstanceLimit := 2
+				Expect(conf.ServiceCatalog.OrgQuotas).To(Equal(map[string]config.Quotas{
+					"some-org-guid": {ServiceInstanceLimit: &orgInstanceLimit, ResourceLimits: map[string]int{"ips": 10}},
+				}))
+				Expect(conf.ServiceCatalog.SpaceQuotas).To(Equal(map[string]config.Quotas{
+					"some-space-guid": {ServiceInstanceLimit: &spaceInstanceLimit},
+				}))
+			})
+		})
+
 		Context("and the config has the expose_operational_errors flag", func() {
 			BeforeEach(func() {
 				configFileName = "good_config_with_optional_fields.yml"
@@ -225,6 +244,11 @@ var _ = Describe("Broker Config", func() {
 				Expect(conf.Broker.ExposeOperationalErrors).To(BeTrue())
 				Expect(conf.Broker.EnablePlanSchemas).To(BeTrue())
 				Expect(conf.Broker.EnableSecureManifests).To(BeTrue())
+				Expect(conf.Broker.EnableBindingsRetrievable).To(BeTrue())
+				Expect(conf.Broker.OperationStorePath).To(Equal("/var/vcap/store/broker/operations.json"))
+				Expect(conf.Broker.OperationStoreMaxOps).To(Equal(500))
+				Expect(conf.Broker.RollbackFailedUpgrades).To(BeTrue())
+				Expect(conf.Broker.ExposeUpgradePreviewValues).To(BeTrue())
 				Expect(conf.BoshCredhub.URL).To(Equal("https://bosh-credhub:8844/api/"))
 				Expect(conf.BoshCredhub.RootCACert).To(Equal("CERT"))
 				Expect(conf.BoshCredhub.Authentication.UAA.ClientCredentials.ID).To(Equal("credhub_id"))
@@ -568,6 +592,124 @@ var _ = Describe("Broker Config", func() {
 			})
 		})
 
+		Context("binding errands", func() {
+			Context("when post bind and post unbind errands are configured", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_binding_errands.yml"
+				})
+
+				It("parses the errands", func() {
+					Expect(parseErr).NotTo(HaveOccurred())
+					plan := conf.ServiceCatalog.Plans[0]
+					Expect(plan.PostBindErrands()).To(Equal([]config.Errand{{Name: "create-acls", Instances: []string{"redis-errand/0"}}}))
+					Expect(plan.PostUnbindErrands()).To(Equal([]config.Errand{{Name: "delete-acls"}}))
+				})
+			})
+
+			Context("when post bind errands are configured but bindings are not retrievable", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_binding_errands_without_retrievable_bindings.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError(ContainSubstring("plan some-dedicated-name: post_bind errands require enable_bindings_retrievable to be set")))
+				})
+			})
+
+			Context("when the instances property is specified as a/b/c", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_invalid_post_bind_instances.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError(MatchRegexp("Must specify pool or instance '.*' in format 'name' or 'name/id-or-index'")))
+				})
+			})
+		})
+
+		Context("upgrade errands", func() {
+			Context("when pre-upgrade and post-upgrade errands are configured", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_upgrade_errands.yml"
+				})
+
+				It("parses the errands", func() {
+					Expect(parseErr).NotTo(HaveOccurred())
+					plan := conf.ServiceCatalog.Plans[0]
+					Expect(plan.PreUpgradeErrands()).To(Equal([]config.Errand{{Name: "health-check", Instances: []string{"redis-errand/0"}}}))
+					Expect(plan.PostUpgradeErrands()).To(Equal([]config.Errand{{Name: "smoke-tests"}}))
+					Expect(conf.ServiceCatalog.HasLifecycleErrands()).To(BeTrue())
+				})
+			})
+
+			Context("when the instances property is specified as a/b/c", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_invalid_pre_upgrade_instances.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError(MatchRegexp("Must specify pool or instance '.*' in format 'name' or 'name/id-or-index'")))
+				})
+			})
+		})
+
+		Context("multiple service offerings", func() {
+			Context("when the service catalog is a list of offerings", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_multiple_service_offerings.yml"
+				})
+
+				It("parses every offering", func() {
+					Expect(parseErr).NotTo(HaveOccurred())
+					Expect(conf.Broker.Port).To(Equal(8080))
+					Expect(conf.ServiceCatalog.ID).To(Equal("redis-id"))
+
+					offerings := conf.ServiceOfferings()
+					Expect(offerings).To(HaveLen(2))
+					Expect(offerings[1].ID).To(Equal("kafka-id"))
+					Expect(offerings[1].Plans[0].ID).To(Equal("kafka-small-id"))
+					Expect(*offerings[1].GlobalQuotas.ServiceInstanceLimit).To(Equal(5))
+				})
+
+				It("uses the adapter and deployment of the broker unless the offering sets its own", func() {
+					offerings := conf.ServiceOfferings()
+					Expect(conf.ServiceDeploymentFor(offerings[0]).Stemcell.OS).To(Equal("ubuntu-trusty"))
+					Expect(conf.ServiceDeploymentFor(offerings[1]).Stemcell.OS).To(Equal("ubuntu-xenial"))
+					Expect(conf.ServiceDeploymentFor(offerings[1]).Releases[0].Name).To(Equal("kafka"))
+					Expect(conf.ServiceAdapterFor(offerings[0]).Path).To(Equal("test_assets/executable.sh"))
+				})
+
+				It("writes the offerings back as a list", func() {
+					rawConfig, err := yaml.Marshal(conf)
+					Expect(err).NotTo(HaveOccurred())
+
+					var roundTripped config.Config
+					Expect(yaml.Unmarshal(rawConfig, &roundTripped)).To(Succeed())
+					Expect(roundTripped.Broker).To(Equal(conf.Broker))
+					Expect(roundTripped.ServiceCatalog.ID).To(Equal("redis-id"))
+					Expect(roundTripped.AdditionalServiceOfferings).To(HaveLen(1))
+					Expect(roundTripped.AdditionalServiceOfferings[0].ID).To(Equal("kafka-id"))
+					Expect(roundTripped.AdditionalServiceOfferings[0].ServiceDeployment).To(Equal(conf.AdditionalServiceOfferings[0].ServiceDeployment))
+				})
+
+				It("does not allow two offerings with the same ID", func() {
+					conf.AdditionalServiceOfferings[0].ID = "redis-id"
+
+					Expect(conf.Validate()).To(MatchError("service offering ID redis-id is used by more than one service offering"))
+				})
+			})
+
+			Context("when a plan ID is used by more than one offering", func() {
+				BeforeEach(func() {
+					configFileName = "config_with_plan_id_in_multiple_service_offerings.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError("plan ID redis-small-id is used by more than one plan"))
+				})
+			})
+		})
+
 		Context("pre delete errand", func() {
 			Context("when the instances property is specified as a/b/c", func() {
 				BeforeEach(func() {
@@ -896,6 +1038,48 @@ var _ = Describe("ServiceOffering", func() {
 	})
 })
 
+var _ = Describe("ServiceAdapter", func() {
+	It("parses the timeouts of the adapter's subcommands", func() {
+		var serviceAdapter config.ServiceAdapter
+		err := yaml.Unmarshal([]byte(`
+path: /some/adapter
+timeout_in_seconds: 60
+subcommand_timeouts_in_seconds:
+  generate-manifest: 300
+  dashboard-url: 0
+`), &serviceAdapter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(serviceAdapter.Validate()).To(Succeed())
+		Expect(serviceAdapter.Timeouts()).To(Equal(map[string]time.Duration{
+			"generate-manifest":     300 * time.Second,
+			"create-binding":        time.Minute,
+			"delete-binding":        time.Minute,
+			"get-binding":           time.Minute,
+			"generate-plan-schemas": time.Minute,
+		}))
+	})
+
+	It("has no timeouts by default", func() {
+		Expect(config.ServiceAdapter{Path: "/some/adapter"}.Timeouts()).To(BeEmpty())
+	})
+
+	It("rejects a negative timeout", func() {
+		serviceAdapter := config.ServiceAdapter{TimeoutSecs: -1}
+		Expect(serviceAdapter.Validate()).To(MatchError("service_adapter.timeout_in_seconds can't be negative"))
+	})
+
+	It("rejects a negative subcommand timeout", func() {
+		serviceAdapter := config.ServiceAdapter{SubcommandTimeoutSecs: map[string]int{"create-binding": -5}}
+		Expect(serviceAdapter.Validate()).To(MatchError("service_adapter.subcommand_timeouts_in_seconds of create-binding can't be negative"))
+	})
+
+	It("rejects a timeout for an unknown subcommand", func() {
+		serviceAdapter := config.ServiceAdapter{SubcommandTimeoutSecs: map[string]int{"generate-manfiest": 10}}
+		Expect(serviceAdapter.Validate()).To(MatchError(ContainSubstring("service_adapter.subcommand_timeouts_in_seconds has unknown subcommand generate-manfiest")))
+	})
+})
+
 var _ = Describe("CF#NewAuthHeaderBuilder", func() {
 	const tokenToReturn = "auth-token"
 	var logger *log.Logger
@@ -1156,6 +1340,21 @@ var _ = Describe("Config validation", func() {
 		Entry("fails when client_secret is empty", clientCredsAuthBlock("id", ""), errors.New("client_secret can't be empty")),
 	)
 
+	Describe("Broker", func() {
+		It("requires an operation store to roll back failed upgrades", func() {
+			broker := config.Broker{Port: 8080, Username: "user", Password: "pass", RollbackFailedUpgrades: true}
+			Expect(broker.Validate()).To(MatchError("broker.rollback_failed_upgrades requires broker.operation_store_path to keep the manifests to roll back to"))
+
+			broker.OperationStorePath = "/var/vcap/store/broker/operations.json"
+			Expect(broker.Validate()).To(Succeed())
+		})
+
+		It("rejects a negative operation store limit", func() {
+			broker := config.Broker{Port: 8080, Username: "user", Password: "pass", OperationStoreMaxOps: -1}
+			Expect(broker.Validate()).To(MatchError("broker.operation_store_max_operations can't be negative"))
+		})
+	})
+
 })
 
 func authBlock(basic config.UserCredentials, uaa config.UAAAuthentication) config.Authentication {
@@ -0,0 +1,205 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Lint reports every problem it finds in the configuration, without
+// contacting BOSH or Cloud Foundry. Validate, which the broker runs when it
+// starts, shares many of these checks but stops at the first problem.
+func (c Config) Lint() []error {
+	var problems []error
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if err := c.Broker.Validate(); err != nil {
+		report("%s", err)
+	}
+	if err := c.Bosh.Validate(); err != nil {
+		report("BOSH configuration error: %s", err)
+	}
+	if !c.Broker.DisableCFStartupChecks {
+		if err := c.CF.Validate(); err != nil {
+			report("CF configuration error: %s", err)
+		}
+	}
+
+	for _, offering := range c.ServiceOfferings() {
+		for _, problem := range c.lintServiceOffering(offering) {
+			report("service offering %s: %s", offering.Name, problem)
+		}
+	}
+
+	return append(problems, uniqueOfferingProblems(c.ServiceOfferings())...)
+}
+
+func (c Config) lintServiceOffering(offering ServiceOffering) []string {
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for _, problem := range c.serviceAdapterProblems(offering) {
+		report("%s", problem)
+	}
+
+	serviceDeployment := c.ServiceDeploymentFor(offering)
+	for _, release := range serviceDeployment.Releases {
+		if release.Version == "" {
+			report("release %s must have an exact version, not ''", release.Name)
+		}
+	}
+	if serviceDeployment.Stemcell.Version == "" {
+		report("stemcell must have an exact version, not ''")
+	}
+	for _, problem := range serviceDeployment.latestVersionProblems() {
+		report("%s", problem)
+	}
+
+	for _, problem := range lintMaintenanceInfo(offering.MaintenanceInfo) {
+		report("maintenance_info %s", problem)
+	}
+
+	costedResources := map[string]bool{}
+	planNames := map[string]int{}
+	for _, plan := range offering.Plans {
+		planNames[plan.Name]++
+		for resource := range plan.ResourceCosts {
+			costedResources[resource] = true
+		}
+	}
+	for _, name := range repeatedKeys(planNames) {
+		report("plan name %s is used by more than one plan", name)
+	}
+
+	lintQuotas := func(quotas Quotas, description string) {
+		for _, resource := range sortedResources(quotas.ResourceLimits) {
+			if !costedResources[resource] {
+				report("%s limit resource %s, which no plan has a resource cost for", description, resource)
+			}
+		}
+	}
+	lintQuotas(offering.GlobalQuotas, "global_quotas")
+	for _, org := range sortedQuotaKeys(offering.OrgQuotas) {
+		lintQuotas(offering.OrgQuotas[org], fmt.Sprintf("org_quotas of %s", org))
+	}
+	for _, space := range sortedQuotaKeys(offering.SpaceQuotas) {
+		lintQuotas(offering.SpaceQuotas[space], fmt.Sprintf("space_quotas of %s", space))
+	}
+
+	for _, plan := range offering.Plans {
+		lintQuotas(plan.Quotas, fmt.Sprintf("plan %s quotas", plan.Name))
+		for _, problem := range c.lintPlan(offering, plan) {
+			report("plan %s: %s", plan.Name, problem)
+		}
+	}
+
+	return problems
+}
+
+func (c Config) lintPlan(offering ServiceOffering, plan Plan) []string {
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for _, problem := range offering.errandProblems(plan) {
+		report("%s", problem)
+	}
+	if err := c.postBindErrandsProblem(plan); err != nil {
+		report("%s", err)
+	}
+
+	instanceGroups := map[string]bool{}
+	for _, instanceGroup := range plan.InstanceGroups {
+		instanceGroups[instanceGroup.Name] = true
+	}
+	for _, binding := range plan.BindingWithDNS {
+		if !instanceGroups[binding.InstanceGroup] {
+			report("binding_with_dns %s refers to instance group '%s', which the plan does not have", binding.Name, binding.InstanceGroup)
+		}
+	}
+
+	for _, problem := range lintMaintenanceInfo(plan.MaintenanceInfo) {
+		report("maintenance_info %s", problem)
+	}
+
+	return problems
+}
+
+// lintMaintenanceInfo checks that maintenance_info, when given, has entries
+// that can be compared with those sent by the platform.
+func lintMaintenanceInfo(maintenanceInfo *MaintenanceInfo) []string {
+	if maintenanceInfo == nil {
+		return nil
+	}
+	if len(maintenanceInfo.Public) == 0 && len(maintenanceInfo.Private) == 0 {
+		return []string{"has no public or private entries"}
+	}
+
+	var problems []string
+	for _, key := range sortedEntries(maintenanceInfo.Public) {
+		if key == "" {
+			problems = append(problems, "has a public entry with an empty key")
+		} else if maintenanceInfo.Public[key] == "" {
+			problems = append(problems, fmt.Sprintf("public entry %s has an empty value", key))
+		}
+		if _, found := maintenanceInfo.Private[key]; found {
+			problems = append(problems, fmt.Sprintf("entry %s is both public and private", key))
+		}
+	}
+	for _, key := range sortedEntries(maintenanceInfo.Private) {
+		if key == "" {
+			problems = append(problems, "has a private entry with an empty key")
+		} else if maintenanceInfo.Private[key] == "" {
+			problems = append(problems, fmt.Sprintf("private entry %s has an empty value", key))
+		}
+	}
+	return problems
+}
+
+func repeatedKeys(counts map[string]int) []string {
+	var repeated []string
+	for key, count := range counts {
+		if count > 1 {
+			repeated = append(repeated, key)
+		}
+	}
+	sort.Strings(repeated)
+	return repeated
+}
+
+func sortedResources(resources map[string]int) []string {
+	var keys []string
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedEntries(entries map[string]string) []string {
+	var keys []string
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedQuotaKeys(quotas map[string]Quotas) []string {
+	var keys []string
+	for key := range quotas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("Lint", func() {
+	var conf config.Config
+
+	problems := func() []string {
+		var messages []string
+		for _, problem := range conf.Lint() {
+			messages = append(messages, problem.Error())
+		}
+		return messages
+	}
+
+	BeforeEach(func() {
+		var err error
+		conf, err = config.Parse(filepath.Join("test_assets", "good_config_with_binding_dns_list.yml"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("finds no problems in a valid config", func() {
+		Expect(conf.Lint()).To(BeEmpty())
+	})
+
+	It("reports every problem at once", func() {
+		conf.Broker.Port = 0
+		conf.ServiceAdapter.Path = "test_assets/good_config.yml"
+		conf.ServiceDeployment.Releases[0].Version = "1.latest"
+		conf.ServiceDeployment.Stemcell.Version = "latest"
+
+		plan := conf.ServiceCatalog.Plans[0]
+		plan.LifecycleErrands = &serviceadapter.LifecycleErrands{
+			PostDeploy: []serviceadapter.Errand{{Name: "health-check", Instances: []string{"redis-server/"}}},
+		}
+		plan.BindingWithDNS = append(plan.BindingWithDNS, config.BindingDNS{Name: "proxy", InstanceGroup: "proxy"})
+		otherPlan := plan
+		otherPlan.ID = "other-plan-id"
+		otherPlan.LifecycleErrands = nil
+		otherPlan.BindingWithDNS = nil
+		conf.ServiceCatalog.Plans = config.Plans{plan, otherPlan}
+
+		Expect(problems()).To(ConsistOf(
+			"broker.port can't be empty",
+			"service offering some-marketplace-name: checking for executable service adapter file: 'test_assets/good_config.yml' is not executable",
+			"service offering some-marketplace-name: release some-name must have an exact version, not '1.latest'",
+			"service offering some-marketplace-name: stemcell must have an exact version, not 'latest'",
+			"service offering some-marketplace-name: plan name some-dedicated-name is used by more than one plan",
+			"service offering some-marketplace-name: plan some-dedicated-name: post_deploy errand health-check: Must specify pool or instance 'redis-server/' in format 'name' or 'name/id-or-index'",
+			"service offering some-marketplace-name: plan some-dedicated-name: binding_with_dns proxy refers to instance group 'proxy', which the plan does not have",
+		))
+	})
+
+	It("reports plan IDs used by more than one offering", func() {
+		other := conf.ServiceCatalog
+		other.ID = "other-id"
+		other.Name = "other-name"
+		conf.AdditionalServiceOfferings = []config.ServiceOffering{other}
+
+		Expect(problems()).To(ConsistOf("plan ID some-dedicated-plan-id is used by more than one plan"))
+	})
+
+	It("reports quotas limiting resources that no plan costs", func() {
+		conf.ServiceCatalog.Plans[0].ResourceCosts = map[string]int{"memory": 1}
+		conf.ServiceCatalog.GlobalQuotas.ResourceLimits = map[string]int{"memory": 10, "ips": 5}
+		conf.ServiceCatalog.OrgQuotas = map[string]config.Quotas{"some-org": {ResourceLimits: map[string]int{"disk": 5}}}
+		conf.ServiceCatalog.Plans[0].Quotas.ResourceLimits = map[string]int{"cpu": 2}
+
+		Expect(problems()).To(ConsistOf(
+			"service offering some-marketplace-name: global_quotas limit resource ips, which no plan has a resource cost for",
+			"service offering some-marketplace-name: org_quotas of some-org limit resource disk, which no plan has a resource cost for",
+			"service offering some-marketplace-name: plan some-dedicated-name quotas limit resource cpu, which no plan has a resource cost for",
+		))
+	})
+
+	It("reports invalid maintenance_info", func() {
+		conf.ServiceCatalog.MaintenanceInfo = &config.MaintenanceInfo{}
+		conf.ServiceCatalog.Plans[0].MaintenanceInfo = &config.MaintenanceInfo{
+			Public:  map[string]string{"version": "", "stemcell": "1234"},
+			Private: map[string]string{"stemcell": "1234"},
+		}
+
+		Expect(problems()).To(ConsistOf(
+			"service offering some-marketplace-name: maintenance_info has no public or private entries",
+			"service offering some-marketplace-name: plan some-dedicated-name: maintenance_info public entry version has an empty value",
+			"service offering some-marketplace-name: plan some-dedicated-name: maintenance_info entry stemcell is both public and private",
+		))
+	})
+
+	It("reports post_bind errands when bindings are not retrievable", func() {
+		conf.ServiceCatalog.Plans[0].BindingErrands = &config.BindingErrands{
+			PostBind: []serviceadapter.Errand{{Name: "register"}},
+		}
+
+		Expect(problems()).To(ConsistOf(
+			"service offering some-marketplace-name: plan some-dedicated-name: post_bind errands require enable_bindings_retrievable to be set so that asynchronously created bindings can be fetched",
+		))
+	})
+
+	It("reports invalid service adapter timeouts", func() {
+		conf.ServiceAdapter.SubcommandTimeoutSecs = map[string]int{"bind": 30}
+
+		Expect(problems()).To(ConsistOf(
+			"service offering some-marketplace-name: service_adapter.subcommand_timeouts_in_seconds has unknown subcommand bind, must be one of generate-manifest, create-binding, delete-binding, get-binding, dashboard-url, generate-plan-schemas",
+		))
+	})
+})
@@ -61,6 +61,14 @@ func New(credhubClient CredhubClient) *Store {
 	return &Store{credhubClient: credhubClient}
 }
 
+func (c *Store) Get(key string) (interface{}, error) {
+	cred, err := c.credhubClient.GetLatestVersion(key)
+	if err != nil {
+		return nil, err
+	}
+	return cred.Value, nil
+}
+
 func (c *Store) Set(key string, value interface{}) error {
 	var err error
 	switch credValue := value.(type) {
@@ -198,6 +198,25 @@ var _ = Describe("CredStore", func() {
 		})
 	})
 
+	Describe("Get", func() {
+		It("returns the value of the latest version of the secret", func() {
+			secret := map[string]interface{}{"foo": "bar"}
+			fakeCredhubClient.GetLatestVersionReturns(credentials.Credential{Value: secret}, nil)
+
+			value, err := store.Get("/path/to/secret")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(secret))
+			Expect(fakeCredhubClient.GetLatestVersionCallCount()).To(Equal(1))
+			Expect(fakeCredhubClient.GetLatestVersionArgsForCall(0)).To(Equal("/path/to/secret"))
+		})
+
+		It("returns an error if the underlying call fails", func() {
+			fakeCredhubClient.GetLatestVersionReturns(credentials.Credential{}, errors.New("not found"))
+			_, err := store.Get("/path/to/secret")
+			Expect(err).To(MatchError("not found"))
+		})
+	})
+
 	Describe("Set", func() {
 		It("can set a json secret", func() {
 			secret := map[string]interface{}{}
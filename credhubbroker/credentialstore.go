@@ -19,6 +19,7 @@ import "code.cloudfoundry.org/credhub-cli/credhub/permissions"
 
 //go:generate counterfeiter -o fakes/credentialstore.go . CredentialStore
 type CredentialStore interface {
+	Get(key string) (interface{}, error)
 	Set(key string, value interface{}) error
 	Delete(key string) error
 	AddPermission(credentialName string, actor string, ops []string) (*permissions.Permission, error)
@@ -32,12 +32,14 @@ type CredHubBroker struct {
 	apiserver.CombinedBroker
 	credStore     CredentialStore
 	serviceName   string
+	serviceID     string
 	loggerFactory *loggerfactory.LoggerFactory
 }
 
 func New(broker apiserver.CombinedBroker,
 	credStore CredentialStore,
 	serviceName string,
+	serviceID string,
 	loggerFactory *loggerfactory.LoggerFactory,
 ) *CredHubBroker {
 
@@ -45,6 +47,7 @@ func New(broker apiserver.CombinedBroker,
 		CombinedBroker: broker,
 		credStore:      credStore,
 		serviceName:    serviceName,
+		serviceID:      serviceID,
 		loggerFactory:  loggerFactory,
 	}
 }
@@ -107,6 +110,29 @@ func (b *CredHubBroker) Unbind(ctx context.Context, instanceID, bindingID string
 	return unbind, nil
 }
 
+func (b *CredHubBroker) GetBinding(ctx context.Context, instanceID, bindingID string) (brokerapi.GetBindingSpec, error) {
+	requestID := uuid.New()
+	ctx = brokercontext.WithReqID(ctx, requestID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	binding, err := b.CombinedBroker.GetBinding(ctx, instanceID, bindingID)
+	if err != nil {
+		return brokerapi.GetBindingSpec{}, err
+	}
+
+	key := constructKey(b.serviceID, instanceID, bindingID)
+	logger.Printf("resolving credentials for instance ID: %s, with binding ID: %s", instanceID, bindingID)
+	if _, err := b.credStore.Get(key); err != nil {
+		ctx = brokercontext.New(ctx, "get-binding", requestID, b.serviceName, instanceID)
+		getErr := broker.NewGenericError(ctx, fmt.Errorf("failed to get credentials from credential store: %v", err))
+		logger.Print(getErr)
+		return brokerapi.GetBindingSpec{}, getErr.ErrorForCFUser()
+	}
+
+	binding.Credentials = map[string]string{"credhub-ref": key}
+	return binding, nil
+}
+
 func constructKey(serviceID, instanceID, bindingID string) string {
 	return fmt.Sprintf("/c/%s/%s/%s/credentials", serviceID, instanceID, bindingID)
 }
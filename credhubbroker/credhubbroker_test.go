@@ -43,6 +43,7 @@ var _ = Describe("CredHub broker", func() {
 		loggerFactory  *loggerfactory.LoggerFactory
 		requestIDRegex string
 		serviceName    string
+		serviceID      string
 	)
 
 	BeforeEach(func() {
@@ -50,8 +51,9 @@ var _ = Describe("CredHub broker", func() {
 		ctx = context.Background()
 		instanceID = "ohai"
 		bindingID = "rofl"
+		serviceID = "big-hybrid-cloud-of-things"
 		bindDetails = brokerapi.BindDetails{
-			ServiceID: "big-hybrid-cloud-of-things",
+			ServiceID: serviceID,
 		}
 		logBuffer = new(bytes.Buffer)
 		loggerFactory = loggerfactory.New(io.MultiWriter(GinkgoWriter, logBuffer), "credhubbroker-unit-test", loggerfactory.Flags)
@@ -71,7 +73,7 @@ var _ = Describe("CredHub broker", func() {
 			expectedBindingCredentials := map[string]string{"credhub-ref": credhubRef}
 
 			fakeCredStore := new(credfakes.FakeCredentialStore)
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 
 			bindDetails.AppGUID = "an-app"
 
@@ -110,7 +112,7 @@ var _ = Describe("CredHub broker", func() {
 			appGUID := "app-guid"
 			bindDetails.AppGUID = appGUID
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, false)
 
 			Expect(fakeCredStore.AddPermissionCallCount()).To(Equal(1))
@@ -137,7 +139,7 @@ var _ = Describe("CredHub broker", func() {
 			bindDetails.BindResource = &brokerapi.BindResource{}
 			bindDetails.BindResource.AppGuid = appGUID
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			_, err := credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, false)
 			Expect(err).NotTo(HaveOccurred())
 		})
@@ -155,7 +157,7 @@ var _ = Describe("CredHub broker", func() {
 				CredentialClientID: credentialClientID,
 			}
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, false)
 
 			Expect(fakeCredStore.AddPermissionCallCount()).To(Equal(1))
@@ -178,7 +180,7 @@ var _ = Describe("CredHub broker", func() {
 			}
 			fakeBroker.BindReturns(bindingResponse, nil)
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			_, err := credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, false)
 
 			Expect(err).To(MatchError(Equal("No app-guid or credential client ID were provided in the binding request, you must configure one of these")))
@@ -189,7 +191,7 @@ var _ = Describe("CredHub broker", func() {
 			emptyCreds := brokerapi.Binding{}
 			fakeBroker.BindReturns(emptyCreds, errors.New("error message from base broker"))
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			bindDetails.AppGUID = "some-app-guid"
 			receivedCreds, bindErr := credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, false)
 
@@ -204,7 +206,7 @@ var _ = Describe("CredHub broker", func() {
 			}
 			fakeBroker.BindReturns(bindingResponse, nil)
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			fakeCredStore.SetReturns(errors.New("credential store unavailable"))
 			bindDetails.AppGUID = "some-app-guid"
 			_, bindErr := credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, false)
@@ -231,7 +233,7 @@ var _ = Describe("CredHub broker", func() {
 			credhubRef := constructCredhubRef(unbindDetails.ServiceID, instanceID, bindingID)
 
 			fakeCredStore := new(credfakes.FakeCredentialStore)
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 
 			_, err := credhubBroker.Unbind(ctx, instanceID, bindingID, unbindDetails, false)
 
@@ -247,7 +249,7 @@ var _ = Describe("CredHub broker", func() {
 		It("sets a request ID and passes it through to the broker via the context", func() {
 			fakeCredStore := new(credfakes.FakeCredentialStore)
 
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 			credhubBroker.Unbind(ctx, instanceID, bindingID, unbindDetails, false)
 
 			brokerctx, _, _, _, _ := fakeBroker.UnbindArgsForCall(0)
@@ -262,7 +264,7 @@ var _ = Describe("CredHub broker", func() {
 			baseError := errors.New("foo")
 			fakeBroker.UnbindReturns(brokerapi.UnbindSpec{}, baseError)
 			fakeCredStore := new(credfakes.FakeCredentialStore)
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 
 			_, err := credhubBroker.Unbind(ctx, instanceID, bindingID, unbindDetails, false)
 			Expect(err).To(MatchError(baseError))
@@ -272,7 +274,7 @@ var _ = Describe("CredHub broker", func() {
 			credhubError := errors.New("foo")
 			fakeCredStore := new(credfakes.FakeCredentialStore)
 			fakeCredStore.DeleteReturns(credhubError)
-			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
 
 			_, err := credhubBroker.Unbind(ctx, instanceID, bindingID, unbindDetails, false)
 			Expect(err).ToNot(HaveOccurred())
@@ -280,6 +282,60 @@ var _ = Describe("CredHub broker", func() {
 			Expect(logBuffer.String()).To(ContainSubstring(fmt.Sprintf("WARNING: failed to remove key '%s'", credhubRef)))
 		})
 	})
+
+	Describe("GetBinding", func() {
+		var fakeCredStore *credfakes.FakeCredentialStore
+
+		BeforeEach(func() {
+			fakeCredStore = new(credfakes.FakeCredentialStore)
+			fakeCredStore.GetReturns(map[string]interface{}{"password": "secret"}, nil)
+			fakeBroker.GetBindingReturns(brokerapi.GetBindingSpec{
+				Credentials:    map[string]interface{}{"password": "secret"},
+				SyslogDrainURL: "syslog://drain",
+			}, nil)
+		})
+
+		It("resolves the stored credentials and returns the credhub reference", func() {
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
+
+			binding, err := credhubBroker.GetBinding(ctx, instanceID, bindingID)
+			Expect(err).NotTo(HaveOccurred())
+
+			credhubRef := constructCredhubRef(serviceID, instanceID, bindingID)
+			Expect(binding).To(Equal(brokerapi.GetBindingSpec{
+				Credentials:    map[string]string{"credhub-ref": credhubRef},
+				SyslogDrainURL: "syslog://drain",
+			}))
+
+			Expect(fakeBroker.GetBindingCallCount()).To(Equal(1))
+			_, actualInstanceID, actualBindingID := fakeBroker.GetBindingArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualBindingID).To(Equal(bindingID))
+
+			Expect(fakeCredStore.GetCallCount()).To(Equal(1))
+			Expect(fakeCredStore.GetArgsForCall(0)).To(Equal(credhubRef))
+		})
+
+		It("returns an error if the wrapped broker get binding call fails", func() {
+			baseError := errors.New("foo")
+			fakeBroker.GetBindingReturns(brokerapi.GetBindingSpec{}, baseError)
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
+
+			_, err := credhubBroker.GetBinding(ctx, instanceID, bindingID)
+			Expect(err).To(MatchError(baseError))
+			Expect(fakeCredStore.GetCallCount()).To(BeZero())
+		})
+
+		It("returns an error if the credentials cannot be resolved from credhub", func() {
+			fakeCredStore.GetReturns(nil, errors.New("credential does not exist"))
+			credhubBroker := credhubbroker.New(fakeBroker, fakeCredStore, serviceName, serviceID, loggerFactory)
+
+			_, err := credhubBroker.GetBinding(ctx, instanceID, bindingID)
+			Expect(err).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(MatchRegexp(requestIDRegex))
+			Expect(logBuffer.String()).To(ContainSubstring("failed to get credentials from credential store: credential does not exist"))
+		})
+	})
 })
 
 func constructCredhubRef(serviceID, instanceID, bindingID string) string {
@@ -0,0 +1,56 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"fmt"
+	"os"
+)
+
+//go:generate counterfeiter -o fakes/fake_approver.go . Approver
+type Approver interface {
+	Approved() (bool, error)
+	// String tells an operator how to approve.
+	String() string
+}
+
+// FileApprover approves once its file exists. The file is removed when the
+// approval is given, so that each approval is only used once.
+type FileApprover struct {
+	path string
+}
+
+func NewFileApprover(path string) *FileApprover {
+	return &FileApprover{path: path}
+}
+
+func (a *FileApprover) Approved() (bool, error) {
+	_, err := os.Stat(a.path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := os.Remove(a.path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *FileApprover) String() string {
+	return fmt.Sprintf("create %s to continue", a.path)
+}
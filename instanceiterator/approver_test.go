@@ -0,0 +1,62 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+)
+
+var _ = Describe("FileApprover", func() {
+	var (
+		dir      string
+		path     string
+		approver *instanceiterator.FileApprover
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "approver")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "approve")
+		approver = instanceiterator.NewFileApprover(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("is not approved until the file exists", func() {
+		Expect(approver.Approved()).To(BeFalse())
+	})
+
+	It("uses up the approval", func() {
+		Expect(ioutil.WriteFile(path, nil, 0644)).To(Succeed())
+
+		Expect(approver.Approved()).To(BeTrue())
+		Expect(path).NotTo(BeAnExistingFile())
+		Expect(approver.Approved()).To(BeFalse())
+	})
+
+	It("tells the operator which file to create", func() {
+		Expect(approver.String()).To(Equal("create " + path + " to continue"))
+	})
+})
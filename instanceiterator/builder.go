@@ -17,6 +17,7 @@ package instanceiterator
 
 import (
 	"log"
+	"os"
 	"time"
 
 	"crypto/x509"
@@ -40,11 +41,25 @@ type Builder struct {
 	AttemptInterval       time.Duration
 	AttemptLimit          int
 	MaxInFlight           int
+	MaxFailures           int
+	MaxFailurePercentage  int
+	PlanMaxInFlight       map[string]int
+	ConcurrencyLimits     []config.ConcurrencyLimit
 	Canaries              int
 	Listener              Listener
 	Sleeper               sleeper
 	Triggerer             Triggerer
 	CanarySelectionParams config.CanarySelectionParams
+	CanaryStages          []CanaryStage
+	Approver              Approver
+	Checkpointer          Checkpointer
+	Resume                bool
+	Operation             string
+	Clock                 clock
+
+	DefaultMaintenanceWindow *MaintenanceWindow
+	ScopedMaintenanceWindows []ScopedMaintenanceWindow
+	InstanceFilters          InstanceFilters
 }
 
 func NewBuilder(conf config.InstanceIteratorConfig, logger *log.Logger, logPrefix string) (*Builder, error) {
@@ -79,6 +94,16 @@ func NewBuilder(conf config.InstanceIteratorConfig, logger *log.Logger, logPrefi
 		return nil, err
 	}
 
+	maxFailures, maxFailurePercentage, err := failureBudget(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	planMaxInFlight, concurrencyLimits, err := concurrencyLimits(conf)
+	if err != nil {
+		return nil, err
+	}
+
 	canaries, err := canaries(conf)
 	if err != nil {
 		return nil, err
@@ -89,7 +114,29 @@ func NewBuilder(conf config.InstanceIteratorConfig, logger *log.Logger, logPrefi
 		return nil, err
 	}
 
+	canaryStages, err := canaryStages(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultWindow, scopedWindows, err := maintenanceWindows(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceFilters, err := NewInstanceFilters(conf.InstanceFilters)
+	if err != nil {
+		return nil, err
+	}
+
 	listener := NewLoggingListener(logger, logPrefix)
+	switch conf.JSONReportPath {
+	case "":
+	case config.JSONReportToStdout:
+		listener = NewJSONReportListener(listener, os.Stdout, logPrefix, logger, &tools.RealClock{})
+	default:
+		listener = NewJSONReportListener(listener, ReportFile(conf.JSONReportPath), logPrefix, logger, &tools.RealClock{})
+	}
 
 	b := &Builder{
 		BrokerServices:        brokerServices,
@@ -98,10 +145,28 @@ func NewBuilder(conf config.InstanceIteratorConfig, logger *log.Logger, logPrefi
 		AttemptInterval:       attemptInterval,
 		AttemptLimit:          attemptLimit,
 		MaxInFlight:           maxInFlight,
+		MaxFailures:           maxFailures,
+		MaxFailurePercentage:  maxFailurePercentage,
+		PlanMaxInFlight:       planMaxInFlight,
+		ConcurrencyLimits:     concurrencyLimits,
 		Canaries:              canaries,
 		Listener:              listener,
 		Sleeper:               &tools.RealSleeper{},
 		CanarySelectionParams: canarySelectionParams,
+		CanaryStages:          canaryStages,
+		Clock:                 &tools.RealClock{},
+
+		DefaultMaintenanceWindow: defaultWindow,
+		ScopedMaintenanceWindows: scopedWindows,
+		InstanceFilters:          instanceFilters,
+	}
+
+	if conf.ApprovalFile != "" {
+		b.Approver = NewFileApprover(conf.ApprovalFile)
+	}
+
+	if conf.StateFile != "" {
+		b.Checkpointer = NewFileCheckpointer(conf.StateFile)
 	}
 
 	return b, nil
@@ -112,6 +177,36 @@ func (b *Builder) SetUpgradeTriggerer() error {
 		return errors.New("unable to set triggerer, brokerServices must not be nil")
 	}
 	b.Triggerer = NewUpgradeTriggerer(b.BrokerServices)
+	b.Operation = "upgrade"
+	return nil
+}
+
+// SetUpgradePreviewTriggerer makes the iterator report the changes an upgrade
+// would make without upgrading anything. Nothing is deployed, so canaries and
+// maintenance windows are disabled and no state is saved for a later run to
+// resume from.
+func (b *Builder) SetUpgradePreviewTriggerer() error {
+	if b.BrokerServices == nil {
+		return errors.New("unable to set triggerer, brokerServices must not be nil")
+	}
+	b.Triggerer = NewUpgradePreviewTriggerer(b.BrokerServices, b.Listener)
+	b.Canaries = 0
+	b.CanarySelectionParams = nil
+	b.CanaryStages = nil
+	b.Checkpointer = nil
+	b.Resume = false
+	b.DefaultMaintenanceWindow = nil
+	b.ScopedMaintenanceWindows = nil
+	return nil
+}
+
+// EnableResume makes the iterator skip the instances that the previous run
+// processed, as recorded in the state file.
+func (b *Builder) EnableResume() error {
+	if b.Checkpointer == nil {
+		return errors.New("unable to resume, state_file must be configured")
+	}
+	b.Resume = true
 	return nil
 }
 
@@ -120,6 +215,7 @@ func (b *Builder) SetRecreateTriggerer() error {
 		return errors.New("unable to set triggerer, brokerServices must not be nil")
 	}
 	b.Triggerer = NewRecreateTriggerer(b.BrokerServices)
+	b.Operation = "recreate"
 	return nil
 }
 
@@ -202,6 +298,85 @@ func maxInFlight(conf config.InstanceIteratorConfig) (int, error) {
 	return conf.MaxInFlight, nil
 }
 
+// CanaryStage is a canary phase. Canaries, or CanaryPercentage of all the
+// instances, are picked from those matching SelectionParams; when neither is
+// set every matching instance is a canary.
+type CanaryStage struct {
+	Canaries         int
+	CanaryPercentage int
+	SelectionParams  config.CanarySelectionParams
+	Pause            time.Duration
+	RequireApproval  bool
+	ApprovalTimeout  time.Duration
+}
+
+func canaryStages(conf config.InstanceIteratorConfig) ([]CanaryStage, error) {
+	if len(conf.CanaryStages) > 0 && (conf.Canaries > 0 || len(conf.CanarySelectionParams) > 0) {
+		return nil, errors.New("canary_stages cannot be combined with canaries or canary_selection_params")
+	}
+
+	var stages []CanaryStage
+	for i, stage := range conf.CanaryStages {
+		switch {
+		case stage.Canaries < 0:
+			return nil, fmt.Errorf("the number of canaries in canary stage %d cannot be negative", i+1)
+		case stage.CanaryPercentage < 0 || stage.CanaryPercentage > 100:
+			return nil, fmt.Errorf("the canary percentage in canary stage %d must be between 0 and 100", i+1)
+		case stage.Canaries > 0 && stage.CanaryPercentage > 0:
+			return nil, fmt.Errorf("only one of canaries and canary percentage can be set in canary stage %d", i+1)
+		case stage.Canaries == 0 && stage.CanaryPercentage == 0 && len(stage.SelectionParams) == 0:
+			return nil, fmt.Errorf("canary stage %d must set canaries, canary percentage or selection params", i+1)
+		case stage.Pause < 0:
+			return nil, fmt.Errorf("the pause after canary stage %d cannot be negative", i+1)
+		case stage.RequireApproval && conf.ApprovalFile == "":
+			return nil, fmt.Errorf("canary stage %d requires approval, approval_file must be configured", i+1)
+		case stage.ApprovalTimeout < 0:
+			return nil, fmt.Errorf("the approval timeout of canary stage %d cannot be negative", i+1)
+		case stage.ApprovalTimeout > 0 && !stage.RequireApproval:
+			return nil, fmt.Errorf("canary stage %d sets an approval timeout but does not require approval", i+1)
+		}
+		stages = append(stages, CanaryStage{
+			Canaries:         stage.Canaries,
+			CanaryPercentage: stage.CanaryPercentage,
+			SelectionParams:  stage.SelectionParams,
+			Pause:            time.Duration(stage.Pause) * time.Second,
+			RequireApproval:  stage.RequireApproval,
+			ApprovalTimeout:  time.Duration(stage.ApprovalTimeout) * time.Second,
+		})
+	}
+	return stages, nil
+}
+
+func concurrencyLimits(conf config.InstanceIteratorConfig) (map[string]int, []config.ConcurrencyLimit, error) {
+	for planID, planMaxInFlight := range conf.PlanMaxInFlight {
+		if planMaxInFlight <= 0 {
+			return nil, nil, fmt.Errorf("the max in flight for plan %s must be greater than zero", planID)
+		}
+	}
+	for i, limit := range conf.ConcurrencyLimits {
+		if len(limit.SelectionParams) == 0 {
+			return nil, nil, fmt.Errorf("concurrency limit %d has no selection params", i)
+		}
+		if limit.MaxInFlight <= 0 {
+			return nil, nil, fmt.Errorf("the max in flight for concurrency limit %d must be greater than zero", i)
+		}
+	}
+	return conf.PlanMaxInFlight, conf.ConcurrencyLimits, nil
+}
+
+func failureBudget(conf config.InstanceIteratorConfig) (int, int, error) {
+	if conf.MaxFailures < 0 {
+		return 0, 0, errors.New("the max failures cannot be negative")
+	}
+	if conf.MaxFailurePercentage < 0 || conf.MaxFailurePercentage > 100 {
+		return 0, 0, errors.New("the max failure percentage must be between 0 and 100")
+	}
+	if conf.MaxFailures > 0 && conf.MaxFailurePercentage > 0 {
+		return 0, 0, errors.New("only one of max failures and max failure percentage can be set")
+	}
+	return conf.MaxFailures, conf.MaxFailurePercentage, nil
+}
+
 func canaries(conf config.InstanceIteratorConfig) (int, error) {
 	if conf.Canaries < 0 {
 		return 0, errors.New("the number of canaries cannot be negative")
@@ -212,3 +387,27 @@ func canaries(conf config.InstanceIteratorConfig) (int, error) {
 func canarySelectionParams(conf config.InstanceIteratorConfig) (config.CanarySelectionParams, error) {
 	return conf.CanarySelectionParams, nil
 }
+
+func maintenanceWindows(conf config.InstanceIteratorConfig) (*MaintenanceWindow, []ScopedMaintenanceWindow, error) {
+	var defaultWindow *MaintenanceWindow
+	if conf.MaintenanceWindows.Default != nil {
+		window, err := NewMaintenanceWindow(*conf.MaintenanceWindows.Default)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid default maintenance window: %s", err)
+		}
+		defaultWindow = &window
+	}
+
+	var scopedWindows []ScopedMaintenanceWindow
+	for i, override := range conf.MaintenanceWindows.Overrides {
+		if len(override.SelectionParams) == 0 {
+			return nil, nil, fmt.Errorf("maintenance window override %d has no selection params", i)
+		}
+		window, err := NewMaintenanceWindow(override.MaintenanceWindow)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid maintenance window override %d: %s", i, err)
+		}
+		scopedWindows = append(scopedWindows, ScopedMaintenanceWindow{MaintenanceWindow: window, SelectionParams: override.SelectionParams})
+	}
+	return defaultWindow, scopedWindows, nil
+}
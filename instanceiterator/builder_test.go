@@ -16,6 +16,8 @@
 package instanceiterator_test
 
 import (
+	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
@@ -173,6 +175,129 @@ var _ = Describe("Builder", func() {
 		})
 	})
 
+	Describe("Canary stages", func() {
+		It("when configured returns the stages and an approver", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.ApprovalFile = "/tmp/approve"
+			conf.CanaryStages = []config.CanaryStage{
+				{Canaries: 1, SelectionParams: config.CanarySelectionParams{"cf_org": "platform"}, Pause: 60},
+				{CanaryPercentage: 5, RequireApproval: true, ApprovalTimeout: 3600},
+			}
+
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.CanaryStages).To(Equal([]instanceiterator.CanaryStage{
+				{Canaries: 1, SelectionParams: config.CanarySelectionParams{"cf_org": "platform"}, Pause: time.Minute},
+				{CanaryPercentage: 5, RequireApproval: true, ApprovalTimeout: time.Hour},
+			}))
+			Expect(builder.Approver).To(Equal(instanceiterator.NewFileApprover("/tmp/approve")))
+		})
+
+		DescribeTable("returns an error when invalid",
+			func(conf config.InstanceIteratorConfig, expectedErr string) {
+				errandConf := makeErrandConfig("user", "password", "http://example.org")
+				errandConf.Canaries = conf.Canaries
+				errandConf.CanaryStages = conf.CanaryStages
+				_, err := instanceiterator.NewBuilder(errandConf, logger, logPrefix)
+				Expect(err).To(MatchError(expectedErr))
+			},
+			Entry("combined with canaries",
+				config.InstanceIteratorConfig{Canaries: 1, CanaryStages: []config.CanaryStage{{Canaries: 1}}},
+				"canary_stages cannot be combined with canaries or canary_selection_params"),
+			Entry("negative canaries",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: -1}}},
+				"the number of canaries in canary stage 1 cannot be negative"),
+			Entry("percentage over 100",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: 1}, {CanaryPercentage: 101}}},
+				"the canary percentage in canary stage 2 must be between 0 and 100"),
+			Entry("canaries and percentage",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: 1, CanaryPercentage: 5}}},
+				"only one of canaries and canary percentage can be set in canary stage 1"),
+			Entry("nothing selected",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Pause: 10}}},
+				"canary stage 1 must set canaries, canary percentage or selection params"),
+			Entry("negative pause",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: 1, Pause: -1}}},
+				"the pause after canary stage 1 cannot be negative"),
+			Entry("approval without an approval file",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: 1, RequireApproval: true}}},
+				"canary stage 1 requires approval, approval_file must be configured"),
+			Entry("negative approval timeout",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: 1, ApprovalTimeout: -1}}},
+				"the approval timeout of canary stage 1 cannot be negative"),
+			Entry("approval timeout without approval",
+				config.InstanceIteratorConfig{CanaryStages: []config.CanaryStage{{Canaries: 1, ApprovalTimeout: 60}}},
+				"canary stage 1 sets an approval timeout but does not require approval"),
+		)
+	})
+
+	Describe("Concurrency limits", func() {
+		It("when configured returns the values", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.PlanMaxInFlight = map[string]int{"small": 10}
+			conf.ConcurrencyLimits = []config.ConcurrencyLimit{{SelectionParams: map[string]string{"cf_org": "org", "cf_space": "space"}, MaxInFlight: 2}}
+
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.PlanMaxInFlight).To(Equal(map[string]int{"small": 10}))
+			Expect(builder.ConcurrencyLimits).To(Equal(conf.ConcurrencyLimits))
+		})
+
+		It("returns an error when a plan max in flight is not positive", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.PlanMaxInFlight = map[string]int{"small": 0}
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError("the max in flight for plan small must be greater than zero"))
+		})
+
+		It("returns an error when a concurrency limit has no selection params", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.ConcurrencyLimits = []config.ConcurrencyLimit{{MaxInFlight: 2}}
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError("concurrency limit 0 has no selection params"))
+		})
+
+		It("returns an error when a concurrency limit max in flight is not positive", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.ConcurrencyLimits = []config.ConcurrencyLimit{{SelectionParams: map[string]string{"cf_org": "org", "cf_space": "space"}}}
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError("the max in flight for concurrency limit 0 must be greater than zero"))
+		})
+	})
+
+	Describe("Failure budget", func() {
+		It("is zero when not configured", func() {
+			builder, err := instanceiterator.NewBuilder(makeErrandConfig("user", "password", "http://example.org"), logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.MaxFailures).To(BeZero())
+			Expect(builder.MaxFailurePercentage).To(BeZero())
+		})
+
+		It("when configured returns the value", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.MaxFailurePercentage = 10
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.MaxFailurePercentage).To(Equal(10))
+		})
+
+		DescribeTable("returns an error when invalid",
+			func(maxFailures, maxFailurePercentage int, expectedErr string) {
+				conf := makeErrandConfig("user", "password", "http://example.org")
+				conf.MaxFailures = maxFailures
+				conf.MaxFailurePercentage = maxFailurePercentage
+				_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+				Expect(err).To(MatchError(expectedErr))
+			},
+			Entry("negative max failures", -1, 0, "the max failures cannot be negative"),
+			Entry("percentage over 100", 0, 101, "the max failure percentage must be between 0 and 100"),
+			Entry("both set", 1, 10, "only one of max failures and max failure percentage can be set"),
+		)
+	})
+
 	Describe("Canaries", func() {
 		DescribeTable(
 			"config is invalidly set to",
@@ -219,6 +344,7 @@ var _ = Describe("Builder", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(builder.Triggerer).ToNot(BeNil())
 			Expect(builder.Triggerer).To(BeAssignableToTypeOf(new(instanceiterator.UpgradeTriggerer)))
+			Expect(builder.Operation).To(Equal("upgrade"))
 		})
 
 		It("returns an error when builder not properly initialised", func() {
@@ -229,6 +355,165 @@ var _ = Describe("Builder", func() {
 		})
 	})
 
+	Describe("SetUpgradePreviewTriggerer", func() {
+		It("sets an upgrade preview triggerer and disables canaries", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.Canaries = 2
+			conf.CanarySelectionParams = config.CanarySelectionParams{"org": "my-org"}
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = builder.SetUpgradePreviewTriggerer()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.Triggerer).To(BeAssignableToTypeOf(new(instanceiterator.UpgradePreviewTriggerer)))
+			Expect(builder.Canaries).To(BeZero())
+			Expect(builder.CanarySelectionParams).To(BeEmpty())
+		})
+
+		It("returns an error when builder not properly initialised", func() {
+			builder := new(instanceiterator.Builder)
+
+			err := builder.SetUpgradePreviewTriggerer()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("resuming", func() {
+		It("saves state to the configured state file", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.StateFile = "/var/vcap/store/upgrade-all/state.json"
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.Checkpointer).To(Equal(instanceiterator.NewFileCheckpointer("/var/vcap/store/upgrade-all/state.json")))
+			Expect(builder.EnableResume()).To(Succeed())
+			Expect(builder.Resume).To(BeTrue())
+		})
+
+		It("cannot resume when no state file is configured", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.Checkpointer).To(BeNil())
+			Expect(builder.EnableResume()).To(MatchError("unable to resume, state_file must be configured"))
+		})
+
+		It("does not save state when previewing upgrades", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.StateFile = "/var/vcap/store/upgrade-all/state.json"
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.EnableResume()).To(Succeed())
+
+			Expect(builder.SetUpgradePreviewTriggerer()).To(Succeed())
+			Expect(builder.Checkpointer).To(BeNil())
+			Expect(builder.Resume).To(BeFalse())
+		})
+	})
+
+	Describe("Maintenance windows", func() {
+		It("parses the default window and the overrides", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.MaintenanceWindows = config.MaintenanceWindows{
+				Default: &config.MaintenanceWindow{Start: "22:00", End: "06:00", Timezone: "UTC"},
+				Overrides: []config.MaintenanceWindowOverride{{
+					MaintenanceWindow: config.MaintenanceWindow{Start: "01:00", End: "04:30", Timezone: "Asia/Tokyo"},
+					SelectionParams:   map[string]string{"cf_org": "org", "cf_space": "space"},
+				}},
+			}
+
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.DefaultMaintenanceWindow.String()).To(Equal("22:00-06:00 UTC"))
+			Expect(builder.ScopedMaintenanceWindows).To(HaveLen(1))
+			Expect(builder.ScopedMaintenanceWindows[0].String()).To(Equal("01:00-04:30 Asia/Tokyo"))
+			Expect(builder.ScopedMaintenanceWindows[0].SelectionParams).To(Equal(map[string]string{"cf_org": "org", "cf_space": "space"}))
+		})
+
+		It("has no windows when none are configured", func() {
+			builder, err := instanceiterator.NewBuilder(makeErrandConfig("user", "password", "http://example.org"), logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.DefaultMaintenanceWindow).To(BeNil())
+			Expect(builder.ScopedMaintenanceWindows).To(BeEmpty())
+		})
+
+		It("returns an error when the default window is invalid", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.MaintenanceWindows.Default = &config.MaintenanceWindow{Start: "10pm", End: "06:00", Timezone: "UTC"}
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError(`invalid default maintenance window: invalid maintenance window start: "10pm" is not a HH:MM time of day`))
+		})
+
+		It("returns an error when an override has no selection params", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.MaintenanceWindows.Overrides = []config.MaintenanceWindowOverride{{
+				MaintenanceWindow: config.MaintenanceWindow{Start: "22:00", End: "06:00", Timezone: "UTC"},
+			}}
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError("maintenance window override 0 has no selection params"))
+		})
+
+		It("ignores maintenance windows when previewing upgrades", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.MaintenanceWindows.Default = &config.MaintenanceWindow{Start: "22:00", End: "06:00", Timezone: "UTC"}
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.SetUpgradePreviewTriggerer()).To(Succeed())
+			Expect(builder.DefaultMaintenanceWindow).To(BeNil())
+			Expect(builder.ScopedMaintenanceWindows).To(BeNil())
+		})
+	})
+
+	Describe("Instance filters", func() {
+		It("combines the configured GUIDs with those in the GUIDs file", func() {
+			guidsFile, err := ioutil.TempFile("", "instance-guids")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(guidsFile.Name())
+			_, err = guidsFile.WriteString("# business unit a\nguid-2\n\n  guid-3  \n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guidsFile.Close()).To(Succeed())
+
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.InstanceFilters = config.InstanceFilters{
+				Include: config.InstanceFilter{
+					PlanIDs:           []string{"plan-a"},
+					InstanceGUIDs:     []string{"guid-1"},
+					InstanceGUIDsFile: guidsFile.Name(),
+				},
+				Exclude: config.InstanceFilter{
+					SelectionParams: []map[string]string{{"cf_org": "org", "cf_space": "space"}},
+				},
+			}
+
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.InstanceFilters).To(Equal(instanceiterator.InstanceFilters{
+				Include: instanceiterator.InstanceFilter{PlanIDs: []string{"plan-a"}, GUIDs: []string{"guid-1", "guid-2", "guid-3"}},
+				Exclude: instanceiterator.InstanceFilter{SelectionParams: []map[string]string{{"cf_org": "org", "cf_space": "space"}}},
+			}))
+		})
+
+		It("returns an error when the GUIDs file cannot be read", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.InstanceFilters.Exclude.InstanceGUIDsFile = "/not/a/file"
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError(ContainSubstring("invalid exclude filter: error reading instance GUIDs file: open /not/a/file")))
+		})
+
+		It("returns an error when selection params are empty", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.InstanceFilters.Include.SelectionParams = []map[string]string{{}}
+
+			_, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).To(MatchError("invalid include filter: selection params 0 are empty"))
+		})
+	})
+
 	Describe("SetRecreateTriggerer", func() {
 		It("sets a recreate triggerer on a properly initiated builder", func() {
 			conf := makeErrandConfig("user", "password", "http://example.org")
@@ -239,6 +524,7 @@ var _ = Describe("Builder", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(builder.Triggerer).ToNot(BeNil())
 			Expect(builder.Triggerer).To(BeAssignableToTypeOf(new(instanceiterator.RecreateTriggerer)))
+			Expect(builder.Operation).To(Equal("recreate"))
 		})
 
 		It("returns an error when builder not properly initialised", func() {
@@ -249,6 +535,25 @@ var _ = Describe("Builder", func() {
 		})
 	})
 
+	Describe("JSON report", func() {
+		It("only logs when no report path is configured", func() {
+			builder, err := instanceiterator.NewBuilder(makeErrandConfig("user", "password", "http://example.org"), logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.Listener).To(BeAssignableToTypeOf(instanceiterator.LoggingListener{}))
+		})
+
+		It("reports to the configured path as well as logging", func() {
+			conf := makeErrandConfig("user", "password", "http://example.org")
+			conf.JSONReportPath = "/tmp/report.json"
+			builder, err := instanceiterator.NewBuilder(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builder.Listener).To(BeAssignableToTypeOf(new(instanceiterator.JSONReportListener)))
+
+			builder.Listener.CanariesFinished()
+			Expect(logBuffer).To(gbytes.Say("FINISHED CANARIES"))
+		})
+	})
+
 	Describe("passing logging prefix into builder", func() {
 		It("sets an appropriately configured logger on the builder", func() {
 			conf := makeErrandConfig("user", "password", "http://example.org")
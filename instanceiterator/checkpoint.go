@@ -0,0 +1,112 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+)
+
+//go:generate counterfeiter -o fakes/fake_checkpointer.go . Checkpointer
+type Checkpointer interface {
+	Load() (*Checkpoint, error)
+	Save(checkpoint Checkpoint) error
+}
+
+// RunIdentity records what a run was doing, so that a resumed run does not
+// skip instances that were processed for a different operation or against a
+// different version of the service offering.
+type RunIdentity struct {
+	Operation       string                               `json:"operation"`
+	MaintenanceInfo map[string]brokerapi.MaintenanceInfo `json:"maintenance_info"`
+}
+
+func (r RunIdentity) Matches(other RunIdentity) bool {
+	return r.Operation == other.Operation && reflect.DeepEqual(r.MaintenanceInfo, other.MaintenanceInfo)
+}
+
+type Checkpoint struct {
+	Run       RunIdentity                           `json:"run"`
+	Finished  bool                                  `json:"finished"`
+	Instances map[string]services.BOSHOperationType `json:"instances"`
+}
+
+// FileCheckpointer keeps the state of each instance in a JSON file, so that an
+// interrupted run can be resumed.
+type FileCheckpointer struct {
+	path string
+}
+
+func NewFileCheckpointer(path string) *FileCheckpointer {
+	return &FileCheckpointer{path: path}
+}
+
+// Load returns the checkpoint saved by the previous run, or nil when there
+// was no previous run.
+func (c *FileCheckpointer) Load() (*Checkpoint, error) {
+	contents, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved Checkpoint
+	if err := json.Unmarshal(contents, &saved); err != nil {
+		return nil, err
+	}
+	if saved.Instances == nil {
+		saved.Instances = map[string]services.BOSHOperationType{}
+	}
+	return &saved, nil
+}
+
+// Save replaces the state file. It writes to a temporary file first, so that
+// the previous states survive the process dying part way through a write.
+func (c *FileCheckpointer) Save(checkpoint Checkpoint) error {
+	contents, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), c.path)
+}
+
+func isCompletedState(state services.BOSHOperationType) bool {
+	return state == services.OperationSucceeded ||
+		state == services.OrphanDeployment ||
+		state == services.InstanceNotFound
+}
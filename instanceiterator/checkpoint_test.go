@@ -0,0 +1,140 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+)
+
+var _ = Describe("FileCheckpointer", func() {
+	var (
+		stateDir     string
+		stateFile    string
+		checkpointer *instanceiterator.FileCheckpointer
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "checkpoint")
+		Expect(err).NotTo(HaveOccurred())
+		stateFile = filepath.Join(stateDir, "state.json")
+		checkpointer = instanceiterator.NewFileCheckpointer(stateFile)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	It("loads no checkpoint when nothing has been saved", func() {
+		checkpoint, err := checkpointer.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint).To(BeNil())
+	})
+
+	It("loads the checkpoint that was saved", func() {
+		saved := instanceiterator.Checkpoint{
+			Run: instanceiterator.RunIdentity{
+				Operation:       "upgrade",
+				MaintenanceInfo: map[string]brokerapi.MaintenanceInfo{"plan-id": {Private: "version-1"}},
+			},
+			Finished: true,
+			Instances: map[string]services.BOSHOperationType{
+				"1": services.OperationSucceeded,
+				"2": services.OperationFailed,
+			},
+		}
+		Expect(checkpointer.Save(saved)).To(Succeed())
+
+		checkpoint, err := checkpointer.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*checkpoint).To(Equal(saved))
+	})
+
+	It("replaces the previously saved checkpoint without leaving temporary files", func() {
+		Expect(checkpointer.Save(instanceiterator.Checkpoint{
+			Instances: map[string]services.BOSHOperationType{"1": services.OperationPending},
+		})).To(Succeed())
+		Expect(checkpointer.Save(instanceiterator.Checkpoint{
+			Instances: map[string]services.BOSHOperationType{"2": services.OperationSucceeded},
+		})).To(Succeed())
+
+		checkpoint, err := checkpointer.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint.Instances).To(Equal(map[string]services.BOSHOperationType{"2": services.OperationSucceeded}))
+
+		files, err := ioutil.ReadDir(stateDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+	})
+
+	It("loads a state file without a run identity, as saved by earlier versions", func() {
+		Expect(ioutil.WriteFile(stateFile, []byte(`{"instances":{"1":"succeeded"}}`), 0644)).To(Succeed())
+
+		checkpoint, err := checkpointer.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint.Run).To(Equal(instanceiterator.RunIdentity{}))
+		Expect(checkpoint.Instances).To(HaveLen(1))
+	})
+
+	It("returns an error when the state file is corrupt", func() {
+		Expect(ioutil.WriteFile(stateFile, []byte("not json"), 0644)).To(Succeed())
+
+		_, err := checkpointer.Load()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the state file cannot be written", func() {
+		checkpointer = instanceiterator.NewFileCheckpointer(filepath.Join(stateDir, "missing", "state.json"))
+
+		err := checkpointer.Save(instanceiterator.Checkpoint{})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("RunIdentity", func() {
+	run := instanceiterator.RunIdentity{
+		Operation:       "upgrade",
+		MaintenanceInfo: map[string]brokerapi.MaintenanceInfo{"plan-id": {Public: map[string]string{"version": "1"}}},
+	}
+
+	It("matches a run of the same operation against the same plans", func() {
+		other := instanceiterator.RunIdentity{
+			Operation:       "upgrade",
+			MaintenanceInfo: map[string]brokerapi.MaintenanceInfo{"plan-id": {Public: map[string]string{"version": "1"}}},
+		}
+		Expect(run.Matches(other)).To(BeTrue())
+	})
+
+	It("does not match a run of a different operation", func() {
+		other := run
+		other.Operation = "recreate"
+		Expect(run.Matches(other)).To(BeFalse())
+	})
+
+	It("does not match a run against a different version of a plan", func() {
+		other := run
+		other.MaintenanceInfo = map[string]brokerapi.MaintenanceInfo{"plan-id": {Public: map[string]string{"version": "2"}}}
+		Expect(run.Matches(other)).To(BeFalse())
+	})
+})
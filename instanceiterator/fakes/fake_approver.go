@@ -0,0 +1,172 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+)
+
+type FakeApprover struct {
+	ApprovedStub        func() (bool, error)
+	approvedMutex       sync.RWMutex
+	approvedArgsForCall []struct {
+	}
+	approvedReturns struct {
+		result1 bool
+		result2 error
+	}
+	approvedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	StringStub        func() string
+	stringMutex       sync.RWMutex
+	stringArgsForCall []struct {
+	}
+	stringReturns struct {
+		result1 string
+	}
+	stringReturnsOnCall map[int]struct {
+		result1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeApprover) Approved() (bool, error) {
+	fake.approvedMutex.Lock()
+	ret, specificReturn := fake.approvedReturnsOnCall[len(fake.approvedArgsForCall)]
+	fake.approvedArgsForCall = append(fake.approvedArgsForCall, struct {
+	}{})
+	stub := fake.ApprovedStub
+	fakeReturns := fake.approvedReturns
+	fake.recordInvocation("Approved", []interface{}{})
+	fake.approvedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeApprover) ApprovedCallCount() int {
+	fake.approvedMutex.RLock()
+	defer fake.approvedMutex.RUnlock()
+	return len(fake.approvedArgsForCall)
+}
+
+func (fake *FakeApprover) ApprovedCalls(stub func() (bool, error)) {
+	fake.approvedMutex.Lock()
+	defer fake.approvedMutex.Unlock()
+	fake.ApprovedStub = stub
+}
+
+func (fake *FakeApprover) ApprovedReturns(result1 bool, result2 error) {
+	fake.approvedMutex.Lock()
+	defer fake.approvedMutex.Unlock()
+	fake.ApprovedStub = nil
+	fake.approvedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApprover) ApprovedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.approvedMutex.Lock()
+	defer fake.approvedMutex.Unlock()
+	fake.ApprovedStub = nil
+	if fake.approvedReturnsOnCall == nil {
+		fake.approvedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.approvedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApprover) String() string {
+	fake.stringMutex.Lock()
+	ret, specificReturn := fake.stringReturnsOnCall[len(fake.stringArgsForCall)]
+	fake.stringArgsForCall = append(fake.stringArgsForCall, struct {
+	}{})
+	stub := fake.StringStub
+	fakeReturns := fake.stringReturns
+	fake.recordInvocation("String", []interface{}{})
+	fake.stringMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeApprover) StringCallCount() int {
+	fake.stringMutex.RLock()
+	defer fake.stringMutex.RUnlock()
+	return len(fake.stringArgsForCall)
+}
+
+func (fake *FakeApprover) StringCalls(stub func() string) {
+	fake.stringMutex.Lock()
+	defer fake.stringMutex.Unlock()
+	fake.StringStub = stub
+}
+
+func (fake *FakeApprover) StringReturns(result1 string) {
+	fake.stringMutex.Lock()
+	defer fake.stringMutex.Unlock()
+	fake.StringStub = nil
+	fake.stringReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeApprover) StringReturnsOnCall(i int, result1 string) {
+	fake.stringMutex.Lock()
+	defer fake.stringMutex.Unlock()
+	fake.StringStub = nil
+	if fake.stringReturnsOnCall == nil {
+		fake.stringReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.stringReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeApprover) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.approvedMutex.RLock()
+	defer fake.approvedMutex.RUnlock()
+	fake.stringMutex.RLock()
+	defer fake.stringMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeApprover) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ instanceiterator.Approver = new(FakeApprover)
@@ -12,25 +12,23 @@ import (
 )
 
 type FakeBrokerServices struct {
-	ProcessInstanceStub        func(instance service.Instance, operationType string) (services.BOSHOperation, error)
-	processInstanceMutex       sync.RWMutex
-	processInstanceArgsForCall []struct {
-		instance      service.Instance
-		operationType string
+	CatalogStub        func() (brokerapi.CatalogResponse, error)
+	catalogMutex       sync.RWMutex
+	catalogArgsForCall []struct {
 	}
-	processInstanceReturns struct {
-		result1 services.BOSHOperation
+	catalogReturns struct {
+		result1 brokerapi.CatalogResponse
 		result2 error
 	}
-	processInstanceReturnsOnCall map[int]struct {
-		result1 services.BOSHOperation
+	catalogReturnsOnCall map[int]struct {
+		result1 brokerapi.CatalogResponse
 		result2 error
 	}
-	LastOperationStub        func(instance string, operationData broker.OperationData) (brokerapi.LastOperation, error)
+	LastOperationStub        func(string, broker.OperationData) (brokerapi.LastOperation, error)
 	lastOperationMutex       sync.RWMutex
 	lastOperationArgsForCall []struct {
-		instance      string
-		operationData broker.OperationData
+		arg1 string
+		arg2 broker.OperationData
 	}
 	lastOperationReturns struct {
 		result1 brokerapi.LastOperation
@@ -40,78 +38,113 @@ type FakeBrokerServices struct {
 		result1 brokerapi.LastOperation
 		result2 error
 	}
+	PreviewUpgradeStub        func(service.Instance) (services.BOSHOperation, broker.UpgradePreview, error)
+	previewUpgradeMutex       sync.RWMutex
+	previewUpgradeArgsForCall []struct {
+		arg1 service.Instance
+	}
+	previewUpgradeReturns struct {
+		result1 services.BOSHOperation
+		result2 broker.UpgradePreview
+		result3 error
+	}
+	previewUpgradeReturnsOnCall map[int]struct {
+		result1 services.BOSHOperation
+		result2 broker.UpgradePreview
+		result3 error
+	}
+	ProcessInstanceStub        func(service.Instance, string) (services.BOSHOperation, error)
+	processInstanceMutex       sync.RWMutex
+	processInstanceArgsForCall []struct {
+		arg1 service.Instance
+		arg2 string
+	}
+	processInstanceReturns struct {
+		result1 services.BOSHOperation
+		result2 error
+	}
+	processInstanceReturnsOnCall map[int]struct {
+		result1 services.BOSHOperation
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBrokerServices) ProcessInstance(instance service.Instance, operationType string) (services.BOSHOperation, error) {
-	fake.processInstanceMutex.Lock()
-	ret, specificReturn := fake.processInstanceReturnsOnCall[len(fake.processInstanceArgsForCall)]
-	fake.processInstanceArgsForCall = append(fake.processInstanceArgsForCall, struct {
-		instance      service.Instance
-		operationType string
-	}{instance, operationType})
-	fake.recordInvocation("ProcessInstance", []interface{}{instance, operationType})
-	fake.processInstanceMutex.Unlock()
-	if fake.ProcessInstanceStub != nil {
-		return fake.ProcessInstanceStub(instance, operationType)
+func (fake *FakeBrokerServices) Catalog() (brokerapi.CatalogResponse, error) {
+	fake.catalogMutex.Lock()
+	ret, specificReturn := fake.catalogReturnsOnCall[len(fake.catalogArgsForCall)]
+	fake.catalogArgsForCall = append(fake.catalogArgsForCall, struct {
+	}{})
+	stub := fake.CatalogStub
+	fakeReturns := fake.catalogReturns
+	fake.recordInvocation("Catalog", []interface{}{})
+	fake.catalogMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.processInstanceReturns.result1, fake.processInstanceReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeBrokerServices) ProcessInstanceCallCount() int {
-	fake.processInstanceMutex.RLock()
-	defer fake.processInstanceMutex.RUnlock()
-	return len(fake.processInstanceArgsForCall)
+func (fake *FakeBrokerServices) CatalogCallCount() int {
+	fake.catalogMutex.RLock()
+	defer fake.catalogMutex.RUnlock()
+	return len(fake.catalogArgsForCall)
 }
 
-func (fake *FakeBrokerServices) ProcessInstanceArgsForCall(i int) (service.Instance, string) {
-	fake.processInstanceMutex.RLock()
-	defer fake.processInstanceMutex.RUnlock()
-	return fake.processInstanceArgsForCall[i].instance, fake.processInstanceArgsForCall[i].operationType
+func (fake *FakeBrokerServices) CatalogCalls(stub func() (brokerapi.CatalogResponse, error)) {
+	fake.catalogMutex.Lock()
+	defer fake.catalogMutex.Unlock()
+	fake.CatalogStub = stub
 }
 
-func (fake *FakeBrokerServices) ProcessInstanceReturns(result1 services.BOSHOperation, result2 error) {
-	fake.ProcessInstanceStub = nil
-	fake.processInstanceReturns = struct {
-		result1 services.BOSHOperation
+func (fake *FakeBrokerServices) CatalogReturns(result1 brokerapi.CatalogResponse, result2 error) {
+	fake.catalogMutex.Lock()
+	defer fake.catalogMutex.Unlock()
+	fake.CatalogStub = nil
+	fake.catalogReturns = struct {
+		result1 brokerapi.CatalogResponse
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeBrokerServices) ProcessInstanceReturnsOnCall(i int, result1 services.BOSHOperation, result2 error) {
-	fake.ProcessInstanceStub = nil
-	if fake.processInstanceReturnsOnCall == nil {
-		fake.processInstanceReturnsOnCall = make(map[int]struct {
-			result1 services.BOSHOperation
+func (fake *FakeBrokerServices) CatalogReturnsOnCall(i int, result1 brokerapi.CatalogResponse, result2 error) {
+	fake.catalogMutex.Lock()
+	defer fake.catalogMutex.Unlock()
+	fake.CatalogStub = nil
+	if fake.catalogReturnsOnCall == nil {
+		fake.catalogReturnsOnCall = make(map[int]struct {
+			result1 brokerapi.CatalogResponse
 			result2 error
 		})
 	}
-	fake.processInstanceReturnsOnCall[i] = struct {
-		result1 services.BOSHOperation
+	fake.catalogReturnsOnCall[i] = struct {
+		result1 brokerapi.CatalogResponse
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeBrokerServices) LastOperation(instance string, operationData broker.OperationData) (brokerapi.LastOperation, error) {
+func (fake *FakeBrokerServices) LastOperation(arg1 string, arg2 broker.OperationData) (brokerapi.LastOperation, error) {
 	fake.lastOperationMutex.Lock()
 	ret, specificReturn := fake.lastOperationReturnsOnCall[len(fake.lastOperationArgsForCall)]
 	fake.lastOperationArgsForCall = append(fake.lastOperationArgsForCall, struct {
-		instance      string
-		operationData broker.OperationData
-	}{instance, operationData})
-	fake.recordInvocation("LastOperation", []interface{}{instance, operationData})
+		arg1 string
+		arg2 broker.OperationData
+	}{arg1, arg2})
+	stub := fake.LastOperationStub
+	fakeReturns := fake.lastOperationReturns
+	fake.recordInvocation("LastOperation", []interface{}{arg1, arg2})
 	fake.lastOperationMutex.Unlock()
-	if fake.LastOperationStub != nil {
-		return fake.LastOperationStub(instance, operationData)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.lastOperationReturns.result1, fake.lastOperationReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeBrokerServices) LastOperationCallCount() int {
@@ -120,13 +153,22 @@ func (fake *FakeBrokerServices) LastOperationCallCount() int {
 	return len(fake.lastOperationArgsForCall)
 }
 
+func (fake *FakeBrokerServices) LastOperationCalls(stub func(string, broker.OperationData) (brokerapi.LastOperation, error)) {
+	fake.lastOperationMutex.Lock()
+	defer fake.lastOperationMutex.Unlock()
+	fake.LastOperationStub = stub
+}
+
 func (fake *FakeBrokerServices) LastOperationArgsForCall(i int) (string, broker.OperationData) {
 	fake.lastOperationMutex.RLock()
 	defer fake.lastOperationMutex.RUnlock()
-	return fake.lastOperationArgsForCall[i].instance, fake.lastOperationArgsForCall[i].operationData
+	argsForCall := fake.lastOperationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBrokerServices) LastOperationReturns(result1 brokerapi.LastOperation, result2 error) {
+	fake.lastOperationMutex.Lock()
+	defer fake.lastOperationMutex.Unlock()
 	fake.LastOperationStub = nil
 	fake.lastOperationReturns = struct {
 		result1 brokerapi.LastOperation
@@ -135,6 +177,8 @@ func (fake *FakeBrokerServices) LastOperationReturns(result1 brokerapi.LastOpera
 }
 
 func (fake *FakeBrokerServices) LastOperationReturnsOnCall(i int, result1 brokerapi.LastOperation, result2 error) {
+	fake.lastOperationMutex.Lock()
+	defer fake.lastOperationMutex.Unlock()
 	fake.LastOperationStub = nil
 	if fake.lastOperationReturnsOnCall == nil {
 		fake.lastOperationReturnsOnCall = make(map[int]struct {
@@ -148,13 +192,149 @@ func (fake *FakeBrokerServices) LastOperationReturnsOnCall(i int, result1 broker
 	}{result1, result2}
 }
 
+func (fake *FakeBrokerServices) PreviewUpgrade(arg1 service.Instance) (services.BOSHOperation, broker.UpgradePreview, error) {
+	fake.previewUpgradeMutex.Lock()
+	ret, specificReturn := fake.previewUpgradeReturnsOnCall[len(fake.previewUpgradeArgsForCall)]
+	fake.previewUpgradeArgsForCall = append(fake.previewUpgradeArgsForCall, struct {
+		arg1 service.Instance
+	}{arg1})
+	stub := fake.PreviewUpgradeStub
+	fakeReturns := fake.previewUpgradeReturns
+	fake.recordInvocation("PreviewUpgrade", []interface{}{arg1})
+	fake.previewUpgradeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeBrokerServices) PreviewUpgradeCallCount() int {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	return len(fake.previewUpgradeArgsForCall)
+}
+
+func (fake *FakeBrokerServices) PreviewUpgradeCalls(stub func(service.Instance) (services.BOSHOperation, broker.UpgradePreview, error)) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = stub
+}
+
+func (fake *FakeBrokerServices) PreviewUpgradeArgsForCall(i int) service.Instance {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	argsForCall := fake.previewUpgradeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBrokerServices) PreviewUpgradeReturns(result1 services.BOSHOperation, result2 broker.UpgradePreview, result3 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	fake.previewUpgradeReturns = struct {
+		result1 services.BOSHOperation
+		result2 broker.UpgradePreview
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBrokerServices) PreviewUpgradeReturnsOnCall(i int, result1 services.BOSHOperation, result2 broker.UpgradePreview, result3 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	if fake.previewUpgradeReturnsOnCall == nil {
+		fake.previewUpgradeReturnsOnCall = make(map[int]struct {
+			result1 services.BOSHOperation
+			result2 broker.UpgradePreview
+			result3 error
+		})
+	}
+	fake.previewUpgradeReturnsOnCall[i] = struct {
+		result1 services.BOSHOperation
+		result2 broker.UpgradePreview
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBrokerServices) ProcessInstance(arg1 service.Instance, arg2 string) (services.BOSHOperation, error) {
+	fake.processInstanceMutex.Lock()
+	ret, specificReturn := fake.processInstanceReturnsOnCall[len(fake.processInstanceArgsForCall)]
+	fake.processInstanceArgsForCall = append(fake.processInstanceArgsForCall, struct {
+		arg1 service.Instance
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ProcessInstanceStub
+	fakeReturns := fake.processInstanceReturns
+	fake.recordInvocation("ProcessInstance", []interface{}{arg1, arg2})
+	fake.processInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBrokerServices) ProcessInstanceCallCount() int {
+	fake.processInstanceMutex.RLock()
+	defer fake.processInstanceMutex.RUnlock()
+	return len(fake.processInstanceArgsForCall)
+}
+
+func (fake *FakeBrokerServices) ProcessInstanceCalls(stub func(service.Instance, string) (services.BOSHOperation, error)) {
+	fake.processInstanceMutex.Lock()
+	defer fake.processInstanceMutex.Unlock()
+	fake.ProcessInstanceStub = stub
+}
+
+func (fake *FakeBrokerServices) ProcessInstanceArgsForCall(i int) (service.Instance, string) {
+	fake.processInstanceMutex.RLock()
+	defer fake.processInstanceMutex.RUnlock()
+	argsForCall := fake.processInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBrokerServices) ProcessInstanceReturns(result1 services.BOSHOperation, result2 error) {
+	fake.processInstanceMutex.Lock()
+	defer fake.processInstanceMutex.Unlock()
+	fake.ProcessInstanceStub = nil
+	fake.processInstanceReturns = struct {
+		result1 services.BOSHOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) ProcessInstanceReturnsOnCall(i int, result1 services.BOSHOperation, result2 error) {
+	fake.processInstanceMutex.Lock()
+	defer fake.processInstanceMutex.Unlock()
+	fake.ProcessInstanceStub = nil
+	if fake.processInstanceReturnsOnCall == nil {
+		fake.processInstanceReturnsOnCall = make(map[int]struct {
+			result1 services.BOSHOperation
+			result2 error
+		})
+	}
+	fake.processInstanceReturnsOnCall[i] = struct {
+		result1 services.BOSHOperation
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBrokerServices) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.processInstanceMutex.RLock()
-	defer fake.processInstanceMutex.RUnlock()
+	fake.catalogMutex.RLock()
+	defer fake.catalogMutex.RUnlock()
 	fake.lastOperationMutex.RLock()
 	defer fake.lastOperationMutex.RUnlock()
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	fake.processInstanceMutex.RLock()
+	defer fake.processInstanceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
@@ -12,100 +13,300 @@ import (
 )
 
 type FakeListener struct {
-	FailedToRefreshInstanceInfoStub        func(instance string)
+	CanariesFinishedStub        func()
+	canariesFinishedMutex       sync.RWMutex
+	canariesFinishedArgsForCall []struct {
+	}
+	CanariesStartingStub        func(int, config.CanarySelectionParams)
+	canariesStartingMutex       sync.RWMutex
+	canariesStartingArgsForCall []struct {
+		arg1 int
+		arg2 config.CanarySelectionParams
+	}
+	CanaryStageStartingStub        func(int, int)
+	canaryStageStartingMutex       sync.RWMutex
+	canaryStageStartingArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	DeferredStub        func([]string)
+	deferredMutex       sync.RWMutex
+	deferredArgsForCall []struct {
+		arg1 []string
+	}
+	FailedToRefreshInstanceInfoStub        func(string)
 	failedToRefreshInstanceInfoMutex       sync.RWMutex
 	failedToRefreshInstanceInfoArgsForCall []struct {
-		instance string
+		arg1 string
 	}
-	StartingStub        func(maxInFlight int)
-	startingMutex       sync.RWMutex
-	startingArgsForCall []struct {
-		maxInFlight int
+	FailedToSaveStateStub        func(error)
+	failedToSaveStateMutex       sync.RWMutex
+	failedToSaveStateArgsForCall []struct {
+		arg1 error
 	}
-	RetryAttemptStub        func(num, limit int)
-	retryAttemptMutex       sync.RWMutex
-	retryAttemptArgsForCall []struct {
-		num   int
-		limit int
+	FailureToleratedStub        func(string, int, int)
+	failureToleratedMutex       sync.RWMutex
+	failureToleratedArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 int
 	}
-	RetryCanariesAttemptStub        func(num, limit, remainingCanaries int)
-	retryCanariesAttemptMutex       sync.RWMutex
-	retryCanariesAttemptArgsForCall []struct {
-		num               int
-		limit             int
-		remainingCanaries int
+	FinishedStub        func(int, int, int, []string, []string)
+	finishedMutex       sync.RWMutex
+	finishedArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 int
+		arg4 []string
+		arg5 []string
 	}
-	InstancesToProcessStub        func(instances []service.Instance)
-	instancesToProcessMutex       sync.RWMutex
-	instancesToProcessArgsForCall []struct {
-		instances []service.Instance
+	InstanceOperationFailedStub        func(string, error)
+	instanceOperationFailedMutex       sync.RWMutex
+	instanceOperationFailedArgsForCall []struct {
+		arg1 string
+		arg2 error
 	}
-	InstanceOperationStartingStub        func(instance string, index int, totalInstances int, isCanary bool)
-	instanceOperationStartingMutex       sync.RWMutex
-	instanceOperationStartingArgsForCall []struct {
-		instance       string
-		index          int
-		totalInstances int
-		isCanary       bool
+	InstanceOperationFinishedStub        func(string, string)
+	instanceOperationFinishedMutex       sync.RWMutex
+	instanceOperationFinishedArgsForCall []struct {
+		arg1 string
+		arg2 string
 	}
-	InstanceOperationStartResultStub        func(instance string, status services.BOSHOperationType)
+	InstanceOperationStartResultStub        func(string, services.BOSHOperationType)
 	instanceOperationStartResultMutex       sync.RWMutex
 	instanceOperationStartResultArgsForCall []struct {
-		instance string
-		status   services.BOSHOperationType
+		arg1 string
+		arg2 services.BOSHOperationType
 	}
-	InstanceOperationFinishedStub        func(instance string, result string)
-	instanceOperationFinishedMutex       sync.RWMutex
-	instanceOperationFinishedArgsForCall []struct {
-		instance string
-		result   string
+	InstanceOperationStartingStub        func(string, int, int, bool)
+	instanceOperationStartingMutex       sync.RWMutex
+	instanceOperationStartingArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 int
+		arg4 bool
 	}
-	WaitingForStub        func(instance string, boshTaskId int)
-	waitingForMutex       sync.RWMutex
-	waitingForArgsForCall []struct {
-		instance   string
-		boshTaskId int
+	InstanceUpgradePreviewStub        func(string, broker.UpgradePreview)
+	instanceUpgradePreviewMutex       sync.RWMutex
+	instanceUpgradePreviewArgsForCall []struct {
+		arg1 string
+		arg2 broker.UpgradePreview
 	}
-	ProgressStub        func(pollingInterval time.Duration, orphanCount, processedCount, toRetryCount, deletedCount int)
+	InstancesFilteredStub        func(int, int)
+	instancesFilteredMutex       sync.RWMutex
+	instancesFilteredArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	InstancesToProcessStub        func([]service.Instance)
+	instancesToProcessMutex       sync.RWMutex
+	instancesToProcessArgsForCall []struct {
+		arg1 []service.Instance
+	}
+	OutsideMaintenanceWindowStub        func(string, instanceiterator.MaintenanceWindow)
+	outsideMaintenanceWindowMutex       sync.RWMutex
+	outsideMaintenanceWindowArgsForCall []struct {
+		arg1 string
+		arg2 instanceiterator.MaintenanceWindow
+	}
+	PausingAfterCanaryStageStub        func(int, time.Duration)
+	pausingAfterCanaryStageMutex       sync.RWMutex
+	pausingAfterCanaryStageArgsForCall []struct {
+		arg1 int
+		arg2 time.Duration
+	}
+	ProgressStub        func(time.Duration, int, int, int, int)
 	progressMutex       sync.RWMutex
 	progressArgsForCall []struct {
-		pollingInterval time.Duration
-		orphanCount     int
-		processedCount  int
-		toRetryCount    int
-		deletedCount    int
+		arg1 time.Duration
+		arg2 int
+		arg3 int
+		arg4 int
+		arg5 int
 	}
-	FinishedStub        func(orphanCount, finishedCount, deletedCount int, busyInstances, failedInstances []string)
-	finishedMutex       sync.RWMutex
-	finishedArgsForCall []struct {
-		orphanCount     int
-		finishedCount   int
-		deletedCount    int
-		busyInstances   []string
-		failedInstances []string
+	ResumingStub        func(int)
+	resumingMutex       sync.RWMutex
+	resumingArgsForCall []struct {
+		arg1 int
 	}
-	CanariesStartingStub        func(canaries int, filter config.CanarySelectionParams)
-	canariesStartingMutex       sync.RWMutex
-	canariesStartingArgsForCall []struct {
-		canaries int
-		filter   config.CanarySelectionParams
+	RetryAttemptStub        func(int, int)
+	retryAttemptMutex       sync.RWMutex
+	retryAttemptArgsForCall []struct {
+		arg1 int
+		arg2 int
 	}
-	CanariesFinishedStub        func()
-	canariesFinishedMutex       sync.RWMutex
-	canariesFinishedArgsForCall []struct{}
-	invocations                 map[string][][]interface{}
-	invocationsMutex            sync.RWMutex
+	RetryCanariesAttemptStub        func(int, int, int)
+	retryCanariesAttemptMutex       sync.RWMutex
+	retryCanariesAttemptArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 int
+	}
+	StartingStub        func(int)
+	startingMutex       sync.RWMutex
+	startingArgsForCall []struct {
+		arg1 int
+	}
+	StillWaitingForApprovalStub        func(int, time.Duration, string)
+	stillWaitingForApprovalMutex       sync.RWMutex
+	stillWaitingForApprovalArgsForCall []struct {
+		arg1 int
+		arg2 time.Duration
+		arg3 string
+	}
+	WaitingForStub        func(string, int)
+	waitingForMutex       sync.RWMutex
+	waitingForArgsForCall []struct {
+		arg1 string
+		arg2 int
+	}
+	WaitingForApprovalStub        func(int, string)
+	waitingForApprovalMutex       sync.RWMutex
+	waitingForApprovalArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeListener) CanariesFinished() {
+	fake.canariesFinishedMutex.Lock()
+	fake.canariesFinishedArgsForCall = append(fake.canariesFinishedArgsForCall, struct {
+	}{})
+	stub := fake.CanariesFinishedStub
+	fake.recordInvocation("CanariesFinished", []interface{}{})
+	fake.canariesFinishedMutex.Unlock()
+	if stub != nil {
+		fake.CanariesFinishedStub()
+	}
+}
+
+func (fake *FakeListener) CanariesFinishedCallCount() int {
+	fake.canariesFinishedMutex.RLock()
+	defer fake.canariesFinishedMutex.RUnlock()
+	return len(fake.canariesFinishedArgsForCall)
+}
+
+func (fake *FakeListener) CanariesFinishedCalls(stub func()) {
+	fake.canariesFinishedMutex.Lock()
+	defer fake.canariesFinishedMutex.Unlock()
+	fake.CanariesFinishedStub = stub
+}
+
+func (fake *FakeListener) CanariesStarting(arg1 int, arg2 config.CanarySelectionParams) {
+	fake.canariesStartingMutex.Lock()
+	fake.canariesStartingArgsForCall = append(fake.canariesStartingArgsForCall, struct {
+		arg1 int
+		arg2 config.CanarySelectionParams
+	}{arg1, arg2})
+	stub := fake.CanariesStartingStub
+	fake.recordInvocation("CanariesStarting", []interface{}{arg1, arg2})
+	fake.canariesStartingMutex.Unlock()
+	if stub != nil {
+		fake.CanariesStartingStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeListener) CanariesStartingCallCount() int {
+	fake.canariesStartingMutex.RLock()
+	defer fake.canariesStartingMutex.RUnlock()
+	return len(fake.canariesStartingArgsForCall)
+}
+
+func (fake *FakeListener) CanariesStartingCalls(stub func(int, config.CanarySelectionParams)) {
+	fake.canariesStartingMutex.Lock()
+	defer fake.canariesStartingMutex.Unlock()
+	fake.CanariesStartingStub = stub
+}
+
+func (fake *FakeListener) CanariesStartingArgsForCall(i int) (int, config.CanarySelectionParams) {
+	fake.canariesStartingMutex.RLock()
+	defer fake.canariesStartingMutex.RUnlock()
+	argsForCall := fake.canariesStartingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) CanaryStageStarting(arg1 int, arg2 int) {
+	fake.canaryStageStartingMutex.Lock()
+	fake.canaryStageStartingArgsForCall = append(fake.canaryStageStartingArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.CanaryStageStartingStub
+	fake.recordInvocation("CanaryStageStarting", []interface{}{arg1, arg2})
+	fake.canaryStageStartingMutex.Unlock()
+	if stub != nil {
+		fake.CanaryStageStartingStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeListener) CanaryStageStartingCallCount() int {
+	fake.canaryStageStartingMutex.RLock()
+	defer fake.canaryStageStartingMutex.RUnlock()
+	return len(fake.canaryStageStartingArgsForCall)
+}
+
+func (fake *FakeListener) CanaryStageStartingCalls(stub func(int, int)) {
+	fake.canaryStageStartingMutex.Lock()
+	defer fake.canaryStageStartingMutex.Unlock()
+	fake.CanaryStageStartingStub = stub
+}
+
+func (fake *FakeListener) CanaryStageStartingArgsForCall(i int) (int, int) {
+	fake.canaryStageStartingMutex.RLock()
+	defer fake.canaryStageStartingMutex.RUnlock()
+	argsForCall := fake.canaryStageStartingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) Deferred(arg1 []string) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deferredMutex.Lock()
+	fake.deferredArgsForCall = append(fake.deferredArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.DeferredStub
+	fake.recordInvocation("Deferred", []interface{}{arg1Copy})
+	fake.deferredMutex.Unlock()
+	if stub != nil {
+		fake.DeferredStub(arg1)
+	}
+}
+
+func (fake *FakeListener) DeferredCallCount() int {
+	fake.deferredMutex.RLock()
+	defer fake.deferredMutex.RUnlock()
+	return len(fake.deferredArgsForCall)
+}
+
+func (fake *FakeListener) DeferredCalls(stub func([]string)) {
+	fake.deferredMutex.Lock()
+	defer fake.deferredMutex.Unlock()
+	fake.DeferredStub = stub
+}
+
+func (fake *FakeListener) DeferredArgsForCall(i int) []string {
+	fake.deferredMutex.RLock()
+	defer fake.deferredMutex.RUnlock()
+	argsForCall := fake.deferredArgsForCall[i]
+	return argsForCall.arg1
 }
 
-func (fake *FakeListener) FailedToRefreshInstanceInfo(instance string) {
+func (fake *FakeListener) FailedToRefreshInstanceInfo(arg1 string) {
 	fake.failedToRefreshInstanceInfoMutex.Lock()
 	fake.failedToRefreshInstanceInfoArgsForCall = append(fake.failedToRefreshInstanceInfoArgsForCall, struct {
-		instance string
-	}{instance})
-	fake.recordInvocation("FailedToRefreshInstanceInfo", []interface{}{instance})
+		arg1 string
+	}{arg1})
+	stub := fake.FailedToRefreshInstanceInfoStub
+	fake.recordInvocation("FailedToRefreshInstanceInfo", []interface{}{arg1})
 	fake.failedToRefreshInstanceInfoMutex.Unlock()
-	if fake.FailedToRefreshInstanceInfoStub != nil {
-		fake.FailedToRefreshInstanceInfoStub(instance)
+	if stub != nil {
+		fake.FailedToRefreshInstanceInfoStub(arg1)
 	}
 }
 
@@ -115,128 +316,243 @@ func (fake *FakeListener) FailedToRefreshInstanceInfoCallCount() int {
 	return len(fake.failedToRefreshInstanceInfoArgsForCall)
 }
 
+func (fake *FakeListener) FailedToRefreshInstanceInfoCalls(stub func(string)) {
+	fake.failedToRefreshInstanceInfoMutex.Lock()
+	defer fake.failedToRefreshInstanceInfoMutex.Unlock()
+	fake.FailedToRefreshInstanceInfoStub = stub
+}
+
 func (fake *FakeListener) FailedToRefreshInstanceInfoArgsForCall(i int) string {
 	fake.failedToRefreshInstanceInfoMutex.RLock()
 	defer fake.failedToRefreshInstanceInfoMutex.RUnlock()
-	return fake.failedToRefreshInstanceInfoArgsForCall[i].instance
+	argsForCall := fake.failedToRefreshInstanceInfoArgsForCall[i]
+	return argsForCall.arg1
 }
 
-func (fake *FakeListener) Starting(maxInFlight int) {
-	fake.startingMutex.Lock()
-	fake.startingArgsForCall = append(fake.startingArgsForCall, struct {
-		maxInFlight int
-	}{maxInFlight})
-	fake.recordInvocation("Starting", []interface{}{maxInFlight})
-	fake.startingMutex.Unlock()
-	if fake.StartingStub != nil {
-		fake.StartingStub(maxInFlight)
+func (fake *FakeListener) FailedToSaveState(arg1 error) {
+	fake.failedToSaveStateMutex.Lock()
+	fake.failedToSaveStateArgsForCall = append(fake.failedToSaveStateArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.FailedToSaveStateStub
+	fake.recordInvocation("FailedToSaveState", []interface{}{arg1})
+	fake.failedToSaveStateMutex.Unlock()
+	if stub != nil {
+		fake.FailedToSaveStateStub(arg1)
 	}
 }
 
-func (fake *FakeListener) StartingCallCount() int {
-	fake.startingMutex.RLock()
-	defer fake.startingMutex.RUnlock()
-	return len(fake.startingArgsForCall)
+func (fake *FakeListener) FailedToSaveStateCallCount() int {
+	fake.failedToSaveStateMutex.RLock()
+	defer fake.failedToSaveStateMutex.RUnlock()
+	return len(fake.failedToSaveStateArgsForCall)
 }
 
-func (fake *FakeListener) StartingArgsForCall(i int) int {
-	fake.startingMutex.RLock()
-	defer fake.startingMutex.RUnlock()
-	return fake.startingArgsForCall[i].maxInFlight
+func (fake *FakeListener) FailedToSaveStateCalls(stub func(error)) {
+	fake.failedToSaveStateMutex.Lock()
+	defer fake.failedToSaveStateMutex.Unlock()
+	fake.FailedToSaveStateStub = stub
 }
 
-func (fake *FakeListener) RetryAttempt(num int, limit int) {
-	fake.retryAttemptMutex.Lock()
-	fake.retryAttemptArgsForCall = append(fake.retryAttemptArgsForCall, struct {
-		num   int
-		limit int
-	}{num, limit})
-	fake.recordInvocation("RetryAttempt", []interface{}{num, limit})
-	fake.retryAttemptMutex.Unlock()
-	if fake.RetryAttemptStub != nil {
-		fake.RetryAttemptStub(num, limit)
+func (fake *FakeListener) FailedToSaveStateArgsForCall(i int) error {
+	fake.failedToSaveStateMutex.RLock()
+	defer fake.failedToSaveStateMutex.RUnlock()
+	argsForCall := fake.failedToSaveStateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeListener) FailureTolerated(arg1 string, arg2 int, arg3 int) {
+	fake.failureToleratedMutex.Lock()
+	fake.failureToleratedArgsForCall = append(fake.failureToleratedArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.FailureToleratedStub
+	fake.recordInvocation("FailureTolerated", []interface{}{arg1, arg2, arg3})
+	fake.failureToleratedMutex.Unlock()
+	if stub != nil {
+		fake.FailureToleratedStub(arg1, arg2, arg3)
 	}
 }
 
-func (fake *FakeListener) RetryAttemptCallCount() int {
-	fake.retryAttemptMutex.RLock()
-	defer fake.retryAttemptMutex.RUnlock()
-	return len(fake.retryAttemptArgsForCall)
+func (fake *FakeListener) FailureToleratedCallCount() int {
+	fake.failureToleratedMutex.RLock()
+	defer fake.failureToleratedMutex.RUnlock()
+	return len(fake.failureToleratedArgsForCall)
 }
 
-func (fake *FakeListener) RetryAttemptArgsForCall(i int) (int, int) {
-	fake.retryAttemptMutex.RLock()
-	defer fake.retryAttemptMutex.RUnlock()
-	return fake.retryAttemptArgsForCall[i].num, fake.retryAttemptArgsForCall[i].limit
+func (fake *FakeListener) FailureToleratedCalls(stub func(string, int, int)) {
+	fake.failureToleratedMutex.Lock()
+	defer fake.failureToleratedMutex.Unlock()
+	fake.FailureToleratedStub = stub
 }
 
-func (fake *FakeListener) RetryCanariesAttempt(num int, limit int, remainingCanaries int) {
-	fake.retryCanariesAttemptMutex.Lock()
-	fake.retryCanariesAttemptArgsForCall = append(fake.retryCanariesAttemptArgsForCall, struct {
-		num               int
-		limit             int
-		remainingCanaries int
-	}{num, limit, remainingCanaries})
-	fake.recordInvocation("RetryCanariesAttempt", []interface{}{num, limit, remainingCanaries})
-	fake.retryCanariesAttemptMutex.Unlock()
-	if fake.RetryCanariesAttemptStub != nil {
-		fake.RetryCanariesAttemptStub(num, limit, remainingCanaries)
+func (fake *FakeListener) FailureToleratedArgsForCall(i int) (string, int, int) {
+	fake.failureToleratedMutex.RLock()
+	defer fake.failureToleratedMutex.RUnlock()
+	argsForCall := fake.failureToleratedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeListener) Finished(arg1 int, arg2 int, arg3 int, arg4 []string, arg5 []string) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	var arg5Copy []string
+	if arg5 != nil {
+		arg5Copy = make([]string, len(arg5))
+		copy(arg5Copy, arg5)
+	}
+	fake.finishedMutex.Lock()
+	fake.finishedArgsForCall = append(fake.finishedArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 int
+		arg4 []string
+		arg5 []string
+	}{arg1, arg2, arg3, arg4Copy, arg5Copy})
+	stub := fake.FinishedStub
+	fake.recordInvocation("Finished", []interface{}{arg1, arg2, arg3, arg4Copy, arg5Copy})
+	fake.finishedMutex.Unlock()
+	if stub != nil {
+		fake.FinishedStub(arg1, arg2, arg3, arg4, arg5)
 	}
 }
 
-func (fake *FakeListener) RetryCanariesAttemptCallCount() int {
-	fake.retryCanariesAttemptMutex.RLock()
-	defer fake.retryCanariesAttemptMutex.RUnlock()
-	return len(fake.retryCanariesAttemptArgsForCall)
+func (fake *FakeListener) FinishedCallCount() int {
+	fake.finishedMutex.RLock()
+	defer fake.finishedMutex.RUnlock()
+	return len(fake.finishedArgsForCall)
 }
 
-func (fake *FakeListener) RetryCanariesAttemptArgsForCall(i int) (int, int, int) {
-	fake.retryCanariesAttemptMutex.RLock()
-	defer fake.retryCanariesAttemptMutex.RUnlock()
-	return fake.retryCanariesAttemptArgsForCall[i].num, fake.retryCanariesAttemptArgsForCall[i].limit, fake.retryCanariesAttemptArgsForCall[i].remainingCanaries
+func (fake *FakeListener) FinishedCalls(stub func(int, int, int, []string, []string)) {
+	fake.finishedMutex.Lock()
+	defer fake.finishedMutex.Unlock()
+	fake.FinishedStub = stub
 }
 
-func (fake *FakeListener) InstancesToProcess(instances []service.Instance) {
-	var instancesCopy []service.Instance
-	if instances != nil {
-		instancesCopy = make([]service.Instance, len(instances))
-		copy(instancesCopy, instances)
+func (fake *FakeListener) FinishedArgsForCall(i int) (int, int, int, []string, []string) {
+	fake.finishedMutex.RLock()
+	defer fake.finishedMutex.RUnlock()
+	argsForCall := fake.finishedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeListener) InstanceOperationFailed(arg1 string, arg2 error) {
+	fake.instanceOperationFailedMutex.Lock()
+	fake.instanceOperationFailedArgsForCall = append(fake.instanceOperationFailedArgsForCall, struct {
+		arg1 string
+		arg2 error
+	}{arg1, arg2})
+	stub := fake.InstanceOperationFailedStub
+	fake.recordInvocation("InstanceOperationFailed", []interface{}{arg1, arg2})
+	fake.instanceOperationFailedMutex.Unlock()
+	if stub != nil {
+		fake.InstanceOperationFailedStub(arg1, arg2)
 	}
-	fake.instancesToProcessMutex.Lock()
-	fake.instancesToProcessArgsForCall = append(fake.instancesToProcessArgsForCall, struct {
-		instances []service.Instance
-	}{instancesCopy})
-	fake.recordInvocation("InstancesToProcess", []interface{}{instancesCopy})
-	fake.instancesToProcessMutex.Unlock()
-	if fake.InstancesToProcessStub != nil {
-		fake.InstancesToProcessStub(instances)
+}
+
+func (fake *FakeListener) InstanceOperationFailedCallCount() int {
+	fake.instanceOperationFailedMutex.RLock()
+	defer fake.instanceOperationFailedMutex.RUnlock()
+	return len(fake.instanceOperationFailedArgsForCall)
+}
+
+func (fake *FakeListener) InstanceOperationFailedCalls(stub func(string, error)) {
+	fake.instanceOperationFailedMutex.Lock()
+	defer fake.instanceOperationFailedMutex.Unlock()
+	fake.InstanceOperationFailedStub = stub
+}
+
+func (fake *FakeListener) InstanceOperationFailedArgsForCall(i int) (string, error) {
+	fake.instanceOperationFailedMutex.RLock()
+	defer fake.instanceOperationFailedMutex.RUnlock()
+	argsForCall := fake.instanceOperationFailedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) InstanceOperationFinished(arg1 string, arg2 string) {
+	fake.instanceOperationFinishedMutex.Lock()
+	fake.instanceOperationFinishedArgsForCall = append(fake.instanceOperationFinishedArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.InstanceOperationFinishedStub
+	fake.recordInvocation("InstanceOperationFinished", []interface{}{arg1, arg2})
+	fake.instanceOperationFinishedMutex.Unlock()
+	if stub != nil {
+		fake.InstanceOperationFinishedStub(arg1, arg2)
 	}
 }
 
-func (fake *FakeListener) InstancesToProcessCallCount() int {
-	fake.instancesToProcessMutex.RLock()
-	defer fake.instancesToProcessMutex.RUnlock()
-	return len(fake.instancesToProcessArgsForCall)
+func (fake *FakeListener) InstanceOperationFinishedCallCount() int {
+	fake.instanceOperationFinishedMutex.RLock()
+	defer fake.instanceOperationFinishedMutex.RUnlock()
+	return len(fake.instanceOperationFinishedArgsForCall)
 }
 
-func (fake *FakeListener) InstancesToProcessArgsForCall(i int) []service.Instance {
-	fake.instancesToProcessMutex.RLock()
-	defer fake.instancesToProcessMutex.RUnlock()
-	return fake.instancesToProcessArgsForCall[i].instances
+func (fake *FakeListener) InstanceOperationFinishedCalls(stub func(string, string)) {
+	fake.instanceOperationFinishedMutex.Lock()
+	defer fake.instanceOperationFinishedMutex.Unlock()
+	fake.InstanceOperationFinishedStub = stub
+}
+
+func (fake *FakeListener) InstanceOperationFinishedArgsForCall(i int) (string, string) {
+	fake.instanceOperationFinishedMutex.RLock()
+	defer fake.instanceOperationFinishedMutex.RUnlock()
+	argsForCall := fake.instanceOperationFinishedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) InstanceOperationStartResult(arg1 string, arg2 services.BOSHOperationType) {
+	fake.instanceOperationStartResultMutex.Lock()
+	fake.instanceOperationStartResultArgsForCall = append(fake.instanceOperationStartResultArgsForCall, struct {
+		arg1 string
+		arg2 services.BOSHOperationType
+	}{arg1, arg2})
+	stub := fake.InstanceOperationStartResultStub
+	fake.recordInvocation("InstanceOperationStartResult", []interface{}{arg1, arg2})
+	fake.instanceOperationStartResultMutex.Unlock()
+	if stub != nil {
+		fake.InstanceOperationStartResultStub(arg1, arg2)
+	}
 }
 
-func (fake *FakeListener) InstanceOperationStarting(instance string, index int, totalInstances int, isCanary bool) {
+func (fake *FakeListener) InstanceOperationStartResultCallCount() int {
+	fake.instanceOperationStartResultMutex.RLock()
+	defer fake.instanceOperationStartResultMutex.RUnlock()
+	return len(fake.instanceOperationStartResultArgsForCall)
+}
+
+func (fake *FakeListener) InstanceOperationStartResultCalls(stub func(string, services.BOSHOperationType)) {
+	fake.instanceOperationStartResultMutex.Lock()
+	defer fake.instanceOperationStartResultMutex.Unlock()
+	fake.InstanceOperationStartResultStub = stub
+}
+
+func (fake *FakeListener) InstanceOperationStartResultArgsForCall(i int) (string, services.BOSHOperationType) {
+	fake.instanceOperationStartResultMutex.RLock()
+	defer fake.instanceOperationStartResultMutex.RUnlock()
+	argsForCall := fake.instanceOperationStartResultArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) InstanceOperationStarting(arg1 string, arg2 int, arg3 int, arg4 bool) {
 	fake.instanceOperationStartingMutex.Lock()
 	fake.instanceOperationStartingArgsForCall = append(fake.instanceOperationStartingArgsForCall, struct {
-		instance       string
-		index          int
-		totalInstances int
-		isCanary       bool
-	}{instance, index, totalInstances, isCanary})
-	fake.recordInvocation("InstanceOperationStarting", []interface{}{instance, index, totalInstances, isCanary})
+		arg1 string
+		arg2 int
+		arg3 int
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.InstanceOperationStartingStub
+	fake.recordInvocation("InstanceOperationStarting", []interface{}{arg1, arg2, arg3, arg4})
 	fake.instanceOperationStartingMutex.Unlock()
-	if fake.InstanceOperationStartingStub != nil {
-		fake.InstanceOperationStartingStub(instance, index, totalInstances, isCanary)
+	if stub != nil {
+		fake.InstanceOperationStartingStub(arg1, arg2, arg3, arg4)
 	}
 }
 
@@ -246,100 +562,202 @@ func (fake *FakeListener) InstanceOperationStartingCallCount() int {
 	return len(fake.instanceOperationStartingArgsForCall)
 }
 
+func (fake *FakeListener) InstanceOperationStartingCalls(stub func(string, int, int, bool)) {
+	fake.instanceOperationStartingMutex.Lock()
+	defer fake.instanceOperationStartingMutex.Unlock()
+	fake.InstanceOperationStartingStub = stub
+}
+
 func (fake *FakeListener) InstanceOperationStartingArgsForCall(i int) (string, int, int, bool) {
 	fake.instanceOperationStartingMutex.RLock()
 	defer fake.instanceOperationStartingMutex.RUnlock()
-	return fake.instanceOperationStartingArgsForCall[i].instance, fake.instanceOperationStartingArgsForCall[i].index, fake.instanceOperationStartingArgsForCall[i].totalInstances, fake.instanceOperationStartingArgsForCall[i].isCanary
+	argsForCall := fake.instanceOperationStartingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeListener) InstanceOperationStartResult(instance string, status services.BOSHOperationType) {
-	fake.instanceOperationStartResultMutex.Lock()
-	fake.instanceOperationStartResultArgsForCall = append(fake.instanceOperationStartResultArgsForCall, struct {
-		instance string
-		status   services.BOSHOperationType
-	}{instance, status})
-	fake.recordInvocation("InstanceOperationStartResult", []interface{}{instance, status})
-	fake.instanceOperationStartResultMutex.Unlock()
-	if fake.InstanceOperationStartResultStub != nil {
-		fake.InstanceOperationStartResultStub(instance, status)
+func (fake *FakeListener) InstanceUpgradePreview(arg1 string, arg2 broker.UpgradePreview) {
+	fake.instanceUpgradePreviewMutex.Lock()
+	fake.instanceUpgradePreviewArgsForCall = append(fake.instanceUpgradePreviewArgsForCall, struct {
+		arg1 string
+		arg2 broker.UpgradePreview
+	}{arg1, arg2})
+	stub := fake.InstanceUpgradePreviewStub
+	fake.recordInvocation("InstanceUpgradePreview", []interface{}{arg1, arg2})
+	fake.instanceUpgradePreviewMutex.Unlock()
+	if stub != nil {
+		fake.InstanceUpgradePreviewStub(arg1, arg2)
 	}
 }
 
-func (fake *FakeListener) InstanceOperationStartResultCallCount() int {
-	fake.instanceOperationStartResultMutex.RLock()
-	defer fake.instanceOperationStartResultMutex.RUnlock()
-	return len(fake.instanceOperationStartResultArgsForCall)
+func (fake *FakeListener) InstanceUpgradePreviewCallCount() int {
+	fake.instanceUpgradePreviewMutex.RLock()
+	defer fake.instanceUpgradePreviewMutex.RUnlock()
+	return len(fake.instanceUpgradePreviewArgsForCall)
 }
 
-func (fake *FakeListener) InstanceOperationStartResultArgsForCall(i int) (string, services.BOSHOperationType) {
-	fake.instanceOperationStartResultMutex.RLock()
-	defer fake.instanceOperationStartResultMutex.RUnlock()
-	return fake.instanceOperationStartResultArgsForCall[i].instance, fake.instanceOperationStartResultArgsForCall[i].status
+func (fake *FakeListener) InstanceUpgradePreviewCalls(stub func(string, broker.UpgradePreview)) {
+	fake.instanceUpgradePreviewMutex.Lock()
+	defer fake.instanceUpgradePreviewMutex.Unlock()
+	fake.InstanceUpgradePreviewStub = stub
 }
 
-func (fake *FakeListener) InstanceOperationFinished(instance string, result string) {
-	fake.instanceOperationFinishedMutex.Lock()
-	fake.instanceOperationFinishedArgsForCall = append(fake.instanceOperationFinishedArgsForCall, struct {
-		instance string
-		result   string
-	}{instance, result})
-	fake.recordInvocation("InstanceOperationFinished", []interface{}{instance, result})
-	fake.instanceOperationFinishedMutex.Unlock()
-	if fake.InstanceOperationFinishedStub != nil {
-		fake.InstanceOperationFinishedStub(instance, result)
+func (fake *FakeListener) InstanceUpgradePreviewArgsForCall(i int) (string, broker.UpgradePreview) {
+	fake.instanceUpgradePreviewMutex.RLock()
+	defer fake.instanceUpgradePreviewMutex.RUnlock()
+	argsForCall := fake.instanceUpgradePreviewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) InstancesFiltered(arg1 int, arg2 int) {
+	fake.instancesFilteredMutex.Lock()
+	fake.instancesFilteredArgsForCall = append(fake.instancesFilteredArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.InstancesFilteredStub
+	fake.recordInvocation("InstancesFiltered", []interface{}{arg1, arg2})
+	fake.instancesFilteredMutex.Unlock()
+	if stub != nil {
+		fake.InstancesFilteredStub(arg1, arg2)
 	}
 }
 
-func (fake *FakeListener) InstanceOperationFinishedCallCount() int {
-	fake.instanceOperationFinishedMutex.RLock()
-	defer fake.instanceOperationFinishedMutex.RUnlock()
-	return len(fake.instanceOperationFinishedArgsForCall)
+func (fake *FakeListener) InstancesFilteredCallCount() int {
+	fake.instancesFilteredMutex.RLock()
+	defer fake.instancesFilteredMutex.RUnlock()
+	return len(fake.instancesFilteredArgsForCall)
 }
 
-func (fake *FakeListener) InstanceOperationFinishedArgsForCall(i int) (string, string) {
-	fake.instanceOperationFinishedMutex.RLock()
-	defer fake.instanceOperationFinishedMutex.RUnlock()
-	return fake.instanceOperationFinishedArgsForCall[i].instance, fake.instanceOperationFinishedArgsForCall[i].result
+func (fake *FakeListener) InstancesFilteredCalls(stub func(int, int)) {
+	fake.instancesFilteredMutex.Lock()
+	defer fake.instancesFilteredMutex.Unlock()
+	fake.InstancesFilteredStub = stub
 }
 
-func (fake *FakeListener) WaitingFor(instance string, boshTaskId int) {
-	fake.waitingForMutex.Lock()
-	fake.waitingForArgsForCall = append(fake.waitingForArgsForCall, struct {
-		instance   string
-		boshTaskId int
-	}{instance, boshTaskId})
-	fake.recordInvocation("WaitingFor", []interface{}{instance, boshTaskId})
-	fake.waitingForMutex.Unlock()
-	if fake.WaitingForStub != nil {
-		fake.WaitingForStub(instance, boshTaskId)
+func (fake *FakeListener) InstancesFilteredArgsForCall(i int) (int, int) {
+	fake.instancesFilteredMutex.RLock()
+	defer fake.instancesFilteredMutex.RUnlock()
+	argsForCall := fake.instancesFilteredArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) InstancesToProcess(arg1 []service.Instance) {
+	var arg1Copy []service.Instance
+	if arg1 != nil {
+		arg1Copy = make([]service.Instance, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.instancesToProcessMutex.Lock()
+	fake.instancesToProcessArgsForCall = append(fake.instancesToProcessArgsForCall, struct {
+		arg1 []service.Instance
+	}{arg1Copy})
+	stub := fake.InstancesToProcessStub
+	fake.recordInvocation("InstancesToProcess", []interface{}{arg1Copy})
+	fake.instancesToProcessMutex.Unlock()
+	if stub != nil {
+		fake.InstancesToProcessStub(arg1)
 	}
 }
 
-func (fake *FakeListener) WaitingForCallCount() int {
-	fake.waitingForMutex.RLock()
-	defer fake.waitingForMutex.RUnlock()
-	return len(fake.waitingForArgsForCall)
+func (fake *FakeListener) InstancesToProcessCallCount() int {
+	fake.instancesToProcessMutex.RLock()
+	defer fake.instancesToProcessMutex.RUnlock()
+	return len(fake.instancesToProcessArgsForCall)
 }
 
-func (fake *FakeListener) WaitingForArgsForCall(i int) (string, int) {
-	fake.waitingForMutex.RLock()
-	defer fake.waitingForMutex.RUnlock()
-	return fake.waitingForArgsForCall[i].instance, fake.waitingForArgsForCall[i].boshTaskId
+func (fake *FakeListener) InstancesToProcessCalls(stub func([]service.Instance)) {
+	fake.instancesToProcessMutex.Lock()
+	defer fake.instancesToProcessMutex.Unlock()
+	fake.InstancesToProcessStub = stub
 }
 
-func (fake *FakeListener) Progress(pollingInterval time.Duration, orphanCount int, processedCount int, toRetryCount int, deletedCount int) {
+func (fake *FakeListener) InstancesToProcessArgsForCall(i int) []service.Instance {
+	fake.instancesToProcessMutex.RLock()
+	defer fake.instancesToProcessMutex.RUnlock()
+	argsForCall := fake.instancesToProcessArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindow(arg1 string, arg2 instanceiterator.MaintenanceWindow) {
+	fake.outsideMaintenanceWindowMutex.Lock()
+	fake.outsideMaintenanceWindowArgsForCall = append(fake.outsideMaintenanceWindowArgsForCall, struct {
+		arg1 string
+		arg2 instanceiterator.MaintenanceWindow
+	}{arg1, arg2})
+	stub := fake.OutsideMaintenanceWindowStub
+	fake.recordInvocation("OutsideMaintenanceWindow", []interface{}{arg1, arg2})
+	fake.outsideMaintenanceWindowMutex.Unlock()
+	if stub != nil {
+		fake.OutsideMaintenanceWindowStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindowCallCount() int {
+	fake.outsideMaintenanceWindowMutex.RLock()
+	defer fake.outsideMaintenanceWindowMutex.RUnlock()
+	return len(fake.outsideMaintenanceWindowArgsForCall)
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindowCalls(stub func(string, instanceiterator.MaintenanceWindow)) {
+	fake.outsideMaintenanceWindowMutex.Lock()
+	defer fake.outsideMaintenanceWindowMutex.Unlock()
+	fake.OutsideMaintenanceWindowStub = stub
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindowArgsForCall(i int) (string, instanceiterator.MaintenanceWindow) {
+	fake.outsideMaintenanceWindowMutex.RLock()
+	defer fake.outsideMaintenanceWindowMutex.RUnlock()
+	argsForCall := fake.outsideMaintenanceWindowArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) PausingAfterCanaryStage(arg1 int, arg2 time.Duration) {
+	fake.pausingAfterCanaryStageMutex.Lock()
+	fake.pausingAfterCanaryStageArgsForCall = append(fake.pausingAfterCanaryStageArgsForCall, struct {
+		arg1 int
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.PausingAfterCanaryStageStub
+	fake.recordInvocation("PausingAfterCanaryStage", []interface{}{arg1, arg2})
+	fake.pausingAfterCanaryStageMutex.Unlock()
+	if stub != nil {
+		fake.PausingAfterCanaryStageStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeListener) PausingAfterCanaryStageCallCount() int {
+	fake.pausingAfterCanaryStageMutex.RLock()
+	defer fake.pausingAfterCanaryStageMutex.RUnlock()
+	return len(fake.pausingAfterCanaryStageArgsForCall)
+}
+
+func (fake *FakeListener) PausingAfterCanaryStageCalls(stub func(int, time.Duration)) {
+	fake.pausingAfterCanaryStageMutex.Lock()
+	defer fake.pausingAfterCanaryStageMutex.Unlock()
+	fake.PausingAfterCanaryStageStub = stub
+}
+
+func (fake *FakeListener) PausingAfterCanaryStageArgsForCall(i int) (int, time.Duration) {
+	fake.pausingAfterCanaryStageMutex.RLock()
+	defer fake.pausingAfterCanaryStageMutex.RUnlock()
+	argsForCall := fake.pausingAfterCanaryStageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) Progress(arg1 time.Duration, arg2 int, arg3 int, arg4 int, arg5 int) {
 	fake.progressMutex.Lock()
 	fake.progressArgsForCall = append(fake.progressArgsForCall, struct {
-		pollingInterval time.Duration
-		orphanCount     int
-		processedCount  int
-		toRetryCount    int
-		deletedCount    int
-	}{pollingInterval, orphanCount, processedCount, toRetryCount, deletedCount})
-	fake.recordInvocation("Progress", []interface{}{pollingInterval, orphanCount, processedCount, toRetryCount, deletedCount})
+		arg1 time.Duration
+		arg2 int
+		arg3 int
+		arg4 int
+		arg5 int
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.ProgressStub
+	fake.recordInvocation("Progress", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.progressMutex.Unlock()
-	if fake.ProgressStub != nil {
-		fake.ProgressStub(pollingInterval, orphanCount, processedCount, toRetryCount, deletedCount)
+	if stub != nil {
+		fake.ProgressStub(arg1, arg2, arg3, arg4, arg5)
 	}
 }
 
@@ -349,120 +767,303 @@ func (fake *FakeListener) ProgressCallCount() int {
 	return len(fake.progressArgsForCall)
 }
 
+func (fake *FakeListener) ProgressCalls(stub func(time.Duration, int, int, int, int)) {
+	fake.progressMutex.Lock()
+	defer fake.progressMutex.Unlock()
+	fake.ProgressStub = stub
+}
+
 func (fake *FakeListener) ProgressArgsForCall(i int) (time.Duration, int, int, int, int) {
 	fake.progressMutex.RLock()
 	defer fake.progressMutex.RUnlock()
-	return fake.progressArgsForCall[i].pollingInterval, fake.progressArgsForCall[i].orphanCount, fake.progressArgsForCall[i].processedCount, fake.progressArgsForCall[i].toRetryCount, fake.progressArgsForCall[i].deletedCount
+	argsForCall := fake.progressArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
-func (fake *FakeListener) Finished(orphanCount int, finishedCount int, deletedCount int, busyInstances []string, failedInstances []string) {
-	var busyInstancesCopy []string
-	if busyInstances != nil {
-		busyInstancesCopy = make([]string, len(busyInstances))
-		copy(busyInstancesCopy, busyInstances)
+func (fake *FakeListener) Resuming(arg1 int) {
+	fake.resumingMutex.Lock()
+	fake.resumingArgsForCall = append(fake.resumingArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.ResumingStub
+	fake.recordInvocation("Resuming", []interface{}{arg1})
+	fake.resumingMutex.Unlock()
+	if stub != nil {
+		fake.ResumingStub(arg1)
 	}
-	var failedInstancesCopy []string
-	if failedInstances != nil {
-		failedInstancesCopy = make([]string, len(failedInstances))
-		copy(failedInstancesCopy, failedInstances)
+}
+
+func (fake *FakeListener) ResumingCallCount() int {
+	fake.resumingMutex.RLock()
+	defer fake.resumingMutex.RUnlock()
+	return len(fake.resumingArgsForCall)
+}
+
+func (fake *FakeListener) ResumingCalls(stub func(int)) {
+	fake.resumingMutex.Lock()
+	defer fake.resumingMutex.Unlock()
+	fake.ResumingStub = stub
+}
+
+func (fake *FakeListener) ResumingArgsForCall(i int) int {
+	fake.resumingMutex.RLock()
+	defer fake.resumingMutex.RUnlock()
+	argsForCall := fake.resumingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeListener) RetryAttempt(arg1 int, arg2 int) {
+	fake.retryAttemptMutex.Lock()
+	fake.retryAttemptArgsForCall = append(fake.retryAttemptArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.RetryAttemptStub
+	fake.recordInvocation("RetryAttempt", []interface{}{arg1, arg2})
+	fake.retryAttemptMutex.Unlock()
+	if stub != nil {
+		fake.RetryAttemptStub(arg1, arg2)
 	}
-	fake.finishedMutex.Lock()
-	fake.finishedArgsForCall = append(fake.finishedArgsForCall, struct {
-		orphanCount     int
-		finishedCount   int
-		deletedCount    int
-		busyInstances   []string
-		failedInstances []string
-	}{orphanCount, finishedCount, deletedCount, busyInstancesCopy, failedInstancesCopy})
-	fake.recordInvocation("Finished", []interface{}{orphanCount, finishedCount, deletedCount, busyInstancesCopy, failedInstancesCopy})
-	fake.finishedMutex.Unlock()
-	if fake.FinishedStub != nil {
-		fake.FinishedStub(orphanCount, finishedCount, deletedCount, busyInstances, failedInstances)
+}
+
+func (fake *FakeListener) RetryAttemptCallCount() int {
+	fake.retryAttemptMutex.RLock()
+	defer fake.retryAttemptMutex.RUnlock()
+	return len(fake.retryAttemptArgsForCall)
+}
+
+func (fake *FakeListener) RetryAttemptCalls(stub func(int, int)) {
+	fake.retryAttemptMutex.Lock()
+	defer fake.retryAttemptMutex.Unlock()
+	fake.RetryAttemptStub = stub
+}
+
+func (fake *FakeListener) RetryAttemptArgsForCall(i int) (int, int) {
+	fake.retryAttemptMutex.RLock()
+	defer fake.retryAttemptMutex.RUnlock()
+	argsForCall := fake.retryAttemptArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) RetryCanariesAttempt(arg1 int, arg2 int, arg3 int) {
+	fake.retryCanariesAttemptMutex.Lock()
+	fake.retryCanariesAttemptArgsForCall = append(fake.retryCanariesAttemptArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.RetryCanariesAttemptStub
+	fake.recordInvocation("RetryCanariesAttempt", []interface{}{arg1, arg2, arg3})
+	fake.retryCanariesAttemptMutex.Unlock()
+	if stub != nil {
+		fake.RetryCanariesAttemptStub(arg1, arg2, arg3)
 	}
 }
 
-func (fake *FakeListener) FinishedCallCount() int {
-	fake.finishedMutex.RLock()
-	defer fake.finishedMutex.RUnlock()
-	return len(fake.finishedArgsForCall)
+func (fake *FakeListener) RetryCanariesAttemptCallCount() int {
+	fake.retryCanariesAttemptMutex.RLock()
+	defer fake.retryCanariesAttemptMutex.RUnlock()
+	return len(fake.retryCanariesAttemptArgsForCall)
 }
 
-func (fake *FakeListener) FinishedArgsForCall(i int) (int, int, int, []string, []string) {
-	fake.finishedMutex.RLock()
-	defer fake.finishedMutex.RUnlock()
-	return fake.finishedArgsForCall[i].orphanCount, fake.finishedArgsForCall[i].finishedCount, fake.finishedArgsForCall[i].deletedCount, fake.finishedArgsForCall[i].busyInstances, fake.finishedArgsForCall[i].failedInstances
+func (fake *FakeListener) RetryCanariesAttemptCalls(stub func(int, int, int)) {
+	fake.retryCanariesAttemptMutex.Lock()
+	defer fake.retryCanariesAttemptMutex.Unlock()
+	fake.RetryCanariesAttemptStub = stub
 }
 
-func (fake *FakeListener) CanariesStarting(canaries int, filter config.CanarySelectionParams) {
-	fake.canariesStartingMutex.Lock()
-	fake.canariesStartingArgsForCall = append(fake.canariesStartingArgsForCall, struct {
-		canaries int
-		filter   config.CanarySelectionParams
-	}{canaries, filter})
-	fake.recordInvocation("CanariesStarting", []interface{}{canaries, filter})
-	fake.canariesStartingMutex.Unlock()
-	if fake.CanariesStartingStub != nil {
-		fake.CanariesStartingStub(canaries, filter)
+func (fake *FakeListener) RetryCanariesAttemptArgsForCall(i int) (int, int, int) {
+	fake.retryCanariesAttemptMutex.RLock()
+	defer fake.retryCanariesAttemptMutex.RUnlock()
+	argsForCall := fake.retryCanariesAttemptArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeListener) Starting(arg1 int) {
+	fake.startingMutex.Lock()
+	fake.startingArgsForCall = append(fake.startingArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.StartingStub
+	fake.recordInvocation("Starting", []interface{}{arg1})
+	fake.startingMutex.Unlock()
+	if stub != nil {
+		fake.StartingStub(arg1)
 	}
 }
 
-func (fake *FakeListener) CanariesStartingCallCount() int {
-	fake.canariesStartingMutex.RLock()
-	defer fake.canariesStartingMutex.RUnlock()
-	return len(fake.canariesStartingArgsForCall)
+func (fake *FakeListener) StartingCallCount() int {
+	fake.startingMutex.RLock()
+	defer fake.startingMutex.RUnlock()
+	return len(fake.startingArgsForCall)
 }
 
-func (fake *FakeListener) CanariesStartingArgsForCall(i int) (int, config.CanarySelectionParams) {
-	fake.canariesStartingMutex.RLock()
-	defer fake.canariesStartingMutex.RUnlock()
-	return fake.canariesStartingArgsForCall[i].canaries, fake.canariesStartingArgsForCall[i].filter
+func (fake *FakeListener) StartingCalls(stub func(int)) {
+	fake.startingMutex.Lock()
+	defer fake.startingMutex.Unlock()
+	fake.StartingStub = stub
 }
 
-func (fake *FakeListener) CanariesFinished() {
-	fake.canariesFinishedMutex.Lock()
-	fake.canariesFinishedArgsForCall = append(fake.canariesFinishedArgsForCall, struct{}{})
-	fake.recordInvocation("CanariesFinished", []interface{}{})
-	fake.canariesFinishedMutex.Unlock()
-	if fake.CanariesFinishedStub != nil {
-		fake.CanariesFinishedStub()
+func (fake *FakeListener) StartingArgsForCall(i int) int {
+	fake.startingMutex.RLock()
+	defer fake.startingMutex.RUnlock()
+	argsForCall := fake.startingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeListener) StillWaitingForApproval(arg1 int, arg2 time.Duration, arg3 string) {
+	fake.stillWaitingForApprovalMutex.Lock()
+	fake.stillWaitingForApprovalArgsForCall = append(fake.stillWaitingForApprovalArgsForCall, struct {
+		arg1 int
+		arg2 time.Duration
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.StillWaitingForApprovalStub
+	fake.recordInvocation("StillWaitingForApproval", []interface{}{arg1, arg2, arg3})
+	fake.stillWaitingForApprovalMutex.Unlock()
+	if stub != nil {
+		fake.StillWaitingForApprovalStub(arg1, arg2, arg3)
 	}
 }
 
-func (fake *FakeListener) CanariesFinishedCallCount() int {
-	fake.canariesFinishedMutex.RLock()
-	defer fake.canariesFinishedMutex.RUnlock()
-	return len(fake.canariesFinishedArgsForCall)
+func (fake *FakeListener) StillWaitingForApprovalCallCount() int {
+	fake.stillWaitingForApprovalMutex.RLock()
+	defer fake.stillWaitingForApprovalMutex.RUnlock()
+	return len(fake.stillWaitingForApprovalArgsForCall)
+}
+
+func (fake *FakeListener) StillWaitingForApprovalCalls(stub func(int, time.Duration, string)) {
+	fake.stillWaitingForApprovalMutex.Lock()
+	defer fake.stillWaitingForApprovalMutex.Unlock()
+	fake.StillWaitingForApprovalStub = stub
+}
+
+func (fake *FakeListener) StillWaitingForApprovalArgsForCall(i int) (int, time.Duration, string) {
+	fake.stillWaitingForApprovalMutex.RLock()
+	defer fake.stillWaitingForApprovalMutex.RUnlock()
+	argsForCall := fake.stillWaitingForApprovalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeListener) WaitingFor(arg1 string, arg2 int) {
+	fake.waitingForMutex.Lock()
+	fake.waitingForArgsForCall = append(fake.waitingForArgsForCall, struct {
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.WaitingForStub
+	fake.recordInvocation("WaitingFor", []interface{}{arg1, arg2})
+	fake.waitingForMutex.Unlock()
+	if stub != nil {
+		fake.WaitingForStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeListener) WaitingForCallCount() int {
+	fake.waitingForMutex.RLock()
+	defer fake.waitingForMutex.RUnlock()
+	return len(fake.waitingForArgsForCall)
+}
+
+func (fake *FakeListener) WaitingForCalls(stub func(string, int)) {
+	fake.waitingForMutex.Lock()
+	defer fake.waitingForMutex.Unlock()
+	fake.WaitingForStub = stub
+}
+
+func (fake *FakeListener) WaitingForArgsForCall(i int) (string, int) {
+	fake.waitingForMutex.RLock()
+	defer fake.waitingForMutex.RUnlock()
+	argsForCall := fake.waitingForArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeListener) WaitingForApproval(arg1 int, arg2 string) {
+	fake.waitingForApprovalMutex.Lock()
+	fake.waitingForApprovalArgsForCall = append(fake.waitingForApprovalArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.WaitingForApprovalStub
+	fake.recordInvocation("WaitingForApproval", []interface{}{arg1, arg2})
+	fake.waitingForApprovalMutex.Unlock()
+	if stub != nil {
+		fake.WaitingForApprovalStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeListener) WaitingForApprovalCallCount() int {
+	fake.waitingForApprovalMutex.RLock()
+	defer fake.waitingForApprovalMutex.RUnlock()
+	return len(fake.waitingForApprovalArgsForCall)
+}
+
+func (fake *FakeListener) WaitingForApprovalCalls(stub func(int, string)) {
+	fake.waitingForApprovalMutex.Lock()
+	defer fake.waitingForApprovalMutex.Unlock()
+	fake.WaitingForApprovalStub = stub
+}
+
+func (fake *FakeListener) WaitingForApprovalArgsForCall(i int) (int, string) {
+	fake.waitingForApprovalMutex.RLock()
+	defer fake.waitingForApprovalMutex.RUnlock()
+	argsForCall := fake.waitingForApprovalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeListener) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.canariesFinishedMutex.RLock()
+	defer fake.canariesFinishedMutex.RUnlock()
+	fake.canariesStartingMutex.RLock()
+	defer fake.canariesStartingMutex.RUnlock()
+	fake.canaryStageStartingMutex.RLock()
+	defer fake.canaryStageStartingMutex.RUnlock()
+	fake.deferredMutex.RLock()
+	defer fake.deferredMutex.RUnlock()
 	fake.failedToRefreshInstanceInfoMutex.RLock()
 	defer fake.failedToRefreshInstanceInfoMutex.RUnlock()
-	fake.startingMutex.RLock()
-	defer fake.startingMutex.RUnlock()
+	fake.failedToSaveStateMutex.RLock()
+	defer fake.failedToSaveStateMutex.RUnlock()
+	fake.failureToleratedMutex.RLock()
+	defer fake.failureToleratedMutex.RUnlock()
+	fake.finishedMutex.RLock()
+	defer fake.finishedMutex.RUnlock()
+	fake.instanceOperationFailedMutex.RLock()
+	defer fake.instanceOperationFailedMutex.RUnlock()
+	fake.instanceOperationFinishedMutex.RLock()
+	defer fake.instanceOperationFinishedMutex.RUnlock()
+	fake.instanceOperationStartResultMutex.RLock()
+	defer fake.instanceOperationStartResultMutex.RUnlock()
+	fake.instanceOperationStartingMutex.RLock()
+	defer fake.instanceOperationStartingMutex.RUnlock()
+	fake.instanceUpgradePreviewMutex.RLock()
+	defer fake.instanceUpgradePreviewMutex.RUnlock()
+	fake.instancesFilteredMutex.RLock()
+	defer fake.instancesFilteredMutex.RUnlock()
+	fake.instancesToProcessMutex.RLock()
+	defer fake.instancesToProcessMutex.RUnlock()
+	fake.outsideMaintenanceWindowMutex.RLock()
+	defer fake.outsideMaintenanceWindowMutex.RUnlock()
+	fake.pausingAfterCanaryStageMutex.RLock()
+	defer fake.pausingAfterCanaryStageMutex.RUnlock()
+	fake.progressMutex.RLock()
+	defer fake.progressMutex.RUnlock()
+	fake.resumingMutex.RLock()
+	defer fake.resumingMutex.RUnlock()
 	fake.retryAttemptMutex.RLock()
 	defer fake.retryAttemptMutex.RUnlock()
 	fake.retryCanariesAttemptMutex.RLock()
 	defer fake.retryCanariesAttemptMutex.RUnlock()
-	fake.instancesToProcessMutex.RLock()
-	defer fake.instancesToProcessMutex.RUnlock()
-	fake.instanceOperationStartingMutex.RLock()
-	defer fake.instanceOperationStartingMutex.RUnlock()
-	fake.instanceOperationStartResultMutex.RLock()
-	defer fake.instanceOperationStartResultMutex.RUnlock()
-	fake.instanceOperationFinishedMutex.RLock()
-	defer fake.instanceOperationFinishedMutex.RUnlock()
+	fake.startingMutex.RLock()
+	defer fake.startingMutex.RUnlock()
+	fake.stillWaitingForApprovalMutex.RLock()
+	defer fake.stillWaitingForApprovalMutex.RUnlock()
 	fake.waitingForMutex.RLock()
 	defer fake.waitingForMutex.RUnlock()
-	fake.progressMutex.RLock()
-	defer fake.progressMutex.RUnlock()
-	fake.finishedMutex.RLock()
-	defer fake.finishedMutex.RUnlock()
-	fake.canariesStartingMutex.RLock()
-	defer fake.canariesStartingMutex.RUnlock()
-	fake.canariesFinishedMutex.RLock()
-	defer fake.canariesFinishedMutex.RUnlock()
+	fake.waitingForApprovalMutex.RLock()
+	defer fake.waitingForApprovalMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
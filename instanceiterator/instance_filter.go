@@ -0,0 +1,135 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+// InstanceFilter selects instances by plan, by selection params, such as
+// cf_org and cf_space, or by GUID. An instance matching any one criterion
+// matches the filter.
+type InstanceFilter struct {
+	PlanIDs         []string
+	SelectionParams []map[string]string
+	GUIDs           []string
+}
+
+type InstanceFilters struct {
+	Include InstanceFilter
+	Exclude InstanceFilter
+}
+
+func NewInstanceFilters(conf config.InstanceFilters) (InstanceFilters, error) {
+	include, err := newInstanceFilter(conf.Include)
+	if err != nil {
+		return InstanceFilters{}, fmt.Errorf("invalid include filter: %s", err)
+	}
+	exclude, err := newInstanceFilter(conf.Exclude)
+	if err != nil {
+		return InstanceFilters{}, fmt.Errorf("invalid exclude filter: %s", err)
+	}
+	return InstanceFilters{Include: include, Exclude: exclude}, nil
+}
+
+func (f InstanceFilters) IsEmpty() bool {
+	return f.Include.IsEmpty() && f.Exclude.IsEmpty()
+}
+
+func (f InstanceFilter) IsEmpty() bool {
+	return len(f.PlanIDs) == 0 && len(f.SelectionParams) == 0 && len(f.GUIDs) == 0
+}
+
+func newInstanceFilter(conf config.InstanceFilter) (InstanceFilter, error) {
+	for i, params := range conf.SelectionParams {
+		if len(params) == 0 {
+			return InstanceFilter{}, fmt.Errorf("selection params %d are empty", i)
+		}
+	}
+
+	var guids []string
+	guids = append(guids, conf.InstanceGUIDs...)
+	if conf.InstanceGUIDsFile != "" {
+		fileGUIDs, err := readGUIDsFile(conf.InstanceGUIDsFile)
+		if err != nil {
+			return InstanceFilter{}, fmt.Errorf("error reading instance GUIDs file: %s", err)
+		}
+		guids = append(guids, fileGUIDs...)
+	}
+
+	return InstanceFilter{
+		PlanIDs:         conf.PlanIDs,
+		SelectionParams: conf.SelectionParams,
+		GUIDs:           guids,
+	}, nil
+}
+
+// readGUIDsFile reads one GUID per line, ignoring blank lines and lines
+// starting with #.
+func readGUIDsFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	guids := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		guids = append(guids, line)
+	}
+	return guids, scanner.Err()
+}
+
+// matchingGUIDs returns the GUIDs of the instances that match the filter.
+func (f InstanceFilter) matchingGUIDs(instances []service.Instance, lister InstanceLister) (map[string]bool, error) {
+	matches := map[string]bool{}
+
+	plans := map[string]bool{}
+	for _, planID := range f.PlanIDs {
+		plans[planID] = true
+	}
+	for _, instance := range instances {
+		if plans[instance.PlanUniqueID] {
+			matches[instance.GUID] = true
+		}
+	}
+
+	for _, guid := range f.GUIDs {
+		matches[guid] = true
+	}
+
+	for _, params := range f.SelectionParams {
+		selected, err := lister.FilteredInstances(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range selected {
+			matches[instance.GUID] = true
+		}
+	}
+	return matches, nil
+}
@@ -31,17 +31,31 @@ type Listener interface {
 	InstanceOperationStarting(instance string, index int, totalInstances int, isCanary bool)
 	InstanceOperationStartResult(instance string, status services.BOSHOperationType)
 	InstanceOperationFinished(instance string, result string)
+	InstanceOperationFailed(instance string, err error)
 	WaitingFor(instance string, boshTaskId int)
 	Progress(pollingInterval time.Duration, orphanCount, processedCount, toRetryCount, deletedCount int)
 	Finished(orphanCount, finishedCount, deletedCount int, busyInstances, failedInstances []string)
 	CanariesStarting(canaries int, filter config.CanarySelectionParams)
 	CanariesFinished()
+	CanaryStageStarting(stage, totalStages int)
+	PausingAfterCanaryStage(stage int, pause time.Duration)
+	WaitingForApproval(stage int, howToApprove string)
+	StillWaitingForApproval(stage int, waited time.Duration, howToApprove string)
+	InstanceUpgradePreview(instance string, preview broker.UpgradePreview)
+	Resuming(completedCount int)
+	FailedToSaveState(err error)
+	OutsideMaintenanceWindow(instance string, window MaintenanceWindow)
+	Deferred(instances []string)
+	InstancesFiltered(totalCount, selectedCount int)
+	FailureTolerated(instance string, failureCount, maxFailures int)
 }
 
 //go:generate counterfeiter -o fakes/fake_broker_services.go . BrokerServices
 type BrokerServices interface {
 	ProcessInstance(instance service.Instance, operationType string) (services.BOSHOperation, error)
 	LastOperation(instance string, operationData broker.OperationData) (brokerapi.LastOperation, error)
+	PreviewUpgrade(instance service.Instance) (services.BOSHOperation, broker.UpgradePreview, error)
+	Catalog() (brokerapi.CatalogResponse, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_instance_lister.go . InstanceLister
@@ -77,18 +91,42 @@ type Iterator struct {
 	attemptInterval time.Duration
 	attemptLimit    int
 	maxInFlight     int
+	maxFailures     int
+	maxFailurePct   int
+	planMaxInFlight map[string]int
 	listener        Listener
 	sleeper         sleeper
 
 	failures              []instanceFailure
 	canaries              int
 	canarySelectionParams config.CanarySelectionParams
+	canaryStages          []CanaryStage
+	stageInstances        [][]service.Instance
+	fleetSize             int
+	approver              Approver
 	iteratorState         *iteratorState
 	triggerer             Triggerer
 	stateChecker          StateChecker
+	checkpointer          Checkpointer
+	resume                bool
+	operation             string
+	run                   RunIdentity
+	completedStates       map[string]services.BOSHOperationType
+	clock                 clock
+	defaultWindow         *MaintenanceWindow
+	scopedWindows         []ScopedMaintenanceWindow
+	instanceWindows       map[string]MaintenanceWindow
+	instanceFilters       InstanceFilters
+	concurrencyLimits     []config.ConcurrencyLimit
+	concurrencyGroups     []map[string]bool
 }
 
 func New(builder *Builder) *Iterator {
+	canaryStages := builder.CanaryStages
+	if len(canaryStages) == 0 && (builder.Canaries > 0 || len(builder.CanarySelectionParams) > 0) {
+		canaryStages = []CanaryStage{{Canaries: builder.Canaries, SelectionParams: builder.CanarySelectionParams}}
+	}
+
 	return &Iterator{
 		brokerServices:        builder.BrokerServices,
 		instanceLister:        builder.ServiceInstanceLister,
@@ -96,12 +134,27 @@ func New(builder *Builder) *Iterator {
 		attemptInterval:       builder.AttemptInterval,
 		attemptLimit:          builder.AttemptLimit,
 		maxInFlight:           builder.MaxInFlight,
+		maxFailures:           builder.MaxFailures,
+		maxFailurePct:         builder.MaxFailurePercentage,
+		planMaxInFlight:       builder.PlanMaxInFlight,
 		listener:              builder.Listener,
 		sleeper:               builder.Sleeper,
 		canaries:              builder.Canaries,
 		canarySelectionParams: builder.CanarySelectionParams,
+		canaryStages:          canaryStages,
+		approver:              builder.Approver,
 		triggerer:             builder.Triggerer,
 		stateChecker:          NewStateChecker(builder.BrokerServices),
+		checkpointer:          builder.Checkpointer,
+		resume:                builder.Resume,
+		operation:             builder.Operation,
+		completedStates:       map[string]services.BOSHOperationType{},
+		clock:                 builder.Clock,
+		defaultWindow:         builder.DefaultMaintenanceWindow,
+		scopedWindows:         builder.ScopedMaintenanceWindows,
+		instanceWindows:       map[string]MaintenanceWindow{},
+		instanceFilters:       builder.InstanceFilters,
+		concurrencyLimits:     builder.ConcurrencyLimits,
 	}
 }
 
@@ -112,9 +165,25 @@ func (it *Iterator) Iterate() error {
 		return err
 	}
 
+	if it.checkpointer != nil {
+		if err := it.checkpointer.Save(it.checkpoint(false)); err != nil {
+			return fmt.Errorf("error saving iterator state: %s", err)
+		}
+	}
+
 	it.listener.InstancesToProcess(it.iteratorState.AllInstances())
 
-	if it.iteratorState.IsProcessingCanaries() {
+	for stage := range it.canaryStages {
+		if stage > 0 {
+			it.startCanaryStage(stage)
+		}
+		if !it.iteratorState.IsProcessingCanaries() {
+			continue
+		}
+
+		if len(it.canaryStages) > 1 {
+			it.listener.CanaryStageStarting(stage+1, len(it.canaryStages))
+		}
 		it.listener.CanariesStarting(it.iteratorState.OutstandingCanaryCount(), it.canarySelectionParams)
 		if err := it.IterateInstancesWithAttempts(); err != nil {
 			it.printSummary()
@@ -122,6 +191,11 @@ func (it *Iterator) Iterate() error {
 		}
 		it.iteratorState.MarkCanariesCompleted()
 		it.listener.CanariesFinished()
+
+		if err := it.waitAfterCanaryStage(stage); err != nil {
+			it.printSummary()
+			return err
+		}
 	}
 
 	if err := it.IterateInstancesWithAttempts(); err != nil {
@@ -129,6 +203,7 @@ func (it *Iterator) Iterate() error {
 		return err
 	}
 	it.printSummary()
+	it.saveFinished()
 	return nil
 }
 
@@ -138,17 +213,18 @@ func (it *Iterator) IterateInstancesWithAttempts() error {
 		it.logRetryAttempt(attempt)
 
 		for it.iteratorState.HasInstancesToProcess() {
-			if !it.iteratorState.HasFailures() {
+			if !it.failureBudgetExhausted() {
 				it.triggerOperation()
 			}
 			it.pollRunningTasks()
+			it.saveStates()
 
 			if it.iteratorState.HasInstancesProcessing() {
 				it.sleeper.Sleep(it.pollingInterval)
 				continue
 			}
 
-			if it.iteratorState.HasFailures() {
+			if it.failureBudgetExhausted() {
 				return it.formatError()
 			}
 
@@ -165,37 +241,101 @@ func (it *Iterator) IterateInstancesWithAttempts() error {
 
 		it.sleeper.Sleep(it.attemptInterval)
 	}
+	if len(it.failures) > 0 {
+		return it.formatError()
+	}
 	return it.checkStillBusyInstances()
 }
 
+// failureBudgetExhausted is true once more instances have failed than the
+// configured budget tolerates. Canaries tolerate no failures.
+func (it *Iterator) failureBudgetExhausted() bool {
+	if it.iteratorState.IsProcessingCanaries() {
+		return len(it.failures) > 0
+	}
+	return len(it.failures) > it.failureBudget()
+}
+
+func (it *Iterator) failureBudget() int {
+	if it.maxFailurePct > 0 {
+		return len(it.iteratorState.AllInstances()) * it.maxFailurePct / 100
+	}
+	return it.maxFailures
+}
+
+func (it *Iterator) recordFailure(guid string, err error) {
+	it.iteratorState.SetState(guid, services.OperationFailed)
+	it.failures = append(it.failures, instanceFailure{guid: guid, err: err})
+	it.listener.InstanceOperationFailed(guid, err)
+	if !it.failureBudgetExhausted() {
+		it.listener.FailureTolerated(guid, len(it.failures), it.failureBudget())
+	}
+}
+
 func (it *Iterator) registerInstancesAndCanaries() error {
-	var canaryInstances []service.Instance
+	if it.checkpointer != nil {
+		run, err := it.runIdentity()
+		if err != nil {
+			return fmt.Errorf("error retrieving the broker catalog: %s", err)
+		}
+		it.run = run
+	}
 
 	allInstances, err := it.instanceLister.Instances()
 	if err != nil {
 		return fmt.Errorf("error listing service instances: %s", err)
 	}
 
-	if len(it.canarySelectionParams) > 0 {
-		canaryInstances, err = it.instanceLister.FilteredInstances(it.canarySelectionParams)
+	filtering := !it.instanceFilters.IsEmpty()
+	if filtering {
+		selectedInstances, err := it.filterInstances(allInstances)
 		if err != nil {
-			return fmt.Errorf("error listing service instances: %s", err)
+			return fmt.Errorf("error filtering service instances: %s", err)
 		}
-		if len(canaryInstances) == 0 && len(allInstances) > 0 {
-			return fmt.Errorf("Failed to find a match to the canary selection criteria: %s. "+
-				"Please ensure these selection criteria will match one or more service instances, "+
-				"or remove `canary_selection_params` to disable selecting canaries from a specific org and space.", it.canarySelectionParams)
+		it.listener.InstancesFiltered(len(allInstances), len(selectedInstances))
+		allInstances = selectedInstances
+	}
+
+	it.fleetSize = len(allInstances)
+	for _, stage := range it.canaryStages {
+		canaryInstances, err := it.canaryCandidates(stage, allInstances, filtering)
+		if err != nil {
+			return err
 		}
-		if len(canaryInstances) < it.canaries {
+		it.stageInstances = append(it.stageInstances, canaryInstances)
+	}
+
+	canaryInstances := []service.Instance{}
+	if len(it.canaryStages) > 0 {
+		canaryInstances = it.stageInstances[0]
+		it.canaries = it.stageCanaryCount(0)
+		it.canarySelectionParams = it.canaryStages[0].SelectionParams
+		if len(it.canarySelectionParams) > 0 && len(canaryInstances) < it.canaries {
 			it.canaries = len(canaryInstances)
 		}
-	} else {
-		if it.canaries > 0 {
-			canaryInstances = allInstances
-		} else {
-			canaryInstances = []service.Instance{}
+	}
+
+	if err := it.assignMaintenanceWindows(allInstances); err != nil {
+		return err
+	}
+	if err := it.assignConcurrencyGroups(); err != nil {
+		return err
+	}
+	if it.resume {
+		if allInstances, err = it.skipCompletedInstances(allInstances); err != nil {
+			return err
+		}
+		for i := range it.stageInstances {
+			it.stageInstances[i] = it.withoutCompletedInstances(it.stageInstances[i])
+		}
+		if len(it.stageInstances) > 0 {
+			canaryInstances = it.stageInstances[0]
+		}
+		if len(canaryInstances) < it.canaries {
+			it.canaries = len(canaryInstances)
 		}
 	}
+
 	it.iteratorState, err = NewIteratorState(canaryInstances, allInstances, it.canaries)
 	if err != nil {
 		return fmt.Errorf("error with canary instance listing: %s", err)
@@ -203,6 +343,318 @@ func (it *Iterator) registerInstancesAndCanaries() error {
 	return nil
 }
 
+// canaryCandidates lists the instances a canary stage picks its canaries from.
+func (it *Iterator) canaryCandidates(stage CanaryStage, allInstances []service.Instance, filtering bool) ([]service.Instance, error) {
+	if len(stage.SelectionParams) == 0 {
+		return allInstances, nil
+	}
+
+	canaryInstances, err := it.instanceLister.FilteredInstances(stage.SelectionParams)
+	if err != nil {
+		return nil, fmt.Errorf("error listing service instances: %s", err)
+	}
+	if filtering {
+		canaryInstances = onlyInstancesIn(canaryInstances, allInstances)
+	}
+	if len(canaryInstances) == 0 && len(allInstances) > 0 {
+		return nil, fmt.Errorf("Failed to find a match to the canary selection criteria: %s. "+
+			"Please ensure these selection criteria will match one or more service instances, "+
+			"or remove `canary_selection_params` to disable selecting canaries from a specific org and space.", stage.SelectionParams)
+	}
+	return canaryInstances, nil
+}
+
+// stageCanaryCount is the number of canaries a stage processes, where 0 means
+// all of its candidates. Percentages are of all the instances to process,
+// rounded up.
+func (it *Iterator) stageCanaryCount(stage int) int {
+	if percentage := it.canaryStages[stage].CanaryPercentage; percentage > 0 {
+		return (it.fleetSize*percentage + 99) / 100
+	}
+	return it.canaryStages[stage].Canaries
+}
+
+// startCanaryStage picks the canaries for a stage after the first from the
+// instances that earlier stages did not process.
+func (it *Iterator) startCanaryStage(stage int) {
+	it.canaries = it.stageCanaryCount(stage)
+	it.canarySelectionParams = it.canaryStages[stage].SelectionParams
+	pending := it.iteratorState.StartCanaryStage(it.stageInstances[stage], it.canaries)
+	if pending < it.canaries {
+		it.canaries = pending
+	}
+}
+
+// waitAfterCanaryStage pauses and then waits for approval, if the stage asks
+// for either, before the next stage starts.
+// approvalReminderInterval is how often the iterator logs again that it is
+// waiting for approval, so that the wait stays visible in long errand logs.
+const approvalReminderInterval = 10 * time.Minute
+
+func (it *Iterator) waitAfterCanaryStage(stage int) error {
+	canaryStage := it.canaryStages[stage]
+	if canaryStage.Pause > 0 {
+		it.listener.PausingAfterCanaryStage(stage+1, canaryStage.Pause)
+		it.sleeper.Sleep(canaryStage.Pause)
+	}
+	if !canaryStage.RequireApproval {
+		return nil
+	}
+
+	it.listener.WaitingForApproval(stage+1, it.approver.String())
+	var waited, sinceReminder time.Duration
+	for {
+		approved, err := it.approver.Approved()
+		if err != nil {
+			return fmt.Errorf("error checking approval to continue after canary stage %d: %s", stage+1, err)
+		}
+		if approved {
+			return nil
+		}
+		if canaryStage.ApprovalTimeout > 0 && waited >= canaryStage.ApprovalTimeout {
+			return fmt.Errorf("canary stage %d was not approved within %s", stage+1, canaryStage.ApprovalTimeout)
+		}
+		if sinceReminder >= approvalReminderInterval {
+			it.listener.StillWaitingForApproval(stage+1, waited, it.approver.String())
+			sinceReminder = 0
+		}
+		it.sleeper.Sleep(it.pollingInterval)
+		waited += it.pollingInterval
+		sinceReminder += it.pollingInterval
+	}
+}
+
+// skipCompletedInstances drops the instances that a previous run processed,
+// according to the saved states. Instances that failed or were busy are
+// processed again.
+func (it *Iterator) skipCompletedInstances(allInstances []service.Instance) ([]service.Instance, error) {
+	previous, err := it.checkpointer.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading iterator state: %s", err)
+	}
+	if previous == nil {
+		previous = &Checkpoint{Run: it.run}
+	}
+	if previous.Finished {
+		return nil, errors.New("unable to resume, the previous run finished: remove the state file to start a new run")
+	}
+	if previous.Run.Operation != it.run.Operation {
+		return nil, fmt.Errorf("unable to resume, the state file was saved by a %q run rather than a %q run: remove the state file to start a new run", previous.Run.Operation, it.run.Operation)
+	}
+	if !previous.Run.Matches(it.run) {
+		return nil, errors.New("unable to resume, the service offering has changed since the state file was saved: remove the state file to start a new run")
+	}
+
+	for guid, state := range previous.Instances {
+		if isCompletedState(state) {
+			it.completedStates[guid] = state
+		}
+	}
+
+	remainingInstances := it.withoutCompletedInstances(allInstances)
+	it.listener.Resuming(len(allInstances) - len(remainingInstances))
+	return remainingInstances, nil
+}
+
+func (it *Iterator) withoutCompletedInstances(instances []service.Instance) []service.Instance {
+	remaining := []service.Instance{}
+	for _, instance := range instances {
+		if _, completed := it.completedStates[instance.GUID]; !completed {
+			remaining = append(remaining, instance)
+		}
+	}
+	return remaining
+}
+
+// filterInstances keeps the instances selected by the include filter, or all of
+// them when there is none, and then drops those selected by the exclude filter.
+func (it *Iterator) filterInstances(allInstances []service.Instance) ([]service.Instance, error) {
+	var included map[string]bool
+	if !it.instanceFilters.Include.IsEmpty() {
+		var err error
+		included, err = it.instanceFilters.Include.matchingGUIDs(allInstances, it.instanceLister)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	excluded, err := it.instanceFilters.Exclude.matchingGUIDs(allInstances, it.instanceLister)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := []service.Instance{}
+	for _, instance := range allInstances {
+		if included != nil && !included[instance.GUID] {
+			continue
+		}
+		if excluded[instance.GUID] {
+			continue
+		}
+		selected = append(selected, instance)
+	}
+	return selected, nil
+}
+
+func onlyInstancesIn(instances, allowed []service.Instance) []service.Instance {
+	allowedGUIDs := map[string]bool{}
+	for _, instance := range allowed {
+		allowedGUIDs[instance.GUID] = true
+	}
+
+	kept := []service.Instance{}
+	for _, instance := range instances {
+		if allowedGUIDs[instance.GUID] {
+			kept = append(kept, instance)
+		}
+	}
+	return kept
+}
+
+// assignMaintenanceWindows works out the window of each instance. The first
+// override whose selection matches an instance wins, otherwise the default
+// window applies.
+func (it *Iterator) assignMaintenanceWindows(allInstances []service.Instance) error {
+	for _, scoped := range it.scopedWindows {
+		instances, err := it.instanceLister.FilteredInstances(scoped.SelectionParams)
+		if err != nil {
+			return fmt.Errorf("error listing service instances for maintenance window %s: %s", scoped.MaintenanceWindow, err)
+		}
+		for _, instance := range instances {
+			if _, assigned := it.instanceWindows[instance.GUID]; !assigned {
+				it.instanceWindows[instance.GUID] = scoped.MaintenanceWindow
+			}
+		}
+	}
+
+	if it.defaultWindow != nil {
+		for _, instance := range allInstances {
+			if _, assigned := it.instanceWindows[instance.GUID]; !assigned {
+				it.instanceWindows[instance.GUID] = *it.defaultWindow
+			}
+		}
+	}
+	return nil
+}
+
+func (it *Iterator) assignConcurrencyGroups() error {
+	for _, limit := range it.concurrencyLimits {
+		instances, err := it.instanceLister.FilteredInstances(limit.SelectionParams)
+		if err != nil {
+			return fmt.Errorf("error listing service instances for concurrency limit %v: %s", limit.SelectionParams, err)
+		}
+		members := map[string]bool{}
+		for _, instance := range instances {
+			members[instance.GUID] = true
+		}
+		it.concurrencyGroups = append(it.concurrencyGroups, members)
+	}
+	return nil
+}
+
+// withinConcurrencyLimits reports whether an operation on the instance can
+// start now. Instances of plans with their own max in flight are limited by it,
+// the rest share the global max in flight, and every concurrency limit that
+// selects the instance must also have room.
+func (it *Iterator) withinConcurrencyLimits(instance service.Instance) bool {
+	inProgress := it.iteratorState.InProgressInstances()
+
+	limit, planLimited := it.planMaxInFlight[instance.PlanUniqueID]
+	if !planLimited {
+		limit = it.maxInFlight
+	}
+	samePool := 0
+	for _, other := range inProgress {
+		_, otherPlanLimited := it.planMaxInFlight[other.PlanUniqueID]
+		if (planLimited && other.PlanUniqueID == instance.PlanUniqueID) || (!planLimited && !otherPlanLimited) {
+			samePool++
+		}
+	}
+	if samePool >= limit {
+		return false
+	}
+
+	for i, members := range it.concurrencyGroups {
+		if !members[instance.GUID] {
+			continue
+		}
+		inGroup := 0
+		for _, other := range inProgress {
+			if members[other.GUID] {
+				inGroup++
+			}
+		}
+		if inGroup >= it.concurrencyLimits[i].MaxInFlight {
+			return false
+		}
+	}
+	return true
+}
+
+// outsideMaintenanceWindow reports the instance to the listener when its window
+// is closed. Instances without a window are never outside it.
+func (it *Iterator) outsideMaintenanceWindow(guid string) bool {
+	window, ok := it.instanceWindows[guid]
+	if !ok || window.IsOpen(it.clock.Now()) {
+		return false
+	}
+	it.listener.OutsideMaintenanceWindow(guid, window)
+	return true
+}
+
+func (it *Iterator) saveStates() {
+	if it.checkpointer == nil {
+		return
+	}
+	if err := it.checkpointer.Save(it.checkpoint(false)); err != nil {
+		it.listener.FailedToSaveState(err)
+	}
+}
+
+// saveFinished marks the state file as belonging to a run that completed, so
+// that it is never resumed.
+func (it *Iterator) saveFinished() {
+	if it.checkpointer == nil {
+		return
+	}
+	if err := it.checkpointer.Save(it.checkpoint(true)); err != nil {
+		it.listener.FailedToSaveState(err)
+	}
+}
+
+func (it *Iterator) checkpoint(finished bool) Checkpoint {
+	return Checkpoint{Run: it.run, Finished: finished, Instances: it.statesToSave()}
+}
+
+// runIdentity pairs the operation with the maintenance info of every plan, which
+// changes whenever the service offering is upgraded.
+func (it *Iterator) runIdentity() (RunIdentity, error) {
+	catalog, err := it.brokerServices.Catalog()
+	if err != nil {
+		return RunIdentity{}, err
+	}
+
+	maintenanceInfo := map[string]brokerapi.MaintenanceInfo{}
+	for _, svc := range catalog.Services {
+		for _, plan := range svc.Plans {
+			if plan.MaintenanceInfo != nil {
+				maintenanceInfo[plan.ID] = *plan.MaintenanceInfo
+			}
+		}
+	}
+	return RunIdentity{Operation: it.operation, MaintenanceInfo: maintenanceInfo}, nil
+}
+
+// statesToSave includes the instances completed by previous runs, so that a
+// run can be resumed more than once.
+func (it *Iterator) statesToSave() map[string]services.BOSHOperationType {
+	states := it.iteratorState.States()
+	for guid, state := range it.completedStates {
+		states[guid] = state
+	}
+	return states
+}
+
 func (it *Iterator) logRetryAttempt(attempt int) {
 	if it.iteratorState.IsProcessingCanaries() {
 		it.listener.RetryCanariesAttempt(attempt, it.attemptLimit, it.iteratorState.OutstandingCanaryCount())
@@ -212,8 +664,12 @@ func (it *Iterator) logRetryAttempt(attempt int) {
 }
 
 func (it *Iterator) operationsToTriggerCount() int {
+	maxInFlight := it.maxInFlight
+	for _, planMaxInFlight := range it.planMaxInFlight {
+		maxInFlight += planMaxInFlight
+	}
 	inProg := it.iteratorState.CountInProgressInstances()
-	needed := it.maxInFlight - inProg
+	needed := maxInFlight - inProg
 	if it.iteratorState.IsProcessingCanaries() {
 		outstandingCanaries := it.iteratorState.OutstandingCanaryCount()
 		if needed > outstandingCanaries {
@@ -225,7 +681,7 @@ func (it *Iterator) operationsToTriggerCount() int {
 
 func (it *Iterator) triggerOperation() {
 	needed := it.operationsToTriggerCount()
-	if needed == 0 {
+	if needed <= 0 {
 		return
 	}
 
@@ -233,10 +689,14 @@ func (it *Iterator) triggerOperation() {
 
 	acceptedCount := 0
 	for acceptedCount < needed {
-		instance, err := it.iteratorState.NextPending()
+		instance, err := it.iteratorState.NextPendingWhere(it.withinConcurrencyLimits)
 		if err != nil {
 			break
 		}
+		if it.outsideMaintenanceWindow(instance.GUID) {
+			it.iteratorState.SetState(instance.GUID, services.OperationDeferred)
+			continue
+		}
 		it.listener.InstanceOperationStarting(instance.GUID, it.iteratorState.GetIteratorIndex(), totalInstances, it.iteratorState.IsProcessingCanaries())
 
 		var operation services.BOSHOperation
@@ -253,9 +713,11 @@ func (it *Iterator) triggerOperation() {
 		}
 
 		if err != nil {
-			it.iteratorState.SetState(instance.GUID, services.OperationFailed)
-			it.failures = append(it.failures, instanceFailure{guid: instance.GUID, err: err})
-			return
+			it.recordFailure(instance.GUID, err)
+			if it.failureBudgetExhausted() {
+				return
+			}
+			continue
 		}
 		it.iteratorState.SetOperation(instance.GUID, operation)
 		it.iteratorState.SetState(instance.GUID, operation.Type)
@@ -273,8 +735,7 @@ func (it *Iterator) pollRunningTasks() {
 		guid := inst.GUID
 		state, err := it.stateChecker.Check(guid, it.iteratorState.GetOperation(guid).Data)
 		if err != nil {
-			it.iteratorState.SetState(guid, services.OperationFailed)
-			it.failures = append(it.failures, instanceFailure{guid: guid, err: err})
+			it.recordFailure(guid, err)
 			continue
 		}
 		it.iteratorState.SetState(guid, state.Type)
@@ -284,8 +745,7 @@ func (it *Iterator) pollRunningTasks() {
 			it.listener.InstanceOperationFinished(guid, "success")
 		case services.OperationFailed:
 			it.listener.InstanceOperationFinished(guid, "failure")
-			err := fmt.Errorf("[%s] Operation failed: bosh task id %d: %s", guid, state.Data.BoshTaskID, state.Description)
-			it.failures = append(it.failures, instanceFailure{guid: guid, err: err})
+			it.recordFailure(guid, fmt.Errorf("[%s] Operation failed: bosh task id %d: %s", guid, state.Data.BoshTaskID, state.Description))
 		}
 	}
 }
@@ -306,12 +766,22 @@ func (it *Iterator) printSummary() {
 	}
 
 	it.listener.Finished(summary.orphaned, summary.succeeded, summary.deleted, busyInstances, failedInstances)
+
+	if deferredInstances := it.iteratorState.GetGUIDsInStates(services.OperationDeferred); len(deferredInstances) > 0 {
+		it.listener.Deferred(deferredInstances)
+	}
 }
 
 func (it *Iterator) checkStillBusyInstances() error {
 	busyInstances := it.iteratorState.GetGUIDsInStates(services.OperationInProgress)
 	busyInstancesCount := len(busyInstances)
 
+	if it.iteratorState.IsProcessingCanaries() && !it.iteratorState.canariesCompleted() {
+		if deferredCount := len(it.iteratorState.GetGUIDsInStates(services.OperationDeferred)); deferredCount > 0 && busyInstancesCount == 0 {
+			return fmt.Errorf("canaries didn't process successfully: %d canaries are outside their maintenance window", deferredCount)
+		}
+	}
+
 	if busyInstancesCount == 0 {
 		return nil
 	}
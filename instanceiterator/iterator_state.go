@@ -80,7 +80,7 @@ func (is *iteratorState) IsProcessingCanaries() bool {
 func (is *iteratorState) RewindAndResetBusyInstances() {
 	is.pos = 0
 	for k, v := range is.states {
-		if v.status == services.OperationInProgress {
+		if v.status == services.OperationInProgress || v.status == services.OperationDeferred {
 			v.status = services.OperationPending
 			is.states[k] = v
 		}
@@ -122,6 +122,33 @@ func (is *iteratorState) NextPending() (service.Instance, error) {
 	return service.Instance{}, errors.New("Cannot retrieve next pending instance")
 }
 
+// NextPendingWhere returns the next pending instance that accept allows. The
+// pending instances it passes over are considered again by the next call.
+func (is *iteratorState) NextPendingWhere(accept func(service.Instance) bool) (service.Instance, error) {
+	firstSkipped := -1
+	for is.pos < len(is.guids) {
+		guid := is.guids[is.pos]
+		is.pos++
+		if !is.processable(guid) {
+			continue
+		}
+		instance := service.Instance{GUID: guid, PlanUniqueID: is.states[guid].initialPlan}
+		if accept(instance) {
+			if firstSkipped >= 0 {
+				is.pos = firstSkipped
+			}
+			return instance, nil
+		}
+		if firstSkipped < 0 {
+			firstSkipped = is.pos - 1
+		}
+	}
+	if firstSkipped >= 0 {
+		is.pos = firstSkipped
+	}
+	return service.Instance{}, errors.New("Cannot retrieve next pending instance")
+}
+
 func (is *iteratorState) GetIteratorIndex() int {
 	return len(is.GetInstancesInStates(services.OperationSucceeded, services.OperationAccepted, services.InstanceNotFound, services.OrphanDeployment)) + 1
 }
@@ -159,6 +186,15 @@ func (is *iteratorState) Summary() summary {
 	}
 }
 
+// States returns the status of every instance, canary or not.
+func (is *iteratorState) States() map[string]services.BOSHOperationType {
+	states := map[string]services.BOSHOperationType{}
+	for guid, info := range is.states {
+		states[guid] = info.status
+	}
+	return states
+}
+
 func (is *iteratorState) SetState(guid string, status services.BOSHOperationType) error {
 	info := is.states[guid]
 	info.status = status
@@ -192,7 +228,7 @@ func (is *iteratorState) OutstandingCanaryCount() int {
 		if !info.couldBeCanary {
 			continue
 		}
-		if info.status == services.OperationPending {
+		if info.status == services.OperationPending || info.status == services.OperationDeferred {
 			pending++
 		} else {
 			triggered++
@@ -243,6 +279,35 @@ func (is *iteratorState) MarkCanariesCompleted() {
 	is.pos = 0
 }
 
+// StartCanaryStage makes the pending instances among the candidates the
+// canaries of a new canary phase, limited to canaryLimit of them, or all of
+// them when canaryLimit is 0. It returns the number of pending candidates.
+func (is *iteratorState) StartCanaryStage(candidates []service.Instance, canaryLimit int) int {
+	for guid, info := range is.states {
+		info.couldBeCanary = false
+		is.states[guid] = info
+	}
+
+	pending := 0
+	for _, candidate := range candidates {
+		info, ok := is.states[candidate.GUID]
+		if !ok || info.status != services.OperationPending || info.couldBeCanary {
+			continue
+		}
+		info.couldBeCanary = true
+		is.states[candidate.GUID] = info
+		pending++
+	}
+
+	if canaryLimit > pending {
+		canaryLimit = pending
+	}
+	is.canaryLimit = canaryLimit
+	is.processCanaries = pending > 0
+	is.pos = 0
+	return pending
+}
+
 func (is *iteratorState) CountInstancesInCurrentPhase() int {
 	c := 0
 	for _, inst := range is.states {
@@ -274,5 +339,5 @@ func isFinalState(status services.BOSHOperationType) bool {
 	// TODO:
 	// * add tests
 	// * add missing states
-	return status != services.OperationInProgress && status != services.OperationPending && status != services.OperationAccepted //status == services.OperationSucceeded || status == services.OperationFailed
+	return status != services.OperationInProgress && status != services.OperationPending && status != services.OperationAccepted && status != services.OperationDeferred //status == services.OperationSucceeded || status == services.OperationFailed
 }
@@ -184,6 +184,33 @@ var _ = Describe("Iterate State", func() {
 		Expect(next.GUID).To(Equal("guid_3"))
 	})
 
+	It("returns instances passed over by NextPendingWhere on later calls", func() {
+		_, all := instances(func(int) bool { return false }, 4)
+		us, err := instanceiterator.NewIteratorState(nil, all, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		allowed := map[string]bool{"guid_2": true}
+		accept := func(instance service.Instance) bool { return allowed[instance.GUID] }
+
+		next, err := us.NextPendingWhere(accept)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.GUID).To(Equal("guid_2"))
+		us.SetState(next.GUID, services.OperationAccepted)
+
+		_, err = us.NextPendingWhere(accept)
+		Expect(err).To(HaveOccurred())
+
+		allowed["guid_0"] = true
+		allowed["guid_3"] = true
+		next, err = us.NextPendingWhere(accept)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.GUID).To(Equal("guid_0"))
+		us.SetState(next.GUID, services.OperationAccepted)
+
+		next, err = us.NextPendingWhere(accept)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.GUID).To(Equal("guid_3"))
+	})
 })
 
 func instances(isCanary func(int) bool, total int) (canaries []service.Instance, all []service.Instance) {
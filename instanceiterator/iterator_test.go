@@ -16,6 +16,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
@@ -151,6 +152,797 @@ var _ = Describe("Iterator", func() {
 		})
 	})
 
+	Context("saving state", func() {
+		var (
+			fakeCheckpointer *fakes.FakeCheckpointer
+			run              instanceiterator.RunIdentity
+		)
+
+		BeforeEach(func() {
+			fakeCheckpointer = new(fakes.FakeCheckpointer)
+			builder.Checkpointer = fakeCheckpointer
+			builder.Operation = "upgrade"
+			brokerServicesClient.CatalogReturns(brokerapi.CatalogResponse{Services: []brokerapi.Service{{
+				Plans: []brokerapi.ServicePlan{
+					{ID: "plan-id-1", MaintenanceInfo: &brokerapi.MaintenanceInfo{Private: "version-1"}},
+					{ID: "plan-id-2"},
+				},
+			}}}, nil)
+			run = instanceiterator.RunIdentity{
+				Operation:       "upgrade",
+				MaintenanceInfo: map[string]brokerapi.MaintenanceInfo{"plan-id-1": {Private: "version-1"}},
+			}
+
+			instanceLister.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			brokerServicesClient.ProcessInstanceReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Succeeded}, nil)
+		})
+
+		It("saves the state of every instance as it is processed", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCheckpointer.LoadCallCount()).To(BeZero())
+			Expect(fakeCheckpointer.SaveArgsForCall(0)).To(Equal(instanceiterator.Checkpoint{
+				Run: run,
+				Instances: map[string]services.BOSHOperationType{
+					"1": services.OperationPending,
+					"2": services.OperationPending,
+				},
+			}))
+			Expect(fakeCheckpointer.SaveArgsForCall(fakeCheckpointer.SaveCallCount() - 2).Finished).To(BeFalse())
+		})
+
+		It("marks the saved state as finished when the run completes", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCheckpointer.SaveArgsForCall(fakeCheckpointer.SaveCallCount() - 1)).To(Equal(instanceiterator.Checkpoint{
+				Run:      run,
+				Finished: true,
+				Instances: map[string]services.BOSHOperationType{
+					"1": services.OperationSucceeded,
+					"2": services.OperationSucceeded,
+				},
+			}))
+		})
+
+		It("does not mark the saved state as finished when the run fails", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Failed}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(HaveOccurred())
+
+			for i := 0; i < fakeCheckpointer.SaveCallCount(); i++ {
+				Expect(fakeCheckpointer.SaveArgsForCall(i).Finished).To(BeFalse())
+			}
+		})
+
+		It("fails before processing any instance when the broker catalog cannot be retrieved", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+			brokerServicesClient.CatalogReturns(brokerapi.CatalogResponse{}, errors.New("broker down"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("error retrieving the broker catalog: broker down"))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+			Expect(fakeCheckpointer.SaveCallCount()).To(BeZero())
+		})
+
+		It("fails before processing any instance when the state cannot be saved", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+			fakeCheckpointer.SaveReturns(errors.New("disk full"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("error saving iterator state: disk full"))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+		})
+
+		It("reports and carries on when the state cannot be saved part way through", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+			fakeCheckpointer.SaveReturns(errors.New("disk full"))
+			fakeCheckpointer.SaveReturnsOnCall(0, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.FailedToSaveStateCallCount()).To(BeNumerically(">", 0))
+			Expect(fakeListener.FailedToSaveStateArgsForCall(0)).To(MatchError("disk full"))
+			hasReportedFinished(fakeListener, 0, 1, 0, emptyBusyList, emptyFailedList)
+		})
+
+		Context("when resuming", func() {
+			BeforeEach(func() {
+				builder.Resume = true
+				fakeCheckpointer.LoadReturns(&instanceiterator.Checkpoint{
+					Run: run,
+					Instances: map[string]services.BOSHOperationType{
+						"1": services.OperationSucceeded,
+						"2": services.OperationFailed,
+						"3": services.OrphanDeployment,
+						"4": services.OperationInProgress,
+					},
+				}, nil)
+			})
+
+			It("skips the instances completed by the previous run", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}, {GUID: "3"}, {GUID: "4"}, {GUID: "5"}}, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(3))
+				Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("2"))
+				Expect(fakeTriggerer.TriggerOperationArgsForCall(1).GUID).To(Equal("4"))
+				Expect(fakeTriggerer.TriggerOperationArgsForCall(2).GUID).To(Equal("5"))
+
+				Expect(fakeListener.ResumingCallCount()).To(Equal(1))
+				Expect(fakeListener.ResumingArgsForCall(0)).To(Equal(2))
+				hasReportedFinished(fakeListener, 0, 3, 0, emptyBusyList, emptyFailedList)
+			})
+
+			It("keeps the previously completed instances in the saved state", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}, {GUID: "3"}}, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeCheckpointer.SaveArgsForCall(fakeCheckpointer.SaveCallCount() - 1).Instances).To(Equal(map[string]services.BOSHOperationType{
+					"1": services.OperationSucceeded,
+					"2": services.OperationSucceeded,
+					"3": services.OrphanDeployment,
+				}))
+			})
+
+			It("skips canaries that were completed by the previous run", func() {
+				builder.Canaries = 1
+				builder.CanarySelectionParams = config.CanarySelectionParams{"org": "my-org"}
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+				instanceLister.FilteredInstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeListener.CanariesStartingCallCount()).To(BeZero())
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+				Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("2"))
+			})
+
+			It("fails when the saved state cannot be loaded", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+				fakeCheckpointer.LoadReturns(nil, errors.New("corrupt file"))
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).To(MatchError("error loading iterator state: corrupt file"))
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+			})
+
+			It("processes every instance when there is no saved state", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+				fakeCheckpointer.LoadReturns(nil, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(2))
+				Expect(fakeListener.ResumingArgsForCall(0)).To(Equal(0))
+			})
+
+			It("refuses to resume a run that finished", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+				fakeCheckpointer.LoadReturns(&instanceiterator.Checkpoint{
+					Run:       run,
+					Finished:  true,
+					Instances: map[string]services.BOSHOperationType{"1": services.OperationSucceeded},
+				}, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).To(MatchError("unable to resume, the previous run finished: remove the state file to start a new run"))
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+				Expect(fakeCheckpointer.SaveCallCount()).To(BeZero())
+			})
+
+			It("refuses to resume the state of a different operation", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+				builder.Operation = "recreate"
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).To(MatchError(`unable to resume, the state file was saved by a "upgrade" run rather than a "recreate" run: remove the state file to start a new run`))
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+				Expect(fakeCheckpointer.SaveCallCount()).To(BeZero())
+			})
+
+			It("refuses to resume when the service offering has changed", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+				brokerServicesClient.CatalogReturns(brokerapi.CatalogResponse{Services: []brokerapi.Service{{
+					Plans: []brokerapi.ServicePlan{
+						{ID: "plan-id-1", MaintenanceInfo: &brokerapi.MaintenanceInfo{Private: "version-2"}},
+					},
+				}}}, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).To(MatchError("unable to resume, the service offering has changed since the state file was saved: remove the state file to start a new run"))
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+			})
+
+			It("refuses to resume a state file that does not record its run", func() {
+				instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+				fakeCheckpointer.LoadReturns(&instanceiterator.Checkpoint{
+					Instances: map[string]services.BOSHOperationType{"1": services.OperationSucceeded},
+				}, nil)
+
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).To(MatchError(`unable to resume, the state file was saved by a "" run rather than a "upgrade" run: remove the state file to start a new run`))
+			})
+		})
+	})
+
+	Context("canary stages", func() {
+		var fakeApprover *fakes.FakeApprover
+
+		BeforeEach(func() {
+			instanceLister.InstancesReturns([]service.Instance{
+				{GUID: "1"}, {GUID: "2"}, {GUID: "3"}, {GUID: "4"}, {GUID: "5"}, {GUID: "6"},
+			}, nil)
+			instanceLister.FilteredInstancesReturns([]service.Instance{{GUID: "4"}, {GUID: "5"}}, nil)
+			instanceLister.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			brokerServicesClient.ProcessInstanceReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Succeeded}, nil)
+
+			fakeApprover = new(fakes.FakeApprover)
+			fakeApprover.StringReturns("create the approval file")
+			builder.Approver = fakeApprover
+			builder.CanaryStages = []instanceiterator.CanaryStage{
+				{Canaries: 1, SelectionParams: config.CanarySelectionParams{"cf_org": "platform", "cf_space": "canaries"}},
+				{CanaryPercentage: 50},
+			}
+		})
+
+		triggeredGUIDs := func() []string {
+			guids := []string{}
+			for i := 0; i < fakeTriggerer.TriggerOperationCallCount(); i++ {
+				guids = append(guids, fakeTriggerer.TriggerOperationArgsForCall(i).GUID)
+			}
+			return guids
+		}
+
+		It("processes each stage in turn before the remaining instances", func() {
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(triggeredGUIDs()).To(Equal([]string{"4", "1", "2", "3", "5", "6"}))
+
+			Expect(fakeListener.CanaryStageStartingCallCount()).To(Equal(2))
+			stage, totalStages := fakeListener.CanaryStageStartingArgsForCall(1)
+			Expect(stage).To(Equal(2))
+			Expect(totalStages).To(Equal(2))
+
+			Expect(fakeListener.CanariesStartingCallCount()).To(Equal(2))
+			canaries, filter := fakeListener.CanariesStartingArgsForCall(0)
+			Expect(canaries).To(Equal(1))
+			Expect(filter).To(Equal(config.CanarySelectionParams{"cf_org": "platform", "cf_space": "canaries"}))
+			canaries, filter = fakeListener.CanariesStartingArgsForCall(1)
+			Expect(canaries).To(Equal(3))
+			Expect(filter).To(BeEmpty())
+			Expect(fakeListener.CanariesFinishedCallCount()).To(Equal(2))
+			hasReportedFinished(fakeListener, 0, 6, 0, emptyBusyList, emptyFailedList)
+		})
+
+		It("does not run later stages when a stage fails", func() {
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Failed}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError(ContainSubstring("canaries didn't process successfully")))
+
+			Expect(triggeredGUIDs()).To(Equal([]string{"4"}))
+			Expect(fakeListener.CanaryStageStartingCallCount()).To(Equal(1))
+		})
+
+		It("skips a stage whose candidates were all processed by earlier stages", func() {
+			builder.CanaryStages = []instanceiterator.CanaryStage{
+				{SelectionParams: config.CanarySelectionParams{"cf_org": "platform", "cf_space": "canaries"}},
+				{Canaries: 1, SelectionParams: config.CanarySelectionParams{"cf_org": "platform", "cf_space": "canaries"}},
+			}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(triggeredGUIDs()).To(Equal([]string{"4", "5", "1", "2", "3", "6"}))
+			Expect(fakeListener.CanaryStageStartingCallCount()).To(Equal(1))
+		})
+
+		It("pauses after a stage", func() {
+			builder.CanaryStages[0].Pause = 5 * time.Minute
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.PausingAfterCanaryStageCallCount()).To(Equal(1))
+			stage, pause := fakeListener.PausingAfterCanaryStageArgsForCall(0)
+			Expect(stage).To(Equal(1))
+			Expect(pause).To(Equal(5 * time.Minute))
+			Expect(fakeSleeper.SleepArgsForCall(0)).To(Equal(5 * time.Minute))
+		})
+
+		It("waits for approval after a stage", func() {
+			builder.CanaryStages[1].RequireApproval = true
+			fakeApprover.ApprovedReturnsOnCall(0, false, nil)
+			fakeApprover.ApprovedReturnsOnCall(1, true, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.WaitingForApprovalCallCount()).To(Equal(1))
+			stage, howToApprove := fakeListener.WaitingForApprovalArgsForCall(0)
+			Expect(stage).To(Equal(2))
+			Expect(howToApprove).To(Equal("create the approval file"))
+			Expect(fakeApprover.ApprovedCallCount()).To(Equal(2))
+			Expect(fakeSleeper.SleepArgsForCall(0)).To(Equal(builder.PollingInterval))
+			Expect(triggeredGUIDs()).To(HaveLen(6))
+		})
+
+		It("fails when a stage is not approved within its approval timeout", func() {
+			builder.CanaryStages[0].RequireApproval = true
+			builder.CanaryStages[0].ApprovalTimeout = 30 * time.Second
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("canary stage 1 was not approved within 30s"))
+
+			Expect(fakeApprover.ApprovedCallCount()).To(Equal(4))
+			Expect(triggeredGUIDs()).To(Equal([]string{"4"}))
+			Expect(fakeListener.FinishedCallCount()).To(Equal(1))
+		})
+
+		It("logs again that it is waiting for approval every ten minutes", func() {
+			builder.CanaryStages[0].RequireApproval = true
+			approvals := 0
+			fakeApprover.ApprovedStub = func() (bool, error) {
+				approvals++
+				return approvals > 150, nil
+			}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.WaitingForApprovalCallCount()).To(Equal(1))
+			Expect(fakeListener.StillWaitingForApprovalCallCount()).To(Equal(2))
+			stage, waited, howToApprove := fakeListener.StillWaitingForApprovalArgsForCall(1)
+			Expect(stage).To(Equal(1))
+			Expect(waited).To(Equal(20 * time.Minute))
+			Expect(howToApprove).To(Equal("create the approval file"))
+		})
+
+		It("stops when approval cannot be checked", func() {
+			builder.CanaryStages[0].RequireApproval = true
+			fakeApprover.ApprovedReturns(false, errors.New("permission denied"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("error checking approval to continue after canary stage 1: permission denied"))
+
+			Expect(triggeredGUIDs()).To(Equal([]string{"4"}))
+			Expect(fakeListener.FinishedCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("concurrency limits", func() {
+		var maxConcurrent map[string]int
+
+		BeforeEach(func() {
+			instanceLister.InstancesReturns([]service.Instance{
+				{GUID: "large-1", PlanUniqueID: "large"},
+				{GUID: "large-2", PlanUniqueID: "large"},
+				{GUID: "small-1", PlanUniqueID: "small"},
+				{GUID: "small-2", PlanUniqueID: "small"},
+				{GUID: "small-3", PlanUniqueID: "small"},
+				{GUID: "small-4", PlanUniqueID: "small"},
+			}, nil)
+			instanceLister.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+
+			maxConcurrent = map[string]int{}
+			inFlight := map[string]string{}
+			polls := map[string]int{}
+			brokerServicesClient.ProcessInstanceStub = func(instance service.Instance, _ string) (services.BOSHOperation, error) {
+				inFlight[instance.GUID] = instance.PlanUniqueID
+				counts := map[string]int{"total": len(inFlight)}
+				for guid, plan := range inFlight {
+					counts[plan]++
+					if guid == "small-1" || guid == "small-2" || guid == "small-3" {
+						counts["selected"]++
+					}
+					if guid == "large-1" || guid == "large-2" {
+						counts["org-a"]++
+						counts["orgs"]++
+					}
+					if guid == "small-1" || guid == "small-2" {
+						counts["org-b"]++
+						counts["orgs"]++
+					}
+				}
+				for key, count := range counts {
+					if count > maxConcurrent[key] {
+						maxConcurrent[key] = count
+					}
+				}
+				return services.BOSHOperation{Type: services.OperationAccepted}, nil
+			}
+			brokerServicesClient.LastOperationStub = func(guid string, _ broker.OperationData) (brokerapi.LastOperation, error) {
+				polls[guid]++
+				if polls[guid] < 2 {
+					return brokerapi.LastOperation{State: brokerapi.InProgress}, nil
+				}
+				delete(inFlight, guid)
+				return brokerapi.LastOperation{State: brokerapi.Succeeded}, nil
+			}
+		})
+
+		It("limits plans with their own max in flight separately from the rest", func() {
+			builder.MaxInFlight = 1
+			builder.PlanMaxInFlight = map[string]int{"small": 3}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(6))
+			Expect(maxConcurrent["large"]).To(Equal(1))
+			Expect(maxConcurrent["small"]).To(Equal(3))
+			Expect(maxConcurrent["total"]).To(Equal(4))
+			hasReportedFinished(fakeListener, 0, 6, 0, emptyBusyList, emptyFailedList)
+		})
+
+		It("limits the instances selected by a concurrency limit", func() {
+			builder.MaxInFlight = 4
+			builder.ConcurrencyLimits = []config.ConcurrencyLimit{
+				{SelectionParams: map[string]string{"cf_org": "prod", "cf_space": "storage"}, MaxInFlight: 1},
+			}
+			instanceLister.FilteredInstancesReturns([]service.Instance{{GUID: "small-1"}, {GUID: "small-2"}, {GUID: "small-3"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instanceLister.FilteredInstancesArgsForCall(0)).To(Equal(map[string]string{"cf_org": "prod", "cf_space": "storage"}))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(6))
+			Expect(maxConcurrent["selected"]).To(Equal(1))
+			Expect(maxConcurrent["total"]).To(Equal(4))
+		})
+
+		It("limits each enumerated selection separately", func() {
+			builder.MaxInFlight = 4
+			builder.ConcurrencyLimits = []config.ConcurrencyLimit{
+				{SelectionParams: map[string]string{"cf_org": "org-a"}, MaxInFlight: 1},
+				{SelectionParams: map[string]string{"cf_org": "org-b"}, MaxInFlight: 1},
+			}
+			instanceLister.FilteredInstancesStub = func(params map[string]string) ([]service.Instance, error) {
+				if params["cf_org"] == "org-a" {
+					return []service.Instance{{GUID: "large-1"}, {GUID: "large-2"}}, nil
+				}
+				return []service.Instance{{GUID: "small-1"}, {GUID: "small-2"}}, nil
+			}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(6))
+			Expect(maxConcurrent["org-a"]).To(Equal(1))
+			Expect(maxConcurrent["org-b"]).To(Equal(1))
+			Expect(maxConcurrent["orgs"]).To(Equal(2))
+		})
+
+		It("fails when the instances for a concurrency limit cannot be listed", func() {
+			builder.ConcurrencyLimits = []config.ConcurrencyLimit{
+				{SelectionParams: map[string]string{"cf_org": "prod", "cf_space": "storage"}, MaxInFlight: 1},
+			}
+			instanceLister.FilteredInstancesReturns(nil, errors.New("cf unavailable"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("error listing service instances for concurrency limit map[cf_org:prod cf_space:storage]: cf unavailable"))
+		})
+	})
+
+	Context("failure budget", func() {
+		BeforeEach(func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}, {GUID: "3"}, {GUID: "4"}}, nil)
+			instanceLister.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			brokerServicesClient.ProcessInstanceReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+			brokerServicesClient.LastOperationStub = func(guid string, _ broker.OperationData) (brokerapi.LastOperation, error) {
+				if guid == "1" || guid == "3" {
+					return brokerapi.LastOperation{State: brokerapi.Failed, Description: "disk corrupted"}, nil
+				}
+				return brokerapi.LastOperation{State: brokerapi.Succeeded}, nil
+			}
+		})
+
+		It("stops at the first failure by default", func() {
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("[1] Operation failed: bosh task id 0: disk corrupted"))
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+			Expect(fakeListener.InstanceOperationFailedCallCount()).To(Equal(1))
+			failedGUID, failure := fakeListener.InstanceOperationFailedArgsForCall(0)
+			Expect(failedGUID).To(Equal("1"))
+			Expect(failure).To(Equal(err))
+			Expect(fakeListener.FailureToleratedCallCount()).To(BeZero())
+		})
+
+		It("carries on processing while failures are within max failures and reports them all", func() {
+			builder.MaxFailures = 2
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError(ContainSubstring("2 errors occurred")))
+			Expect(err).To(MatchError(ContainSubstring("[1] Operation failed")))
+			Expect(err).To(MatchError(ContainSubstring("[3] Operation failed")))
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(4))
+			Expect(fakeListener.FailureToleratedCallCount()).To(Equal(2))
+			guid, failureCount, maxFailures := fakeListener.FailureToleratedArgsForCall(1)
+			Expect(guid).To(Equal("3"))
+			Expect(failureCount).To(Equal(2))
+			Expect(maxFailures).To(Equal(2))
+			hasReportedFinished(fakeListener, 0, 2, 0, emptyBusyList, []string{"1", "3"})
+		})
+
+		It("stops once the failures exceed max failures", func() {
+			builder.MaxFailures = 1
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError(ContainSubstring("2 errors occurred")))
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(3))
+			hasReportedFinished(fakeListener, 0, 1, 0, emptyBusyList, []string{"1", "3"})
+		})
+
+		It("tolerates a percentage of the instances failing", func() {
+			builder.MaxFailurePercentage = 50
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(4))
+			_, _, maxFailures := fakeListener.FailureToleratedArgsForCall(0)
+			Expect(maxFailures).To(Equal(2))
+		})
+
+		It("tolerates failures to trigger an operation", func() {
+			builder.MaxFailures = 1
+			brokerServicesClient.ProcessInstanceReturnsOnCall(0, services.BOSHOperation{}, errors.New("broker unavailable"))
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Succeeded}, nil)
+			brokerServicesClient.LastOperationStub = nil
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("broker unavailable"))
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(4))
+			hasReportedFinished(fakeListener, 0, 3, 0, emptyBusyList, []string{"1"})
+		})
+
+		It("does not tolerate canary failures", func() {
+			builder.MaxFailures = 2
+			builder.Canaries = 1
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError(ContainSubstring("canaries didn't process successfully")))
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+			Expect(fakeListener.FailureToleratedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("filtering instances", func() {
+		BeforeEach(func() {
+			instanceLister.InstancesReturns([]service.Instance{
+				{GUID: "1", PlanUniqueID: "small"},
+				{GUID: "2", PlanUniqueID: "large"},
+				{GUID: "3", PlanUniqueID: "small"},
+				{GUID: "4", PlanUniqueID: "large"},
+			}, nil)
+			instanceLister.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			brokerServicesClient.ProcessInstanceReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Succeeded}, nil)
+		})
+
+		triggeredGUIDs := func() []string {
+			guids := []string{}
+			for i := 0; i < fakeTriggerer.TriggerOperationCallCount(); i++ {
+				guids = append(guids, fakeTriggerer.TriggerOperationArgsForCall(i).GUID)
+			}
+			return guids
+		}
+
+		It("only processes instances matching an include criterion", func() {
+			builder.InstanceFilters = instanceiterator.InstanceFilters{
+				Include: instanceiterator.InstanceFilter{PlanIDs: []string{"small"}, GUIDs: []string{"4"}},
+			}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(triggeredGUIDs()).To(Equal([]string{"1", "3", "4"}))
+			Expect(fakeListener.InstancesFilteredCallCount()).To(Equal(1))
+			totalCount, selectedCount := fakeListener.InstancesFilteredArgsForCall(0)
+			Expect(totalCount).To(Equal(4))
+			Expect(selectedCount).To(Equal(3))
+			Expect(fakeListener.InstancesToProcessArgsForCall(0)).To(HaveLen(3))
+		})
+
+		It("does not process excluded instances", func() {
+			builder.InstanceFilters = instanceiterator.InstanceFilters{
+				Include: instanceiterator.InstanceFilter{PlanIDs: []string{"small"}},
+				Exclude: instanceiterator.InstanceFilter{SelectionParams: []map[string]string{{"cf_org": "org", "cf_space": "space"}}},
+			}
+			instanceLister.FilteredInstancesReturns([]service.Instance{{GUID: "3"}, {GUID: "4"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instanceLister.FilteredInstancesArgsForCall(0)).To(Equal(map[string]string{"cf_org": "org", "cf_space": "space"}))
+			Expect(triggeredGUIDs()).To(Equal([]string{"1"}))
+		})
+
+		It("only picks canaries from the filtered instances", func() {
+			builder.Canaries = 1
+			builder.CanarySelectionParams = config.CanarySelectionParams{"cf_org": "canary-org", "cf_space": "canary-space"}
+			builder.InstanceFilters = instanceiterator.InstanceFilters{
+				Exclude: instanceiterator.InstanceFilter{GUIDs: []string{"1"}},
+			}
+			instanceLister.FilteredInstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(triggeredGUIDs()).To(Equal([]string{"2", "3", "4"}))
+			Expect(fakeListener.CanariesStartingCallCount()).To(Equal(1))
+		})
+
+		It("does not report filtering when no filters are configured", func() {
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.InstancesFilteredCallCount()).To(BeZero())
+			Expect(triggeredGUIDs()).To(HaveLen(4))
+		})
+
+		It("fails when the instances for a selection cannot be listed", func() {
+			builder.InstanceFilters = instanceiterator.InstanceFilters{
+				Include: instanceiterator.InstanceFilter{SelectionParams: []map[string]string{{"cf_org": "org", "cf_space": "space"}}},
+			}
+			instanceLister.FilteredInstancesReturns(nil, errors.New("cf unavailable"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("error filtering service instances: cf unavailable"))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+		})
+	})
+
+	Context("maintenance windows", func() {
+		var (
+			fakeClock   *fakes.FakeClock
+			nightWindow instanceiterator.MaintenanceWindow
+		)
+
+		BeforeEach(func() {
+			var err error
+			nightWindow, err = instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "22:00", End: "06:00", Timezone: "UTC"})
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeClock = new(fakes.FakeClock)
+			fakeClock.NowReturns(time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC))
+			builder.Clock = fakeClock
+			builder.DefaultMaintenanceWindow = &nightWindow
+
+			instanceLister.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			brokerServicesClient.ProcessInstanceReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+			brokerServicesClient.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Succeeded}, nil)
+		})
+
+		It("defers instances outside the default window without failing", func() {
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+			Expect(fakeListener.OutsideMaintenanceWindowCallCount()).To(Equal(2 * builder.AttemptLimit))
+			guid, window := fakeListener.OutsideMaintenanceWindowArgsForCall(0)
+			Expect(guid).To(Equal("1"))
+			Expect(window.String()).To(Equal("22:00-06:00 UTC"))
+
+			Expect(fakeListener.DeferredCallCount()).To(Equal(1))
+			Expect(fakeListener.DeferredArgsForCall(0)).To(Equal([]string{"1", "2"}))
+			hasReportedFinished(fakeListener, 0, 0, 0, emptyBusyList, emptyFailedList)
+		})
+
+		It("processes deferred instances on a later attempt once their window opens", func() {
+			fakeClock.NowReturnsOnCall(1, time.Date(2018, 1, 1, 23, 0, 0, 0, time.UTC))
+			fakeClock.NowReturnsOnCall(2, time.Date(2018, 1, 1, 23, 0, 0, 0, time.UTC))
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+			Expect(fakeListener.OutsideMaintenanceWindowCallCount()).To(Equal(1))
+			Expect(fakeSleeper.SleepArgsForCall(0)).To(Equal(builder.AttemptInterval))
+			Expect(fakeListener.DeferredCallCount()).To(BeZero())
+			hasReportedFinished(fakeListener, 0, 1, 0, emptyBusyList, emptyFailedList)
+		})
+
+		It("uses the first override whose selection matches an instance", func() {
+			alwaysOpen, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "00:00", End: "23:59", Timezone: "UTC"})
+			Expect(err).NotTo(HaveOccurred())
+			builder.ScopedMaintenanceWindows = []instanceiterator.ScopedMaintenanceWindow{
+				{MaintenanceWindow: alwaysOpen, SelectionParams: map[string]string{"cf_org": "day-shift", "cf_space": "dev"}},
+				{MaintenanceWindow: nightWindow, SelectionParams: map[string]string{"cf_org": "night-shift", "cf_space": "prod"}},
+			}
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}, {GUID: "3"}}, nil)
+			instanceLister.FilteredInstancesStub = func(filter map[string]string) ([]service.Instance, error) {
+				if filter["cf_org"] == "day-shift" {
+					return []service.Instance{{GUID: "2"}}, nil
+				}
+				return []service.Instance{{GUID: "2"}, {GUID: "3"}}, nil
+			}
+
+			err = instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+			Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("2"))
+			Expect(fakeListener.DeferredArgsForCall(0)).To(Equal([]string{"1", "3"}))
+		})
+
+		It("fails when the instances for an override cannot be listed", func() {
+			builder.ScopedMaintenanceWindows = []instanceiterator.ScopedMaintenanceWindow{
+				{MaintenanceWindow: nightWindow, SelectionParams: map[string]string{"cf_org": "org", "cf_space": "space"}},
+			}
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+			instanceLister.FilteredInstancesReturns(nil, errors.New("cf unavailable"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("error listing service instances for maintenance window 22:00-06:00 UTC: cf unavailable"))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+		})
+
+		It("fails the canaries when they are all outside their window", func() {
+			builder.Canaries = 1
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(MatchError("canaries didn't process successfully: 2 canaries are outside their maintenance window"))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+		})
+
+		It("picks another canary when one is outside its window", func() {
+			builder.Canaries = 1
+			builder.DefaultMaintenanceWindow = nil
+			builder.ScopedMaintenanceWindows = []instanceiterator.ScopedMaintenanceWindow{
+				{MaintenanceWindow: nightWindow, SelectionParams: map[string]string{"cf_org": "org", "cf_space": "space"}},
+			}
+			instanceLister.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+			instanceLister.FilteredInstancesReturns([]service.Instance{{GUID: "1"}}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+			Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("2"))
+			Expect(fakeListener.CanariesFinishedCallCount()).To(Equal(1))
+		})
+	})
+
 	Context("processes instances without canaries", func() {
 		AfterEach(func() {
 			hasReportedStarting(fakeListener, builder.MaxInFlight)
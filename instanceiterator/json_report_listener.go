@@ -0,0 +1,211 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+type Report struct {
+	Operation  string           `json:"operation"`
+	Status     string           `json:"status"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+	Summary    ReportSummary    `json:"summary"`
+	Instances  []InstanceReport `json:"instances"`
+}
+
+type ReportSummary struct {
+	Succeeded int `json:"succeeded"`
+	Orphaned  int `json:"orphaned"`
+	Deleted   int `json:"deleted"`
+	Busy      int `json:"busy"`
+	Failed    int `json:"failed"`
+}
+
+type InstanceReport struct {
+	GUID           string                     `json:"guid"`
+	PlanID         string                     `json:"plan_id"`
+	Result         services.BOSHOperationType `json:"result"`
+	StartedAt      *time.Time                 `json:"started_at,omitempty"`
+	FinishedAt     *time.Time                 `json:"finished_at,omitempty"`
+	BoshTaskID     int                        `json:"bosh_task_id,omitempty"`
+	Error          string                     `json:"error,omitempty"`
+	UpgradePreview *broker.UpgradePreview     `json:"upgrade_preview,omitempty"`
+}
+
+// JSONReportListener passes every event on to another listener, and writes a
+// report of the outcome for each instance as a single JSON document when the
+// run finishes.
+type JSONReportListener struct {
+	Listener
+
+	output io.Writer
+	logger *log.Logger
+	clock  clock
+
+	report    Report
+	guids     []string
+	instances map[string]*InstanceReport
+}
+
+func NewJSONReportListener(listener Listener, output io.Writer, operation string, logger *log.Logger, clock clock) *JSONReportListener {
+	return &JSONReportListener{
+		Listener:  listener,
+		output:    output,
+		logger:    logger,
+		clock:     clock,
+		report:    Report{Operation: operation},
+		instances: map[string]*InstanceReport{},
+	}
+}
+
+func (rl *JSONReportListener) Starting(maxInFlight int) {
+	rl.Listener.Starting(maxInFlight)
+	rl.report.StartedAt = rl.clock.Now()
+}
+
+func (rl *JSONReportListener) InstancesToProcess(instances []service.Instance) {
+	rl.Listener.InstancesToProcess(instances)
+	for _, instance := range instances {
+		rl.guids = append(rl.guids, instance.GUID)
+		rl.instances[instance.GUID] = &InstanceReport{
+			GUID:   instance.GUID,
+			PlanID: instance.PlanUniqueID,
+			Result: services.OperationPending,
+		}
+	}
+}
+
+func (rl *JSONReportListener) InstanceOperationStarting(instance string, index int, totalInstances int, isCanary bool) {
+	rl.Listener.InstanceOperationStarting(instance, index, totalInstances, isCanary)
+	now := rl.clock.Now()
+	rl.update(instance, func(r *InstanceReport) {
+		r.StartedAt = &now
+		r.FinishedAt = nil
+		r.BoshTaskID = 0
+		r.Error = ""
+	})
+}
+
+func (rl *JSONReportListener) InstanceOperationStartResult(instance string, status services.BOSHOperationType) {
+	rl.Listener.InstanceOperationStartResult(instance, status)
+	rl.update(instance, func(r *InstanceReport) {
+		r.Result = status
+		if status != services.OperationAccepted {
+			now := rl.clock.Now()
+			r.FinishedAt = &now
+		}
+	})
+}
+
+func (rl *JSONReportListener) WaitingFor(instance string, boshTaskId int) {
+	rl.Listener.WaitingFor(instance, boshTaskId)
+	rl.update(instance, func(r *InstanceReport) {
+		r.BoshTaskID = boshTaskId
+	})
+}
+
+func (rl *JSONReportListener) InstanceOperationFinished(instance string, result string) {
+	rl.Listener.InstanceOperationFinished(instance, result)
+	now := rl.clock.Now()
+	rl.update(instance, func(r *InstanceReport) {
+		r.FinishedAt = &now
+		r.Result = services.OperationSucceeded
+		if result != "success" {
+			r.Result = services.OperationFailed
+		}
+	})
+}
+
+func (rl *JSONReportListener) InstanceOperationFailed(instance string, err error) {
+	rl.Listener.InstanceOperationFailed(instance, err)
+	now := rl.clock.Now()
+	rl.update(instance, func(r *InstanceReport) {
+		r.FinishedAt = &now
+		r.Result = services.OperationFailed
+		r.Error = err.Error()
+	})
+}
+
+func (rl *JSONReportListener) OutsideMaintenanceWindow(instance string, window MaintenanceWindow) {
+	rl.Listener.OutsideMaintenanceWindow(instance, window)
+	rl.update(instance, func(r *InstanceReport) {
+		r.Result = services.OperationDeferred
+	})
+}
+
+func (rl *JSONReportListener) InstanceUpgradePreview(instance string, preview broker.UpgradePreview) {
+	rl.Listener.InstanceUpgradePreview(instance, preview)
+	rl.update(instance, func(r *InstanceReport) {
+		r.UpgradePreview = &preview
+	})
+}
+
+func (rl *JSONReportListener) Finished(orphanCount, finishedCount, deletedCount int, busyInstances, failedInstances []string) {
+	rl.Listener.Finished(orphanCount, finishedCount, deletedCount, busyInstances, failedInstances)
+
+	rl.report.FinishedAt = rl.clock.Now()
+	rl.report.Status = "SUCCESS"
+	if len(failedInstances) > 0 || len(busyInstances) > 0 {
+		rl.report.Status = "FAILED"
+	}
+	rl.report.Summary = ReportSummary{
+		Succeeded: finishedCount,
+		Orphaned:  orphanCount,
+		Deleted:   deletedCount,
+		Busy:      len(busyInstances),
+		Failed:    len(failedInstances),
+	}
+	rl.report.Instances = []InstanceReport{}
+	for _, guid := range rl.guids {
+		rl.report.Instances = append(rl.report.Instances, *rl.instances[guid])
+	}
+
+	contents, err := json.MarshalIndent(rl.report, "", "  ")
+	if err != nil {
+		rl.logger.Printf("error encoding JSON report: %s", err)
+		return
+	}
+	if _, err := rl.output.Write(append(contents, '\n')); err != nil {
+		rl.logger.Printf("error writing JSON report: %s", err)
+	}
+}
+
+func (rl *JSONReportListener) update(instance string, change func(*InstanceReport)) {
+	if r, ok := rl.instances[instance]; ok {
+		change(r)
+	}
+}
+
+// ReportFile writes each report to the file at its path, replacing any
+// previous contents.
+type ReportFile string
+
+func (f ReportFile) Write(contents []byte) (int, error) {
+	if err := ioutil.WriteFile(string(f), contents, 0644); err != nil {
+		return 0, err
+	}
+	return len(contents), nil
+}
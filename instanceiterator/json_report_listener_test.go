@@ -0,0 +1,175 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("JSONReportListener", func() {
+	var (
+		output       *bytes.Buffer
+		logBuffer    *gbytes.Buffer
+		fakeListener *fakes.FakeListener
+		fakeClock    *fakes.FakeClock
+		listener     *instanceiterator.JSONReportListener
+		startTime    time.Time
+	)
+
+	BeforeEach(func() {
+		output = new(bytes.Buffer)
+		logBuffer = gbytes.NewBuffer()
+		fakeListener = new(fakes.FakeListener)
+		fakeClock = new(fakes.FakeClock)
+		startTime = time.Date(2018, 1, 1, 22, 0, 0, 0, time.UTC)
+		minutes := 0
+		fakeClock.NowStub = func() time.Time {
+			minutes++
+			return startTime.Add(time.Duration(minutes-1) * time.Minute)
+		}
+		listener = instanceiterator.NewJSONReportListener(fakeListener, output, "upgrade-all", log.New(logBuffer, "", 0), fakeClock)
+	})
+
+	It("passes events on to the wrapped listener", func() {
+		listener.Starting(3)
+		listener.CanariesFinished()
+		listener.InstanceOperationFailed("one", errors.New("boom"))
+
+		Expect(fakeListener.StartingArgsForCall(0)).To(Equal(3))
+		Expect(fakeListener.CanariesFinishedCallCount()).To(Equal(1))
+		guid, err := fakeListener.InstanceOperationFailedArgsForCall(0)
+		Expect(guid).To(Equal("one"))
+		Expect(err).To(MatchError("boom"))
+	})
+
+	It("writes the outcome of each instance when the run finishes", func() {
+		listener.Starting(1)
+		listener.InstancesToProcess([]service.Instance{
+			{GUID: "upgraded", PlanUniqueID: "small"},
+			{GUID: "broken", PlanUniqueID: "large"},
+			{GUID: "orphan", PlanUniqueID: "small"},
+			{GUID: "untouched", PlanUniqueID: "small"},
+		})
+		listener.InstanceOperationStarting("upgraded", 1, 4, false)
+		listener.InstanceOperationStartResult("upgraded", services.OperationAccepted)
+		listener.WaitingFor("upgraded", 42)
+		listener.InstanceOperationFinished("upgraded", "success")
+		listener.InstanceOperationStarting("broken", 2, 4, false)
+		listener.InstanceOperationStartResult("broken", services.OperationAccepted)
+		listener.WaitingFor("broken", 43)
+		listener.InstanceOperationFinished("broken", "failure")
+		listener.InstanceOperationFailed("broken", errors.New("[broken] Operation failed: bosh task id 43: disk full"))
+		listener.InstanceOperationStarting("orphan", 3, 4, false)
+		listener.InstanceOperationStartResult("orphan", services.OrphanDeployment)
+
+		Expect(output.Len()).To(BeZero())
+		listener.Finished(1, 1, 0, []string{}, []string{"broken"})
+
+		Expect(fakeListener.FinishedCallCount()).To(Equal(1))
+		Expect(output.String()).To(MatchJSON(`{
+			"operation": "upgrade-all",
+			"status": "FAILED",
+			"started_at": "2018-01-01T22:00:00Z",
+			"finished_at": "2018-01-01T22:08:00Z",
+			"summary": {"succeeded": 1, "orphaned": 1, "deleted": 0, "busy": 0, "failed": 1},
+			"instances": [
+				{"guid": "upgraded", "plan_id": "small", "result": "succeeded", "started_at": "2018-01-01T22:01:00Z", "finished_at": "2018-01-01T22:02:00Z", "bosh_task_id": 42},
+				{"guid": "broken", "plan_id": "large", "result": "failed", "started_at": "2018-01-01T22:03:00Z", "finished_at": "2018-01-01T22:05:00Z", "bosh_task_id": 43, "error": "[broken] Operation failed: bosh task id 43: disk full"},
+				{"guid": "orphan", "plan_id": "small", "result": "orphan-deployment", "started_at": "2018-01-01T22:06:00Z", "finished_at": "2018-01-01T22:07:00Z"},
+				{"guid": "untouched", "plan_id": "small", "result": "not-started"}
+			]
+		}`))
+	})
+
+	It("reports instances retried after being busy or outside their maintenance window by their final result", func() {
+		window, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "22:00", End: "06:00", Timezone: "UTC"})
+		Expect(err).NotTo(HaveOccurred())
+
+		listener.Starting(1)
+		listener.InstancesToProcess([]service.Instance{{GUID: "busy"}, {GUID: "deferred"}})
+		listener.InstanceOperationStarting("busy", 1, 2, false)
+		listener.InstanceOperationStartResult("busy", services.OperationInProgress)
+		listener.OutsideMaintenanceWindow("deferred", window)
+		listener.InstanceOperationStarting("busy", 1, 2, false)
+		listener.InstanceOperationStartResult("busy", services.OperationAccepted)
+		listener.InstanceOperationFinished("busy", "success")
+		listener.Finished(0, 1, 0, []string{}, []string{})
+
+		var report instanceiterator.Report
+		Expect(json.Unmarshal(output.Bytes(), &report)).To(Succeed())
+		Expect(report.Status).To(Equal("SUCCESS"))
+		Expect(report.Instances[0].Result).To(Equal(services.OperationSucceeded))
+		Expect(report.Instances[1].Result).To(Equal(services.OperationDeferred))
+		Expect(report.Instances[1].StartedAt).To(BeNil())
+	})
+
+	It("includes upgrade previews", func() {
+		preview := broker.UpgradePreview{ManifestChanges: []manifestdiff.Change{{Path: "/name", Type: manifestdiff.Changed, Old: "a", New: "b"}}}
+
+		listener.Starting(1)
+		listener.InstancesToProcess([]service.Instance{{GUID: "one"}})
+		listener.InstanceUpgradePreview("one", preview)
+		listener.Finished(0, 1, 0, []string{}, []string{})
+
+		var report instanceiterator.Report
+		Expect(json.Unmarshal(output.Bytes(), &report)).To(Succeed())
+		Expect(report.Instances[0].UpgradePreview.ManifestChanges).To(Equal(preview.ManifestChanges))
+	})
+
+	It("logs when the report cannot be written", func() {
+		listener = instanceiterator.NewJSONReportListener(fakeListener, instanceiterator.ReportFile("/not/a/dir/report.json"), "upgrade-all", log.New(logBuffer, "", 0), fakeClock)
+
+		listener.Starting(1)
+		listener.Finished(0, 0, 0, []string{}, []string{})
+
+		Expect(logBuffer).To(gbytes.Say("error writing JSON report: open /not/a/dir/report.json"))
+	})
+
+	Describe("ReportFile", func() {
+		It("replaces the file contents on each write", func() {
+			dir, err := ioutil.TempDir("", "report")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "report.json")
+
+			reportFile := instanceiterator.ReportFile(path)
+			_, err = reportFile.Write([]byte("first report"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reportFile.Write([]byte("second"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ioutil.ReadFile(path)).To(Equal([]byte("second")))
+		})
+	})
+})
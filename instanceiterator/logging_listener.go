@@ -9,9 +9,11 @@ package instanceiterator
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
@@ -78,6 +80,8 @@ func (ll LoggingListener) InstanceOperationStartResult(instance string, resultTy
 		message = "orphan service instance detected - no corresponding bosh deployment"
 	case services.OperationInProgress:
 		message = "operation in progress"
+	case services.OperationSucceeded:
+		message = "operation succeeded"
 	default:
 		message = "unexpected result"
 	}
@@ -89,6 +93,10 @@ func (ll LoggingListener) InstanceOperationFinished(instance string, result stri
 	ll.printf("[%s] Result: Service Instance operation %s\n", instance, result)
 }
 
+// InstanceOperationFailed logs nothing, as failures are reported when the
+// iterator finishes.
+func (ll LoggingListener) InstanceOperationFailed(instance string, err error) {}
+
 func (ll LoggingListener) WaitingFor(instance string, boshTaskId int) {
 	ll.printf("[%s] Waiting for operation to complete: bosh task id %d", instance, boshTaskId)
 }
@@ -152,6 +160,71 @@ func (ll LoggingListener) CanariesFinished() {
 	ll.printf("FINISHED CANARIES")
 }
 
+func (ll LoggingListener) CanaryStageStarting(stage, totalStages int) {
+	ll.printf("STARTING CANARY STAGE %d of %d\n", stage, totalStages)
+}
+
+func (ll LoggingListener) PausingAfterCanaryStage(stage int, pause time.Duration) {
+	ll.printf("Pausing for %s after canary stage %d\n", pause, stage)
+}
+
+func (ll LoggingListener) WaitingForApproval(stage int, howToApprove string) {
+	ll.printf("WAITING FOR APPROVAL to continue after canary stage %d: %s\n", stage, howToApprove)
+}
+
+func (ll LoggingListener) StillWaitingForApproval(stage int, waited time.Duration, howToApprove string) {
+	ll.printf("STILL WAITING FOR APPROVAL to continue after canary stage %d, for %s so far: %s\n", stage, waited, howToApprove)
+}
+
+func (ll LoggingListener) InstanceUpgradePreview(instance string, preview broker.UpgradePreview) {
+	if len(preview.ManifestChanges) == 0 && len(preview.ConfigChanges) == 0 {
+		ll.printf("[%s] Upgrade preview: no changes", instance)
+		return
+	}
+
+	// Only paths and change types are logged: the values may contain secrets,
+	// even when the broker has been configured to return them.
+	var summary strings.Builder
+	for _, change := range preview.ManifestChanges {
+		fmt.Fprintf(&summary, "  manifest %s (%s)\n", change.Path, change.Type)
+	}
+	configTypes := make([]string, 0, len(preview.ConfigChanges))
+	for configType := range preview.ConfigChanges {
+		configTypes = append(configTypes, configType)
+	}
+	sort.Strings(configTypes)
+	for _, configType := range configTypes {
+		for _, change := range preview.ConfigChanges[configType] {
+			fmt.Fprintf(&summary, "  %s config %s (%s)\n", configType, change.Path, change.Type)
+		}
+	}
+	ll.printf("[%s] Upgrade preview:\n%s", instance, summary.String())
+}
+
+func (ll LoggingListener) Resuming(completedCount int) {
+	ll.printf("RESUMING: skipping %d instances processed by a previous run\n", completedCount)
+}
+
+func (ll LoggingListener) FailedToSaveState(err error) {
+	ll.printf("Failed to save iterator state, this run may not be resumable: %s\n", err)
+}
+
+func (ll LoggingListener) OutsideMaintenanceWindow(instance string, window MaintenanceWindow) {
+	ll.printf("[%s] Deferred: outside maintenance window %s\n", instance, window)
+}
+
+func (ll LoggingListener) Deferred(instances []string) {
+	ll.printf("DEFERRED: %d instances outside their maintenance window were not processed, they will be processed by a later run: [%s]\n", len(instances), strings.Join(instances, ", "))
+}
+
+func (ll LoggingListener) InstancesFiltered(totalCount, selectedCount int) {
+	ll.printf("Instance filters selected %d of %d service instances\n", selectedCount, totalCount)
+}
+
+func (ll LoggingListener) FailureTolerated(instance string, failureCount, maxFailures int) {
+	ll.printf("[%s] Continuing with other instances after failure %d of %d tolerated\n", instance, failureCount, maxFailures)
+}
+
 func (ll LoggingListener) FailedToRefreshInstanceInfo(instance string) {
 	ll.logger.Printf("[%s] Failed to get refreshed list of instances. Continuing with previously fetched info.\n", instance)
 }
@@ -7,6 +7,7 @@
 package instanceiterator_test
 
 import (
+	"errors"
 	"io"
 	"log"
 	"time"
@@ -14,10 +15,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
 
@@ -172,6 +175,84 @@ var _ = Describe("Logging Listener", func() {
 		})
 	})
 
+	It("Shows the paths and types of the changes an upgrade would make, but never their values", func() {
+		preview := broker.UpgradePreview{
+			ManifestChanges: []manifestdiff.Change{{Path: "/properties/password", Type: manifestdiff.Changed, Old: "old-secret", New: "new-secret"}},
+			ConfigChanges: map[string][]manifestdiff.Change{
+				"cloud": {{Path: "/vm_extensions/name=lb", Type: manifestdiff.Added, New: "cloud-secret"}},
+			},
+		}
+		result := logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.InstanceUpgradePreview("one", preview) })
+
+		Expect(result).To(SatisfyAll(
+			ContainSubstring("[%s] [one] Upgrade preview:", logPrefix),
+			ContainSubstring("manifest /properties/password (changed)"),
+			ContainSubstring("cloud config /vm_extensions/name=lb (added)"),
+			Not(ContainSubstring("secret")),
+		))
+	})
+
+	It("Shows when an upgrade would make no changes", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
+			listener.InstanceUpgradePreview("one", broker.UpgradePreview{ManifestChanges: []manifestdiff.Change{}})
+		})).To(ContainSubstring("[%s] [one] Upgrade preview: no changes", logPrefix))
+	})
+
+	It("Shows how many instances are skipped when resuming", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.Resuming(400) })).
+			To(ContainSubstring("[%s] RESUMING: skipping 400 instances processed by a previous run", logPrefix))
+	})
+
+	It("Shows that the state could not be saved", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.FailedToSaveState(errors.New("disk full")) })).
+			To(ContainSubstring("[%s] Failed to save iterator state, this run may not be resumable: disk full", logPrefix))
+	})
+
+	It("Shows that an instance is outside its maintenance window", func() {
+		window, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "22:00", End: "06:00", Timezone: "Europe/London"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.OutsideMaintenanceWindow("one", window) })).
+			To(ContainSubstring("[%s] [one] Deferred: outside maintenance window 22:00-06:00 Europe/London", logPrefix))
+	})
+
+	It("Shows which instances were deferred to a later run", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.Deferred([]string{"one", "two"}) })).
+			To(ContainSubstring("[%s] DEFERRED: 2 instances outside their maintenance window were not processed, they will be processed by a later run: [one, two]", logPrefix))
+	})
+
+	It("Shows how many instances the filters selected", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.InstancesFiltered(10, 4) })).
+			To(ContainSubstring("[%s] Instance filters selected 4 of 10 service instances", logPrefix))
+	})
+
+	It("Shows that a failure was tolerated", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.FailureTolerated("one", 1, 3) })).
+			To(ContainSubstring("[%s] [one] Continuing with other instances after failure 1 of 3 tolerated", logPrefix))
+	})
+
+	It("Shows which canary stage is starting", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.CanaryStageStarting(2, 3) })).
+			To(ContainSubstring("[%s] STARTING CANARY STAGE 2 of 3", logPrefix))
+	})
+
+	It("Shows the pause after a canary stage", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.PausingAfterCanaryStage(1, 10*time.Minute) })).
+			To(ContainSubstring("[%s] Pausing for 10m0s after canary stage 1", logPrefix))
+	})
+
+	It("Shows how to approve the next canary stage", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
+			listener.WaitingForApproval(1, "create /tmp/approve to continue")
+		})).To(ContainSubstring("[%s] WAITING FOR APPROVAL to continue after canary stage 1: create /tmp/approve to continue", logPrefix))
+	})
+
+	It("Shows that it is still waiting for approval", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
+			listener.StillWaitingForApproval(1, 20*time.Minute, "create /tmp/approve to continue")
+		})).To(ContainSubstring("[%s] STILL WAITING FOR APPROVAL to continue after canary stage 1, for 20m0s so far: create /tmp/approve to continue", logPrefix))
+	})
+
 	It("Shows which instance is still in progress", func() {
 		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.WaitingFor("one", 999) })).
 			To(ContainSubstring("[%s] [one] Waiting for operation to complete: bosh task id 999", logPrefix))
@@ -0,0 +1,86 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+//go:generate counterfeiter -o fakes/fake_clock.go . clock
+type clock interface {
+	Now() time.Time
+}
+
+// MaintenanceWindow is a daily period during which operations may be
+// triggered on an instance.
+type MaintenanceWindow struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+type ScopedMaintenanceWindow struct {
+	MaintenanceWindow
+	SelectionParams map[string]string
+}
+
+func NewMaintenanceWindow(conf config.MaintenanceWindow) (MaintenanceWindow, error) {
+	start, err := timeOfDay(conf.Start)
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid maintenance window start: %s", err)
+	}
+	end, err := timeOfDay(conf.End)
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid maintenance window end: %s", err)
+	}
+	if start == end {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window start and end must differ, both are %s", conf.Start)
+	}
+	location, err := time.LoadLocation(conf.Timezone)
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid maintenance window timezone: %s", err)
+	}
+	return MaintenanceWindow{start: start, end: end, location: location}, nil
+}
+
+// IsOpen reports whether t falls in the window, in the window's timezone.
+func (w MaintenanceWindow) IsOpen(t time.Time) bool {
+	local := t.In(w.location)
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if w.start < w.end {
+		return now >= w.start && now < w.end
+	}
+	return now >= w.start || now < w.end
+}
+
+func (w MaintenanceWindow) String() string {
+	return fmt.Sprintf("%s-%s %s", formatTimeOfDay(w.start), formatTimeOfDay(w.end), w.location)
+}
+
+func timeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time of day", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+)
+
+var _ = Describe("MaintenanceWindow", func() {
+	DescribeTable("IsOpen",
+		func(start, end string, hour, minute int, expectedOpen bool) {
+			window, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: start, End: end, Timezone: "UTC"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(window.IsOpen(time.Date(2018, 1, 1, hour, minute, 0, 0, time.UTC))).To(Equal(expectedOpen))
+		},
+		Entry("inside a daytime window", "09:00", "17:00", 12, 0, true),
+		Entry("at the start of a window", "09:00", "17:00", 9, 0, true),
+		Entry("at the end of a window", "09:00", "17:00", 17, 0, false),
+		Entry("before a daytime window", "09:00", "17:00", 8, 59, false),
+		Entry("late in an overnight window", "22:00", "06:00", 23, 30, true),
+		Entry("early in an overnight window", "22:00", "06:00", 5, 59, true),
+		Entry("outside an overnight window", "22:00", "06:00", 12, 0, false),
+	)
+
+	It("checks the time in the window's timezone", func() {
+		window, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "01:00", End: "05:00", Timezone: "Asia/Tokyo"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(window.IsOpen(time.Date(2018, 1, 1, 17, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(window.IsOpen(time.Date(2018, 1, 1, 1, 0, 0, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("uses UTC when no timezone is configured", func() {
+		window, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "01:00", End: "05:00"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window.String()).To(Equal("01:00-05:00 UTC"))
+	})
+
+	It("returns an error when the timezone is unknown", func() {
+		_, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "01:00", End: "05:00", Timezone: "Mars/Olympus"})
+		Expect(err).To(MatchError(ContainSubstring("invalid maintenance window timezone")))
+	})
+
+	It("returns an error when the end is not a time of day", func() {
+		_, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "01:00", End: "25:00", Timezone: "UTC"})
+		Expect(err).To(MatchError(`invalid maintenance window end: "25:00" is not a HH:MM time of day`))
+	})
+
+	It("returns an error when the window is empty", func() {
+		_, err := instanceiterator.NewMaintenanceWindow(config.MaintenanceWindow{Start: "01:00", End: "01:00", Timezone: "UTC"})
+		Expect(err).To(MatchError("maintenance window start and end must differ, both are 01:00"))
+	})
+})
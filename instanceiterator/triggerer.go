@@ -40,6 +40,31 @@ func (t *UpgradeTriggerer) TriggerOperation(instance service.Instance) (services
 	return operation, nil
 }
 
+// UpgradePreviewTriggerer reports the changes an upgrade would make to each
+// instance instead of upgrading it.
+type UpgradePreviewTriggerer struct {
+	brokerServices BrokerServices
+	listener       Listener
+}
+
+func NewUpgradePreviewTriggerer(brokerServices BrokerServices, listener Listener) *UpgradePreviewTriggerer {
+	return &UpgradePreviewTriggerer{
+		brokerServices: brokerServices,
+		listener:       listener,
+	}
+}
+
+func (t *UpgradePreviewTriggerer) TriggerOperation(instance service.Instance) (services.BOSHOperation, error) {
+	operation, preview, err := t.brokerServices.PreviewUpgrade(instance)
+	if err != nil {
+		return services.BOSHOperation{}, fmt.Errorf("Operation type: upgrade preview failed for service instance %s: %s", instance.GUID, err)
+	}
+	if operation.Type == services.OperationSucceeded {
+		t.listener.InstanceUpgradePreview(instance.GUID, preview)
+	}
+	return operation, nil
+}
+
 type RecreateTriggerer struct {
 	brokerServices BrokerServices
 }
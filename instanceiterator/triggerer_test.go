@@ -22,9 +22,11 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
 
@@ -126,4 +128,51 @@ var _ = Describe("Operation triggerer", func() {
 			Entry("operation in progress", services.OperationInProgress, services.BOSHOperation{Type: services.OperationInProgress}),
 		)
 	})
+
+	Context("with an upgradePreviewTriggerer", func() {
+		var fakeListener *fakes.FakeListener
+
+		BeforeEach(func() {
+			guid = "some-guid"
+			instance = service.Instance{GUID: guid}
+			fakeBrokerService = new(fakes.FakeBrokerServices)
+			fakeListener = new(fakes.FakeListener)
+
+			t = instanceiterator.NewUpgradePreviewTriggerer(fakeBrokerService, fakeListener)
+		})
+
+		It("reports the preview and returns OperationSucceeded", func() {
+			preview := broker.UpgradePreview{
+				ManifestChanges: []manifestdiff.Change{{Path: "/name", Type: manifestdiff.Changed, Old: "a", New: "b"}},
+			}
+			fakeBrokerService.PreviewUpgradeReturns(services.BOSHOperation{Type: services.OperationSucceeded}, preview, nil)
+
+			operation, err := t.TriggerOperation(instance)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation).To(Equal(services.BOSHOperation{Type: services.OperationSucceeded}))
+
+			Expect(fakeBrokerService.PreviewUpgradeArgsForCall(0)).To(Equal(instance))
+			Expect(fakeBrokerService.ProcessInstanceCallCount()).To(BeZero())
+			Expect(fakeListener.InstanceUpgradePreviewCallCount()).To(Equal(1))
+			actualGUID, actualPreview := fakeListener.InstanceUpgradePreviewArgsForCall(0)
+			Expect(actualGUID).To(Equal(guid))
+			Expect(actualPreview).To(Equal(preview))
+		})
+
+		It("does not report a preview when the instance could not be previewed", func() {
+			fakeBrokerService.PreviewUpgradeReturns(services.BOSHOperation{Type: services.OrphanDeployment}, broker.UpgradePreview{}, nil)
+
+			operation, err := t.TriggerOperation(instance)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation).To(Equal(services.BOSHOperation{Type: services.OrphanDeployment}))
+			Expect(fakeListener.InstanceUpgradePreviewCallCount()).To(BeZero())
+		})
+
+		It("returns an error if the preview request fails", func() {
+			fakeBrokerService.PreviewUpgradeReturns(services.BOSHOperation{}, broker.UpgradePreview{}, errors.New("oops"))
+
+			_, err := t.TriggerOperation(instance)
+			Expect(err).To(MatchError(fmt.Sprintf("Operation type: upgrade preview failed for service instance %s: oops", guid)))
+		})
+	})
 })
@@ -0,0 +1,99 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package odb_config_check_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/integration_tests/helpers"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("ODB Config Check", func() {
+	var (
+		conf        config.Config
+		adapterPath string
+	)
+
+	BeforeEach(func() {
+		adapter, err := ioutil.TempFile("", "service-adapter")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(adapter.Close()).To(Succeed())
+		Expect(os.Chmod(adapter.Name(), 0755)).To(Succeed())
+		adapterPath = adapter.Name()
+
+		conf = config.Config{
+			Broker: config.Broker{Port: 8080, Username: "username", Password: "password", DisableCFStartupChecks: true},
+			Bosh: config.Bosh{
+				URL: "https://bosh.example.com",
+				Authentication: config.Authentication{
+					Basic: config.UserCredentials{Username: "bosh-username", Password: "bosh-password"},
+				},
+			},
+			ServiceAdapter: config.ServiceAdapter{Path: adapterPath},
+			ServiceDeployment: config.ServiceDeployment{
+				Releases: serviceadapter.ServiceReleases{{Name: "redis", Version: "1.2.3", Jobs: []string{"redis-server"}}},
+				Stemcell: serviceadapter.Stemcell{OS: "ubuntu-xenial", Version: "250.1"},
+			},
+			ServiceCatalog: config.ServiceOffering{
+				ID:   "redis-id",
+				Name: "redis",
+				Plans: config.Plans{{
+					ID:             "small-id",
+					Name:           "small",
+					InstanceGroups: []serviceadapter.InstanceGroup{{Name: "redis-server", Instances: 1}},
+				}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Remove(adapterPath)).To(Succeed())
+	})
+
+	It("exits with 0 when the config has no problems", func() {
+		session := helpers.StartBinaryWithParams(binaryPath, []string{"-configFilePath", write(conf)})
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(session.Out.Contents()).To(BeEmpty())
+		Expect(session.Err).To(gbytes.Say("found no problems in"))
+	})
+
+	It("lists every problem and exits with code 10 when the config has problems", func() {
+		conf.ServiceDeployment.Stemcell.Version = "latest"
+		conf.ServiceCatalog.GlobalQuotas.ResourceLimits = map[string]int{"memory": 10}
+		conf.ServiceCatalog.Plans[0].BindingWithDNS = []config.BindingDNS{{Name: "leader", InstanceGroup: "redis-leader"}}
+
+		session := helpers.StartBinaryWithParams(binaryPath, []string{"-configFilePath", write(conf)})
+
+		Eventually(session).Should(gexec.Exit(10))
+		Expect(session.Out).To(gbytes.Say("service offering redis: stemcell must have an exact version, not 'latest'"))
+		Expect(session.Out).To(gbytes.Say("service offering redis: global_quotas limit resource memory, which no plan has a resource cost for"))
+		Expect(session.Out).To(gbytes.Say("service offering redis: plan small: binding_with_dns leader refers to instance group 'redis-leader', which the plan does not have"))
+		Expect(session.Err).To(gbytes.Say("found 3 problems in"))
+	})
+
+	It("fails when the config file cannot be parsed", func() {
+		session := helpers.StartBinaryWithParams(binaryPath, []string{"-configFilePath", write("not a config")})
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(session.Err).To(gbytes.Say("error parsing config file"))
+	})
+
+	It("fails when the config file path is not given", func() {
+		session := helpers.StartBinaryWithParams(binaryPath, []string{})
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(session.Err).To(gbytes.Say("-configFilePath must be given as argument"))
+	})
+})
@@ -8,6 +8,7 @@ package upgrade_all_service_instances_test
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
 	"github.com/pivotal-cf/on-demand-service-broker/integration_tests/helpers"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
@@ -446,6 +448,38 @@ var _ = Describe("running the tool to upgrade all service instances", func() {
 		Expect(runningTool).To(gbytes.Say("Number of successful operations: 1"))
 	})
 
+	It("writes only the JSON report to stdout and logs to stderr when json_report_path is -", func() {
+		operationData := `{"BoshTaskID":1,"OperationType":"upgrade","PostDeployErrand":{},"PreDeleteErrand":{}}`
+		instanceID := "service-instance-id"
+		odb.VerifyAndMock(
+			mockbroker.ListInstances().RespondsOKWith(fmt.Sprintf(`[{"plan_id": "service-plan-id", "service_instance_id": "%s"}]`, instanceID)),
+			mockbroker.ListInstances().RespondsOKWith(fmt.Sprintf(`[{"plan_id": "service-plan-id", "service_instance_id": "%s"}]`, instanceID)),
+			mockbroker.UpgradeInstance(instanceID).RespondsAcceptedWith(operationData),
+			mockbroker.LastOperation(instanceID, operationData).RespondWithOperationSucceeded(),
+		)
+
+		brokerConfig := populateBrokerConfig(odb.URL, brokerUsername, brokerPassword)
+		serviceInstancesAPIConfig := populateServiceInstancesAPIConfig(
+			odb.URL+brokerServiceInstancesURLPath,
+			brokerUsername,
+			brokerPassword,
+		)
+		pollingIntervalConfig := populateUpgraderConfig(1, 2, 5)
+		config := brokerConfig + serviceInstancesAPIConfig + pollingIntervalConfig + "\njson_report_path: \"-\""
+		configPath = writeConfigFile(config)
+
+		runningTool := startUpgradeAllInstanceBinary()
+
+		Eventually(runningTool, 5*time.Second).Should(gexec.Exit(0))
+		Expect(runningTool.Err).To(gbytes.Say(`\[upgrade\-all\] FINISHED PROCESSING Status: SUCCESS`))
+
+		var report instanceiterator.Report
+		Expect(json.Unmarshal(runningTool.Out.Contents(), &report)).To(Succeed())
+		Expect(report.Status).To(Equal("SUCCESS"))
+		Expect(report.Instances).To(HaveLen(1))
+		Expect(report.Instances[0].GUID).To(Equal(instanceID))
+	})
+
 	It("when there is one service instance which fails to upgrade, exits with failure and shows summary message", func() {
 		operationData := `{"BoshTaskID":1,"OperationType":"upgrade","PostDeployErrand":{},"PreDeleteErrand":{}}`
 		instanceID := "service-instance-id"
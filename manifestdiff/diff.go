@@ -0,0 +1,209 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package manifestdiff compares YAML documents, such as BOSH manifests and
+// configs, and lists the values that differ between them.
+package manifestdiff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Changed ChangeType = "changed"
+)
+
+// Change is a single difference between two documents. Path locates the value
+// in the style of BOSH ops files: list elements that have a name, such as
+// instance groups and jobs, are addressed by name rather than by index.
+type Change struct {
+	Path string      `json:"path" yaml:"path"`
+	Type ChangeType  `json:"type" yaml:"type"`
+	Old  interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New  interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// Redact returns copies of the changes with their old and new values left
+// out, keeping only the path and type. Manifest and config values may
+// contain secrets, so this is what should be shown unless values have been
+// explicitly asked for.
+func Redact(changes []Change) []Change {
+	if changes == nil {
+		return nil
+	}
+	redacted := make([]Change, len(changes))
+	for i, change := range changes {
+		redacted[i] = Change{Path: change.Path, Type: change.Type}
+	}
+	return redacted
+}
+
+// Diff lists the changes needed to turn the old YAML document into the new
+// one. An empty document is treated as an empty map.
+func Diff(oldYAML, newYAML []byte) ([]Change, error) {
+	oldDoc, err := unmarshal(oldYAML)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing old document: %s", err)
+	}
+	newDoc, err := unmarshal(newYAML)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing new document: %s", err)
+	}
+
+	changes := []Change{}
+	compare("", oldDoc, newDoc, &changes)
+	return changes, nil
+}
+
+func unmarshal(document []byte) (interface{}, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return map[string]interface{}{}, nil
+	}
+	return normalise(doc), nil
+}
+
+// normalise converts the maps decoded by yaml into maps keyed by strings, so
+// that changes can be encoded as JSON.
+func normalise(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, element := range v {
+			m[fmt.Sprintf("%v", key)] = normalise(element)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, element := range v {
+			l[i] = normalise(element)
+		}
+		return l
+	default:
+		return value
+	}
+}
+
+func compare(path string, oldValue, newValue interface{}, changes *[]Change) {
+	switch o := oldValue.(type) {
+	case map[string]interface{}:
+		if n, ok := newValue.(map[string]interface{}); ok {
+			compareMaps(path, o, n, changes)
+			return
+		}
+	case []interface{}:
+		if n, ok := newValue.([]interface{}); ok {
+			compareLists(path, o, n, changes)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, Change{Path: rootIfEmpty(path), Type: Changed, Old: oldValue, New: newValue})
+	}
+}
+
+func compareMaps(path string, oldMap, newMap map[string]interface{}, changes *[]Change) {
+	keys := map[string]bool{}
+	for key := range oldMap {
+		keys[key] = true
+	}
+	for key := range newMap {
+		keys[key] = true
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		oldElement, inOld := oldMap[key]
+		newElement, inNew := newMap[key]
+		compareElements(path+"/"+key, oldElement, inOld, newElement, inNew, changes)
+	}
+}
+
+func compareLists(path string, oldList, newList []interface{}, changes *[]Change) {
+	if !allNamed(oldList) || !allNamed(newList) {
+		for i := 0; i < len(oldList) || i < len(newList); i++ {
+			var oldElement, newElement interface{}
+			if i < len(oldList) {
+				oldElement = oldList[i]
+			}
+			if i < len(newList) {
+				newElement = newList[i]
+			}
+			compareElements(fmt.Sprintf("%s/%d", path, i), oldElement, i < len(oldList), newElement, i < len(newList), changes)
+		}
+		return
+	}
+
+	newByName := map[string]interface{}{}
+	for _, element := range newList {
+		newByName[nameOf(element)] = element
+	}
+	oldNames := map[string]bool{}
+	for _, oldElement := range oldList {
+		name := nameOf(oldElement)
+		oldNames[name] = true
+		newElement, inNew := newByName[name]
+		compareElements(path+"/name="+name, oldElement, true, newElement, inNew, changes)
+	}
+	for _, newElement := range newList {
+		if name := nameOf(newElement); !oldNames[name] {
+			compareElements(path+"/name="+name, nil, false, newElement, true, changes)
+		}
+	}
+}
+
+func compareElements(path string, oldElement interface{}, inOld bool, newElement interface{}, inNew bool, changes *[]Change) {
+	switch {
+	case inOld && !inNew:
+		*changes = append(*changes, Change{Path: path, Type: Removed, Old: oldElement})
+	case !inOld && inNew:
+		*changes = append(*changes, Change{Path: path, Type: Added, New: newElement})
+	default:
+		compare(path, oldElement, newElement, changes)
+	}
+}
+
+func allNamed(list []interface{}) bool {
+	for _, element := range list {
+		if nameOf(element) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func nameOf(element interface{}) string {
+	m, ok := element.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	name, _ := m["name"].(string)
+	return name
+}
+
+func rootIfEmpty(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
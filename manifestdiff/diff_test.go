@@ -0,0 +1,122 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package manifestdiff_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
+)
+
+var _ = Describe("Diff", func() {
+	It("returns no changes for identical documents", func() {
+		manifest := []byte("name: foo\nstemcells:\n- alias: default\n  os: ubuntu-trusty\n")
+
+		changes, err := manifestdiff.Diff(manifest, manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+		Expect(changes).NotTo(BeNil())
+	})
+
+	It("reports added, removed and changed keys in path order", func() {
+		oldManifest := []byte("name: foo\nupdate:\n  canaries: 1\n  serial: true\n")
+		newManifest := []byte("name: foo\nupdate:\n  canaries: 2\n  max_in_flight: 4\n")
+
+		changes, err := manifestdiff.Diff(oldManifest, newManifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]manifestdiff.Change{
+			{Path: "/update/canaries", Type: manifestdiff.Changed, Old: 1, New: 2},
+			{Path: "/update/max_in_flight", Type: manifestdiff.Added, New: 4},
+			{Path: "/update/serial", Type: manifestdiff.Removed, Old: true},
+		}))
+	})
+
+	It("matches list elements by name", func() {
+		oldManifest := []byte(`
+instance_groups:
+- name: redis
+  instances: 1
+  jobs:
+  - name: redis-server
+    properties: {port: 6379}
+- name: sentinel
+  instances: 3
+`)
+		newManifest := []byte(`
+instance_groups:
+- name: proxy
+  instances: 1
+- name: redis
+  instances: 1
+  jobs:
+  - name: redis-server
+    properties: {port: 6380}
+`)
+
+		changes, err := manifestdiff.Diff(oldManifest, newManifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]manifestdiff.Change{
+			{Path: "/instance_groups/name=redis/jobs/name=redis-server/properties/port", Type: manifestdiff.Changed, Old: 6379, New: 6380},
+			{Path: "/instance_groups/name=sentinel", Type: manifestdiff.Removed, Old: map[string]interface{}{"name": "sentinel", "instances": 3}},
+			{Path: "/instance_groups/name=proxy", Type: manifestdiff.Added, New: map[string]interface{}{"name": "proxy", "instances": 1}},
+		}))
+	})
+
+	It("compares unnamed list elements by index", func() {
+		changes, err := manifestdiff.Diff([]byte("azs: [z1, z2]\n"), []byte("azs: [z1, z3, z4]\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]manifestdiff.Change{
+			{Path: "/azs/1", Type: manifestdiff.Changed, Old: "z2", New: "z3"},
+			{Path: "/azs/2", Type: manifestdiff.Added, New: "z4"},
+		}))
+	})
+
+	It("treats an empty document as an empty map", func() {
+		changes, err := manifestdiff.Diff(nil, []byte("name: foo\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]manifestdiff.Change{
+			{Path: "/name", Type: manifestdiff.Added, New: "foo"},
+		}))
+	})
+
+	It("returns changes that can be encoded as JSON", func() {
+		changes, err := manifestdiff.Diff([]byte("properties: {a: 1}\n"), []byte("properties: {a: {b: 2}}\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		encoded, err := json.Marshal(changes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(MatchJSON(`[{"path": "/properties/a", "type": "changed", "old": 1, "new": {"b": 2}}]`))
+	})
+
+	It("returns an error when a document is not valid YAML", func() {
+		_, err := manifestdiff.Diff([]byte("name: foo\n"), []byte("{{"))
+		Expect(err).To(MatchError(ContainSubstring("error parsing new document")))
+	})
+})
+
+var _ = Describe("Redact", func() {
+	It("keeps only the path and type of each change", func() {
+		changes := []manifestdiff.Change{
+			{Path: "/properties/password", Type: manifestdiff.Changed, Old: "old-secret", New: "new-secret"},
+			{Path: "/variables/name=admin", Type: manifestdiff.Removed, Old: map[interface{}]interface{}{"name": "admin"}},
+		}
+
+		Expect(manifestdiff.Redact(changes)).To(Equal([]manifestdiff.Change{
+			{Path: "/properties/password", Type: manifestdiff.Changed},
+			{Path: "/variables/name=admin", Type: manifestdiff.Removed},
+		}))
+		Expect(changes[0].Old).To(Equal("old-secret"), "the original changes are not modified")
+	})
+
+	It("leaves out the values when encoded as JSON", func() {
+		encoded, err := json.Marshal(manifestdiff.Redact([]manifestdiff.Change{{Path: "/properties/a", Type: manifestdiff.Changed, Old: 1, New: 2}}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(MatchJSON(`[{"path": "/properties/a", "type": "changed"}]`))
+	})
+})
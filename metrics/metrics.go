@@ -0,0 +1,55 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package metrics holds the broker's runtime counters and histograms, which
+// are exposed in the Prometheus exposition format by the management API.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+var (
+	Registry = prometheus.NewRegistry()
+
+	OSBAPIRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "odb_osbapi_request_duration_seconds",
+			Help:    "Time taken to serve Open Service Broker API requests.",
+			Buckets: DefaultBuckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	AdapterInvocationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "odb_adapter_invocation_duration_seconds",
+			Help:    "Time taken by service adapter invocations.",
+			Buckets: DefaultBuckets,
+		},
+		[]string{"subcommand"},
+	)
+
+	AdapterInvocations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "odb_adapter_invocations_total",
+			Help: "Service adapter invocations by exit code.",
+		},
+		[]string{"subcommand", "exit_code"},
+	)
+
+	BoshTaskOutcomes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "odb_bosh_task_outcomes_total",
+			Help: "Final states of broker operations and binding errands, counted once per operation.",
+		},
+		[]string{"operation_type", "state"},
+	)
+)
+
+func init() {
+	Registry.MustRegister(OSBAPIRequestDuration, AdapterInvocationDuration, AdapterInvocations, BoshTaskOutcomes)
+}
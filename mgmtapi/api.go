@@ -13,6 +13,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
@@ -22,15 +24,29 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type api struct {
 	manageableBroker ManageableBroker
-	serviceOffering  config.ServiceOffering
+	serviceCatalog   ServiceCatalog
 	loggerFactory    *loggerfactory.LoggerFactory
 }
 
+// ServiceCatalog provides the service offerings of the broker, which change
+// when the broker reloads its configuration.
+type ServiceCatalog interface {
+	ServiceOfferings() []config.ServiceOffering
+}
+
+//go:generate counterfeiter -o fake_config_reloader/fake_config_reloader.go . ConfigReloader
+type ConfigReloader interface {
+	Reload(logger *log.Logger) error
+}
+
 //go:generate counterfeiter -o fake_manageable_broker/fake_manageable_broker.go . ManageableBroker
 type ManageableBroker interface {
 	Instances(logger *log.Logger) ([]service.Instance, error)
@@ -39,6 +55,8 @@ type ManageableBroker interface {
 	Upgrade(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error)
 	Recreate(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error)
 	CountInstancesOfPlans(logger *log.Logger) (map[cf.ServicePlan]int, error)
+	InstanceOperations(instanceID string, logger *log.Logger) ([]broker.InstanceOperation, error)
+	PreviewUpgrade(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.UpgradePreview, error)
 }
 
 type Deployment struct {
@@ -51,14 +69,20 @@ type Metric struct {
 	Unit  string  `json:"unit"`
 }
 
-func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, serviceOffering config.ServiceOffering, loggerFactory *loggerfactory.LoggerFactory) {
-	a := &api{manageableBroker: manageableBroker, serviceOffering: serviceOffering, loggerFactory: loggerFactory}
+func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, serviceCatalog ServiceCatalog, loggerFactory *loggerfactory.LoggerFactory) {
+	a := &api{manageableBroker: manageableBroker, serviceCatalog: serviceCatalog, loggerFactory: loggerFactory}
 	r.HandleFunc("/mgmt/service_instances", a.listAllInstances).Methods("GET")
 
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/operations", a.listInstanceOperations).Methods("GET")
+
 	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.recreateInstance).
 		Methods("PATCH").
 		Queries("operation_type", "recreate")
 
+	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.previewUpgrade).
+		Methods("PATCH").
+		Queries("operation_type", "upgrade", "dry_run", "true")
+
 	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.upgradeInstance).
 		Methods("PATCH").
 		Queries("operation_type", "upgrade")
@@ -70,6 +94,23 @@ func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, serviceOffer
 	r.HandleFunc("/mgmt/orphan_deployments", a.listOrphanDeployments).Methods("GET")
 }
 
+// AttachConfigReloadRoute serves a route that makes the broker reload its
+// configuration file.
+func AttachConfigReloadRoute(r *mux.Router, reloader ConfigReloader, loggerFactory *loggerfactory.LoggerFactory) {
+	r.HandleFunc("/mgmt/config/reload", func(w http.ResponseWriter, r *http.Request) {
+		logger := loggerFactory.NewWithRequestID()
+		if err := reloader.Reload(logger); err != nil {
+			logger.Printf("error occurred reloading the configuration: %s", err)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			if err := json.NewEncoder(w).Encode(brokerapi.ErrorResponse{Description: err.Error()}); err != nil {
+				logger.Printf("error occurred encoding json: %s", err)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("POST")
+}
+
 func badRequestHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -116,18 +157,36 @@ func (a *api) listAllInstances(w http.ResponseWriter, r *http.Request) {
 	a.writeJson(w, instances, logger)
 }
 
+func (a *api) listInstanceOperations(w http.ResponseWriter, r *http.Request) {
+	logger := a.loggerFactory.NewWithRequestID()
+	instanceID := mux.Vars(r)["instance_id"]
+
+	operations, err := a.manageableBroker.InstanceOperations(instanceID, logger)
+	switch err.(type) {
+	case nil:
+		a.writeJson(w, operations, logger)
+	case broker.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		logger.Printf("error occurred querying operations for instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
 func (a *api) recreateInstance(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	instanceID := vars["instance_id"]
 
+	var details brokerapi.UpdateDetails
+	decodeErr := json.NewDecoder(r.Body).Decode(&details)
+
 	requestID := uuid.New()
-	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRecreate), requestID, a.serviceOffering.Name, instanceID)
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRecreate), requestID, a.serviceOfferingName(details.PlanID), instanceID)
 
 	logger := a.loggerFactory.NewWithContext(ctx)
 
-	var details brokerapi.UpdateDetails
-	if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
-		logger.Printf("error occurred parsing requests body: %s", err)
+	if decodeErr != nil {
+		logger.Printf("error occurred parsing requests body: %s", decodeErr)
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		a.writeJson(w, brokerapi.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
 		return
@@ -156,14 +215,16 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	instanceID := vars["instance_id"]
 
+	var details brokerapi.UpdateDetails
+	decodeErr := json.NewDecoder(r.Body).Decode(&details)
+
 	requestID := uuid.New()
-	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, a.serviceOffering.Name, instanceID)
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, a.serviceOfferingName(details.PlanID), instanceID)
 
 	logger := a.loggerFactory.NewWithContext(ctx)
 
-	var details brokerapi.UpdateDetails
-	if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
-		logger.Printf("error occurred parsing requests body: %s", err)
+	if decodeErr != nil {
+		logger.Printf("error occurred parsing requests body: %s", decodeErr)
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		a.writeJson(w, brokerapi.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
 		return
@@ -188,36 +249,106 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (a *api) previewUpgrade(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["instance_id"]
+
+	var details brokerapi.UpdateDetails
+	decodeErr := json.NewDecoder(r.Body).Decode(&details)
+
+	requestID := uuid.New()
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, a.serviceOfferingName(details.PlanID), instanceID)
+
+	logger := a.loggerFactory.NewWithContext(ctx)
+
+	if decodeErr != nil {
+		logger.Printf("error occurred parsing requests body: %s", decodeErr)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
+		return
+	}
+
+	preview, err := a.manageableBroker.PreviewUpgrade(ctx, instanceID, details, logger)
+
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusOK)
+		a.writeJson(w, preview, logger)
+	case cf.ResourceNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+	case broker.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusGone)
+	case error:
+		logger.Printf("error occurred previewing upgrade of instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	}
+}
+
 func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 	logger := a.loggerFactory.NewWithRequestID()
 
 	brokerMetrics := []Metric{}
 	instanceCountsByPlan, err := a.manageableBroker.CountInstancesOfPlans(logger)
 
+	// The runtime metrics do not depend on Cloud Foundry, so in the prometheus
+	// format they are served without the instance gauges when the instances
+	// cannot be counted.
+	prometheusFormat := r.URL.Query().Get("format") == "prometheus"
+
 	if err != nil {
-		logger.Printf("error getting instance count for service offering %s: %s", a.serviceOffering.Name, err)
+		logger.Printf("error getting instance count for service offering %s: %s", a.serviceOfferingNames(), err)
+		if prometheusFormat {
+			a.writePrometheusMetrics(w, r, nil, logger)
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if len(instanceCountsByPlan) == 0 {
-		logger.Printf("The %s service broker must be registered with Cloud Foundry before metrics can be collected", a.serviceOffering.Name)
+		logger.Printf("The %s service broker must be registered with Cloud Foundry before metrics can be collected", a.serviceOfferingNames())
+		if prometheusFormat {
+			a.writePrometheusMetrics(w, r, nil, logger)
+			return
+		}
 		w.WriteHeader(http.StatusServiceUnavailable)
 		return
 	}
 
-	totalInstances := 0
+	if prometheusFormat {
+		a.writePrometheusMetrics(w, r, instanceCountsByPlan, logger)
+		return
+	}
 
-	for plan, instanceCount := range instanceCountsByPlan {
-		serviceOfferingPlan, err := a.getPlan(plan.ServicePlanEntity.UniqueID)
-		if err != nil {
+	for plan := range instanceCountsByPlan {
+		if _, _, err := a.getPlan(plan.ServicePlanEntity.UniqueID); err != nil {
 			logger.Println(err)
 			a.writeJson(w, []interface{}{}, logger)
 			return
 		}
+	}
+
+	for _, serviceOffering := range a.serviceCatalog.ServiceOfferings() {
+		brokerMetrics = append(brokerMetrics, serviceOfferingMetrics(serviceOffering, instanceCountsByPlan)...)
+	}
+
+	a.writeJson(w, brokerMetrics, logger)
+}
+
+func serviceOfferingMetrics(serviceOffering config.ServiceOffering, instanceCountsByPlan map[cf.ServicePlan]int) []Metric {
+	var brokerMetrics []Metric
+	totalInstances := 0
+	planCounts := map[string]int{}
+
+	for plan, instanceCount := range instanceCountsByPlan {
+		serviceOfferingPlan, found := serviceOffering.FindPlanByID(plan.ServicePlanEntity.UniqueID)
+		if !found {
+			continue
+		}
 
 		countMetric := Metric{
-			Key:   fmt.Sprintf("/on-demand-broker/%s/%s/total_instances", a.serviceOffering.Name, serviceOfferingPlan.Name),
+			Key:   fmt.Sprintf("/on-demand-broker/%s/%s/total_instances", serviceOffering.Name, serviceOfferingPlan.Name),
 			Unit:  "count",
 			Value: float64(instanceCount),
 		}
@@ -226,34 +357,162 @@ func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 		if serviceOfferingPlan.Quotas.ServiceInstanceLimit != nil {
 			limit := *serviceOfferingPlan.Quotas.ServiceInstanceLimit
 			quotaMetric := Metric{
-				Key:   fmt.Sprintf("/on-demand-broker/%s/%s/quota_remaining", a.serviceOffering.Name, serviceOfferingPlan.Name),
+				Key:   fmt.Sprintf("/on-demand-broker/%s/%s/quota_remaining", serviceOffering.Name, serviceOfferingPlan.Name),
 				Unit:  "count",
 				Value: float64(limit - instanceCount),
 			}
 			brokerMetrics = append(brokerMetrics, quotaMetric)
 		}
 
+		if limits := serviceOfferingPlan.Quotas.ResourceLimits; limits != nil {
+			prefix := fmt.Sprintf("/on-demand-broker/%s/%s", serviceOffering.Name, serviceOfferingPlan.Name)
+			usage := broker.PlanResourceUsage(serviceOfferingPlan, instanceCount)
+			brokerMetrics = append(brokerMetrics, resourceMetrics(prefix, usage, limits)...)
+		}
+
+		planCounts[serviceOfferingPlan.ID] = instanceCount
 		totalInstances = totalInstances + instanceCount
 	}
 
 	totalCountMetric := Metric{
-		Key:   fmt.Sprintf("/on-demand-broker/%s/total_instances", a.serviceOffering.Name),
+		Key:   fmt.Sprintf("/on-demand-broker/%s/total_instances", serviceOffering.Name),
 		Unit:  "count",
 		Value: float64(totalInstances),
 	}
 	brokerMetrics = append(brokerMetrics, totalCountMetric)
 
-	if a.serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
-		limit := *a.serviceOffering.GlobalQuotas.ServiceInstanceLimit
+	if serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
+		limit := *serviceOffering.GlobalQuotas.ServiceInstanceLimit
 		quotaMetric := Metric{
-			Key:   fmt.Sprintf("/on-demand-broker/%s/quota_remaining", a.serviceOffering.Name),
+			Key:   fmt.Sprintf("/on-demand-broker/%s/quota_remaining", serviceOffering.Name),
 			Unit:  "count",
 			Value: float64(limit - totalInstances),
 		}
 		brokerMetrics = append(brokerMetrics, quotaMetric)
 	}
 
-	a.writeJson(w, brokerMetrics, logger)
+	if limits := serviceOffering.GlobalQuotas.ResourceLimits; limits != nil {
+		prefix := fmt.Sprintf("/on-demand-broker/%s", serviceOffering.Name)
+		usage := broker.GlobalResourceUsage(serviceOffering.Plans, planCounts)
+		brokerMetrics = append(brokerMetrics, resourceMetrics(prefix, usage, limits)...)
+	}
+
+	return brokerMetrics
+}
+
+// resourceMetrics reports the usage and the remaining quota of each kind of
+// resource that has a limit.
+func resourceMetrics(prefix string, usage, limits map[string]int) []Metric {
+	var resourceMetrics []Metric
+	for _, kind := range sortedKinds(limits) {
+		resourceMetrics = append(resourceMetrics,
+			Metric{
+				Key:   fmt.Sprintf("%s/%s/resource_used", prefix, kind),
+				Unit:  "count",
+				Value: float64(usage[kind]),
+			},
+			Metric{
+				Key:   fmt.Sprintf("%s/%s/resource_remaining", prefix, kind),
+				Unit:  "count",
+				Value: float64(limits[kind] - usage[kind]),
+			},
+		)
+	}
+	return resourceMetrics
+}
+
+func setResourceGauges(used, remaining *prometheus.GaugeVec, labels prometheus.Labels, usage, limits map[string]int) {
+	for kind, limit := range limits {
+		resourceLabels := prometheus.Labels{"resource": kind}
+		for name, value := range labels {
+			resourceLabels[name] = value
+		}
+		used.With(resourceLabels).Set(float64(usage[kind]))
+		remaining.With(resourceLabels).Set(float64(limit - usage[kind]))
+	}
+}
+
+func sortedKinds(limits map[string]int) []string {
+	var kinds []string
+	for kind := range limits {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// writePrometheusMetrics renders the instance counts as gauges, followed by the
+// request, adapter and BOSH task metrics collected since the broker started.
+// writePrometheusMetrics serves the instance gauges along with the broker's
+// runtime metrics. Without instance counts, only the runtime metrics are
+// served.
+func (a *api) writePrometheusMetrics(w http.ResponseWriter, r *http.Request, instanceCountsByPlan map[cf.ServicePlan]int, logger *log.Logger) {
+	if len(instanceCountsByPlan) == 0 {
+		promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{ErrorLog: logger}).ServeHTTP(w, r)
+		return
+	}
+
+	newGaugeVec := func(name, help string, labelNames ...string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames)
+	}
+	planInstances := newGaugeVec("odb_plan_service_instances", "Number of service instances of a plan.", "service", "plan")
+	planQuotaRemaining := newGaugeVec("odb_plan_service_instances_quota_remaining", "Number of service instances that can still be created for a plan.", "service", "plan")
+	serviceInstances := newGaugeVec("odb_service_instances", "Number of service instances of the service offering.", "service")
+	serviceQuotaRemaining := newGaugeVec("odb_service_instances_quota_remaining", "Number of service instances that can still be created for the service offering.", "service")
+	planResourceUsed := newGaugeVec("odb_plan_resource_used", "Amount of a resource used by the service instances of a plan.", "service", "plan", "resource")
+	planResourceRemaining := newGaugeVec("odb_plan_resource_remaining", "Amount of a resource that the service instances of a plan can still use.", "service", "plan", "resource")
+	serviceResourceUsed := newGaugeVec("odb_service_resource_used", "Amount of a resource used by the service instances of the service offering.", "service", "resource")
+	serviceResourceRemaining := newGaugeVec("odb_service_resource_remaining", "Amount of a resource that the service instances of the service offering can still use.", "service", "resource")
+
+	totalInstances := map[string]int{}
+	planCounts := map[string]map[string]int{}
+
+	for plan, instanceCount := range instanceCountsByPlan {
+		serviceOffering, serviceOfferingPlan, err := a.getPlan(plan.ServicePlanEntity.UniqueID)
+		if err != nil {
+			logger.Println(err)
+			continue
+		}
+
+		labels := prometheus.Labels{"service": serviceOffering.Name, "plan": serviceOfferingPlan.Name}
+		planInstances.With(labels).Set(float64(instanceCount))
+		if serviceOfferingPlan.Quotas.ServiceInstanceLimit != nil {
+			limit := *serviceOfferingPlan.Quotas.ServiceInstanceLimit
+			planQuotaRemaining.With(labels).Set(float64(limit - instanceCount))
+		}
+		if limits := serviceOfferingPlan.Quotas.ResourceLimits; limits != nil {
+			setResourceGauges(planResourceUsed, planResourceRemaining, labels, broker.PlanResourceUsage(serviceOfferingPlan, instanceCount), limits)
+		}
+
+		if planCounts[serviceOffering.ID] == nil {
+			planCounts[serviceOffering.ID] = map[string]int{}
+		}
+		planCounts[serviceOffering.ID][serviceOfferingPlan.ID] = instanceCount
+		totalInstances[serviceOffering.ID] = totalInstances[serviceOffering.ID] + instanceCount
+	}
+
+	for _, serviceOffering := range a.serviceCatalog.ServiceOfferings() {
+		serviceLabels := prometheus.Labels{"service": serviceOffering.Name}
+		serviceInstances.With(serviceLabels).Set(float64(totalInstances[serviceOffering.ID]))
+		if serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
+			limit := *serviceOffering.GlobalQuotas.ServiceInstanceLimit
+			serviceQuotaRemaining.With(serviceLabels).Set(float64(limit - totalInstances[serviceOffering.ID]))
+		}
+		if limits := serviceOffering.GlobalQuotas.ResourceLimits; limits != nil {
+			setResourceGauges(serviceResourceUsed, serviceResourceRemaining, serviceLabels, broker.GlobalResourceUsage(serviceOffering.Plans, planCounts[serviceOffering.ID]), limits)
+		}
+	}
+
+	instanceMetrics := prometheus.NewRegistry()
+	instanceMetrics.MustRegister(
+		planInstances, planQuotaRemaining,
+		serviceInstances, serviceQuotaRemaining,
+		planResourceUsed, planResourceRemaining,
+		serviceResourceUsed, serviceResourceRemaining,
+	)
+
+	gatherers := prometheus.Gatherers{instanceMetrics, metrics.Registry}
+	promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorLog: logger}).ServeHTTP(w, r)
 }
 
 func (a *api) writeJson(w io.Writer, obj interface{}, logger *log.Logger) {
@@ -262,11 +521,28 @@ func (a *api) writeJson(w io.Writer, obj interface{}, logger *log.Logger) {
 	}
 }
 
-func (a *api) getPlan(planID string) (config.Plan, error) {
-	for _, plan := range a.serviceOffering.Plans {
-		if plan.ID == planID {
-			return plan, nil
+func (a *api) getPlan(planID string) (config.ServiceOffering, config.Plan, error) {
+	for _, serviceOffering := range a.serviceCatalog.ServiceOfferings() {
+		if plan, found := serviceOffering.FindPlanByID(planID); found {
+			return serviceOffering, plan, nil
 		}
 	}
-	return config.Plan{}, fmt.Errorf("no plan found with marketplace ID %s", planID)
+	return config.ServiceOffering{}, config.Plan{}, fmt.Errorf("no plan found with marketplace ID %s", planID)
+}
+
+// serviceOfferingName returns the name of the offering of the plan, for
+// logging, or the name of the first offering when the plan is not known.
+func (a *api) serviceOfferingName(planID string) string {
+	if serviceOffering, _, err := a.getPlan(planID); err == nil {
+		return serviceOffering.Name
+	}
+	return a.serviceCatalog.ServiceOfferings()[0].Name
+}
+
+func (a *api) serviceOfferingNames() string {
+	var names []string
+	for _, serviceOffering := range a.serviceCatalog.ServiceOfferings() {
+		names = append(names, serviceOffering.Name)
+	}
+	return strings.Join(names, ", ")
 }
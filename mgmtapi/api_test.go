@@ -17,6 +17,7 @@ import (
 	"net/http/httptest"
 
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	. "github.com/onsi/ginkgo"
@@ -27,7 +28,10 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi/fake_config_reloader"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi/fake_manageable_broker"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
@@ -39,6 +43,7 @@ var _ = Describe("Management API", func() {
 		logs             *gbytes.Buffer
 		loggerFactory    *loggerfactory.LoggerFactory
 		serviceOffering  config.ServiceOffering
+		otherOfferings   []config.ServiceOffering
 	)
 
 	BeforeEach(func() {
@@ -47,6 +52,7 @@ var _ = Describe("Management API", func() {
 			Name:  "some_service_offering",
 			Plans: []config.Plan{{ID: "foo_id", Name: "foo_plan"}, {ID: "bar_id", Name: "bar_plan"}},
 		}
+		otherOfferings = nil
 		logs = gbytes.NewBuffer()
 		loggerFactory = loggerfactory.New(io.MultiWriter(GinkgoWriter, logs), "mgmtapi-unit-tests", log.LstdFlags)
 		manageableBroker = new(fake_manageable_broker.FakeManageableBroker)
@@ -54,7 +60,8 @@ var _ = Describe("Management API", func() {
 
 	JustBeforeEach(func() {
 		router := mux.NewRouter()
-		mgmtapi.AttachRoutes(router, manageableBroker, serviceOffering, loggerFactory)
+		serviceCatalog := config.Config{ServiceCatalog: serviceOffering, AdditionalServiceOfferings: otherOfferings}
+		mgmtapi.AttachRoutes(router, manageableBroker, serviceCatalog, loggerFactory)
 		server = httptest.NewServer(router)
 	})
 
@@ -144,6 +151,64 @@ var _ = Describe("Management API", func() {
 		})
 	})
 
+	Describe("listing the operations of an instance", func() {
+		var listResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			listResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/some-instance-id/operations", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the operations can be retrieved", func() {
+			var operations []broker.InstanceOperation
+
+			BeforeEach(func() {
+				startedAt := time.Date(2019, 3, 12, 10, 0, 0, 0, time.UTC)
+				operations = []broker.InstanceOperation{
+					{BoshTaskID: 1, OperationType: broker.OperationTypeCreate, State: "done", Description: "create deployment", StartedAt: startedAt, LastActivityAt: startedAt.Add(time.Minute)},
+					{BoshTaskID: 2, OperationType: broker.OperationTypeCreate, State: "error", Description: "run errand health-check from deployment service-instance_some-instance-id", Errand: "health-check", StartedAt: startedAt.Add(2 * time.Minute), LastActivityAt: startedAt.Add(3 * time.Minute)},
+				}
+				manageableBroker.InstanceOperationsReturns(operations, nil)
+			})
+
+			It("returns HTTP 200", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns the operations of the instance", func() {
+				Expect(manageableBroker.InstanceOperationsCallCount()).To(Equal(1))
+				instanceID, _ := manageableBroker.InstanceOperationsArgsForCall(0)
+				Expect(instanceID).To(Equal("some-instance-id"))
+
+				var actualOperations []broker.InstanceOperation
+				Expect(json.NewDecoder(listResp.Body).Decode(&actualOperations)).To(Succeed())
+				Expect(actualOperations).To(Equal(operations))
+			})
+		})
+
+		Context("when the instance is not known to BOSH", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceOperationsReturns(nil, broker.NewDeploymentNotFoundError(errors.New("bosh deployment 'service-instance_some-instance-id' not found")))
+			})
+
+			It("returns HTTP 404", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the operations cannot be retrieved", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceOperationsReturns(nil, errors.New("error getting tasks"))
+			})
+
+			It("returns HTTP 500 and logs the error", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Eventually(logs).Should(gbytes.Say("error occurred querying operations for instance some-instance-id: error getting tasks"))
+			})
+		})
+	})
+
 	Describe("process an instance", func() {
 		var (
 			instanceID  = "283974"
@@ -386,6 +451,86 @@ var _ = Describe("Management API", func() {
 			})
 
 		})
+
+		Context("when the process is an upgrade dry run", func() {
+			JustBeforeEach(func() {
+				var err error
+				response, err = Patch(fmt.Sprintf("%s/mgmt/service_instances/%s?operation_type=upgrade&dry_run=true", server.URL, instanceID), requestBody)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when it succeeds", func() {
+				BeforeEach(func() {
+					manageableBroker.PreviewUpgradeReturns(broker.UpgradePreview{
+						ManifestChanges: []manifestdiff.Change{{Path: "/stemcells/0/version", Type: manifestdiff.Changed, Old: "1", New: "2"}},
+						ConfigChanges: map[string][]manifestdiff.Change{
+							"cloud": {{Path: "/vm_types/name=large", Type: manifestdiff.Removed, Old: map[string]interface{}{"name": "large"}}},
+						},
+					}, nil)
+				})
+
+				It("previews the upgrade using the broker without upgrading", func() {
+					Expect(manageableBroker.PreviewUpgradeCallCount()).To(Equal(1))
+					_, actualInstanceID, actualUpdateDetails, _ := manageableBroker.PreviewUpgradeArgsForCall(0)
+					Expect(actualInstanceID).To(Equal(instanceID))
+					Expect(actualUpdateDetails).To(Equal(brokerapi.UpdateDetails{PlanID: planID}))
+					Expect(manageableBroker.UpgradeCallCount()).To(Equal(0))
+				})
+
+				It("responds with HTTP 200 and the changes", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{
+						"manifest_changes": [{"path": "/stemcells/0/version", "type": "changed", "old": "1", "new": "2"}],
+						"config_changes": {
+							"cloud": [{"path": "/vm_types/name=large", "type": "removed", "old": {"name": "large"}}]
+						}
+					}`))
+				})
+			})
+
+			Context("when the CF service instance is not found", func() {
+				BeforeEach(func() {
+					manageableBroker.PreviewUpgradeReturns(broker.UpgradePreview{}, cf.ResourceNotFoundError{})
+				})
+
+				It("responds with HTTP 404 Not Found", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the bosh deployment is not found", func() {
+				BeforeEach(func() {
+					manageableBroker.PreviewUpgradeReturns(broker.UpgradePreview{}, broker.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+				})
+
+				It("responds with HTTP 410 Gone", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusGone))
+				})
+			})
+
+			Context("when it fails", func() {
+				BeforeEach(func() {
+					manageableBroker.PreviewUpgradeReturns(broker.UpgradePreview{}, errors.New("adapter error"))
+				})
+
+				It("responds with HTTP 500 and the error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"description": "adapter error"}`))
+					Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred previewing upgrade of instance %s: adapter error", instanceID)))
+				})
+			})
+
+			Context("when no request body is provided", func() {
+				BeforeEach(func() {
+					requestBody = ""
+				})
+
+				It("fails with an appropriate error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+					Expect(manageableBroker.PreviewUpgradeCallCount()).To(Equal(0))
+				})
+			})
+		})
 	})
 
 	Describe("producing service metrics", func() {
@@ -596,6 +741,86 @@ var _ = Describe("Management API", func() {
 			})
 		})
 
+		Context("when resource quotas are set", func() {
+			BeforeEach(func() {
+				serviceOffering.Plans[0].ResourceCosts = map[string]int{"ips": 1, "memory": 2}
+				serviceOffering.Plans[0].Quotas = config.Quotas{ResourceLimits: map[string]int{"memory": 10}}
+				serviceOffering.Plans[1].ResourceCosts = map[string]int{"ips": 3}
+				serviceOffering.GlobalQuotas = config.Quotas{ResourceLimits: map[string]int{"ips": 20}}
+
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+					cfServicePlan("1234", "foo_id", "url", "name"): 2,
+					cfServicePlan("1234", "bar_id", "url", "name"): 4,
+				}, nil)
+			})
+
+			It("returns the usage and remaining quota of each limited resource", func() {
+				defer instancesForPlanResponse.Body.Close()
+				var brokerMetrics []mgmtapi.Metric
+
+				Expect(json.NewDecoder(instancesForPlanResponse.Body).Decode(&brokerMetrics)).To(Succeed())
+				Expect(brokerMetrics).To(ConsistOf(
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/foo_plan/total_instances", Value: 2, Unit: "count"},
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/foo_plan/memory/resource_used", Value: 4, Unit: "count"},
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/foo_plan/memory/resource_remaining", Value: 6, Unit: "count"},
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/bar_plan/total_instances", Value: 4, Unit: "count"},
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/total_instances", Value: 6, Unit: "count"},
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/ips/resource_used", Value: 14, Unit: "count"},
+					mgmtapi.Metric{Key: "/on-demand-broker/some_service_offering/ips/resource_remaining", Value: 6, Unit: "count"},
+				))
+			})
+		})
+
+		Context("when the broker serves more than one service offering", func() {
+			BeforeEach(func() {
+				limit := 4
+				otherOfferings = []config.ServiceOffering{{
+					ID:           "other_service_offering-id",
+					Name:         "other_service_offering",
+					Plans:        []config.Plan{{ID: "baz_id", Name: "baz_plan"}},
+					GlobalQuotas: config.Quotas{ServiceInstanceLimit: &limit},
+				}}
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+					cfServicePlan("1234", "foo_id", "url", "name"): 2,
+					cfServicePlan("5678", "baz_id", "url", "name"): 3,
+				}, nil)
+			})
+
+			It("returns the instance counts and quotas of each service offering", func() {
+				defer instancesForPlanResponse.Body.Close()
+				var brokerMetrics []mgmtapi.Metric
+
+				Expect(json.NewDecoder(instancesForPlanResponse.Body).Decode(&brokerMetrics)).To(Succeed())
+				Expect(brokerMetrics).To(ConsistOf(
+					mgmtapi.Metric{
+						Key:   "/on-demand-broker/some_service_offering/foo_plan/total_instances",
+						Value: 2,
+						Unit:  "count",
+					},
+					mgmtapi.Metric{
+						Key:   "/on-demand-broker/some_service_offering/total_instances",
+						Value: 2,
+						Unit:  "count",
+					},
+					mgmtapi.Metric{
+						Key:   "/on-demand-broker/other_service_offering/baz_plan/total_instances",
+						Value: 3,
+						Unit:  "count",
+					},
+					mgmtapi.Metric{
+						Key:   "/on-demand-broker/other_service_offering/total_instances",
+						Value: 3,
+						Unit:  "count",
+					},
+					mgmtapi.Metric{
+						Key:   "/on-demand-broker/other_service_offering/quota_remaining",
+						Value: 1,
+						Unit:  "count",
+					},
+				))
+			})
+		})
+
 		Context("when there are no service instances", func() {
 			BeforeEach(func() {
 				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
@@ -638,6 +863,141 @@ var _ = Describe("Management API", func() {
 		})
 	})
 
+	Describe("producing service metrics in the prometheus format", func() {
+		var (
+			metricsResponse *http.Response
+			body            string
+		)
+
+		BeforeEach(func() {
+			planLimit := 7
+			serviceOffering.Plans[0].Quotas = config.Quotas{ServiceInstanceLimit: &planLimit}
+			globalLimit := 12
+			serviceOffering.GlobalQuotas = config.Quotas{ServiceInstanceLimit: &globalLimit}
+
+			manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", "foo_id", "url", "name"): 2,
+				cfServicePlan("1234", "bar_id", "url", "name"): 3,
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			metricsResponse, err = http.Get(fmt.Sprintf("%s/mgmt/metrics?format=prometheus", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			defer metricsResponse.Body.Close()
+			bodyBytes, err := ioutil.ReadAll(metricsResponse.Body)
+			Expect(err).NotTo(HaveOccurred())
+			body = string(bodyBytes)
+		})
+
+		It("returns HTTP 200 with the prometheus content type", func() {
+			Expect(metricsResponse.StatusCode).To(Equal(http.StatusOK))
+			Expect(metricsResponse.Header.Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+		})
+
+		It("exposes the instance counts and remaining quotas as gauges", func() {
+			Expect(body).To(ContainSubstring(`# TYPE odb_plan_service_instances gauge
+odb_plan_service_instances{plan="bar_plan",service="some_service_offering"} 3
+odb_plan_service_instances{plan="foo_plan",service="some_service_offering"} 2
+`))
+			Expect(body).To(ContainSubstring(`odb_plan_service_instances_quota_remaining{plan="foo_plan",service="some_service_offering"} 5
+`))
+			Expect(body).NotTo(ContainSubstring(`odb_plan_service_instances_quota_remaining{plan="bar_plan"`))
+			Expect(body).To(ContainSubstring(`odb_service_instances{service="some_service_offering"} 5
+`))
+			Expect(body).To(ContainSubstring(`odb_service_instances_quota_remaining{service="some_service_offering"} 7
+`))
+		})
+
+		Context("when resource quotas are set", func() {
+			BeforeEach(func() {
+				serviceOffering.Plans[0].ResourceCosts = map[string]int{"ips": 1, "memory": 2}
+				serviceOffering.Plans[0].Quotas.ResourceLimits = map[string]int{"memory": 10}
+				serviceOffering.Plans[1].ResourceCosts = map[string]int{"ips": 3}
+				serviceOffering.GlobalQuotas.ResourceLimits = map[string]int{"ips": 20}
+			})
+
+			It("exposes the usage and remaining quota of each limited resource", func() {
+				Expect(body).To(ContainSubstring(`odb_plan_resource_used{plan="foo_plan",resource="memory",service="some_service_offering"} 4
+`))
+				Expect(body).To(ContainSubstring(`odb_plan_resource_remaining{plan="foo_plan",resource="memory",service="some_service_offering"} 6
+`))
+				Expect(body).To(ContainSubstring(`odb_service_resource_used{resource="ips",service="some_service_offering"} 11
+`))
+				Expect(body).To(ContainSubstring(`odb_service_resource_remaining{resource="ips",service="some_service_offering"} 9
+`))
+			})
+		})
+
+		Context("when the broker serves more than one service offering", func() {
+			BeforeEach(func() {
+				otherOfferings = []config.ServiceOffering{{
+					ID:    "other_service_offering-id",
+					Name:  "other_service_offering",
+					Plans: []config.Plan{{ID: "baz_id", Name: "baz_plan"}},
+				}}
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+					cfServicePlan("1234", "foo_id", "url", "name"): 2,
+					cfServicePlan("5678", "baz_id", "url", "name"): 4,
+				}, nil)
+			})
+
+			It("labels the gauges with the service offering", func() {
+				Expect(body).To(ContainSubstring(`odb_plan_service_instances{plan="baz_plan",service="other_service_offering"} 4
+odb_plan_service_instances{plan="foo_plan",service="some_service_offering"} 2
+`))
+				Expect(body).To(ContainSubstring(`odb_service_instances{service="other_service_offering"} 4
+odb_service_instances{service="some_service_offering"} 2
+`))
+			})
+		})
+
+		Context("when the broker has served requests", func() {
+			BeforeEach(func() {
+				metrics.OSBAPIRequestDuration.WithLabelValues("GET", "/v2/catalog", "200").Observe(0.01)
+				metrics.AdapterInvocationDuration.WithLabelValues("generate-manifest").Observe(0.2)
+				metrics.AdapterInvocations.WithLabelValues("generate-manifest", "0").Inc()
+				metrics.BoshTaskOutcomes.WithLabelValues("create", "succeeded").Inc()
+			})
+
+			It("exposes the broker runtime metrics", func() {
+				Expect(body).To(ContainSubstring("# TYPE odb_osbapi_request_duration_seconds histogram"))
+				Expect(body).To(ContainSubstring("# TYPE odb_adapter_invocation_duration_seconds histogram"))
+				Expect(body).To(ContainSubstring("# TYPE odb_adapter_invocations_total counter"))
+				Expect(body).To(ContainSubstring("# TYPE odb_bosh_task_outcomes_total counter"))
+			})
+		})
+
+		Context("when the broker is not registered with CF", func() {
+			BeforeEach(func() {
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{}, nil)
+				metrics.AdapterInvocations.WithLabelValues("generate-manifest", "0").Inc()
+			})
+
+			It("exposes the broker runtime metrics without the instance gauges", func() {
+				Expect(metricsResponse.StatusCode).To(Equal(http.StatusOK))
+				Expect(body).To(ContainSubstring("# TYPE odb_adapter_invocations_total counter"))
+				Expect(body).NotTo(ContainSubstring("odb_plan_service_instances"))
+				Expect(body).NotTo(ContainSubstring("odb_service_instances"))
+			})
+		})
+
+		Context("when the instances cannot be counted", func() {
+			BeforeEach(func() {
+				manageableBroker.CountInstancesOfPlansReturns(nil, errors.New("CF API unavailable"))
+				metrics.AdapterInvocations.WithLabelValues("generate-manifest", "0").Inc()
+			})
+
+			It("exposes the broker runtime metrics without the instance gauges", func() {
+				Expect(metricsResponse.StatusCode).To(Equal(http.StatusOK))
+				Expect(body).To(ContainSubstring("# TYPE odb_adapter_invocations_total counter"))
+				Expect(body).NotTo(ContainSubstring("odb_plan_service_instances"))
+			})
+		})
+	})
+
 	Describe("listing orphan service deployments", func() {
 		var listResp *http.Response
 
@@ -706,6 +1066,49 @@ var _ = Describe("Management API", func() {
 			})
 		})
 	})
+
+	Describe("reloading the configuration", func() {
+		var (
+			reloader     *fake_config_reloader.FakeConfigReloader
+			reloadServer *httptest.Server
+			reloadResp   *http.Response
+		)
+
+		BeforeEach(func() {
+			reloader = new(fake_config_reloader.FakeConfigReloader)
+		})
+
+		JustBeforeEach(func() {
+			router := mux.NewRouter()
+			mgmtapi.AttachConfigReloadRoute(router, reloader, loggerFactory)
+			reloadServer = httptest.NewServer(router)
+
+			var err error
+			reloadResp, err = http.Post(fmt.Sprintf("%s/mgmt/config/reload", reloadServer.URL), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			reloadServer.Close()
+		})
+
+		It("reloads the configuration and returns HTTP 204", func() {
+			Expect(reloadResp.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(reloader.ReloadCallCount()).To(Equal(1))
+		})
+
+		Context("when the configuration cannot be reloaded", func() {
+			BeforeEach(func() {
+				reloader.ReloadReturns(errors.New("error parsing config: broker.port can't be empty"))
+			})
+
+			It("returns HTTP 422 and the error", func() {
+				Expect(reloadResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(reloadResp.Body)).To(MatchJSON(`{"description": "error parsing config: broker.port can't be empty"}`))
+				Expect(logs).To(gbytes.Say("error occurred reloading the configuration: error parsing config: broker.port can't be empty"))
+			})
+		})
+	})
 })
 
 func Patch(url, body string) (resp *http.Response, err error) {
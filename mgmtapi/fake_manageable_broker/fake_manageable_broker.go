@@ -14,25 +14,25 @@ import (
 )
 
 type FakeManageableBroker struct {
-	InstancesStub        func(logger *log.Logger) ([]service.Instance, error)
-	instancesMutex       sync.RWMutex
-	instancesArgsForCall []struct {
-		logger *log.Logger
+	CountInstancesOfPlansStub        func(*log.Logger) (map[cf.ServicePlan]int, error)
+	countInstancesOfPlansMutex       sync.RWMutex
+	countInstancesOfPlansArgsForCall []struct {
+		arg1 *log.Logger
 	}
-	instancesReturns struct {
-		result1 []service.Instance
+	countInstancesOfPlansReturns struct {
+		result1 map[cf.ServicePlan]int
 		result2 error
 	}
-	instancesReturnsOnCall map[int]struct {
-		result1 []service.Instance
+	countInstancesOfPlansReturnsOnCall map[int]struct {
+		result1 map[cf.ServicePlan]int
 		result2 error
 	}
-	FilteredInstancesStub        func(orgName, spaceName string, logger *log.Logger) ([]service.Instance, error)
+	FilteredInstancesStub        func(string, string, *log.Logger) ([]service.Instance, error)
 	filteredInstancesMutex       sync.RWMutex
 	filteredInstancesArgsForCall []struct {
-		orgName   string
-		spaceName string
-		logger    *log.Logger
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
 	}
 	filteredInstancesReturns struct {
 		result1 []service.Instance
@@ -42,10 +42,37 @@ type FakeManageableBroker struct {
 		result1 []service.Instance
 		result2 error
 	}
-	OrphanDeploymentsStub        func(logger *log.Logger) ([]string, error)
+	InstanceOperationsStub        func(string, *log.Logger) ([]broker.InstanceOperation, error)
+	instanceOperationsMutex       sync.RWMutex
+	instanceOperationsArgsForCall []struct {
+		arg1 string
+		arg2 *log.Logger
+	}
+	instanceOperationsReturns struct {
+		result1 []broker.InstanceOperation
+		result2 error
+	}
+	instanceOperationsReturnsOnCall map[int]struct {
+		result1 []broker.InstanceOperation
+		result2 error
+	}
+	InstancesStub        func(*log.Logger) ([]service.Instance, error)
+	instancesMutex       sync.RWMutex
+	instancesArgsForCall []struct {
+		arg1 *log.Logger
+	}
+	instancesReturns struct {
+		result1 []service.Instance
+		result2 error
+	}
+	instancesReturnsOnCall map[int]struct {
+		result1 []service.Instance
+		result2 error
+	}
+	OrphanDeploymentsStub        func(*log.Logger) ([]string, error)
 	orphanDeploymentsMutex       sync.RWMutex
 	orphanDeploymentsArgsForCall []struct {
-		logger *log.Logger
+		arg1 *log.Logger
 	}
 	orphanDeploymentsReturns struct {
 		result1 []string
@@ -55,29 +82,29 @@ type FakeManageableBroker struct {
 		result1 []string
 		result2 error
 	}
-	UpgradeStub        func(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error)
-	upgradeMutex       sync.RWMutex
-	upgradeArgsForCall []struct {
-		ctx           context.Context
-		instanceID    string
-		updateDetails brokerapi.UpdateDetails
-		logger        *log.Logger
+	PreviewUpgradeStub        func(context.Context, string, brokerapi.UpdateDetails, *log.Logger) (broker.UpgradePreview, error)
+	previewUpgradeMutex       sync.RWMutex
+	previewUpgradeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 brokerapi.UpdateDetails
+		arg4 *log.Logger
 	}
-	upgradeReturns struct {
-		result1 broker.OperationData
+	previewUpgradeReturns struct {
+		result1 broker.UpgradePreview
 		result2 error
 	}
-	upgradeReturnsOnCall map[int]struct {
-		result1 broker.OperationData
+	previewUpgradeReturnsOnCall map[int]struct {
+		result1 broker.UpgradePreview
 		result2 error
 	}
-	RecreateStub        func(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error)
+	RecreateStub        func(context.Context, string, brokerapi.UpdateDetails, *log.Logger) (broker.OperationData, error)
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
-		ctx           context.Context
-		instanceID    string
-		updateDetails brokerapi.UpdateDetails
-		logger        *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 brokerapi.UpdateDetails
+		arg4 *log.Logger
 	}
 	recreateReturns struct {
 		result1 broker.OperationData
@@ -87,91 +114,109 @@ type FakeManageableBroker struct {
 		result1 broker.OperationData
 		result2 error
 	}
-	CountInstancesOfPlansStub        func(logger *log.Logger) (map[cf.ServicePlan]int, error)
-	countInstancesOfPlansMutex       sync.RWMutex
-	countInstancesOfPlansArgsForCall []struct {
-		logger *log.Logger
+	UpgradeStub        func(context.Context, string, brokerapi.UpdateDetails, *log.Logger) (broker.OperationData, error)
+	upgradeMutex       sync.RWMutex
+	upgradeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 brokerapi.UpdateDetails
+		arg4 *log.Logger
 	}
-	countInstancesOfPlansReturns struct {
-		result1 map[cf.ServicePlan]int
+	upgradeReturns struct {
+		result1 broker.OperationData
 		result2 error
 	}
-	countInstancesOfPlansReturnsOnCall map[int]struct {
-		result1 map[cf.ServicePlan]int
+	upgradeReturnsOnCall map[int]struct {
+		result1 broker.OperationData
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeManageableBroker) Instances(logger *log.Logger) ([]service.Instance, error) {
-	fake.instancesMutex.Lock()
-	ret, specificReturn := fake.instancesReturnsOnCall[len(fake.instancesArgsForCall)]
-	fake.instancesArgsForCall = append(fake.instancesArgsForCall, struct {
-		logger *log.Logger
-	}{logger})
-	fake.recordInvocation("Instances", []interface{}{logger})
-	fake.instancesMutex.Unlock()
-	if fake.InstancesStub != nil {
-		return fake.InstancesStub(logger)
+func (fake *FakeManageableBroker) CountInstancesOfPlans(arg1 *log.Logger) (map[cf.ServicePlan]int, error) {
+	fake.countInstancesOfPlansMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfPlansReturnsOnCall[len(fake.countInstancesOfPlansArgsForCall)]
+	fake.countInstancesOfPlansArgsForCall = append(fake.countInstancesOfPlansArgsForCall, struct {
+		arg1 *log.Logger
+	}{arg1})
+	stub := fake.CountInstancesOfPlansStub
+	fakeReturns := fake.countInstancesOfPlansReturns
+	fake.recordInvocation("CountInstancesOfPlans", []interface{}{arg1})
+	fake.countInstancesOfPlansMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.instancesReturns.result1, fake.instancesReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeManageableBroker) InstancesCallCount() int {
-	fake.instancesMutex.RLock()
-	defer fake.instancesMutex.RUnlock()
-	return len(fake.instancesArgsForCall)
+func (fake *FakeManageableBroker) CountInstancesOfPlansCallCount() int {
+	fake.countInstancesOfPlansMutex.RLock()
+	defer fake.countInstancesOfPlansMutex.RUnlock()
+	return len(fake.countInstancesOfPlansArgsForCall)
 }
 
-func (fake *FakeManageableBroker) InstancesArgsForCall(i int) *log.Logger {
-	fake.instancesMutex.RLock()
-	defer fake.instancesMutex.RUnlock()
-	return fake.instancesArgsForCall[i].logger
+func (fake *FakeManageableBroker) CountInstancesOfPlansCalls(stub func(*log.Logger) (map[cf.ServicePlan]int, error)) {
+	fake.countInstancesOfPlansMutex.Lock()
+	defer fake.countInstancesOfPlansMutex.Unlock()
+	fake.CountInstancesOfPlansStub = stub
 }
 
-func (fake *FakeManageableBroker) InstancesReturns(result1 []service.Instance, result2 error) {
-	fake.InstancesStub = nil
-	fake.instancesReturns = struct {
-		result1 []service.Instance
+func (fake *FakeManageableBroker) CountInstancesOfPlansArgsForCall(i int) *log.Logger {
+	fake.countInstancesOfPlansMutex.RLock()
+	defer fake.countInstancesOfPlansMutex.RUnlock()
+	argsForCall := fake.countInstancesOfPlansArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlansReturns(result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfPlansMutex.Lock()
+	defer fake.countInstancesOfPlansMutex.Unlock()
+	fake.CountInstancesOfPlansStub = nil
+	fake.countInstancesOfPlansReturns = struct {
+		result1 map[cf.ServicePlan]int
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) InstancesReturnsOnCall(i int, result1 []service.Instance, result2 error) {
-	fake.InstancesStub = nil
-	if fake.instancesReturnsOnCall == nil {
-		fake.instancesReturnsOnCall = make(map[int]struct {
-			result1 []service.Instance
+func (fake *FakeManageableBroker) CountInstancesOfPlansReturnsOnCall(i int, result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfPlansMutex.Lock()
+	defer fake.countInstancesOfPlansMutex.Unlock()
+	fake.CountInstancesOfPlansStub = nil
+	if fake.countInstancesOfPlansReturnsOnCall == nil {
+		fake.countInstancesOfPlansReturnsOnCall = make(map[int]struct {
+			result1 map[cf.ServicePlan]int
 			result2 error
 		})
 	}
-	fake.instancesReturnsOnCall[i] = struct {
-		result1 []service.Instance
+	fake.countInstancesOfPlansReturnsOnCall[i] = struct {
+		result1 map[cf.ServicePlan]int
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) FilteredInstances(orgName string, spaceName string, logger *log.Logger) ([]service.Instance, error) {
+func (fake *FakeManageableBroker) FilteredInstances(arg1 string, arg2 string, arg3 *log.Logger) ([]service.Instance, error) {
 	fake.filteredInstancesMutex.Lock()
 	ret, specificReturn := fake.filteredInstancesReturnsOnCall[len(fake.filteredInstancesArgsForCall)]
 	fake.filteredInstancesArgsForCall = append(fake.filteredInstancesArgsForCall, struct {
-		orgName   string
-		spaceName string
-		logger    *log.Logger
-	}{orgName, spaceName, logger})
-	fake.recordInvocation("FilteredInstances", []interface{}{orgName, spaceName, logger})
+		arg1 string
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.FilteredInstancesStub
+	fakeReturns := fake.filteredInstancesReturns
+	fake.recordInvocation("FilteredInstances", []interface{}{arg1, arg2, arg3})
 	fake.filteredInstancesMutex.Unlock()
-	if fake.FilteredInstancesStub != nil {
-		return fake.FilteredInstancesStub(orgName, spaceName, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.filteredInstancesReturns.result1, fake.filteredInstancesReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeManageableBroker) FilteredInstancesCallCount() int {
@@ -180,13 +225,22 @@ func (fake *FakeManageableBroker) FilteredInstancesCallCount() int {
 	return len(fake.filteredInstancesArgsForCall)
 }
 
+func (fake *FakeManageableBroker) FilteredInstancesCalls(stub func(string, string, *log.Logger) ([]service.Instance, error)) {
+	fake.filteredInstancesMutex.Lock()
+	defer fake.filteredInstancesMutex.Unlock()
+	fake.FilteredInstancesStub = stub
+}
+
 func (fake *FakeManageableBroker) FilteredInstancesArgsForCall(i int) (string, string, *log.Logger) {
 	fake.filteredInstancesMutex.RLock()
 	defer fake.filteredInstancesMutex.RUnlock()
-	return fake.filteredInstancesArgsForCall[i].orgName, fake.filteredInstancesArgsForCall[i].spaceName, fake.filteredInstancesArgsForCall[i].logger
+	argsForCall := fake.filteredInstancesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeManageableBroker) FilteredInstancesReturns(result1 []service.Instance, result2 error) {
+	fake.filteredInstancesMutex.Lock()
+	defer fake.filteredInstancesMutex.Unlock()
 	fake.FilteredInstancesStub = nil
 	fake.filteredInstancesReturns = struct {
 		result1 []service.Instance
@@ -195,6 +249,8 @@ func (fake *FakeManageableBroker) FilteredInstancesReturns(result1 []service.Ins
 }
 
 func (fake *FakeManageableBroker) FilteredInstancesReturnsOnCall(i int, result1 []service.Instance, result2 error) {
+	fake.filteredInstancesMutex.Lock()
+	defer fake.filteredInstancesMutex.Unlock()
 	fake.FilteredInstancesStub = nil
 	if fake.filteredInstancesReturnsOnCall == nil {
 		fake.filteredInstancesReturnsOnCall = make(map[int]struct {
@@ -208,21 +264,152 @@ func (fake *FakeManageableBroker) FilteredInstancesReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) OrphanDeployments(logger *log.Logger) ([]string, error) {
+func (fake *FakeManageableBroker) InstanceOperations(arg1 string, arg2 *log.Logger) ([]broker.InstanceOperation, error) {
+	fake.instanceOperationsMutex.Lock()
+	ret, specificReturn := fake.instanceOperationsReturnsOnCall[len(fake.instanceOperationsArgsForCall)]
+	fake.instanceOperationsArgsForCall = append(fake.instanceOperationsArgsForCall, struct {
+		arg1 string
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.InstanceOperationsStub
+	fakeReturns := fake.instanceOperationsReturns
+	fake.recordInvocation("InstanceOperations", []interface{}{arg1, arg2})
+	fake.instanceOperationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) InstanceOperationsCallCount() int {
+	fake.instanceOperationsMutex.RLock()
+	defer fake.instanceOperationsMutex.RUnlock()
+	return len(fake.instanceOperationsArgsForCall)
+}
+
+func (fake *FakeManageableBroker) InstanceOperationsCalls(stub func(string, *log.Logger) ([]broker.InstanceOperation, error)) {
+	fake.instanceOperationsMutex.Lock()
+	defer fake.instanceOperationsMutex.Unlock()
+	fake.InstanceOperationsStub = stub
+}
+
+func (fake *FakeManageableBroker) InstanceOperationsArgsForCall(i int) (string, *log.Logger) {
+	fake.instanceOperationsMutex.RLock()
+	defer fake.instanceOperationsMutex.RUnlock()
+	argsForCall := fake.instanceOperationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeManageableBroker) InstanceOperationsReturns(result1 []broker.InstanceOperation, result2 error) {
+	fake.instanceOperationsMutex.Lock()
+	defer fake.instanceOperationsMutex.Unlock()
+	fake.InstanceOperationsStub = nil
+	fake.instanceOperationsReturns = struct {
+		result1 []broker.InstanceOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceOperationsReturnsOnCall(i int, result1 []broker.InstanceOperation, result2 error) {
+	fake.instanceOperationsMutex.Lock()
+	defer fake.instanceOperationsMutex.Unlock()
+	fake.InstanceOperationsStub = nil
+	if fake.instanceOperationsReturnsOnCall == nil {
+		fake.instanceOperationsReturnsOnCall = make(map[int]struct {
+			result1 []broker.InstanceOperation
+			result2 error
+		})
+	}
+	fake.instanceOperationsReturnsOnCall[i] = struct {
+		result1 []broker.InstanceOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) Instances(arg1 *log.Logger) ([]service.Instance, error) {
+	fake.instancesMutex.Lock()
+	ret, specificReturn := fake.instancesReturnsOnCall[len(fake.instancesArgsForCall)]
+	fake.instancesArgsForCall = append(fake.instancesArgsForCall, struct {
+		arg1 *log.Logger
+	}{arg1})
+	stub := fake.InstancesStub
+	fakeReturns := fake.instancesReturns
+	fake.recordInvocation("Instances", []interface{}{arg1})
+	fake.instancesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) InstancesCallCount() int {
+	fake.instancesMutex.RLock()
+	defer fake.instancesMutex.RUnlock()
+	return len(fake.instancesArgsForCall)
+}
+
+func (fake *FakeManageableBroker) InstancesCalls(stub func(*log.Logger) ([]service.Instance, error)) {
+	fake.instancesMutex.Lock()
+	defer fake.instancesMutex.Unlock()
+	fake.InstancesStub = stub
+}
+
+func (fake *FakeManageableBroker) InstancesArgsForCall(i int) *log.Logger {
+	fake.instancesMutex.RLock()
+	defer fake.instancesMutex.RUnlock()
+	argsForCall := fake.instancesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeManageableBroker) InstancesReturns(result1 []service.Instance, result2 error) {
+	fake.instancesMutex.Lock()
+	defer fake.instancesMutex.Unlock()
+	fake.InstancesStub = nil
+	fake.instancesReturns = struct {
+		result1 []service.Instance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstancesReturnsOnCall(i int, result1 []service.Instance, result2 error) {
+	fake.instancesMutex.Lock()
+	defer fake.instancesMutex.Unlock()
+	fake.InstancesStub = nil
+	if fake.instancesReturnsOnCall == nil {
+		fake.instancesReturnsOnCall = make(map[int]struct {
+			result1 []service.Instance
+			result2 error
+		})
+	}
+	fake.instancesReturnsOnCall[i] = struct {
+		result1 []service.Instance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) OrphanDeployments(arg1 *log.Logger) ([]string, error) {
 	fake.orphanDeploymentsMutex.Lock()
 	ret, specificReturn := fake.orphanDeploymentsReturnsOnCall[len(fake.orphanDeploymentsArgsForCall)]
 	fake.orphanDeploymentsArgsForCall = append(fake.orphanDeploymentsArgsForCall, struct {
-		logger *log.Logger
-	}{logger})
-	fake.recordInvocation("OrphanDeployments", []interface{}{logger})
+		arg1 *log.Logger
+	}{arg1})
+	stub := fake.OrphanDeploymentsStub
+	fakeReturns := fake.orphanDeploymentsReturns
+	fake.recordInvocation("OrphanDeployments", []interface{}{arg1})
 	fake.orphanDeploymentsMutex.Unlock()
-	if fake.OrphanDeploymentsStub != nil {
-		return fake.OrphanDeploymentsStub(logger)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.orphanDeploymentsReturns.result1, fake.orphanDeploymentsReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeManageableBroker) OrphanDeploymentsCallCount() int {
@@ -231,13 +418,22 @@ func (fake *FakeManageableBroker) OrphanDeploymentsCallCount() int {
 	return len(fake.orphanDeploymentsArgsForCall)
 }
 
+func (fake *FakeManageableBroker) OrphanDeploymentsCalls(stub func(*log.Logger) ([]string, error)) {
+	fake.orphanDeploymentsMutex.Lock()
+	defer fake.orphanDeploymentsMutex.Unlock()
+	fake.OrphanDeploymentsStub = stub
+}
+
 func (fake *FakeManageableBroker) OrphanDeploymentsArgsForCall(i int) *log.Logger {
 	fake.orphanDeploymentsMutex.RLock()
 	defer fake.orphanDeploymentsMutex.RUnlock()
-	return fake.orphanDeploymentsArgsForCall[i].logger
+	argsForCall := fake.orphanDeploymentsArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *FakeManageableBroker) OrphanDeploymentsReturns(result1 []string, result2 error) {
+	fake.orphanDeploymentsMutex.Lock()
+	defer fake.orphanDeploymentsMutex.Unlock()
 	fake.OrphanDeploymentsStub = nil
 	fake.orphanDeploymentsReturns = struct {
 		result1 []string
@@ -246,6 +442,8 @@ func (fake *FakeManageableBroker) OrphanDeploymentsReturns(result1 []string, res
 }
 
 func (fake *FakeManageableBroker) OrphanDeploymentsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.orphanDeploymentsMutex.Lock()
+	defer fake.orphanDeploymentsMutex.Unlock()
 	fake.OrphanDeploymentsStub = nil
 	if fake.orphanDeploymentsReturnsOnCall == nil {
 		fake.orphanDeploymentsReturnsOnCall = make(map[int]struct {
@@ -259,78 +457,93 @@ func (fake *FakeManageableBroker) OrphanDeploymentsReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) Upgrade(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
-	fake.upgradeMutex.Lock()
-	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
-	fake.upgradeArgsForCall = append(fake.upgradeArgsForCall, struct {
-		ctx           context.Context
-		instanceID    string
-		updateDetails brokerapi.UpdateDetails
-		logger        *log.Logger
-	}{ctx, instanceID, updateDetails, logger})
-	fake.recordInvocation("Upgrade", []interface{}{ctx, instanceID, updateDetails, logger})
-	fake.upgradeMutex.Unlock()
-	if fake.UpgradeStub != nil {
-		return fake.UpgradeStub(ctx, instanceID, updateDetails, logger)
+func (fake *FakeManageableBroker) PreviewUpgrade(arg1 context.Context, arg2 string, arg3 brokerapi.UpdateDetails, arg4 *log.Logger) (broker.UpgradePreview, error) {
+	fake.previewUpgradeMutex.Lock()
+	ret, specificReturn := fake.previewUpgradeReturnsOnCall[len(fake.previewUpgradeArgsForCall)]
+	fake.previewUpgradeArgsForCall = append(fake.previewUpgradeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 brokerapi.UpdateDetails
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PreviewUpgradeStub
+	fakeReturns := fake.previewUpgradeReturns
+	fake.recordInvocation("PreviewUpgrade", []interface{}{arg1, arg2, arg3, arg4})
+	fake.previewUpgradeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.upgradeReturns.result1, fake.upgradeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeManageableBroker) UpgradeCallCount() int {
-	fake.upgradeMutex.RLock()
-	defer fake.upgradeMutex.RUnlock()
-	return len(fake.upgradeArgsForCall)
+func (fake *FakeManageableBroker) PreviewUpgradeCallCount() int {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	return len(fake.previewUpgradeArgsForCall)
 }
 
-func (fake *FakeManageableBroker) UpgradeArgsForCall(i int) (context.Context, string, brokerapi.UpdateDetails, *log.Logger) {
-	fake.upgradeMutex.RLock()
-	defer fake.upgradeMutex.RUnlock()
-	return fake.upgradeArgsForCall[i].ctx, fake.upgradeArgsForCall[i].instanceID, fake.upgradeArgsForCall[i].updateDetails, fake.upgradeArgsForCall[i].logger
+func (fake *FakeManageableBroker) PreviewUpgradeCalls(stub func(context.Context, string, brokerapi.UpdateDetails, *log.Logger) (broker.UpgradePreview, error)) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = stub
 }
 
-func (fake *FakeManageableBroker) UpgradeReturns(result1 broker.OperationData, result2 error) {
-	fake.UpgradeStub = nil
-	fake.upgradeReturns = struct {
-		result1 broker.OperationData
+func (fake *FakeManageableBroker) PreviewUpgradeArgsForCall(i int) (context.Context, string, brokerapi.UpdateDetails, *log.Logger) {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	argsForCall := fake.previewUpgradeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) PreviewUpgradeReturns(result1 broker.UpgradePreview, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	fake.previewUpgradeReturns = struct {
+		result1 broker.UpgradePreview
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) UpgradeReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
-	fake.UpgradeStub = nil
-	if fake.upgradeReturnsOnCall == nil {
-		fake.upgradeReturnsOnCall = make(map[int]struct {
-			result1 broker.OperationData
+func (fake *FakeManageableBroker) PreviewUpgradeReturnsOnCall(i int, result1 broker.UpgradePreview, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	if fake.previewUpgradeReturnsOnCall == nil {
+		fake.previewUpgradeReturnsOnCall = make(map[int]struct {
+			result1 broker.UpgradePreview
 			result2 error
 		})
 	}
-	fake.upgradeReturnsOnCall[i] = struct {
-		result1 broker.OperationData
+	fake.previewUpgradeReturnsOnCall[i] = struct {
+		result1 broker.UpgradePreview
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) Recreate(ctx context.Context, instanceID string, updateDetails brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+func (fake *FakeManageableBroker) Recreate(arg1 context.Context, arg2 string, arg3 brokerapi.UpdateDetails, arg4 *log.Logger) (broker.OperationData, error) {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
 	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-		ctx           context.Context
-		instanceID    string
-		updateDetails brokerapi.UpdateDetails
-		logger        *log.Logger
-	}{ctx, instanceID, updateDetails, logger})
-	fake.recordInvocation("Recreate", []interface{}{ctx, instanceID, updateDetails, logger})
+		arg1 context.Context
+		arg2 string
+		arg3 brokerapi.UpdateDetails
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecreateStub
+	fakeReturns := fake.recreateReturns
+	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
 	fake.recreateMutex.Unlock()
-	if fake.RecreateStub != nil {
-		return fake.RecreateStub(ctx, instanceID, updateDetails, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.recreateReturns.result1, fake.recreateReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeManageableBroker) RecreateCallCount() int {
@@ -339,13 +552,22 @@ func (fake *FakeManageableBroker) RecreateCallCount() int {
 	return len(fake.recreateArgsForCall)
 }
 
+func (fake *FakeManageableBroker) RecreateCalls(stub func(context.Context, string, brokerapi.UpdateDetails, *log.Logger) (broker.OperationData, error)) {
+	fake.recreateMutex.Lock()
+	defer fake.recreateMutex.Unlock()
+	fake.RecreateStub = stub
+}
+
 func (fake *FakeManageableBroker) RecreateArgsForCall(i int) (context.Context, string, brokerapi.UpdateDetails, *log.Logger) {
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
-	return fake.recreateArgsForCall[i].ctx, fake.recreateArgsForCall[i].instanceID, fake.recreateArgsForCall[i].updateDetails, fake.recreateArgsForCall[i].logger
+	argsForCall := fake.recreateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeManageableBroker) RecreateReturns(result1 broker.OperationData, result2 error) {
+	fake.recreateMutex.Lock()
+	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = nil
 	fake.recreateReturns = struct {
 		result1 broker.OperationData
@@ -354,6 +576,8 @@ func (fake *FakeManageableBroker) RecreateReturns(result1 broker.OperationData,
 }
 
 func (fake *FakeManageableBroker) RecreateReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.recreateMutex.Lock()
+	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = nil
 	if fake.recreateReturnsOnCall == nil {
 		fake.recreateReturnsOnCall = make(map[int]struct {
@@ -367,53 +591,69 @@ func (fake *FakeManageableBroker) RecreateReturnsOnCall(i int, result1 broker.Op
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlans(logger *log.Logger) (map[cf.ServicePlan]int, error) {
-	fake.countInstancesOfPlansMutex.Lock()
-	ret, specificReturn := fake.countInstancesOfPlansReturnsOnCall[len(fake.countInstancesOfPlansArgsForCall)]
-	fake.countInstancesOfPlansArgsForCall = append(fake.countInstancesOfPlansArgsForCall, struct {
-		logger *log.Logger
-	}{logger})
-	fake.recordInvocation("CountInstancesOfPlans", []interface{}{logger})
-	fake.countInstancesOfPlansMutex.Unlock()
-	if fake.CountInstancesOfPlansStub != nil {
-		return fake.CountInstancesOfPlansStub(logger)
+func (fake *FakeManageableBroker) Upgrade(arg1 context.Context, arg2 string, arg3 brokerapi.UpdateDetails, arg4 *log.Logger) (broker.OperationData, error) {
+	fake.upgradeMutex.Lock()
+	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
+	fake.upgradeArgsForCall = append(fake.upgradeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 brokerapi.UpdateDetails
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UpgradeStub
+	fakeReturns := fake.upgradeReturns
+	fake.recordInvocation("Upgrade", []interface{}{arg1, arg2, arg3, arg4})
+	fake.upgradeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.countInstancesOfPlansReturns.result1, fake.countInstancesOfPlansReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlansCallCount() int {
-	fake.countInstancesOfPlansMutex.RLock()
-	defer fake.countInstancesOfPlansMutex.RUnlock()
-	return len(fake.countInstancesOfPlansArgsForCall)
+func (fake *FakeManageableBroker) UpgradeCallCount() int {
+	fake.upgradeMutex.RLock()
+	defer fake.upgradeMutex.RUnlock()
+	return len(fake.upgradeArgsForCall)
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlansArgsForCall(i int) *log.Logger {
-	fake.countInstancesOfPlansMutex.RLock()
-	defer fake.countInstancesOfPlansMutex.RUnlock()
-	return fake.countInstancesOfPlansArgsForCall[i].logger
+func (fake *FakeManageableBroker) UpgradeCalls(stub func(context.Context, string, brokerapi.UpdateDetails, *log.Logger) (broker.OperationData, error)) {
+	fake.upgradeMutex.Lock()
+	defer fake.upgradeMutex.Unlock()
+	fake.UpgradeStub = stub
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlansReturns(result1 map[cf.ServicePlan]int, result2 error) {
-	fake.CountInstancesOfPlansStub = nil
-	fake.countInstancesOfPlansReturns = struct {
-		result1 map[cf.ServicePlan]int
+func (fake *FakeManageableBroker) UpgradeArgsForCall(i int) (context.Context, string, brokerapi.UpdateDetails, *log.Logger) {
+	fake.upgradeMutex.RLock()
+	defer fake.upgradeMutex.RUnlock()
+	argsForCall := fake.upgradeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) UpgradeReturns(result1 broker.OperationData, result2 error) {
+	fake.upgradeMutex.Lock()
+	defer fake.upgradeMutex.Unlock()
+	fake.UpgradeStub = nil
+	fake.upgradeReturns = struct {
+		result1 broker.OperationData
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlansReturnsOnCall(i int, result1 map[cf.ServicePlan]int, result2 error) {
-	fake.CountInstancesOfPlansStub = nil
-	if fake.countInstancesOfPlansReturnsOnCall == nil {
-		fake.countInstancesOfPlansReturnsOnCall = make(map[int]struct {
-			result1 map[cf.ServicePlan]int
+func (fake *FakeManageableBroker) UpgradeReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.upgradeMutex.Lock()
+	defer fake.upgradeMutex.Unlock()
+	fake.UpgradeStub = nil
+	if fake.upgradeReturnsOnCall == nil {
+		fake.upgradeReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
 			result2 error
 		})
 	}
-	fake.countInstancesOfPlansReturnsOnCall[i] = struct {
-		result1 map[cf.ServicePlan]int
+	fake.upgradeReturnsOnCall[i] = struct {
+		result1 broker.OperationData
 		result2 error
 	}{result1, result2}
 }
@@ -421,18 +661,22 @@ func (fake *FakeManageableBroker) CountInstancesOfPlansReturnsOnCall(i int, resu
 func (fake *FakeManageableBroker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.instancesMutex.RLock()
-	defer fake.instancesMutex.RUnlock()
+	fake.countInstancesOfPlansMutex.RLock()
+	defer fake.countInstancesOfPlansMutex.RUnlock()
 	fake.filteredInstancesMutex.RLock()
 	defer fake.filteredInstancesMutex.RUnlock()
+	fake.instanceOperationsMutex.RLock()
+	defer fake.instanceOperationsMutex.RUnlock()
+	fake.instancesMutex.RLock()
+	defer fake.instancesMutex.RUnlock()
 	fake.orphanDeploymentsMutex.RLock()
 	defer fake.orphanDeploymentsMutex.RUnlock()
-	fake.upgradeMutex.RLock()
-	defer fake.upgradeMutex.RUnlock()
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
-	fake.countInstancesOfPlansMutex.RLock()
-	defer fake.countInstancesOfPlansMutex.RUnlock()
+	fake.upgradeMutex.RLock()
+	defer fake.upgradeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
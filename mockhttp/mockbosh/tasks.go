@@ -28,6 +28,12 @@ func TasksWithLimit(deploymentName string, limit int) *tasksMock {
 		Handler: mockhttp.NewMockedHttpRequest("GET", fmt.Sprintf("/tasks?deployment=%s&limit=%d&verbose=1", deploymentName, limit)),
 	}
 }
+func CurrentTasks() *tasksMock {
+	return &tasksMock{
+		Handler: mockhttp.NewMockedHttpRequest("GET", "/tasks?state=processing%2Ccancelling%2Cqueued&verbose=2"),
+	}
+}
+
 func (t *tasksMock) RespondsWithNoTasks() *mockhttp.Handler {
 	return t.RespondsOKWithJSON([]boshdirector.BoshTask{})
 }
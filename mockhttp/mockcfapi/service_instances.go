@@ -84,6 +84,16 @@ func ListServiceInstancesBySpace(servicePlanGUID, spaceGUID string) *listService
 	}
 }
 
+func ListServiceInstancesByOrg(servicePlanGUID, orgGUID string) *listServiceInstancesMock {
+	return &listServiceInstancesMock{
+
+		mockhttp.NewMockedHttpRequest(
+			"GET",
+			"/v2/service_plans/"+servicePlanGUID+"/service_instances?results-per-page=100&q=organization_guid:"+orgGUID,
+		),
+	}
+}
+
 func ListServiceInstancesForPage(servicePlanGUID string, page int) *listServiceInstancesMock {
 	return &listServiceInstancesMock{
 		mockhttp.NewMockedHttpRequest(
@@ -29,6 +29,14 @@ func (Client) CountInstancesOfServiceOffering(serviceOfferingID string, logger *
 	return make(map[cf.ServicePlan]int), nil
 }
 
+func (Client) CountInstancesOfServiceOfferingInOrg(serviceOfferingID, orgGUID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error) {
+	return make(map[cf.ServicePlan]int), nil
+}
+
+func (Client) CountInstancesOfServiceOfferingInSpace(serviceOfferingID, spaceGUID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error) {
+	return make(map[cf.ServicePlan]int), nil
+}
+
 func (Client) GetInstanceState(serviceInstanceGUID string, logger *log.Logger) (cf.InstanceState, error) {
 	return cf.InstanceState{}, nil
 }
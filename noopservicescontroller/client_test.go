@@ -77,6 +77,24 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("CountInstancesOfServiceOfferingInOrg", func() {
+		It("returns empty map", func() {
+			client := noopservicescontroller.New()
+			instanceCountByPlanID, err := client.CountInstancesOfServiceOfferingInOrg("offeringId", "orgGUID", testLogger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instanceCountByPlanID).To(BeEmpty())
+		})
+	})
+
+	Describe("CountInstancesOfServiceOfferingInSpace", func() {
+		It("returns empty map", func() {
+			client := noopservicescontroller.New()
+			instanceCountByPlanID, err := client.CountInstancesOfServiceOfferingInSpace("offeringId", "spaceGUID", testLogger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instanceCountByPlanID).To(BeEmpty())
+		})
+	})
+
 	Describe("GetInstanceState", func() {
 		It("return default state", func() {
 			client := noopservicescontroller.New()
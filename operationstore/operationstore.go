@@ -0,0 +1,504 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package operationstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// The states of an operation, as reported to the platform.
+const (
+	inProgress = "in progress"
+	succeeded  = "succeeded"
+)
+
+// Operation is the journal entry for a single broker-initiated operation on a
+// service instance, from the request that started it to its final state.
+type Operation struct {
+	RequestID     string    `json:"request_id,omitempty"`
+	InstanceID    string    `json:"instance_id"`
+	Type          string    `json:"type"`
+	PlanID        string    `json:"plan_id,omitempty"`
+	BoshTaskIDs   []int     `json:"bosh_task_ids"`
+	BoshContextID string    `json:"bosh_context_id,omitempty"`
+	Errands       []string  `json:"errands,omitempty"`
+	State         string    `json:"state"`
+	StartedAt     time.Time `json:"started_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Rollback      *Rollback `json:"rollback,omitempty"`
+}
+
+// Rollback marks an upgrade that can be rolled back, as a Snapshot of the
+// deployment was saved before it, and then records the BOSH task that rolled
+// it back. The snapshot itself is never written to the journal.
+type Rollback struct {
+	BoshTaskID int `json:"bosh_task_id,omitempty"`
+}
+
+// Snapshot is the manifest and BOSH configs that were deployed before an
+// upgrade, as the director returns them, so with their variables unresolved.
+type Snapshot struct {
+	Manifest string            `json:"manifest"`
+	Configs  map[string]string `json:"configs,omitempty"`
+}
+
+// DefaultMaxOperations is how many operations a FileStore keeps when no
+// limit is given.
+const DefaultMaxOperations = 10000
+
+// AbandonedAfter is how long an operation can stay in progress without being
+// updated before it is treated as abandoned, for instance because the platform
+// gave up polling it. Abandoned operations can be dropped like finished ones.
+const AbandonedAfter = 7 * 24 * time.Hour
+
+// FileStore keeps the journal on local disk as an append-only log of JSON
+// lines, each of them the latest version of one operation. The log is read
+// once and kept in memory, so an update that changes nothing does not touch
+// the disk. Once the log has twice as many lines as the store may keep
+// operations, it is rewritten with only the latest version of each
+// operation, and the oldest finished or abandoned operations are dropped. A
+// lock file stops two brokers from using the same store.
+//
+// Rollback snapshots are kept apart from the log, in one file per instance in
+// a directory next to it, and are removed once they are no longer needed:
+// when the upgrade succeeds, when it has been rolled back, or when another
+// operation is recorded for the instance.
+type FileStore struct {
+	path          string
+	maxOperations int
+
+	lock       sync.Mutex
+	lockFile   *os.File
+	operations []storedOperation
+	nextID     int
+	logLines   int
+}
+
+type storedOperation struct {
+	ID        int       `json:"id"`
+	Operation Operation `json:"operation"`
+}
+
+// NewFileStore returns a store that keeps at most maxOperations operations,
+// or DefaultMaxOperations if maxOperations is zero.
+func NewFileStore(path string, maxOperations int) *FileStore {
+	if maxOperations <= 0 {
+		maxOperations = DefaultMaxOperations
+	}
+	return &FileStore{path: path, maxOperations: maxOperations}
+}
+
+func (s *FileStore) Record(operation Operation) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	if operation.Rollback == nil {
+		if err := s.removeSnapshot(operation.InstanceID); err != nil {
+			return err
+		}
+	}
+
+	stored := storedOperation{ID: s.nextID, Operation: operation}
+	if err := s.append(stored); err != nil {
+		return err
+	}
+	s.nextID++
+	s.operations = append(s.operations, stored)
+	return s.compactIfNeeded()
+}
+
+// UpdateState records the latest BOSH task and state of the operation that
+// was started on instanceID by the BOSH task boshTaskID, and reports whether
+// that changed the operation. Operations that were started before the journal
+// existed are ignored. A succeeded operation will never be rolled back, so its
+// rollback snapshot is removed.
+func (s *FileStore) UpdateState(instanceID string, boshTaskID, latestBoshTaskID int, state string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.open(); err != nil {
+		return false, err
+	}
+
+	stored := s.findOperation(instanceID, boshTaskID)
+	if stored == nil {
+		return false, nil
+	}
+
+	operation := stored.Operation
+	if operation.State == state && operation.BoshTaskIDs[len(operation.BoshTaskIDs)-1] == latestBoshTaskID {
+		return false, nil
+	}
+
+	if !containsTaskID(operation.BoshTaskIDs, latestBoshTaskID) {
+		operation.BoshTaskIDs = append(append([]int{}, operation.BoshTaskIDs...), latestBoshTaskID)
+	}
+	operation.State = state
+	if state == succeeded && operation.Rollback != nil {
+		operation.Rollback = nil
+		if err := s.removeSnapshot(instanceID); err != nil {
+			return false, err
+		}
+	}
+	operation.UpdatedAt = time.Now()
+	if err := s.update(stored, operation); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordRollbackTask records the BOSH task that rolls back the operation that
+// was started on instanceID by the BOSH task boshTaskID. The snapshot it
+// redeploys is no longer needed, so it is removed.
+func (s *FileStore) RecordRollbackTask(instanceID string, boshTaskID, rollbackTaskID int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	stored := s.findOperation(instanceID, boshTaskID)
+	if stored == nil {
+		return fmt.Errorf("no operation started by bosh task %d found for instance %s", boshTaskID, instanceID)
+	}
+
+	operation := stored.Operation
+	operation.BoshTaskIDs = append(append([]int{}, operation.BoshTaskIDs...), rollbackTaskID)
+	operation.Rollback = &Rollback{BoshTaskID: rollbackTaskID}
+	operation.State = inProgress
+	operation.UpdatedAt = time.Now()
+	if err := s.update(stored, operation); err != nil {
+		return err
+	}
+	return s.removeSnapshot(instanceID)
+}
+
+// SaveSnapshot keeps the snapshot to roll the instance back to, replacing any
+// previous one. It must be saved before the operation it belongs to is
+// recorded.
+func (s *FileStore) SaveSnapshot(instanceID string, snapshot Snapshot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	contents, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.snapshotDir(), 0700); err != nil {
+		return fmt.Errorf("error writing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	tmpFile, err := ioutil.TempFile(s.snapshotDir(), "snapshot")
+	if err != nil {
+		return fmt.Errorf("error writing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("error writing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("error writing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	if err := os.Rename(tmpFile.Name(), s.snapshotPath(instanceID)); err != nil {
+		return fmt.Errorf("error writing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	return nil
+}
+
+// Snapshot returns the snapshot to roll the instance back to, or nil if there
+// is none.
+func (s *FileStore) Snapshot(instanceID string) (*Snapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	contents, err := ioutil.ReadFile(s.snapshotPath(instanceID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollback snapshot for instance %s: %s", instanceID, err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(contents, &snapshot); err != nil {
+		return nil, fmt.Errorf("error parsing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	return &snapshot, nil
+}
+
+func (s *FileStore) ForInstance(instanceID string) ([]Operation, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	var instanceOperations []Operation
+	for _, stored := range s.operations {
+		if stored.Operation.InstanceID == instanceID {
+			instanceOperations = append(instanceOperations, stored.Operation)
+		}
+	}
+	return instanceOperations, nil
+}
+
+// Close releases the store, so that another FileStore can use its file.
+func (s *FileStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.lockFile == nil {
+		return nil
+	}
+	err := s.lockFile.Close()
+	s.lockFile = nil
+	s.operations = nil
+	return err
+}
+
+func (s *FileStore) removeSnapshot(instanceID string) error {
+	err := os.Remove(s.snapshotPath(instanceID))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing rollback snapshot for instance %s: %s", instanceID, err)
+	}
+	return nil
+}
+
+func (s *FileStore) snapshotDir() string {
+	return s.path + ".snapshots"
+}
+
+func (s *FileStore) snapshotPath(instanceID string) string {
+	return filepath.Join(s.snapshotDir(), url.PathEscape(instanceID)+".json")
+}
+
+// open locks the store and reads the log, the first time it is used.
+func (s *FileStore) open() error {
+	if s.lockFile != nil {
+		return nil
+	}
+
+	lockFile, err := os.OpenFile(s.path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening operation store %s: %s", s.path, err)
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lockFile.Close()
+		return fmt.Errorf("error opening operation store %s, it may be in use by another broker: %s", s.path, err)
+	}
+
+	if err := s.load(); err != nil {
+		lockFile.Close()
+		return err
+	}
+	s.lockFile = lockFile
+	return nil
+}
+
+// load reads the log into memory. A crash while appending to the log can
+// leave its last line incomplete; such a line is ignored, and the log is
+// rewritten without it.
+func (s *FileStore) load() error {
+	s.operations = nil
+	s.nextID = 0
+	s.logLines = 0
+
+	contents, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading operation store %s: %s", s.path, err)
+	}
+
+	indexes := map[int]int{}
+	lines := bytes.Split(contents, []byte("\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		var stored storedOperation
+		if err := json.Unmarshal(line, &stored); err != nil {
+			if i == len(lines)-1 {
+				return s.compact()
+			}
+			return fmt.Errorf("error parsing operation store %s: %s", s.path, err)
+		}
+
+		s.logLines++
+		if index, found := indexes[stored.ID]; found {
+			s.operations[index] = stored
+		} else {
+			indexes[stored.ID] = len(s.operations)
+			s.operations = append(s.operations, stored)
+		}
+		if stored.ID >= s.nextID {
+			s.nextID = stored.ID + 1
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) update(stored *storedOperation, operation Operation) error {
+	updated := storedOperation{ID: stored.ID, Operation: operation}
+	if err := s.append(updated); err != nil {
+		return err
+	}
+	*stored = updated
+	return s.compactIfNeeded()
+}
+
+func (s *FileStore) append(stored storedOperation) error {
+	line, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+
+	s.logLines++
+	return nil
+}
+
+func (s *FileStore) compactIfNeeded() error {
+	if s.logLines <= 2*s.maxOperations {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact rewrites the log with the latest version of each operation it
+// keeps. The oldest operations that are no longer in progress, or that were
+// abandoned, are dropped until no more than maxOperations are left.
+func (s *FileStore) compact() error {
+	excess := len(s.operations) - s.maxOperations
+	abandonedBefore := time.Now().Add(-AbandonedAfter)
+	var kept []storedOperation
+	for _, stored := range s.operations {
+		if excess > 0 && (stored.Operation.State != inProgress || stored.Operation.UpdatedAt.Before(abandonedBefore)) {
+			excess--
+			continue
+		}
+		kept = append(kept, stored)
+	}
+
+	var contents []byte
+	for _, stored := range kept {
+		line, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		contents = append(append(contents, line...), '\n')
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path))
+	if err != nil {
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), s.path); err != nil {
+		return fmt.Errorf("error writing operation store %s: %s", s.path, err)
+	}
+
+	s.operations = kept
+	s.logLines = len(kept)
+	return nil
+}
+
+// findOperation returns the latest operation started on instanceID by the
+// BOSH task boshTaskID, or nil if there is none.
+func (s *FileStore) findOperation(instanceID string, boshTaskID int) *storedOperation {
+	for i := len(s.operations) - 1; i >= 0; i-- {
+		operation := s.operations[i].Operation
+		if operation.InstanceID == instanceID && len(operation.BoshTaskIDs) > 0 && operation.BoshTaskIDs[0] == boshTaskID {
+			return &s.operations[i]
+		}
+	}
+	return nil
+}
+
+func containsTaskID(taskIDs []int, taskID int) bool {
+	for _, id := range taskIDs {
+		if id == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// NoopStore discards every operation. It is used when no operation store is
+// configured.
+type NoopStore struct{}
+
+func (NoopStore) Record(Operation) error {
+	return nil
+}
+
+func (NoopStore) UpdateState(string, int, int, string) (bool, error) {
+	return false, nil
+}
+
+func (NoopStore) RecordRollbackTask(string, int, int) error {
+	return nil
+}
+
+func (NoopStore) ForInstance(string) ([]Operation, error) {
+	return nil, nil
+}
+
+func (NoopStore) SaveSnapshot(string, Snapshot) error {
+	return nil
+}
+
+func (NoopStore) Snapshot(string) (*Snapshot, error) {
+	return nil, nil
+}
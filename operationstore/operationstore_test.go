@@ -0,0 +1,377 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package operationstore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/operationstore"
+)
+
+var _ = Describe("FileStore", func() {
+	var (
+		storeDir  string
+		storePath string
+		store     *operationstore.FileStore
+		startedAt time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		storeDir, err = ioutil.TempDir("", "operation-store")
+		Expect(err).NotTo(HaveOccurred())
+		storePath = filepath.Join(storeDir, "operations.json")
+		store = operationstore.NewFileStore(storePath, 0)
+		startedAt = time.Date(2019, 3, 12, 10, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		Expect(store.Close()).To(Succeed())
+		Expect(os.RemoveAll(storeDir)).To(Succeed())
+	})
+
+	operation := func(instanceID string, taskID int) operationstore.Operation {
+		return operationstore.Operation{
+			RequestID:     "some-request-id",
+			InstanceID:    instanceID,
+			Type:          "create",
+			PlanID:        "some-plan",
+			BoshTaskIDs:   []int{taskID},
+			BoshContextID: "some-context-id",
+			Errands:       []string{"health-check"},
+			State:         "in progress",
+			StartedAt:     startedAt,
+			UpdatedAt:     startedAt,
+		}
+	}
+
+	It("returns no operations when nothing has been recorded", func() {
+		operations, err := store.ForInstance("some-instance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations).To(BeEmpty())
+	})
+
+	It("returns the recorded operations for an instance in the order they were recorded", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+		Expect(store.Record(operation("another-instance", 2))).To(Succeed())
+		Expect(store.Record(operation("some-instance", 3))).To(Succeed())
+
+		operations, err := store.ForInstance("some-instance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations).To(Equal([]operationstore.Operation{operation("some-instance", 1), operation("some-instance", 3)}))
+	})
+
+	It("persists operations across store instances", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+		Expect(store.UpdateState("some-instance", 1, 2, "succeeded")).To(BeTrue())
+		Expect(store.Record(operation("some-instance", 3))).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+
+		store = operationstore.NewFileStore(storePath, 0)
+		operations, err := store.ForInstance("some-instance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations).To(HaveLen(2))
+		Expect(operations[0].BoshTaskIDs).To(Equal([]int{1, 2}))
+		Expect(operations[0].State).To(Equal("succeeded"))
+		Expect(operations[1]).To(Equal(operation("some-instance", 3)))
+	})
+
+	It("appends each change to the store file", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+		Expect(store.UpdateState("some-instance", 1, 2, "succeeded")).To(BeTrue())
+
+		contents, err := ioutil.ReadFile(storePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(contents), "\n")).To(Equal(2))
+	})
+
+	It("does not write to the store file when an update changes nothing", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+		Expect(os.Remove(storePath)).To(Succeed())
+
+		Expect(store.UpdateState("some-instance", 1, 1, "in progress")).To(BeFalse())
+		Expect(store.UpdateState("some-instance", 42, 43, "failed")).To(BeFalse())
+		Expect(storePath).NotTo(BeAnExistingFile())
+	})
+
+	It("ignores an incomplete last line left by an interrupted write", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+
+		file, err := os.OpenFile(storePath, os.O_WRONLY|os.O_APPEND, 0600)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = file.WriteString(`{"id":1,"operation":{"instance_id":"some-ins`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+
+		store = operationstore.NewFileStore(storePath, 0)
+		Expect(store.Record(operation("some-instance", 3))).To(Succeed())
+
+		operations, err := store.ForInstance("some-instance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations).To(Equal([]operationstore.Operation{operation("some-instance", 1), operation("some-instance", 3)}))
+	})
+
+	It("refuses to share the store file with another store", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+
+		_, err := operationstore.NewFileStore(storePath, 0).ForInstance("some-instance")
+		Expect(err).To(MatchError(ContainSubstring("it may be in use by another broker")))
+	})
+
+	Describe("retention", func() {
+		BeforeEach(func() {
+			store = operationstore.NewFileStore(storePath, 2)
+		})
+
+		It("keeps only the latest operations once the store file is compacted", func() {
+			for taskID := 1; taskID <= 5; taskID++ {
+				finished := operation("some-instance", taskID)
+				finished.State = "succeeded"
+				Expect(store.Record(finished)).To(Succeed())
+			}
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations).To(HaveLen(2))
+			Expect(operations[0].BoshTaskIDs).To(Equal([]int{4}))
+			Expect(operations[1].BoshTaskIDs).To(Equal([]int{5}))
+
+			contents, err := ioutil.ReadFile(storePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Count(string(contents), "\n")).To(Equal(2))
+		})
+
+		It("keeps operations that are still in progress", func() {
+			running := operation("some-instance", 1)
+			running.UpdatedAt = time.Now().Add(-time.Hour).UTC()
+			Expect(store.Record(running)).To(Succeed())
+			for taskID := 2; taskID <= 5; taskID++ {
+				finished := operation("some-instance", taskID)
+				finished.State = "succeeded"
+				Expect(store.Record(finished)).To(Succeed())
+			}
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations).To(HaveLen(2))
+			Expect(operations[0]).To(Equal(running))
+			Expect(operations[1].BoshTaskIDs).To(Equal([]int{5}))
+		})
+
+		It("drops operations that have been in progress without updates for too long", func() {
+			abandoned := operation("some-instance", 1)
+			abandoned.UpdatedAt = time.Now().Add(-operationstore.AbandonedAfter - time.Hour)
+			Expect(store.Record(abandoned)).To(Succeed())
+			for taskID := 2; taskID <= 5; taskID++ {
+				running := operation("some-instance", taskID)
+				running.UpdatedAt = time.Now()
+				Expect(store.Record(running)).To(Succeed())
+			}
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations).To(HaveLen(4))
+			Expect(operations[0].BoshTaskIDs).To(Equal([]int{2}))
+		})
+	})
+
+	Describe("UpdateState", func() {
+		BeforeEach(func() {
+			Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+			Expect(store.Record(operation("some-instance", 5))).To(Succeed())
+		})
+
+		It("records the state and the latest task of the operation started by the given task", func() {
+			Expect(store.UpdateState("some-instance", 1, 2, "succeeded")).To(BeTrue())
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations[0].State).To(Equal("succeeded"))
+			Expect(operations[0].BoshTaskIDs).To(Equal([]int{1, 2}))
+			Expect(operations[0].UpdatedAt).To(BeTemporally(">", startedAt))
+			Expect(operations[1]).To(Equal(operation("some-instance", 5)))
+		})
+
+		It("does not record the same task twice", func() {
+			Expect(store.UpdateState("some-instance", 1, 1, "in progress")).To(BeFalse())
+			Expect(store.UpdateState("some-instance", 1, 1, "succeeded")).To(BeTrue())
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations[0].BoshTaskIDs).To(Equal([]int{1}))
+			Expect(operations[0].State).To(Equal("succeeded"))
+		})
+
+		It("ignores operations that were not recorded", func() {
+			Expect(store.UpdateState("some-instance", 42, 43, "failed")).To(BeFalse())
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations).To(Equal([]operationstore.Operation{operation("some-instance", 1), operation("some-instance", 5)}))
+		})
+
+		It("drops the rollback and its snapshot once the operation succeeds", func() {
+			Expect(store.SaveSnapshot("another-instance", operationstore.Snapshot{Manifest: "name: old-manifest"})).To(Succeed())
+			upgrade := operation("another-instance", 7)
+			upgrade.Rollback = &operationstore.Rollback{}
+			Expect(store.Record(upgrade)).To(Succeed())
+
+			Expect(store.UpdateState("another-instance", 7, 7, "in progress")).To(BeFalse())
+			operations, err := store.ForInstance("another-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations[0].Rollback).To(Equal(&operationstore.Rollback{}))
+			Expect(store.Snapshot("another-instance")).NotTo(BeNil())
+
+			Expect(store.UpdateState("another-instance", 7, 8, "succeeded")).To(BeTrue())
+			operations, err = store.ForInstance("another-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations[0].Rollback).To(BeNil())
+			Expect(store.Snapshot("another-instance")).To(BeNil())
+		})
+
+		It("keeps the snapshot when the operation fails, so that it can be rolled back", func() {
+			Expect(store.SaveSnapshot("another-instance", operationstore.Snapshot{Manifest: "name: old-manifest"})).To(Succeed())
+			upgrade := operation("another-instance", 7)
+			upgrade.Rollback = &operationstore.Rollback{}
+			Expect(store.Record(upgrade)).To(Succeed())
+
+			Expect(store.UpdateState("another-instance", 7, 7, "failed")).To(BeTrue())
+			Expect(store.Snapshot("another-instance")).To(Equal(&operationstore.Snapshot{Manifest: "name: old-manifest"}))
+		})
+	})
+
+	Describe("RecordRollbackTask", func() {
+		BeforeEach(func() {
+			upgrade := operation("some-instance", 1)
+			upgrade.State = "failed"
+			upgrade.Rollback = &operationstore.Rollback{}
+			Expect(store.SaveSnapshot("some-instance", operationstore.Snapshot{Manifest: "name: old-manifest"})).To(Succeed())
+			Expect(store.Record(upgrade)).To(Succeed())
+		})
+
+		It("records the rollback task and removes the snapshot it redeployed", func() {
+			Expect(store.RecordRollbackTask("some-instance", 1, 3)).To(Succeed())
+
+			operations, err := store.ForInstance("some-instance")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations[0].BoshTaskIDs).To(Equal([]int{1, 3}))
+			Expect(operations[0].State).To(Equal("in progress"))
+			Expect(operations[0].Rollback).To(Equal(&operationstore.Rollback{BoshTaskID: 3}))
+			Expect(store.Snapshot("some-instance")).To(BeNil())
+		})
+
+		It("returns an error when the operation was not recorded", func() {
+			err := store.RecordRollbackTask("some-instance", 42, 43)
+			Expect(err).To(MatchError("no operation started by bosh task 42 found for instance some-instance"))
+		})
+	})
+
+	Describe("rollback snapshots", func() {
+		snapshot := operationstore.Snapshot{
+			Manifest: "name: old-manifest\npassword: ((password))",
+			Configs:  map[string]string{"cloud": "old-cloud-config"},
+		}
+
+		It("returns the saved snapshot of an instance", func() {
+			Expect(store.SaveSnapshot("some-instance", snapshot)).To(Succeed())
+
+			Expect(store.Snapshot("some-instance")).To(Equal(&snapshot))
+			Expect(store.Snapshot("another-instance")).To(BeNil())
+		})
+
+		It("keeps snapshots out of the store file, readable only by the broker", func() {
+			Expect(store.SaveSnapshot("some-instance", snapshot)).To(Succeed())
+			upgrade := operation("some-instance", 1)
+			upgrade.Rollback = &operationstore.Rollback{}
+			Expect(store.Record(upgrade)).To(Succeed())
+
+			contents, err := ioutil.ReadFile(storePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).NotTo(ContainSubstring("old-manifest"))
+			Expect(string(contents)).NotTo(ContainSubstring("old-cloud-config"))
+
+			snapshotFiles, err := filepath.Glob(filepath.Join(storePath+".snapshots", "*"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshotFiles).To(HaveLen(1))
+			info, err := os.Stat(snapshotFiles[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("keeps snapshots across store instances", func() {
+			Expect(store.SaveSnapshot("some-instance", snapshot)).To(Succeed())
+			Expect(store.Close()).To(Succeed())
+
+			store = operationstore.NewFileStore(storePath, 0)
+			Expect(store.Snapshot("some-instance")).To(Equal(&snapshot))
+		})
+
+		It("removes the snapshot of an instance when another operation is recorded for it", func() {
+			Expect(store.SaveSnapshot("some-instance", snapshot)).To(Succeed())
+			Expect(store.SaveSnapshot("another-instance", snapshot)).To(Succeed())
+
+			Expect(store.Record(operation("some-instance", 2))).To(Succeed())
+
+			Expect(store.Snapshot("some-instance")).To(BeNil())
+			Expect(store.Snapshot("another-instance")).To(Equal(&snapshot))
+		})
+
+		It("returns an error when a snapshot is corrupt", func() {
+			Expect(store.SaveSnapshot("some-instance", snapshot)).To(Succeed())
+			snapshotFiles, err := filepath.Glob(filepath.Join(storePath+".snapshots", "*"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(snapshotFiles[0], []byte("not json"), 0600)).To(Succeed())
+
+			_, err = store.Snapshot("some-instance")
+			Expect(err).To(MatchError(ContainSubstring("error parsing rollback snapshot for instance some-instance")))
+		})
+
+		It("returns an error when a snapshot cannot be written", func() {
+			Expect(ioutil.WriteFile(storePath+".snapshots", nil, 0600)).To(Succeed())
+
+			err := store.SaveSnapshot("some-instance", snapshot)
+			Expect(err).To(MatchError(ContainSubstring("error writing rollback snapshot for instance some-instance")))
+		})
+	})
+
+	It("returns an error when the store file is corrupt", func() {
+		Expect(ioutil.WriteFile(storePath, []byte("not json\n"), 0644)).To(Succeed())
+
+		_, err := store.ForInstance("some-instance")
+		Expect(err).To(MatchError(ContainSubstring("error parsing operation store " + storePath)))
+	})
+
+	It("returns an error when the store cannot be opened", func() {
+		store = operationstore.NewFileStore(filepath.Join(storeDir, "missing-dir", "operations.json"), 0)
+
+		err := store.Record(operation("some-instance", 1))
+		Expect(err).To(MatchError(ContainSubstring("error opening operation store")))
+	})
+
+	It("returns an error when the store cannot be read", func() {
+		Expect(os.Mkdir(storePath, 0700)).To(Succeed())
+
+		err := store.Record(operation("some-instance", 1))
+		Expect(err).To(MatchError(ContainSubstring("error reading operation store " + storePath)))
+	})
+
+	It("returns an error when the store cannot be written", func() {
+		Expect(store.Record(operation("some-instance", 1))).To(Succeed())
+		Expect(os.Remove(storePath)).To(Succeed())
+		Expect(os.Mkdir(storePath, 0700)).To(Succeed())
+
+		_, err := store.UpdateState("some-instance", 1, 2, "succeeded")
+		Expect(err).To(MatchError(ContainSubstring("error writing operation store " + storePath)))
+	})
+})
@@ -0,0 +1,210 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package reloadablebroker lets the broker pick up changes to its
+// configuration file without a restart. A reload builds a new broker from the
+// reloaded configuration and swaps it in, so that requests already in flight
+// finish with the broker they started with.
+package reloadablebroker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+// ConfigLoader parses and validates the configuration file.
+type ConfigLoader func() (config.Config, error)
+
+// Builder builds the broker for a configuration, running the startup checks.
+type Builder func(conf config.Config) (apiserver.CombinedBroker, error)
+
+type ReloadableBroker struct {
+	loadConfig  ConfigLoader
+	buildBroker Builder
+
+	reloadLock sync.Mutex
+	lock       sync.RWMutex
+	conf       config.Config
+	broker     apiserver.CombinedBroker
+}
+
+func New(conf config.Config, onDemandBroker apiserver.CombinedBroker, loadConfig ConfigLoader, buildBroker Builder) *ReloadableBroker {
+	return &ReloadableBroker{
+		loadConfig:  loadConfig,
+		buildBroker: buildBroker,
+		conf:        conf,
+		broker:      onDemandBroker,
+	}
+}
+
+// Reload parses the configuration file again and swaps in a broker built
+// from it. The broker keeps its current configuration if the file is not
+// valid, or if a startup check fails.
+func (r *ReloadableBroker) Reload(logger *log.Logger) error {
+	r.reloadLock.Lock()
+	defer r.reloadLock.Unlock()
+
+	logger.Println("Reloading the broker configuration")
+	conf, err := r.loadConfig()
+	if err != nil {
+		return fmt.Errorf("error parsing config: %s", err)
+	}
+
+	onDemandBroker, err := r.buildBroker(conf)
+	if err != nil {
+		return fmt.Errorf("error building broker: %s", err)
+	}
+
+	if settings := settingsRequiringRestart(r.currentConfig(), conf); len(settings) > 0 {
+		logger.Printf("changes to %s take effect when the broker restarts\n", strings.Join(settings, ", "))
+	}
+
+	r.lock.Lock()
+	r.conf = conf
+	r.broker = onDemandBroker
+	r.lock.Unlock()
+
+	logger.Println("Reloaded the broker configuration")
+	return nil
+}
+
+// ReloadOnSignal reloads the configuration every time a signal is received,
+// until the channel is closed.
+func (r *ReloadableBroker) ReloadOnSignal(signals <-chan os.Signal, logger *log.Logger) {
+	for range signals {
+		if err := r.Reload(logger); err != nil {
+			logger.Printf("error reloading the broker configuration: %s\n", err)
+		}
+	}
+}
+
+func (r *ReloadableBroker) ServiceOfferings() []config.ServiceOffering {
+	return r.currentConfig().ServiceOfferings()
+}
+
+func (r *ReloadableBroker) currentConfig() config.Config {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.conf
+}
+
+func (r *ReloadableBroker) current() apiserver.CombinedBroker {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.broker
+}
+
+// settingsRequiringRestart lists the settings that changed, but that the
+// clients and the server were built with when the broker started.
+func settingsRequiringRestart(current, reloaded config.Config) []string {
+	settings := []struct {
+		name              string
+		current, reloaded interface{}
+	}{
+		{"broker.port", current.Broker.Port, reloaded.Broker.Port},
+		{"broker.username", current.Broker.Username, reloaded.Broker.Username},
+		{"broker.password", current.Broker.Password, reloaded.Broker.Password},
+		{"broker.tls", current.Broker.TLS, reloaded.Broker.TLS},
+		{"broker.operation_store_path", current.Broker.OperationStorePath, reloaded.Broker.OperationStorePath},
+		{"broker.operation_store_max_operations", current.Broker.OperationStoreMaxOps, reloaded.Broker.OperationStoreMaxOps},
+		{"bosh", current.Bosh, reloaded.Bosh},
+		{"cf", current.CF, reloaded.CF},
+		{"credhub", current.CredHub, reloaded.CredHub},
+		{"bosh_credhub", current.BoshCredhub, reloaded.BoshCredhub},
+	}
+
+	var changed []string
+	for _, setting := range settings {
+		if !reflect.DeepEqual(setting.current, setting.reloaded) {
+			changed = append(changed, setting.name)
+		}
+	}
+	return changed
+}
+
+func (r *ReloadableBroker) Services(ctx context.Context) ([]brokerapi.Service, error) {
+	return r.current().Services(ctx)
+}
+
+func (r *ReloadableBroker) Provision(ctx context.Context, instanceID string, details brokerapi.ProvisionDetails, asyncAllowed bool) (brokerapi.ProvisionedServiceSpec, error) {
+	return r.current().Provision(ctx, instanceID, details, asyncAllowed)
+}
+
+func (r *ReloadableBroker) Deprovision(ctx context.Context, instanceID string, details brokerapi.DeprovisionDetails, asyncAllowed bool) (brokerapi.DeprovisionServiceSpec, error) {
+	return r.current().Deprovision(ctx, instanceID, details, asyncAllowed)
+}
+
+func (r *ReloadableBroker) GetInstance(ctx context.Context, instanceID string) (brokerapi.GetInstanceDetailsSpec, error) {
+	return r.current().GetInstance(ctx, instanceID)
+}
+
+func (r *ReloadableBroker) Update(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, asyncAllowed bool) (brokerapi.UpdateServiceSpec, error) {
+	return r.current().Update(ctx, instanceID, details, asyncAllowed)
+}
+
+func (r *ReloadableBroker) Bind(ctx context.Context, instanceID, bindingID string, details brokerapi.BindDetails, asyncAllowed bool) (brokerapi.Binding, error) {
+	return r.current().Bind(ctx, instanceID, bindingID, details, asyncAllowed)
+}
+
+func (r *ReloadableBroker) Unbind(ctx context.Context, instanceID, bindingID string, details brokerapi.UnbindDetails, asyncAllowed bool) (brokerapi.UnbindSpec, error) {
+	return r.current().Unbind(ctx, instanceID, bindingID, details, asyncAllowed)
+}
+
+func (r *ReloadableBroker) GetBinding(ctx context.Context, instanceID, bindingID string) (brokerapi.GetBindingSpec, error) {
+	return r.current().GetBinding(ctx, instanceID, bindingID)
+}
+
+func (r *ReloadableBroker) LastOperation(ctx context.Context, instanceID string, details brokerapi.PollDetails) (brokerapi.LastOperation, error) {
+	return r.current().LastOperation(ctx, instanceID, details)
+}
+
+func (r *ReloadableBroker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details brokerapi.PollDetails) (brokerapi.LastOperation, error) {
+	return r.current().LastBindingOperation(ctx, instanceID, bindingID, details)
+}
+
+func (r *ReloadableBroker) Instances(logger *log.Logger) ([]service.Instance, error) {
+	return r.current().Instances(logger)
+}
+
+func (r *ReloadableBroker) FilteredInstances(orgName, spaceName string, logger *log.Logger) ([]service.Instance, error) {
+	return r.current().FilteredInstances(orgName, spaceName, logger)
+}
+
+func (r *ReloadableBroker) OrphanDeployments(logger *log.Logger) ([]string, error) {
+	return r.current().OrphanDeployments(logger)
+}
+
+func (r *ReloadableBroker) Upgrade(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+	return r.current().Upgrade(ctx, instanceID, details, logger)
+}
+
+func (r *ReloadableBroker) Recreate(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+	return r.current().Recreate(ctx, instanceID, details, logger)
+}
+
+func (r *ReloadableBroker) CountInstancesOfPlans(logger *log.Logger) (map[cf.ServicePlan]int, error) {
+	return r.current().CountInstancesOfPlans(logger)
+}
+
+func (r *ReloadableBroker) InstanceOperations(instanceID string, logger *log.Logger) ([]broker.InstanceOperation, error) {
+	return r.current().InstanceOperations(instanceID, logger)
+}
+
+func (r *ReloadableBroker) PreviewUpgrade(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (broker.UpgradePreview, error) {
+	return r.current().PreviewUpgrade(ctx, instanceID, details, logger)
+}
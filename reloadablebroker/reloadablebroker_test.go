@@ -0,0 +1,128 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package reloadablebroker_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver"
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/reloadablebroker"
+)
+
+var _ = Describe("ReloadableBroker", func() {
+	var (
+		initialConf, reloadedConf  config.Config
+		initialBroker, builtBroker *fakes.FakeCombinedBroker
+		loadErr, buildErr          error
+		builtWith                  []config.Config
+		reloadableBroker           *reloadablebroker.ReloadableBroker
+		logs                       *gbytes.Buffer
+		logger                     *log.Logger
+	)
+
+	BeforeEach(func() {
+		initialConf = config.Config{ServiceCatalog: config.ServiceOffering{ID: "initial-id"}}
+		reloadedConf = config.Config{ServiceCatalog: config.ServiceOffering{ID: "reloaded-id"}}
+		initialBroker = new(fakes.FakeCombinedBroker)
+		builtBroker = new(fakes.FakeCombinedBroker)
+		loadErr, buildErr = nil, nil
+		builtWith = nil
+		logs = gbytes.NewBuffer()
+		logger = log.New(io.MultiWriter(GinkgoWriter, logs), "", log.LstdFlags)
+
+		loadConfig := func() (config.Config, error) {
+			return reloadedConf, loadErr
+		}
+		buildBroker := func(conf config.Config) (apiserver.CombinedBroker, error) {
+			builtWith = append(builtWith, conf)
+			if buildErr != nil {
+				return nil, buildErr
+			}
+			return builtBroker, nil
+		}
+		reloadableBroker = reloadablebroker.New(initialConf, initialBroker, loadConfig, buildBroker)
+	})
+
+	It("serves requests with the broker it started with", func() {
+		_, err := reloadableBroker.Services(context.Background())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(initialBroker.ServicesCallCount()).To(Equal(1))
+		Expect(reloadableBroker.ServiceOfferings()).To(Equal(initialConf.ServiceOfferings()))
+	})
+
+	It("serves requests with a broker built from the reloaded configuration", func() {
+		Expect(reloadableBroker.Reload(logger)).To(Succeed())
+
+		_, err := reloadableBroker.Provision(context.Background(), "some-instance", brokerapi.ProvisionDetails{}, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builtWith).To(Equal([]config.Config{reloadedConf}))
+		Expect(builtBroker.ProvisionCallCount()).To(Equal(1))
+		Expect(initialBroker.ProvisionCallCount()).To(Equal(0))
+		Expect(reloadableBroker.ServiceOfferings()).To(Equal(reloadedConf.ServiceOfferings()))
+		Expect(logs).To(gbytes.Say("Reloaded the broker configuration"))
+	})
+
+	It("keeps the current broker when the configuration is not valid", func() {
+		loadErr = errors.New("broker.port can't be empty")
+
+		err := reloadableBroker.Reload(logger)
+
+		Expect(err).To(MatchError("error parsing config: broker.port can't be empty"))
+		Expect(builtWith).To(BeEmpty())
+		_, _ = reloadableBroker.Services(context.Background())
+		Expect(initialBroker.ServicesCallCount()).To(Equal(1))
+	})
+
+	It("keeps the current broker when a startup check fails", func() {
+		buildErr = errors.New("The following broker startup checks failed: plan inconsistent")
+
+		err := reloadableBroker.Reload(logger)
+
+		Expect(err).To(MatchError("error building broker: The following broker startup checks failed: plan inconsistent"))
+		Expect(reloadableBroker.ServiceOfferings()).To(Equal(initialConf.ServiceOfferings()))
+	})
+
+	It("warns about changed settings that need a restart", func() {
+		reloadedConf.Broker.Port = 8081
+		reloadedConf.Bosh.URL = "https://other-director"
+
+		Expect(reloadableBroker.Reload(logger)).To(Succeed())
+
+		Expect(logs).To(gbytes.Say("changes to broker.port, bosh take effect when the broker restarts"))
+	})
+
+	It("reloads the configuration on every signal", func() {
+		signals := make(chan os.Signal, 1)
+		done := make(chan struct{})
+		go func() {
+			reloadableBroker.ReloadOnSignal(signals, logger)
+			close(done)
+		}()
+
+		signals <- syscall.SIGHUP
+		Eventually(logs).Should(gbytes.Say("Reloaded the broker configuration"))
+		loadErr = errors.New("not yaml")
+		signals <- syscall.SIGHUP
+		Eventually(logs).Should(gbytes.Say("error reloading the broker configuration: error parsing config: not yaml"))
+
+		close(signals)
+		Eventually(done).Should(BeClosed())
+	})
+})
@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"log"
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker"
+)
+
+type FakeInstanceOwner struct {
+	OwnsInstanceStub        func(string, *log.Logger) (bool, error)
+	ownsInstanceMutex       sync.RWMutex
+	ownsInstanceArgsForCall []struct {
+		arg1 string
+		arg2 *log.Logger
+	}
+	ownsInstanceReturns struct {
+		result1 bool
+		result2 error
+	}
+	ownsInstanceReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeInstanceOwner) OwnsInstance(arg1 string, arg2 *log.Logger) (bool, error) {
+	fake.ownsInstanceMutex.Lock()
+	ret, specificReturn := fake.ownsInstanceReturnsOnCall[len(fake.ownsInstanceArgsForCall)]
+	fake.ownsInstanceArgsForCall = append(fake.ownsInstanceArgsForCall, struct {
+		arg1 string
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.OwnsInstanceStub
+	fakeReturns := fake.ownsInstanceReturns
+	fake.recordInvocation("OwnsInstance", []interface{}{arg1, arg2})
+	fake.ownsInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInstanceOwner) OwnsInstanceCallCount() int {
+	fake.ownsInstanceMutex.RLock()
+	defer fake.ownsInstanceMutex.RUnlock()
+	return len(fake.ownsInstanceArgsForCall)
+}
+
+func (fake *FakeInstanceOwner) OwnsInstanceCalls(stub func(string, *log.Logger) (bool, error)) {
+	fake.ownsInstanceMutex.Lock()
+	defer fake.ownsInstanceMutex.Unlock()
+	fake.OwnsInstanceStub = stub
+}
+
+func (fake *FakeInstanceOwner) OwnsInstanceArgsForCall(i int) (string, *log.Logger) {
+	fake.ownsInstanceMutex.RLock()
+	defer fake.ownsInstanceMutex.RUnlock()
+	argsForCall := fake.ownsInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInstanceOwner) OwnsInstanceReturns(result1 bool, result2 error) {
+	fake.ownsInstanceMutex.Lock()
+	defer fake.ownsInstanceMutex.Unlock()
+	fake.OwnsInstanceStub = nil
+	fake.ownsInstanceReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstanceOwner) OwnsInstanceReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.ownsInstanceMutex.Lock()
+	defer fake.ownsInstanceMutex.Unlock()
+	fake.OwnsInstanceStub = nil
+	if fake.ownsInstanceReturnsOnCall == nil {
+		fake.ownsInstanceReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.ownsInstanceReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstanceOwner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.ownsInstanceMutex.RLock()
+	defer fake.ownsInstanceMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeInstanceOwner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routingbroker.InstanceOwner = new(FakeInstanceOwner)
@@ -0,0 +1,244 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package routingbroker serves several service offerings from one broker, by
+// routing each request to the broker of the offering it is for.
+package routingbroker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+//go:generate counterfeiter -o fakes/instance_owner.go . InstanceOwner
+type InstanceOwner interface {
+	OwnsInstance(instanceID string, logger *log.Logger) (bool, error)
+}
+
+// Offering is a service offering and the broker that serves it.
+type Offering struct {
+	ServiceOffering config.ServiceOffering
+	Broker          apiserver.CombinedBroker
+	Owner           InstanceOwner
+}
+
+type RoutingBroker struct {
+	offerings     []Offering
+	loggerFactory *loggerfactory.LoggerFactory
+}
+
+func New(offerings []Offering, loggerFactory *loggerfactory.LoggerFactory) *RoutingBroker {
+	return &RoutingBroker{offerings: offerings, loggerFactory: loggerFactory}
+}
+
+func (r *RoutingBroker) Services(ctx context.Context) ([]brokerapi.Service, error) {
+	var services []brokerapi.Service
+	for _, offering := range r.offerings {
+		offeringServices, err := offering.Broker.Services(ctx)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, offeringServices...)
+	}
+	return services, nil
+}
+
+func (r *RoutingBroker) Provision(ctx context.Context, instanceID string, details brokerapi.ProvisionDetails, asyncAllowed bool) (brokerapi.ProvisionedServiceSpec, error) {
+	offeringBroker, err := r.brokerForService(details.ServiceID)
+	if err != nil {
+		return brokerapi.ProvisionedServiceSpec{}, err
+	}
+	return offeringBroker.Provision(ctx, instanceID, details, asyncAllowed)
+}
+
+func (r *RoutingBroker) Deprovision(ctx context.Context, instanceID string, details brokerapi.DeprovisionDetails, asyncAllowed bool) (brokerapi.DeprovisionServiceSpec, error) {
+	offeringBroker, err := r.brokerForService(details.ServiceID)
+	if err != nil {
+		return brokerapi.DeprovisionServiceSpec{}, err
+	}
+	return offeringBroker.Deprovision(ctx, instanceID, details, asyncAllowed)
+}
+
+func (r *RoutingBroker) GetInstance(ctx context.Context, instanceID string) (brokerapi.GetInstanceDetailsSpec, error) {
+	return r.brokerForInstance(instanceID).GetInstance(ctx, instanceID)
+}
+
+func (r *RoutingBroker) Update(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, asyncAllowed bool) (brokerapi.UpdateServiceSpec, error) {
+	offeringBroker, err := r.brokerForService(details.ServiceID)
+	if err != nil {
+		return brokerapi.UpdateServiceSpec{}, err
+	}
+	return offeringBroker.Update(ctx, instanceID, details, asyncAllowed)
+}
+
+func (r *RoutingBroker) Bind(ctx context.Context, instanceID, bindingID string, details brokerapi.BindDetails, asyncAllowed bool) (brokerapi.Binding, error) {
+	offeringBroker, err := r.brokerForService(details.ServiceID)
+	if err != nil {
+		return brokerapi.Binding{}, err
+	}
+	return offeringBroker.Bind(ctx, instanceID, bindingID, details, asyncAllowed)
+}
+
+func (r *RoutingBroker) Unbind(ctx context.Context, instanceID, bindingID string, details brokerapi.UnbindDetails, asyncAllowed bool) (brokerapi.UnbindSpec, error) {
+	offeringBroker, err := r.brokerForService(details.ServiceID)
+	if err != nil {
+		return brokerapi.UnbindSpec{}, err
+	}
+	return offeringBroker.Unbind(ctx, instanceID, bindingID, details, asyncAllowed)
+}
+
+func (r *RoutingBroker) GetBinding(ctx context.Context, instanceID, bindingID string) (brokerapi.GetBindingSpec, error) {
+	return r.brokerForInstance(instanceID).GetBinding(ctx, instanceID, bindingID)
+}
+
+func (r *RoutingBroker) LastOperation(ctx context.Context, instanceID string, details brokerapi.PollDetails) (brokerapi.LastOperation, error) {
+	offeringBroker, err := r.brokerForPoll(instanceID, details)
+	if err != nil {
+		return brokerapi.LastOperation{}, err
+	}
+	return offeringBroker.LastOperation(ctx, instanceID, details)
+}
+
+func (r *RoutingBroker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details brokerapi.PollDetails) (brokerapi.LastOperation, error) {
+	offeringBroker, err := r.brokerForPoll(instanceID, details)
+	if err != nil {
+		return brokerapi.LastOperation{}, err
+	}
+	return offeringBroker.LastBindingOperation(ctx, instanceID, bindingID, details)
+}
+
+func (r *RoutingBroker) Instances(logger *log.Logger) ([]service.Instance, error) {
+	var instances []service.Instance
+	for _, offering := range r.offerings {
+		offeringInstances, err := offering.Broker.Instances(logger)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, offeringInstances...)
+	}
+	return instances, nil
+}
+
+func (r *RoutingBroker) FilteredInstances(orgName, spaceName string, logger *log.Logger) ([]service.Instance, error) {
+	var instances []service.Instance
+	for _, offering := range r.offerings {
+		offeringInstances, err := offering.Broker.FilteredInstances(orgName, spaceName, logger)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, offeringInstances...)
+	}
+	return instances, nil
+}
+
+// OrphanDeployments returns the deployments that are orphans for every
+// offering, as the broker of each offering only knows its own instances.
+func (r *RoutingBroker) OrphanDeployments(logger *log.Logger) ([]string, error) {
+	orphanCounts := map[string]int{}
+	var orphans []string
+	for _, offering := range r.offerings {
+		offeringOrphans, err := offering.Broker.OrphanDeployments(logger)
+		if err != nil {
+			return nil, err
+		}
+		for _, orphan := range offeringOrphans {
+			orphanCounts[orphan]++
+			if orphanCounts[orphan] == len(r.offerings) {
+				orphans = append(orphans, orphan)
+			}
+		}
+	}
+	return orphans, nil
+}
+
+func (r *RoutingBroker) Upgrade(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+	return r.brokerForPlan(instanceID, details.PlanID).Upgrade(ctx, instanceID, details, logger)
+}
+
+func (r *RoutingBroker) Recreate(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+	return r.brokerForPlan(instanceID, details.PlanID).Recreate(ctx, instanceID, details, logger)
+}
+
+func (r *RoutingBroker) PreviewUpgrade(ctx context.Context, instanceID string, details brokerapi.UpdateDetails, logger *log.Logger) (broker.UpgradePreview, error) {
+	return r.brokerForPlan(instanceID, details.PlanID).PreviewUpgrade(ctx, instanceID, details, logger)
+}
+
+func (r *RoutingBroker) CountInstancesOfPlans(logger *log.Logger) (map[cf.ServicePlan]int, error) {
+	counts := map[cf.ServicePlan]int{}
+	for _, offering := range r.offerings {
+		offeringCounts, err := offering.Broker.CountInstancesOfPlans(logger)
+		if err != nil {
+			return nil, err
+		}
+		for plan, count := range offeringCounts {
+			counts[plan] = count
+		}
+	}
+	return counts, nil
+}
+
+// InstanceOperations reads the operation journal, which all the offerings
+// share.
+func (r *RoutingBroker) InstanceOperations(instanceID string, logger *log.Logger) ([]broker.InstanceOperation, error) {
+	return r.offerings[0].Broker.InstanceOperations(instanceID, logger)
+}
+
+func (r *RoutingBroker) brokerForService(serviceID string) (apiserver.CombinedBroker, error) {
+	for _, offering := range r.offerings {
+		if offering.ServiceOffering.ID == serviceID {
+			return offering.Broker, nil
+		}
+	}
+	return nil, brokerapi.NewFailureResponse(
+		fmt.Errorf("service offering %s not found", serviceID),
+		http.StatusBadRequest,
+		"service-offering-not-found",
+	)
+}
+
+func (r *RoutingBroker) brokerForPoll(instanceID string, details brokerapi.PollDetails) (apiserver.CombinedBroker, error) {
+	if details.ServiceID != "" {
+		return r.brokerForService(details.ServiceID)
+	}
+	return r.brokerForPlan(instanceID, details.PlanID), nil
+}
+
+func (r *RoutingBroker) brokerForPlan(instanceID, planID string) apiserver.CombinedBroker {
+	for _, offering := range r.offerings {
+		if _, found := offering.ServiceOffering.FindPlanByID(planID); found {
+			return offering.Broker
+		}
+	}
+	return r.brokerForInstance(instanceID)
+}
+
+// brokerForInstance returns the broker of the offering that the instance
+// belongs to. When no offering claims the instance, the broker of the first
+// offering handles the request, and reports that the instance is not found.
+func (r *RoutingBroker) brokerForInstance(instanceID string) apiserver.CombinedBroker {
+	logger := r.loggerFactory.NewWithRequestID()
+	for _, offering := range r.offerings {
+		owned, err := offering.Owner.OwnsInstance(instanceID, logger)
+		if err != nil {
+			logger.Printf("error finding whether instance %s belongs to service offering %s: %s\n", instanceID, offering.ServiceOffering.Name, err)
+			continue
+		}
+		if owned {
+			return offering.Broker
+		}
+	}
+	return r.offerings[0].Broker
+}
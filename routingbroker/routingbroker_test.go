@@ -0,0 +1,187 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package routingbroker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker"
+	routingfakes "github.com/pivotal-cf/on-demand-service-broker/routingbroker/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("RoutingBroker", func() {
+	var (
+		redisBroker, kafkaBroker *fakes.FakeCombinedBroker
+		redisOwner, kafkaOwner   *routingfakes.FakeInstanceOwner
+		routingBroker            *routingbroker.RoutingBroker
+		logger                   *log.Logger
+	)
+
+	BeforeEach(func() {
+		redisBroker = new(fakes.FakeCombinedBroker)
+		kafkaBroker = new(fakes.FakeCombinedBroker)
+		redisOwner = new(routingfakes.FakeInstanceOwner)
+		kafkaOwner = new(routingfakes.FakeInstanceOwner)
+		loggerFactory := loggerfactory.New(GinkgoWriter, "routingbroker-unit-tests", log.LstdFlags)
+		logger = loggerFactory.New()
+
+		routingBroker = routingbroker.New([]routingbroker.Offering{
+			{
+				ServiceOffering: config.ServiceOffering{ID: "redis-id", Plans: config.Plans{{ID: "redis-small"}}},
+				Broker:          redisBroker,
+				Owner:           redisOwner,
+			},
+			{
+				ServiceOffering: config.ServiceOffering{ID: "kafka-id", Plans: config.Plans{{ID: "kafka-small"}}},
+				Broker:          kafkaBroker,
+				Owner:           kafkaOwner,
+			},
+		}, loggerFactory)
+	})
+
+	It("returns the catalogs of all the offerings", func() {
+		redisBroker.ServicesReturns([]brokerapi.Service{{ID: "redis-id"}}, nil)
+		kafkaBroker.ServicesReturns([]brokerapi.Service{{ID: "kafka-id"}}, nil)
+
+		services, err := routingBroker.Services(context.Background())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(services).To(Equal([]brokerapi.Service{{ID: "redis-id"}, {ID: "kafka-id"}}))
+	})
+
+	It("routes provisioning by service ID", func() {
+		kafkaBroker.ProvisionReturns(brokerapi.ProvisionedServiceSpec{OperationData: "kafka"}, nil)
+
+		spec, err := routingBroker.Provision(context.Background(), "some-instance", brokerapi.ProvisionDetails{ServiceID: "kafka-id"}, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.OperationData).To(Equal("kafka"))
+		Expect(redisBroker.ProvisionCallCount()).To(Equal(0))
+		_, instanceID, _, _ := kafkaBroker.ProvisionArgsForCall(0)
+		Expect(instanceID).To(Equal("some-instance"))
+	})
+
+	It("fails requests for an unknown service ID", func() {
+		_, err := routingBroker.Bind(context.Background(), "some-instance", "some-binding", brokerapi.BindDetails{ServiceID: "mysql-id"}, false)
+
+		Expect(err).To(MatchError("service offering mysql-id not found"))
+		Expect(err.(*brokerapi.FailureResponse).ValidatedStatusCode(nil)).To(Equal(http.StatusBadRequest))
+		Expect(redisBroker.BindCallCount()).To(Equal(0))
+		Expect(kafkaBroker.BindCallCount()).To(Equal(0))
+	})
+
+	It("routes polls for the last operation by service ID", func() {
+		_, err := routingBroker.LastOperation(context.Background(), "some-instance", brokerapi.PollDetails{ServiceID: "kafka-id"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kafkaBroker.LastOperationCallCount()).To(Equal(1))
+		Expect(redisOwner.OwnsInstanceCallCount()).To(Equal(0))
+	})
+
+	It("routes upgrades by plan ID", func() {
+		_, err := routingBroker.Upgrade(context.Background(), "some-instance", brokerapi.UpdateDetails{PlanID: "kafka-small"}, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kafkaBroker.UpgradeCallCount()).To(Equal(1))
+		Expect(redisBroker.UpgradeCallCount()).To(Equal(0))
+	})
+
+	Describe("requests that only name the instance", func() {
+		It("are routed to the offering that owns the instance", func() {
+			kafkaOwner.OwnsInstanceReturns(true, nil)
+
+			_, err := routingBroker.GetInstance(context.Background(), "some-instance")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kafkaBroker.GetInstanceCallCount()).To(Equal(1))
+			Expect(redisBroker.GetInstanceCallCount()).To(Equal(0))
+			instanceID, _ := redisOwner.OwnsInstanceArgsForCall(0)
+			Expect(instanceID).To(Equal("some-instance"))
+		})
+
+		It("skip offerings whose ownership cannot be determined", func() {
+			redisOwner.OwnsInstanceReturns(false, errors.New("bosh unavailable"))
+			kafkaOwner.OwnsInstanceReturns(true, nil)
+
+			_, err := routingBroker.GetBinding(context.Background(), "some-instance", "some-binding")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kafkaBroker.GetBindingCallCount()).To(Equal(1))
+		})
+
+		It("are handled by the first offering when no offering owns the instance", func() {
+			redisBroker.GetInstanceReturns(brokerapi.GetInstanceDetailsSpec{}, errors.New("instance does not exist"))
+
+			_, err := routingBroker.GetInstance(context.Background(), "some-instance")
+
+			Expect(err).To(MatchError("instance does not exist"))
+			Expect(redisBroker.GetInstanceCallCount()).To(Equal(1))
+		})
+	})
+
+	It("lists the instances of all the offerings", func() {
+		redisBroker.InstancesReturns([]service.Instance{{GUID: "redis-instance"}}, nil)
+		kafkaBroker.InstancesReturns([]service.Instance{{GUID: "kafka-instance"}}, nil)
+
+		instances, err := routingBroker.Instances(logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances).To(Equal([]service.Instance{{GUID: "redis-instance"}, {GUID: "kafka-instance"}}))
+	})
+
+	It("returns an error when the instances of an offering cannot be listed", func() {
+		kafkaBroker.FilteredInstancesReturns(nil, errors.New("cf unavailable"))
+
+		_, err := routingBroker.FilteredInstances("some-org", "some-space", logger)
+
+		Expect(err).To(MatchError("cf unavailable"))
+	})
+
+	It("counts the instances of the plans of all the offerings", func() {
+		redisPlan := cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: "redis-small"}}
+		kafkaPlan := cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: "kafka-small"}}
+		redisBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{redisPlan: 2}, nil)
+		kafkaBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{kafkaPlan: 3}, nil)
+
+		counts, err := routingBroker.CountInstancesOfPlans(logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(Equal(map[cf.ServicePlan]int{redisPlan: 2, kafkaPlan: 3}))
+	})
+
+	It("only reports deployments that no offering has an instance for as orphans", func() {
+		redisBroker.OrphanDeploymentsReturns([]string{"service-instance_kafka", "service-instance_orphan"}, nil)
+		kafkaBroker.OrphanDeploymentsReturns([]string{"service-instance_redis", "service-instance_orphan"}, nil)
+
+		orphans, err := routingBroker.OrphanDeployments(logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(Equal([]string{"service-instance_orphan"}))
+	})
+
+	It("reads the operations of an instance from the shared journal", func() {
+		redisBroker.InstanceOperationsReturns([]broker.InstanceOperation{{BoshTaskID: 42}}, nil)
+
+		operations, err := routingBroker.InstanceOperations("some-instance", logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations).To(Equal([]broker.InstanceOperation{{BoshTaskID: 42}}))
+		Expect(kafkaBroker.InstanceOperationsCallCount()).To(Equal(0))
+	})
+})
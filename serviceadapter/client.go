@@ -7,8 +7,10 @@
 package serviceadapter
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
@@ -17,14 +19,39 @@ const SuccessExitCode = 0
 
 //go:generate counterfeiter -o fakes/fake_command_runner.go . CommandRunner
 type CommandRunner interface {
-	Run(arg ...string) ([]byte, []byte, *int, error)
-	RunWithInputParams(inputParams interface{}, arg ...string) ([]byte, []byte, *int, error)
+	Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error)
+	RunWithInputParams(ctx context.Context, inputParams interface{}, arg ...string) ([]byte, []byte, *int, error)
 }
 
 type Client struct {
 	ExternalBinPath string
 	CommandRunner   CommandRunner
 	UsingStdin      bool
+	// Timeouts bounds how long each subcommand may run. Subcommands without a
+	// timeout run until they exit or their request is cancelled.
+	Timeouts map[string]time.Duration
+}
+
+// withTimeout returns a context that is done when the subcommand's timeout
+// expires, if it has one, or when ctx is done.
+func (c *Client) withTimeout(ctx context.Context, subcommand string) (context.Context, context.CancelFunc) {
+	if timeout, found := c.Timeouts[subcommand]; found {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// runError describes an error running the subcommand, which is a
+// TimeoutError if the subcommand was stopped for taking too long, or a
+// CanceledError if it was stopped because its request was cancelled.
+func (c *Client) runError(subcommand string, stdout, stderr []byte, err error) error {
+	switch err {
+	case context.DeadlineExceeded:
+		return NewTimeoutError(fmt.Sprintf("external service adapter at %s did not complete %s in time and was stopped", c.ExternalBinPath, subcommand))
+	case context.Canceled:
+		return NewCanceledError(fmt.Sprintf("external service adapter at %s was stopped running %s because the request was cancelled", c.ExternalBinPath, subcommand))
+	}
+	return adapterError(c.ExternalBinPath, stdout, stderr, err)
 }
 
 func SanitiseForJSON(properties sdk.Properties) sdk.Properties {
@@ -97,6 +124,18 @@ type BindingNotFoundError struct {
 	error
 }
 
+// TimeoutError is returned when the adapter is stopped because it took longer
+// than its timeout, or than the request allowed.
+type TimeoutError struct {
+	error
+}
+
+// CanceledError is returned when the adapter is stopped because its request
+// was cancelled, for example because the client disconnected.
+type CanceledError struct {
+	error
+}
+
 func NewNotImplementedError(msg string) NotImplementedError {
 	return NotImplementedError{errors.New(msg)}
 }
@@ -105,6 +144,14 @@ func NewUnknownFailureError(msg string) UnknownFailureError {
 	return UnknownFailureError{errors.New(msg)}
 }
 
+func NewTimeoutError(msg string) TimeoutError {
+	return TimeoutError{errors.New(msg)}
+}
+
+func NewCanceledError(msg string) CanceledError {
+	return CanceledError{errors.New(msg)}
+}
+
 func invalidJSONError(adapterPath string, stdout, stderr []byte, err error) error {
 	return fmt.Errorf("external service adapter returned invalid JSON at %s: stdout: '%s', stderr: '%s', JSON error: '%s'", adapterPath, string(stdout), string(stderr), err)
 }
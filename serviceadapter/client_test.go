@@ -7,12 +7,17 @@
 package serviceadapter_test
 
 import (
+	"context"
+	"log"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
 
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
@@ -70,4 +75,47 @@ var _ = Describe("Adapter Client", func() {
 			Equal("some other error"),
 		),
 	)
+
+	Describe("timeouts", func() {
+		var (
+			cmdRunner *fakes.FakeCommandRunner
+			a         *serviceadapter.Client
+			logger    *log.Logger
+		)
+
+		BeforeEach(func() {
+			cmdRunner = new(fakes.FakeCommandRunner)
+			cmdRunner.RunStub = func(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
+				<-ctx.Done()
+				return nil, nil, nil, ctx.Err()
+			}
+			a = &serviceadapter.Client{
+				ExternalBinPath: "/thing",
+				CommandRunner:   cmdRunner,
+				Timeouts:        map[string]time.Duration{"dashboard-url": 10 * time.Millisecond},
+			}
+			logger = log.New(GinkgoWriter, "[unit-tests] ", log.LstdFlags)
+		})
+
+		It("returns a TimeoutError when a subcommand does not complete within its timeout", func() {
+			_, err := a.GenerateDashboardUrl(context.Background(), "some-instance-id", sdk.Plan{}, []byte{}, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(serviceadapter.TimeoutError{}))
+			Expect(err).To(MatchError("external service adapter at /thing did not complete dashboard-url in time and was stopped"))
+		})
+
+		It("runs a subcommand without a timeout until its request is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := a.GeneratePlanSchema(ctx, sdk.Plan{}, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(serviceadapter.CanceledError{}))
+			Expect(err).To(MatchError("external service adapter at /thing was stopped running generate-plan-schemas because the request was cancelled"))
+
+			runCtx, _ := cmdRunner.RunArgsForCall(0)
+			_, hasDeadline := runCtx.Deadline()
+			Expect(hasDeadline).To(BeFalse())
+		})
+	})
 })
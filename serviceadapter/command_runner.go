@@ -8,11 +8,15 @@ package serviceadapter
 
 import (
 	"bytes"
+	"context"
 	"os/exec"
+	"strconv"
 	"syscall"
+	"time"
 
 	"encoding/json"
 
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
@@ -24,12 +28,12 @@ type commandRunner struct {
 	inputParams sdk.InputParams
 }
 
-func (c commandRunner) Run(arg ...string) ([]byte, []byte, *int, error) {
+func (c commandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
 	cmd := exec.Command(arg[0], arg[1:]...)
-	return c.run(cmd)
+	return c.run(ctx, cmd, arg)
 }
 
-func (c commandRunner) RunWithInputParams(inputParams interface{}, arg ...string) ([]byte, []byte, *int, error) {
+func (c commandRunner) RunWithInputParams(ctx context.Context, inputParams interface{}, arg ...string) ([]byte, []byte, *int, error) {
 	cmd := exec.Command(arg[0], arg[1:]...)
 
 	b := bytes.NewBuffer([]byte{})
@@ -40,18 +44,39 @@ func (c commandRunner) RunWithInputParams(inputParams interface{}, arg ...string
 	}
 	cmd.Stdin = b
 
-	return c.run(cmd)
+	return c.run(ctx, cmd, arg)
+}
+
+func recordInvocation(arg []string, duration time.Duration, exitCode *int) {
+	subcommand := "unknown"
+	if len(arg) > 1 {
+		subcommand = arg[1]
+	}
+
+	exitCodeLabel := "none"
+	if exitCode != nil {
+		exitCodeLabel = strconv.Itoa(*exitCode)
+	}
+
+	metrics.AdapterInvocationDuration.WithLabelValues(subcommand).Observe(duration.Seconds())
+	metrics.AdapterInvocations.WithLabelValues(subcommand, exitCodeLabel).Inc()
 }
 
 func intPtr(val int) *int {
 	return &val
 }
 
-func (c commandRunner) run(cmd *exec.Cmd) ([]byte, []byte, *int, error) {
-	var stderr bytes.Buffer
+// run runs the command until it exits or ctx is done. The command runs in its
+// own process group, so that when ctx is done any processes it has started
+// are killed along with it, and ctx.Err() is returned with no exit code.
+func (c commandRunner) run(ctx context.Context, cmd *exec.Cmd, arg []string) ([]byte, []byte, *int, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
-	stdout, err := cmd.Output()
+	startedAt := time.Now()
+	err := wait(ctx, cmd)
 
 	var exitCode *int
 
@@ -64,5 +89,27 @@ func (c commandRunner) run(cmd *exec.Cmd) ([]byte, []byte, *int, error) {
 		exitCode = intPtr(0)
 	}
 
-	return stdout, stderr.Bytes(), exitCode, err
+	recordInvocation(arg, time.Since(startedAt), exitCode)
+
+	return stdout.Bytes(), stderr.Bytes(), exitCode, err
+}
+
+func wait(ctx context.Context, cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-ctx.Done():
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-exited
+		return ctx.Err()
+	}
 }
@@ -7,16 +7,22 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 
 	"math/rand"
+	"time"
 
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 )
 
 var _ = Describe("CommandRunner", func() {
@@ -51,7 +57,7 @@ var _ = Describe("CommandRunner", func() {
 		JustBeforeEach(func() {
 			runner := serviceadapter.NewCommandRunner()
 			var stdoutBytes, stderrBytes []byte
-			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.Run(scriptPath)
+			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.Run(context.Background(), scriptPath)
 			stdout = string(stdoutBytes)
 			stderr = string(stderrBytes)
 		})
@@ -134,6 +140,49 @@ var _ = Describe("CommandRunner", func() {
 		})
 	})
 
+	Describe("metrics", func() {
+		AfterEach(func() {
+			os.Remove(scriptPath)
+		})
+
+		It("records the duration and exit code of each invocation by subcommand", func() {
+			scriptPath = createScript("exit 3")
+			subcommand := "subcommand-" + randSeq(8)
+
+			_, _, _, err := serviceadapter.NewCommandRunner().Run(context.Background(), scriptPath, subcommand)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testutil.ToFloat64(metrics.AdapterInvocations.WithLabelValues(subcommand, "3"))).To(Equal(1.0))
+
+			var duration dto.Metric
+			Expect(metrics.AdapterInvocationDuration.WithLabelValues(subcommand).(prometheus.Histogram).Write(&duration)).To(Succeed())
+			Expect(duration.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("cancellation", func() {
+		AfterEach(func() {
+			os.Remove(scriptPath)
+		})
+
+		It("kills the command and any processes it started when the context is done", func() {
+			// The background sleep holds the command's standard output open, so
+			// Run only returns early if it is killed too.
+			scriptPath = createScript("sleep 60 &\nsleep 60")
+			subcommand := "subcommand-" + randSeq(8)
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			startedAt := time.Now()
+			_, _, exitCode, err := serviceadapter.NewCommandRunner().Run(ctx, scriptPath, subcommand)
+
+			Expect(err).To(Equal(context.DeadlineExceeded))
+			Expect(exitCode).To(BeNil())
+			Expect(time.Since(startedAt)).To(BeNumerically("<", 10*time.Second))
+			Expect(testutil.ToFloat64(metrics.AdapterInvocations.WithLabelValues(subcommand, "none"))).To(Equal(1.0))
+		})
+	})
+
 	Describe("RunWithInputParams", func() {
 		var inputParams interface{}
 
@@ -144,7 +193,7 @@ var _ = Describe("CommandRunner", func() {
 		JustBeforeEach(func() {
 			runner := serviceadapter.NewCommandRunner()
 			var stdoutBytes, stderrBytes []byte
-			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.RunWithInputParams(inputParams, scriptPath)
+			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.RunWithInputParams(context.Background(), inputParams, scriptPath)
 			stdout = string(stdoutBytes)
 			stderr = string(stderrBytes)
 		})
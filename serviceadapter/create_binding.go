@@ -7,6 +7,7 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -15,6 +16,7 @@ import (
 )
 
 func (c *Client) CreateBinding(
+	ctx context.Context,
 	bindingID string,
 	deploymentTopology bosh.BoshVMs,
 	manifest []byte,
@@ -48,6 +50,9 @@ func (c *Client) CreateBinding(
 	var stdout, stderr []byte
 	var exitCode *int
 
+	ctx, cancel := c.withTimeout(ctx, "create-binding")
+	defer cancel()
+
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			CreateBinding: sdk.CreateBindingJSONParams{
@@ -60,13 +65,13 @@ func (c *Client) CreateBinding(
 			},
 		}
 
-		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(inputParams, c.ExternalBinPath, "create-binding")
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "create-binding")
 	} else {
-		stdout, stderr, exitCode, err = c.CommandRunner.Run(c.ExternalBinPath, "create-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
+		stdout, stderr, exitCode, err = c.CommandRunner.Run(ctx, c.ExternalBinPath, "create-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
 	}
 
 	if err != nil {
-		return binding, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return binding, c.runError("create-binding", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -7,6 +7,7 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -70,7 +71,7 @@ var _ = Describe("external service adapter", func() {
 	})
 
 	JustBeforeEach(func() {
-		adapterBinding, createBindingErr = a.CreateBinding(bindingID, deploymentTopology,
+		adapterBinding, createBindingErr = a.CreateBinding(context.Background(), bindingID, deploymentTopology,
 			manifest, requestParams, secrets, dnsAddresses, logger)
 	})
 
@@ -82,7 +83,7 @@ var _ = Describe("external service adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(cmdRunner.RunCallCount()).To(Equal(1))
-		argsPassed := cmdRunner.RunArgsForCall(0)
+		_, argsPassed := cmdRunner.RunArgsForCall(0)
 		Expect(argsPassed).To(ConsistOf(externalBinPath, "create-binding", bindingID, string(serialisedVMs), string(manifest), string(serialisedRequestParams)))
 	})
 
@@ -210,7 +211,7 @@ var _ = Describe("external service adapter", func() {
 		It("invokes external binding creator with serialised parameters in the stdin", func() {
 			Expect(cmdRunner.RunCallCount()).To(Equal(0))
 			Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+			_, actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 			Expect(argsPassed).To(ConsistOf(
 				externalBinPath,
 				"create-binding",
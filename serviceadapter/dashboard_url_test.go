@@ -7,6 +7,7 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -67,7 +68,7 @@ var _ = Describe("dashboard url", func() {
 	})
 
 	JustBeforeEach(func() {
-		actualDashboardUrl, actualError = a.GenerateDashboardUrl(instanceID, plan, manifest, logger)
+		actualDashboardUrl, actualError = a.GenerateDashboardUrl(context.Background(), instanceID, plan, manifest, logger)
 	})
 	Context("when stdin is not set", func() {
 
@@ -75,7 +76,7 @@ var _ = Describe("dashboard url", func() {
 			Expect(cmdRunner.RunCallCount()).To(Equal(1))
 			planJson, err := json.Marshal(plan)
 			Expect(err).NotTo(HaveOccurred())
-			argsPassed := cmdRunner.RunArgsForCall(0)
+			_, argsPassed := cmdRunner.RunArgsForCall(0)
 			Expect(argsPassed).To(ConsistOf(externalBinPath, "dashboard-url", instanceID, string(planJson), string(manifest)))
 		})
 
@@ -106,7 +107,7 @@ var _ = Describe("dashboard url", func() {
 			It("converts plan properties to be json serializable", func() {
 				Expect(actualError).NotTo(HaveOccurred())
 				Expect(cmdRunner.RunCallCount()).To(Equal(1))
-				argsPassed := cmdRunner.RunArgsForCall(0)
+				_, argsPassed := cmdRunner.RunArgsForCall(0)
 
 				convertedPlan := sdk.Plan{
 					Properties: sdk.Properties{
@@ -191,7 +192,7 @@ var _ = Describe("dashboard url", func() {
 			By("invoking the handler")
 			Expect(cmdRunner.RunCallCount()).To(Equal(0))
 			Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+			_, inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 			Expect(inputParams.(sdk.InputParams)).To(Equal(sdk.InputParams{
 				DashboardUrl: sdk.DashboardUrlJSONParams{
 					InstanceId: instanceID, Plan: string(planJson), Manifest: string(manifest),
@@ -224,7 +225,7 @@ var _ = Describe("dashboard url", func() {
 			It("converts plan properties to be json serializable", func() {
 				Expect(actualError).NotTo(HaveOccurred())
 				Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-				inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+				_, inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 
 				convertedPlan := sdk.Plan{
 					Properties: sdk.Properties{
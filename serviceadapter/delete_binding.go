@@ -7,6 +7,7 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -14,7 +15,7 @@ import (
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
-func (c *Client) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, logger *log.Logger) error {
+func (c *Client) DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap map[string]string, logger *log.Logger) error {
 	serialisedBoshVMs, err := json.Marshal(deploymentTopology)
 	if err != nil {
 		return err
@@ -33,6 +34,9 @@ func (c *Client) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs
 	var stdout, stderr []byte
 	var exitCode *int
 
+	ctx, cancel := c.withTimeout(ctx, "delete-binding")
+	defer cancel()
+
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			DeleteBinding: sdk.DeleteBindingJSONParams{
@@ -43,13 +47,13 @@ func (c *Client) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs
 				Secrets:           string(serialisedSecrets),
 			},
 		}
-		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(inputParams, c.ExternalBinPath, "delete-binding")
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "delete-binding")
 	} else {
-		stdout, stderr, exitCode, err = c.CommandRunner.Run(c.ExternalBinPath, "delete-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
+		stdout, stderr, exitCode, err = c.CommandRunner.Run(ctx, c.ExternalBinPath, "delete-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
 	}
 
 	if err != nil {
-		return adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return c.runError("delete-binding", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -7,6 +7,7 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log"
@@ -120,7 +121,7 @@ var _ = Describe("external service adapter", func() {
 	})
 
 	JustBeforeEach(func() {
-		generateManifestOutput, generateErr = a.GenerateManifest(serviceDeployment, plan, params, previousManifest, previousPlan, previousSecrets, previousConfigs, logger)
+		generateManifestOutput, generateErr = a.GenerateManifest(context.Background(), serviceDeployment, plan, params, previousManifest, previousPlan, previousSecrets, previousConfigs, logger)
 	})
 
 	It("invokes external manifest generator with serialised parameters when 'UsingStdin' not set", func() {
@@ -138,7 +139,7 @@ var _ = Describe("external service adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(cmdRunner.RunCallCount()).To(Equal(1))
-		argsPassed := cmdRunner.RunArgsForCall(0)
+		_, argsPassed := cmdRunner.RunArgsForCall(0)
 		Expect(argsPassed).To(ConsistOf(externalBinPath, "generate-manifest",
 			string(serialisedServiceDeployment), string(serialisedPlan),
 			string(serialisedParams), string(previousManifest), string(serialisedPreviousPlan)))
@@ -269,7 +270,7 @@ stemcells:
 		})
 
 		It("it writes 'null' to the argument list", func() {
-			argsPassed := cmdRunner.RunArgsForCall(0)
+			_, argsPassed := cmdRunner.RunArgsForCall(0)
 			Expect(argsPassed[6]).To(Equal("null"))
 		})
 	})
@@ -282,7 +283,7 @@ stemcells:
 		It("invokes external manifest generator with serialised parameters in the stdin", func() {
 			Expect(cmdRunner.RunCallCount()).To(Equal(0))
 			Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+			_, actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 			Expect(argsPassed).To(ConsistOf(
 				externalBinPath,
 				"generate-manifest",
@@ -436,7 +437,7 @@ stemcells:
 				By("not erroring")
 				Expect(generateErr).ToNot(HaveOccurred())
 
-				actualInputParams, _ := cmdRunner.RunWithInputParamsArgsForCall(0)
+				_, actualInputParams, _ := cmdRunner.RunWithInputParamsArgsForCall(0)
 				Expect(actualInputParams.(sdk.InputParams).GenerateManifest.PreviousPlan).To(Equal("null"))
 			})
 		})
@@ -7,6 +7,7 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -14,7 +15,7 @@ import (
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
-func (c *Client) GeneratePlanSchema(plan sdk.Plan, logger *log.Logger) (brokerapi.ServiceSchemas, error) {
+func (c *Client) GeneratePlanSchema(ctx context.Context, plan sdk.Plan, logger *log.Logger) (brokerapi.ServiceSchemas, error) {
 	var stdout, stderr []byte
 	var exitCode *int
 	var err error
@@ -25,21 +26,25 @@ func (c *Client) GeneratePlanSchema(plan sdk.Plan, logger *log.Logger) (brokerap
 		return brokerapi.ServiceSchemas{}, err
 	}
 
+	ctx, cancel := c.withTimeout(ctx, "generate-plan-schemas")
+	defer cancel()
+
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			GeneratePlanSchemas: sdk.GeneratePlanSchemasJSONParams{
 				Plan: string(serialisedPlan),
 			},
 		}
-		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(inputParams, c.ExternalBinPath, "generate-plan-schemas")
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "generate-plan-schemas")
 	} else {
 		stdout, stderr, exitCode, err = c.CommandRunner.Run(
+			ctx,
 			c.ExternalBinPath, "generate-plan-schemas", "--plan-json", string(serialisedPlan),
 		)
 	}
 
 	if err != nil {
-		return brokerapi.ServiceSchemas{}, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return brokerapi.ServiceSchemas{}, c.runError("generate-plan-schemas", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
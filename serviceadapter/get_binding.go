@@ -0,0 +1,100 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+type getBindingJSONParams struct {
+	BindingId    string `json:"binding_id"`
+	BoshVms      string `json:"bosh_vms"`
+	Manifest     string `json:"manifest"`
+	Secrets      string `json:"secrets"`
+	DNSAddresses string `json:"dns_addresses"`
+}
+
+type getBindingInputParams struct {
+	GetBinding getBindingJSONParams `json:"get_binding"`
+}
+
+func (c *Client) GetBinding(
+	ctx context.Context,
+	bindingID string,
+	deploymentTopology bosh.BoshVMs,
+	manifest []byte,
+	secrets map[string]string,
+	dnsAddresses map[string]string,
+	logger *log.Logger) (sdk.Binding, error) {
+
+	var binding sdk.Binding
+
+	serialisedBoshVMs, err := json.Marshal(deploymentTopology)
+	if err != nil {
+		return binding, err
+	}
+
+	serialisedSecrets, err := json.Marshal(secrets)
+	if err != nil {
+		return binding, err
+	}
+
+	serialisedDNSAddresses, err := json.Marshal(dnsAddresses)
+	if err != nil {
+		return binding, err
+	}
+
+	var stdout, stderr []byte
+	var exitCode *int
+
+	ctx, cancel := c.withTimeout(ctx, "get-binding")
+	defer cancel()
+
+	if c.UsingStdin {
+		inputParams := getBindingInputParams{
+			GetBinding: getBindingJSONParams{
+				BindingId:    bindingID,
+				BoshVms:      string(serialisedBoshVMs),
+				Manifest:     string(manifest),
+				Secrets:      string(serialisedSecrets),
+				DNSAddresses: string(serialisedDNSAddresses),
+			},
+		}
+
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "get-binding")
+	} else {
+		stdout, stderr, exitCode, err = c.CommandRunner.Run(ctx, c.ExternalBinPath, "get-binding", bindingID, string(serialisedBoshVMs), string(manifest))
+	}
+
+	if err != nil {
+		return binding, c.runError("get-binding", stdout, stderr, err)
+	}
+
+	// adapters built against an SDK that predates get-binding reject it as an unknown subcommand
+	if *exitCode == sdk.ErrorExitCode && strings.Contains(string(stderr), "unknown subcommand: get-binding") {
+		return binding, NewNotImplementedError("get-binding not implemented by service adapter")
+	}
+
+	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
+		logger.Printf(adapterFailedMessage(*exitCode, c.ExternalBinPath, stdout, stderr))
+		return binding, err
+	}
+
+	logger.Printf("service adapter ran get-binding successfully, stderr logs: %s", string(stderr))
+
+	if err := json.Unmarshal(stdout, &binding); err != nil {
+		return binding, invalidJSONError(c.ExternalBinPath, stdout, stderr, err)
+	}
+
+	return binding, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("external service adapter", func() {
+	const externalBinPath = "/thing"
+
+	var (
+		a                  *serviceadapter.Client
+		cmdRunner          *fakes.FakeCommandRunner
+		logs               *gbytes.Buffer
+		logger             *log.Logger
+		bindingID          string
+		deploymentTopology bosh.BoshVMs
+		manifest           []byte
+		secrets            map[string]string
+		dnsAddresses       map[string]string
+
+		binding       sdk.Binding
+		getBindingErr error
+	)
+
+	BeforeEach(func() {
+		logs = gbytes.NewBuffer()
+		logger = log.New(io.MultiWriter(GinkgoWriter, logs), "[unit-tests] ", log.LstdFlags)
+		cmdRunner = new(fakes.FakeCommandRunner)
+		a = &serviceadapter.Client{
+			CommandRunner:   cmdRunner,
+			ExternalBinPath: externalBinPath,
+		}
+		cmdRunner.RunReturns([]byte(`{"credentials":{"password":"secret"},"syslog_drain_url":"syslog://drain"}`), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+
+		bindingID = "the-binding"
+		deploymentTopology = bosh.BoshVMs{"redis": []string{"10.0.0.1"}}
+		manifest = []byte("property: ((/secret/path))")
+		secrets = map[string]string{"/secret/path": "s3cr3t"}
+		dnsAddresses = map[string]string{"config-1": "some.dns.bosh"}
+	})
+
+	JustBeforeEach(func() {
+		binding, getBindingErr = a.GetBinding(context.Background(), bindingID, deploymentTopology, manifest, secrets, dnsAddresses, logger)
+	})
+
+	When("UsingStdin is set to false", func() {
+		It("invokes external executable with params to get the binding", func() {
+			Expect(cmdRunner.RunCallCount()).To(Equal(1))
+			_, argsPassed := cmdRunner.RunArgsForCall(0)
+			Expect(argsPassed).To(ConsistOf(externalBinPath, "get-binding", bindingID, toJson(deploymentTopology), string(manifest)))
+		})
+
+		It("returns the binding", func() {
+			Expect(getBindingErr).NotTo(HaveOccurred())
+			Expect(binding).To(Equal(sdk.Binding{
+				Credentials:    map[string]interface{}{"password": "secret"},
+				SyslogDrainURL: "syslog://drain",
+			}))
+		})
+
+		Context("when the external adapter fails with no exit code", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns(nil, nil, nil, errors.New("oops"))
+			})
+
+			It("returns an error", func() {
+				Expect(getBindingErr).To(MatchError("an error occurred running external service adapter at /thing: 'oops'. stdout: '', stderr: ''"))
+			})
+		})
+
+		Context("when the external adapter fails", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns([]byte("I'm stdout"), []byte("I'm stderr"), intPtr(sdk.ErrorExitCode), nil)
+			})
+
+			It("returns an UnknownFailureError", func() {
+				commandError, ok := getBindingErr.(serviceadapter.UnknownFailureError)
+				Expect(ok).To(BeTrue(), "error should be a Generic Error")
+				Expect(commandError.Error()).To(Equal("I'm stdout"))
+			})
+
+			It("logs a message to the operator", func() {
+				Expect(logs).To(gbytes.Say("external service adapter exited with 1 at /thing: stdout: 'I'm stdout', stderr: 'I'm stderr'\n"))
+			})
+		})
+
+		Context("when the external adapter fails with exit code 10", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns([]byte("I'm stdout"), []byte("I'm stderr"), intPtr(sdk.NotImplementedExitCode), nil)
+			})
+
+			It("returns a NotImplementedError", func() {
+				Expect(getBindingErr).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+			})
+		})
+
+		Context("when the external adapter does not know the get-binding subcommand", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns(nil, []byte("[odb-sdk] unknown subcommand: get-binding. The following commands are supported: create-binding"), intPtr(sdk.ErrorExitCode), nil)
+			})
+
+			It("returns a NotImplementedError", func() {
+				Expect(getBindingErr).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+			})
+		})
+
+		Context("when the external adapter fails with exit code 41", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns([]byte("I'm stdout"), []byte("I'm stderr"), intPtr(sdk.BindingNotFoundErrorExitCode), nil)
+			})
+
+			It("returns a BindingNotFoundError", func() {
+				Expect(getBindingErr).To(BeAssignableToTypeOf(serviceadapter.BindingNotFoundError{}))
+				Expect(getBindingErr.Error()).NotTo(ContainSubstring("stdout"))
+			})
+		})
+
+		Context("when the external adapter outputs invalid JSON", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns([]byte("not json"), []byte("I'm stderr"), intPtr(serviceadapter.SuccessExitCode), nil)
+			})
+
+			It("returns an error", func() {
+				Expect(getBindingErr).To(MatchError(ContainSubstring("external service adapter returned invalid JSON at /thing: stdout: 'not json'")))
+			})
+		})
+	})
+
+	When("UsingStdin is set to true", func() {
+		BeforeEach(func() {
+			a.UsingStdin = true
+
+			cmdRunner.RunWithInputParamsReturns([]byte(`{"credentials":{"password":"secret"}}`), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+		})
+
+		It("invokes external executable with params to get the binding", func() {
+			Expect(cmdRunner.RunCallCount()).To(Equal(0))
+			Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
+			_, actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+			Expect(argsPassed).To(ConsistOf(externalBinPath, "get-binding"))
+
+			serialisedInputParams, err := json.Marshal(actualInputParams)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serialisedInputParams).To(MatchJSON(toJson(map[string]interface{}{
+				"get_binding": map[string]string{
+					"binding_id":    bindingID,
+					"bosh_vms":      toJson(deploymentTopology),
+					"manifest":      string(manifest),
+					"secrets":       toJson(secrets),
+					"dns_addresses": toJson(dnsAddresses),
+				},
+			})))
+		})
+
+		It("returns the binding", func() {
+			Expect(getBindingErr).NotTo(HaveOccurred())
+			Expect(binding.Credentials).To(Equal(map[string]interface{}{"password": "secret"}))
+		})
+
+		Context("when the external adapter does not know the get-binding subcommand", func() {
+			BeforeEach(func() {
+				cmdRunner.RunWithInputParamsReturns(nil, []byte("[odb-sdk] unknown subcommand: get-binding."), intPtr(sdk.ErrorExitCode), nil)
+			})
+
+			It("returns a NotImplementedError", func() {
+				Expect(getBindingErr).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+			})
+		})
+	})
+})
@@ -2,11 +2,11 @@
 package fakes
 
 import (
-	log "log"
-	sync "sync"
+	"log"
+	"sync"
 
-	boshdirector "github.com/pivotal-cf/on-demand-service-broker/boshdirector"
-	task "github.com/pivotal-cf/on-demand-service-broker/task"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
 type FakeBoshClient struct {
@@ -26,6 +26,19 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
+	GetCloudConfigsStub        func(*log.Logger) ([]boshdirector.BoshConfig, error)
+	getCloudConfigsMutex       sync.RWMutex
+	getCloudConfigsArgsForCall []struct {
+		arg1 *log.Logger
+	}
+	getCloudConfigsReturns struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}
+	getCloudConfigsReturnsOnCall map[int]struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}
 	GetConfigsStub        func(string, *log.Logger) ([]boshdirector.BoshConfig, error)
 	getConfigsMutex       sync.RWMutex
 	getConfigsArgsForCall []struct {
@@ -56,6 +69,20 @@ type FakeBoshClient struct {
 		result2 bool
 		result3 error
 	}
+	GetReleaseJobsStub        func([]boshdirector.ReleaseVersion, *log.Logger) (map[boshdirector.ReleaseVersion][]string, error)
+	getReleaseJobsMutex       sync.RWMutex
+	getReleaseJobsArgsForCall []struct {
+		arg1 []boshdirector.ReleaseVersion
+		arg2 *log.Logger
+	}
+	getReleaseJobsReturns struct {
+		result1 map[boshdirector.ReleaseVersion][]string
+		result2 error
+	}
+	getReleaseJobsReturnsOnCall map[int]struct {
+		result1 map[boshdirector.ReleaseVersion][]string
+		result2 error
+	}
 	GetTasksStub        func(string, *log.Logger) (boshdirector.BoshTasks, error)
 	getTasksMutex       sync.RWMutex
 	getTasksArgsForCall []struct {
@@ -118,15 +145,16 @@ func (fake *FakeBoshClient) Deploy(arg1 []byte, arg2 string, arg3 *log.Logger, a
 		arg3 *log.Logger
 		arg4 *boshdirector.AsyncTaskReporter
 	}{arg1Copy, arg2, arg3, arg4})
+	stub := fake.DeployStub
+	fakeReturns := fake.deployReturns
 	fake.recordInvocation("Deploy", []interface{}{arg1Copy, arg2, arg3, arg4})
 	fake.deployMutex.Unlock()
-	if fake.DeployStub != nil {
-		return fake.DeployStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.deployReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -175,6 +203,70 @@ func (fake *FakeBoshClient) DeployReturnsOnCall(i int, result1 int, result2 erro
 	}{result1, result2}
 }
 
+func (fake *FakeBoshClient) GetCloudConfigs(arg1 *log.Logger) ([]boshdirector.BoshConfig, error) {
+	fake.getCloudConfigsMutex.Lock()
+	ret, specificReturn := fake.getCloudConfigsReturnsOnCall[len(fake.getCloudConfigsArgsForCall)]
+	fake.getCloudConfigsArgsForCall = append(fake.getCloudConfigsArgsForCall, struct {
+		arg1 *log.Logger
+	}{arg1})
+	stub := fake.GetCloudConfigsStub
+	fakeReturns := fake.getCloudConfigsReturns
+	fake.recordInvocation("GetCloudConfigs", []interface{}{arg1})
+	fake.getCloudConfigsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) GetCloudConfigsCallCount() int {
+	fake.getCloudConfigsMutex.RLock()
+	defer fake.getCloudConfigsMutex.RUnlock()
+	return len(fake.getCloudConfigsArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetCloudConfigsCalls(stub func(*log.Logger) ([]boshdirector.BoshConfig, error)) {
+	fake.getCloudConfigsMutex.Lock()
+	defer fake.getCloudConfigsMutex.Unlock()
+	fake.GetCloudConfigsStub = stub
+}
+
+func (fake *FakeBoshClient) GetCloudConfigsArgsForCall(i int) *log.Logger {
+	fake.getCloudConfigsMutex.RLock()
+	defer fake.getCloudConfigsMutex.RUnlock()
+	argsForCall := fake.getCloudConfigsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBoshClient) GetCloudConfigsReturns(result1 []boshdirector.BoshConfig, result2 error) {
+	fake.getCloudConfigsMutex.Lock()
+	defer fake.getCloudConfigsMutex.Unlock()
+	fake.GetCloudConfigsStub = nil
+	fake.getCloudConfigsReturns = struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetCloudConfigsReturnsOnCall(i int, result1 []boshdirector.BoshConfig, result2 error) {
+	fake.getCloudConfigsMutex.Lock()
+	defer fake.getCloudConfigsMutex.Unlock()
+	fake.GetCloudConfigsStub = nil
+	if fake.getCloudConfigsReturnsOnCall == nil {
+		fake.getCloudConfigsReturnsOnCall = make(map[int]struct {
+			result1 []boshdirector.BoshConfig
+			result2 error
+		})
+	}
+	fake.getCloudConfigsReturnsOnCall[i] = struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBoshClient) GetConfigs(arg1 string, arg2 *log.Logger) ([]boshdirector.BoshConfig, error) {
 	fake.getConfigsMutex.Lock()
 	ret, specificReturn := fake.getConfigsReturnsOnCall[len(fake.getConfigsArgsForCall)]
@@ -182,15 +274,16 @@ func (fake *FakeBoshClient) GetConfigs(arg1 string, arg2 *log.Logger) ([]boshdir
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetConfigsStub
+	fakeReturns := fake.getConfigsReturns
 	fake.recordInvocation("GetConfigs", []interface{}{arg1, arg2})
 	fake.getConfigsMutex.Unlock()
-	if fake.GetConfigsStub != nil {
-		return fake.GetConfigsStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getConfigsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -246,15 +339,16 @@ func (fake *FakeBoshClient) GetDeployment(arg1 string, arg2 *log.Logger) ([]byte
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetDeploymentStub
+	fakeReturns := fake.getDeploymentReturns
 	fake.recordInvocation("GetDeployment", []interface{}{arg1, arg2})
 	fake.getDeploymentMutex.Unlock()
-	if fake.GetDeploymentStub != nil {
-		return fake.GetDeploymentStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.getDeploymentReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -306,6 +400,76 @@ func (fake *FakeBoshClient) GetDeploymentReturnsOnCall(i int, result1 []byte, re
 	}{result1, result2, result3}
 }
 
+func (fake *FakeBoshClient) GetReleaseJobs(arg1 []boshdirector.ReleaseVersion, arg2 *log.Logger) (map[boshdirector.ReleaseVersion][]string, error) {
+	var arg1Copy []boshdirector.ReleaseVersion
+	if arg1 != nil {
+		arg1Copy = make([]boshdirector.ReleaseVersion, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.getReleaseJobsMutex.Lock()
+	ret, specificReturn := fake.getReleaseJobsReturnsOnCall[len(fake.getReleaseJobsArgsForCall)]
+	fake.getReleaseJobsArgsForCall = append(fake.getReleaseJobsArgsForCall, struct {
+		arg1 []boshdirector.ReleaseVersion
+		arg2 *log.Logger
+	}{arg1Copy, arg2})
+	stub := fake.GetReleaseJobsStub
+	fakeReturns := fake.getReleaseJobsReturns
+	fake.recordInvocation("GetReleaseJobs", []interface{}{arg1Copy, arg2})
+	fake.getReleaseJobsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) GetReleaseJobsCallCount() int {
+	fake.getReleaseJobsMutex.RLock()
+	defer fake.getReleaseJobsMutex.RUnlock()
+	return len(fake.getReleaseJobsArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetReleaseJobsCalls(stub func([]boshdirector.ReleaseVersion, *log.Logger) (map[boshdirector.ReleaseVersion][]string, error)) {
+	fake.getReleaseJobsMutex.Lock()
+	defer fake.getReleaseJobsMutex.Unlock()
+	fake.GetReleaseJobsStub = stub
+}
+
+func (fake *FakeBoshClient) GetReleaseJobsArgsForCall(i int) ([]boshdirector.ReleaseVersion, *log.Logger) {
+	fake.getReleaseJobsMutex.RLock()
+	defer fake.getReleaseJobsMutex.RUnlock()
+	argsForCall := fake.getReleaseJobsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBoshClient) GetReleaseJobsReturns(result1 map[boshdirector.ReleaseVersion][]string, result2 error) {
+	fake.getReleaseJobsMutex.Lock()
+	defer fake.getReleaseJobsMutex.Unlock()
+	fake.GetReleaseJobsStub = nil
+	fake.getReleaseJobsReturns = struct {
+		result1 map[boshdirector.ReleaseVersion][]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetReleaseJobsReturnsOnCall(i int, result1 map[boshdirector.ReleaseVersion][]string, result2 error) {
+	fake.getReleaseJobsMutex.Lock()
+	defer fake.getReleaseJobsMutex.Unlock()
+	fake.GetReleaseJobsStub = nil
+	if fake.getReleaseJobsReturnsOnCall == nil {
+		fake.getReleaseJobsReturnsOnCall = make(map[int]struct {
+			result1 map[boshdirector.ReleaseVersion][]string
+			result2 error
+		})
+	}
+	fake.getReleaseJobsReturnsOnCall[i] = struct {
+		result1 map[boshdirector.ReleaseVersion][]string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBoshClient) GetTasks(arg1 string, arg2 *log.Logger) (boshdirector.BoshTasks, error) {
 	fake.getTasksMutex.Lock()
 	ret, specificReturn := fake.getTasksReturnsOnCall[len(fake.getTasksArgsForCall)]
@@ -313,15 +477,16 @@ func (fake *FakeBoshClient) GetTasks(arg1 string, arg2 *log.Logger) (boshdirecto
 		arg1 string
 		arg2 *log.Logger
 	}{arg1, arg2})
+	stub := fake.GetTasksStub
+	fakeReturns := fake.getTasksReturns
 	fake.recordInvocation("GetTasks", []interface{}{arg1, arg2})
 	fake.getTasksMutex.Unlock()
-	if fake.GetTasksStub != nil {
-		return fake.GetTasksStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getTasksReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -379,15 +544,16 @@ func (fake *FakeBoshClient) Recreate(arg1 string, arg2 string, arg3 *log.Logger,
 		arg3 *log.Logger
 		arg4 *boshdirector.AsyncTaskReporter
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecreateStub
+	fakeReturns := fake.recreateReturns
 	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
 	fake.recreateMutex.Unlock()
-	if fake.RecreateStub != nil {
-		return fake.RecreateStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.recreateReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -450,15 +616,16 @@ func (fake *FakeBoshClient) UpdateConfig(arg1 string, arg2 string, arg3 []byte,
 		arg3 []byte
 		arg4 *log.Logger
 	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.UpdateConfigStub
+	fakeReturns := fake.updateConfigReturns
 	fake.recordInvocation("UpdateConfig", []interface{}{arg1, arg2, arg3Copy, arg4})
 	fake.updateConfigMutex.Unlock()
-	if fake.UpdateConfigStub != nil {
-		return fake.UpdateConfigStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.updateConfigReturns
 	return fakeReturns.result1
 }
 
@@ -509,10 +676,14 @@ func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.deployMutex.RLock()
 	defer fake.deployMutex.RUnlock()
+	fake.getCloudConfigsMutex.RLock()
+	defer fake.getCloudConfigsMutex.RUnlock()
 	fake.getConfigsMutex.RLock()
 	defer fake.getConfigsMutex.RUnlock()
 	fake.getDeploymentMutex.RLock()
 	defer fake.getDeploymentMutex.RUnlock()
+	fake.getReleaseJobsMutex.RLock()
+	defer fake.getReleaseJobsMutex.RUnlock()
 	fake.getTasksMutex.RLock()
 	defer fake.getTasksMutex.RUnlock()
 	fake.recreateMutex.RLock()
@@ -0,0 +1,170 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	"gopkg.in/yaml.v2"
+)
+
+var odbSecretRef = regexp.MustCompile(fmt.Sprintf(`\(\(%s:([^)]*)\)\)`, serviceadapter.ODBSecretPrefix))
+
+type cloudConfig struct {
+	Networks  []struct{ Name string } `yaml:"networks"`
+	VMTypes   []struct{ Name string } `yaml:"vm_types"`
+	DiskTypes []struct{ Name string } `yaml:"disk_types"`
+}
+
+// validateManifest checks that BOSH can deploy the manifest, so that a problem
+// with the manifest generated by the service adapter is reported when the
+// operation is requested, rather than by a failed BOSH task. The configs are
+// those that will be updated along with the deployment.
+func (d Deployer) validateManifest(deploymentName string, manifest []byte, configs map[string]string, logger *log.Logger) error {
+	var boshManifest bosh.BoshManifest
+	if err := yaml.Unmarshal(manifest, &boshManifest); err != nil {
+		return fmt.Errorf("error validating manifest, unable to unmarshal manifest: %s", err)
+	}
+
+	var problems []string
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for _, match := range odbSecretRef.FindAllStringSubmatch(string(manifest), -1) {
+		report("odb_secret %s is not one of the secrets managed by the broker", match[1])
+	}
+
+	releaseJobs, err := d.releaseJobs(boshManifest, report, logger)
+	if err != nil {
+		return err
+	}
+
+	cloudConfig, err := d.cloudConfig(deploymentName, configs, logger)
+	if err != nil {
+		return err
+	}
+
+	stemcells := map[string]bool{}
+	for _, stemcell := range boshManifest.Stemcells {
+		stemcells[stemcell.Alias] = true
+	}
+
+	for _, instanceGroup := range boshManifest.InstanceGroups {
+		if !stemcells[instanceGroup.Stemcell] {
+			report("instance group %s uses stemcell %s, which the manifest does not declare", instanceGroup.Name, instanceGroup.Stemcell)
+		}
+
+		for _, job := range instanceGroup.Jobs {
+			jobs, declared := releaseJobs[job.Release]
+			if !declared {
+				report("job %s of instance group %s is from release %s, which the manifest does not declare", job.Name, instanceGroup.Name, job.Release)
+			} else if jobs != nil && !jobs[job.Name] {
+				report("job %s of instance group %s is not in release %s", job.Name, instanceGroup.Name, job.Release)
+			}
+		}
+
+		for _, network := range instanceGroup.Networks {
+			if !cloudConfig["networks"][network.Name] {
+				report("network %s of instance group %s is not in the cloud config", network.Name, instanceGroup.Name)
+			}
+		}
+		if instanceGroup.VMType != "" && !cloudConfig["vm_types"][instanceGroup.VMType] {
+			report("vm_type %s of instance group %s is not in the cloud config", instanceGroup.VMType, instanceGroup.Name)
+		}
+		if instanceGroup.PersistentDiskType != "" && !cloudConfig["disk_types"][instanceGroup.PersistentDiskType] {
+			report("persistent_disk_type %s of instance group %s is not in the cloud config", instanceGroup.PersistentDiskType, instanceGroup.Name)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	logger.Printf("manifest generated for deployment %s is invalid: %s\n", deploymentName, strings.Join(problems, "; "))
+	return broker.NewDisplayableError(
+		fmt.Errorf("The service adapter generated an invalid manifest: %s", strings.Join(problems, "; ")),
+		fmt.Errorf("invalid manifest for deployment %s: %s", deploymentName, strings.Join(problems, "; ")),
+	)
+}
+
+// releaseJobs returns the jobs of each release declared by the manifest. The
+// jobs of a release that has not been uploaded are nil, and reported.
+func (d Deployer) releaseJobs(manifest bosh.BoshManifest, report func(string, ...interface{}), logger *log.Logger) (map[string]map[string]bool, error) {
+	var releaseVersions []boshdirector.ReleaseVersion
+	for _, release := range manifest.Releases {
+		releaseVersions = append(releaseVersions, boshdirector.ReleaseVersion{Name: release.Name, Version: release.Version})
+	}
+
+	uploadedJobs, err := d.boshClient.GetReleaseJobs(releaseVersions, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error validating manifest: %s", err)
+	}
+
+	releaseJobs := map[string]map[string]bool{}
+	for _, release := range manifest.Releases {
+		jobNames, found := uploadedJobs[boshdirector.ReleaseVersion{Name: release.Name, Version: release.Version}]
+		if !found {
+			report("release %s version %s has not been uploaded", release.Name, release.Version)
+			releaseJobs[release.Name] = nil
+			continue
+		}
+
+		jobs := map[string]bool{}
+		for _, name := range jobNames {
+			jobs[name] = true
+		}
+		releaseJobs[release.Name] = jobs
+	}
+	return releaseJobs, nil
+}
+
+// cloudConfig returns the names of the networks, vm_types and disk_types of
+// the cloud configs that the deployment will be deployed with, including the
+// one the service adapter generated.
+func (d Deployer) cloudConfig(deploymentName string, configs map[string]string, logger *log.Logger) (map[string]map[string]bool, error) {
+	boshConfigs, err := d.boshClient.GetCloudConfigs(logger)
+	if err != nil {
+		return nil, fmt.Errorf("error validating manifest: %s", err)
+	}
+
+	var contents []string
+	for _, boshConfig := range boshConfigs {
+		if _, replaced := configs["cloud"]; replaced && boshConfig.Name == deploymentName {
+			continue
+		}
+		contents = append(contents, boshConfig.Content)
+	}
+	if content, found := configs["cloud"]; found {
+		contents = append(contents, content)
+	}
+
+	names := map[string]map[string]bool{"networks": {}, "vm_types": {}, "disk_types": {}}
+	for _, content := range contents {
+		var config cloudConfig
+		if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+			return nil, fmt.Errorf("error validating manifest, unable to unmarshal cloud config: %s", err)
+		}
+		for _, network := range config.Networks {
+			names["networks"][network.Name] = true
+		}
+		for _, vmType := range config.VMTypes {
+			names["vm_types"][vmType.Name] = true
+		}
+		for _, diskType := range config.DiskTypes {
+			names["disk_types"][diskType.Name] = true
+		}
+	}
+	return names, nil
+}
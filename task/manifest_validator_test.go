@@ -0,0 +1,181 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+	"github.com/pivotal-cf/on-demand-service-broker/task/fakes"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("Manifest validation", func() {
+	const validManifest = `name: some-deployment-name
+releases:
+- name: redis
+  version: 1.2.3
+stemcells:
+- alias: default
+  os: ubuntu-xenial
+  version: "250.1"
+instance_groups:
+- name: redis-server
+  instances: 1
+  stemcell: default
+  vm_type: small
+  persistent_disk_type: ten
+  networks:
+  - name: services
+  jobs:
+  - name: redis-server
+    release: redis
+`
+
+	var (
+		boshClient        *fakes.FakeBoshClient
+		manifestGenerator *fakes.FakeManifestGenerator
+		deployer          task.Deployer
+		generated         serviceadapter.MarshalledGenerateManifest
+	)
+
+	deploy := func() error {
+		manifestGenerator.GenerateManifestReturns(generated, nil)
+		_, _, err := deployer.Create(context.Background(), deploymentName, existingPlanID, map[string]interface{}{}, "", logger)
+		return err
+	}
+
+	BeforeEach(func() {
+		boshClient = new(fakes.FakeBoshClient)
+		manifestGenerator = new(fakes.FakeManifestGenerator)
+		deployer = task.NewDeployer(boshClient, manifestGenerator, nil, nil)
+		deployer.ValidateManifests = true
+
+		generated = serviceadapter.MarshalledGenerateManifest{Manifest: validManifest}
+		boshClient.GetReleaseJobsReturns(map[boshdirector.ReleaseVersion][]string{
+			{Name: "redis", Version: "1.2.3"}: {"redis-server", "health-check"},
+		}, nil)
+		boshClient.GetCloudConfigsReturns([]boshdirector.BoshConfig{
+			{Type: "cloud", Name: "default", Content: "networks: [{name: services}]\nvm_types: [{name: small}]"},
+			{Type: "cloud", Name: "disks", Content: "disk_types: [{name: ten}]"},
+		}, nil)
+		boshClient.DeployReturns(42, nil)
+	})
+
+	It("deploys a valid manifest", func() {
+		Expect(deploy()).To(Succeed())
+
+		Expect(boshClient.DeployCallCount()).To(Equal(1))
+		Expect(boshClient.GetReleaseJobsCallCount()).To(Equal(1))
+		releaseVersions, _ := boshClient.GetReleaseJobsArgsForCall(0)
+		Expect(releaseVersions).To(Equal([]boshdirector.ReleaseVersion{{Name: "redis", Version: "1.2.3"}}))
+	})
+
+	It("does not contact BOSH unless manifest validation is enabled", func() {
+		deployer.ValidateManifests = false
+		generated.Manifest = "name: some-deployment-name\nreleases: [{name: unknown, version: 1}]"
+
+		Expect(deploy()).To(Succeed())
+
+		Expect(boshClient.GetReleaseJobsCallCount()).To(Equal(0))
+		Expect(boshClient.GetCloudConfigsCallCount()).To(Equal(0))
+	})
+
+	It("reports every problem with the manifest without deploying", func() {
+		generated.Manifest = `name: some-deployment-name
+releases:
+- name: redis
+  version: 1.2.3
+stemcells:
+- alias: default
+  os: ubuntu-xenial
+  version: "250.1"
+instance_groups:
+- name: redis-server
+  instances: 1
+  stemcell: trusty
+  vm_type: huge
+  persistent_disk_type: hundred
+  networks:
+  - name: default
+  jobs:
+  - name: redis-sentinel
+    release: redis
+  - name: syslog-forwarder
+    release: syslog
+properties:
+  password: ((odb_secret:admin_password))
+`
+
+		err := deploy()
+
+		Expect(err).To(BeAssignableToTypeOf(broker.DisplayableError{}))
+		Expect(err.(broker.DisplayableError).ErrorForCFUser()).To(MatchError(
+			"The service adapter generated an invalid manifest: " +
+				"odb_secret admin_password is not one of the secrets managed by the broker; " +
+				"instance group redis-server uses stemcell trusty, which the manifest does not declare; " +
+				"job redis-sentinel of instance group redis-server is not in release redis; " +
+				"job syslog-forwarder of instance group redis-server is from release syslog, which the manifest does not declare; " +
+				"network default of instance group redis-server is not in the cloud config; " +
+				"vm_type huge of instance group redis-server is not in the cloud config; " +
+				"persistent_disk_type hundred of instance group redis-server is not in the cloud config",
+		))
+		Expect(boshClient.DeployCallCount()).To(Equal(0))
+	})
+
+	It("reports releases that have not been uploaded", func() {
+		boshClient.GetReleaseJobsReturns(map[boshdirector.ReleaseVersion][]string{}, nil)
+
+		err := deploy()
+
+		Expect(err).To(MatchError(ContainSubstring("invalid manifest for deployment some-deployment-name: release redis version 1.2.3 has not been uploaded")))
+		Expect(err.Error()).NotTo(ContainSubstring("is not in release redis"))
+	})
+
+	It("validates against the cloud config generated by the service adapter", func() {
+		boshClient.GetCloudConfigsReturns([]boshdirector.BoshConfig{
+			{Type: "cloud", Name: "default", Content: "networks: [{name: services}]\nvm_types: [{name: small}]"},
+			{Type: "cloud", Name: deploymentName, Content: "disk_types: [{name: ten}]"},
+		}, nil)
+		generated.Configs = serviceadapter.BOSHConfigs{"cloud": "disk_types: [{name: twenty}]"}
+
+		err := deploy()
+
+		Expect(err).To(MatchError(ContainSubstring("persistent_disk_type ten of instance group redis-server is not in the cloud config")))
+	})
+
+	It("does not store secrets or update configs when the manifest is invalid", func() {
+		bulkSetter := new(fakes.FakeBulkSetter)
+		odbSecrets := new(fakes.FakeODBSecrets)
+		odbSecrets.ReplaceODBRefsStub = func(m string, s []broker.ManifestSecret) string {
+			return m
+		}
+		deployer = task.NewDeployer(boshClient, manifestGenerator, odbSecrets, bulkSetter)
+		deployer.ValidateManifests = true
+		boshClient.GetReleaseJobsReturns(map[boshdirector.ReleaseVersion][]string{}, nil)
+		generated.Configs = serviceadapter.BOSHConfigs{"cloud": "networks: [{name: services}]"}
+
+		Expect(deploy()).To(HaveOccurred())
+
+		Expect(bulkSetter.BulkSetCallCount()).To(Equal(0))
+		Expect(boshClient.UpdateConfigCallCount()).To(Equal(0))
+	})
+
+	It("returns an error when the director cannot be queried", func() {
+		boshClient.GetCloudConfigsReturns(nil, errors.New("director unavailable"))
+
+		err := deploy()
+
+		Expect(err).To(MatchError("error validating manifest: director unavailable"))
+		Expect(boshClient.DeployCallCount()).To(Equal(0))
+	})
+})
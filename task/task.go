@@ -7,6 +7,7 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 	"gopkg.in/yaml.v2"
@@ -28,11 +30,14 @@ type BoshClient interface {
 	GetDeployment(name string, logger *log.Logger) ([]byte, bool, error)
 	GetConfigs(configName string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
 	UpdateConfig(configType, configName string, configContent []byte, logger *log.Logger) error
+	GetReleaseJobs(releaseVersions []boshdirector.ReleaseVersion, logger *log.Logger) (map[boshdirector.ReleaseVersion][]string, error)
+	GetCloudConfigs(logger *log.Logger) ([]boshdirector.BoshConfig, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_manifest_generator.go . ManifestGenerator
 type ManifestGenerator interface {
 	GenerateManifest(
+		ctx context.Context,
 		deploymentName,
 		planID string,
 		requestParams map[string]interface{},
@@ -63,6 +68,14 @@ type Deployer struct {
 	odbSecrets         ODBSecrets
 	bulkSetter         BulkSetter
 	DisableBoshConfigs bool
+	ValidateManifests  bool
+	// RecordInstanceDetails records the plan and retrievable parameters of
+	// the instance in its manifest, so that the instance can be retrieved.
+	// Without it the generated manifest is deployed as it is.
+	RecordInstanceDetails bool
+	// RetrievableParameters lists, by plan ID, the arbitrary parameters that
+	// are recorded in the manifest along with the plan.
+	RetrievableParameters map[string][]string
 }
 
 func NewDeployer(boshClient BoshClient, manifestGenerator ManifestGenerator, odbSecrets ODBSecrets, bulkSetter BulkSetter) Deployer {
@@ -74,16 +87,16 @@ func NewDeployer(boshClient BoshClient, manifestGenerator ManifestGenerator, odb
 	}
 }
 
-func (d Deployer) Create(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (d Deployer) Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
 	err := d.assertNoOperationsInProgress(deploymentName, logger)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	return d.doDeploy(deploymentName, planID, "create", requestParams, nil, nil, boshContextID, nil, nil, logger)
+	return d.doDeploy(ctx, deploymentName, planID, "create", requestParams, nil, nil, boshContextID, nil, nil, logger)
 }
 
-func (d Deployer) Upgrade(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (d Deployer) Upgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
 	err := d.assertNoOperationsInProgress(deploymentName, logger)
 	if err != nil {
 		return 0, nil, err
@@ -102,7 +115,7 @@ func (d Deployer) Upgrade(deploymentName, planID string, previousPlanID *string,
 		}
 	}
 
-	return d.doDeploy(deploymentName, planID, "upgrade", nil, oldManifest, previousPlanID, boshContextID, nil, oldConfigs, logger)
+	return d.doDeploy(ctx, deploymentName, planID, "upgrade", nil, oldManifest, previousPlanID, boshContextID, nil, oldConfigs, logger)
 }
 
 func (d Deployer) Recreate(
@@ -127,6 +140,7 @@ func (d Deployer) Recreate(
 }
 
 func (d Deployer) Update(
+	ctx context.Context,
 	deploymentName,
 	planID string,
 	requestParams map[string]interface{},
@@ -152,11 +166,56 @@ func (d Deployer) Update(
 			return 0, nil, err
 		}
 	}
-	if err := d.checkForPendingChanges(deploymentName, previousPlanID, oldManifest, oldSecretsMap, oldConfigs, logger); err != nil {
+	if err := d.checkForPendingChanges(ctx, deploymentName, previousPlanID, oldManifest, oldSecretsMap, oldConfigs, logger); err != nil {
 		return 0, nil, err
 	}
 
-	return d.doDeploy(deploymentName, planID, "update", requestParams, oldManifest, previousPlanID, boshContextID, oldSecretsMap, oldConfigs, logger)
+	return d.doDeploy(ctx, deploymentName, planID, "update", requestParams, oldManifest, previousPlanID, boshContextID, oldSecretsMap, oldConfigs, logger)
+}
+
+// PreviewUpgrade generates the manifest and configs that an upgrade of the
+// deployment would apply and returns how they differ from the deployed ones.
+// Nothing is deployed and no secrets are stored.
+func (d Deployer) PreviewUpgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, logger *log.Logger) (broker.UpgradePreview, error) {
+	oldManifest, err := d.getDeploymentManifest(deploymentName, logger)
+	if err != nil {
+		return broker.UpgradePreview{}, err
+	}
+
+	var oldConfigs map[string]string
+	if !d.DisableBoshConfigs {
+		oldConfigs, err = d.getConfigMap(deploymentName, logger)
+		if err != nil {
+			return broker.UpgradePreview{}, err
+		}
+	}
+
+	manifest, configs, _, err := d.generateManifest(ctx, deploymentName, planID, nil, oldManifest, previousPlanID, nil, oldConfigs, logger)
+	if err != nil {
+		return broker.UpgradePreview{}, err
+	}
+
+	preview := broker.UpgradePreview{}
+	preview.ManifestChanges, err = manifestdiff.Diff(oldManifest, manifest)
+	if err != nil {
+		return broker.UpgradePreview{}, fmt.Errorf("error comparing manifests: %s", err)
+	}
+
+	for configType, configContent := range configs {
+		changes, err := manifestdiff.Diff([]byte(oldConfigs[configType]), []byte(configContent))
+		if err != nil {
+			return broker.UpgradePreview{}, fmt.Errorf("error comparing %s configs: %s", configType, err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		if preview.ConfigChanges == nil {
+			preview.ConfigChanges = map[string][]manifestdiff.Change{}
+		}
+		preview.ConfigChanges[configType] = changes
+	}
+
+	return preview, nil
 }
 
 func (d Deployer) getDeploymentManifest(deploymentName string, logger *log.Logger) ([]byte, error) {
@@ -201,6 +260,7 @@ func (d Deployer) assertNoOperationsInProgress(deploymentName string, logger *lo
 }
 
 func (d Deployer) checkForPendingChanges(
+	ctx context.Context,
 	deploymentName string,
 	previousPlanID *string,
 	rawOldManifest RawBoshManifest,
@@ -208,7 +268,7 @@ func (d Deployer) checkForPendingChanges(
 	previousConfigs map[string]string,
 	logger *log.Logger,
 ) error {
-	regeneratedManifestContent, err := d.manifestGenerator.GenerateManifest(deploymentName, *previousPlanID, map[string]interface{}{}, rawOldManifest, previousPlanID, oldSecretsMap, previousConfigs, logger)
+	regeneratedManifestContent, err := d.manifestGenerator.GenerateManifest(ctx, deploymentName, *previousPlanID, map[string]interface{}{}, rawOldManifest, previousPlanID, oldSecretsMap, previousConfigs, logger)
 	if err != nil {
 		return err
 	}
@@ -230,13 +290,38 @@ func (d Deployer) checkForPendingChanges(
 	pendingChanges := !manifestsSame
 
 	if pendingChanges {
-		return broker.NewPendingChangesNotAppliedError(errors.New("There are pending changes"))
+		changes := manifestChanges(oldManifest, regeneratedManifest, logger)
+		return broker.NewPendingChangesNotAppliedError(errors.New("There are pending changes"), changes)
 	}
 
 	return nil
 }
 
+// manifestChanges lists the fields that differ between the manifests as they
+// were compared, so that the update block is left out. The changes only
+// explain the failure, so an error computing them is logged and ignored.
+func manifestChanges(oldManifest, regeneratedManifest bosh.BoshManifest, logger *log.Logger) []manifestdiff.Change {
+	oldContent, err := yaml.Marshal(oldManifest)
+	if err != nil {
+		logger.Printf("unable to list pending changes: %s", err)
+		return nil
+	}
+	regeneratedContent, err := yaml.Marshal(regeneratedManifest)
+	if err != nil {
+		logger.Printf("unable to list pending changes: %s", err)
+		return nil
+	}
+
+	changes, err := manifestdiff.Diff(oldContent, regeneratedContent)
+	if err != nil {
+		logger.Printf("unable to list pending changes: %s", err)
+		return nil
+	}
+	return changes
+}
+
 func (d Deployer) doDeploy(
+	ctx context.Context,
 	deploymentName,
 	planID string,
 	operationType string,
@@ -249,25 +334,25 @@ func (d Deployer) doDeploy(
 	logger *log.Logger,
 ) (int, []byte, error) {
 
-	generateManifestOutput, err := d.manifestGenerator.GenerateManifest(deploymentName, planID, requestParams, oldManifest, previousPlanID, oldSecretsMap, previousConfigs, logger)
+	manifest, configs, secrets, err := d.generateManifest(ctx, deploymentName, planID, requestParams, oldManifest, previousPlanID, oldSecretsMap, previousConfigs, logger)
 	if err != nil {
 		return 0, nil, err
 	}
-	manifest := generateManifestOutput.Manifest
 
-	if d.bulkSetter != nil && !reflect.ValueOf(d.bulkSetter).IsNil() {
-		secrets := d.odbSecrets.GenerateSecretPaths(deploymentName, manifest, generateManifestOutput.ODBManagedSecrets)
-		if err = d.bulkSetter.BulkSet(secrets); err != nil {
+	if d.ValidateManifests {
+		if err := d.validateManifest(deploymentName, manifest, configs, logger); err != nil {
 			return 0, nil, err
 		}
-		manifest = d.odbSecrets.ReplaceODBRefs(generateManifestOutput.Manifest, secrets)
 	}
 
-	if d.DisableBoshConfigs && len(generateManifestOutput.Configs) > 0 {
-		return 0, nil, errors.New("adapter returned bosh configs but feature is turned off")
+	if d.bulkSetterConfigured() {
+		if err = d.bulkSetter.BulkSet(secrets); err != nil {
+			return 0, nil, err
+		}
 	}
+
 	if !d.DisableBoshConfigs {
-		for configType, configContent := range generateManifestOutput.Configs {
+		for configType, configContent := range configs {
 			err := d.boshClient.UpdateConfig(configType, deploymentName, []byte(configContent), logger)
 			if err != nil {
 				return 0, nil, fmt.Errorf("error updating config: %s\n", err)
@@ -275,13 +360,87 @@ func (d Deployer) doDeploy(
 		}
 	}
 
-	boshTaskID, err := d.boshClient.Deploy([]byte(manifest), boshContextID, logger, boshdirector.NewAsyncTaskReporter())
+	boshTaskID, err := d.boshClient.Deploy(manifest, boshContextID, logger, boshdirector.NewAsyncTaskReporter())
 	if err != nil {
 		return 0, nil, fmt.Errorf("error deploying instance: %s\n", err)
 	}
 	logger.Printf("Bosh task ID for %s deployment %s is %d\n", operationType, deploymentName, boshTaskID)
 
-	return boshTaskID, []byte(manifest), nil
+	return boshTaskID, manifest, nil
+}
+
+// generateManifest asks the adapter for the manifest and configs of the
+// deployment and prepares the manifest for deploying, without changing
+// anything in BOSH or CredHub.
+func (d Deployer) generateManifest(
+	ctx context.Context,
+	deploymentName,
+	planID string,
+	requestParams map[string]interface{},
+	oldManifest []byte,
+	previousPlanID *string,
+	oldSecretsMap map[string]string,
+	previousConfigs map[string]string,
+	logger *log.Logger,
+) ([]byte, map[string]string, []broker.ManifestSecret, error) {
+	generateManifestOutput, err := d.manifestGenerator.GenerateManifest(ctx, deploymentName, planID, requestParams, oldManifest, previousPlanID, oldSecretsMap, previousConfigs, logger)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	manifest := generateManifestOutput.Manifest
+
+	var secrets []broker.ManifestSecret
+	if d.bulkSetterConfigured() {
+		secrets = d.odbSecrets.GenerateSecretPaths(deploymentName, manifest, generateManifestOutput.ODBManagedSecrets)
+		manifest = d.odbSecrets.ReplaceODBRefs(generateManifestOutput.Manifest, secrets)
+	}
+
+	manifestBytes := []byte(manifest)
+	if d.RecordInstanceDetails {
+		manifestBytes, err = d.recordInstanceDetails(manifestBytes, planID, requestParams, oldManifest)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if d.DisableBoshConfigs && len(generateManifestOutput.Configs) > 0 {
+		return nil, nil, nil, errors.New("adapter returned bosh configs but feature is turned off")
+	}
+
+	return manifestBytes, generateManifestOutput.Configs, secrets, nil
+}
+
+func (d Deployer) bulkSetterConfigured() bool {
+	return d.bulkSetter != nil && !reflect.ValueOf(d.bulkSetter).IsNil()
+}
+
+func (d Deployer) recordInstanceDetails(manifest []byte, planID string, requestParams map[string]interface{}, oldManifest []byte) ([]byte, error) {
+	parameters := map[string]interface{}{}
+
+	if oldManifest != nil {
+		previousDetails, found, err := broker.InstanceDetailsFromManifest(oldManifest)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			parameters = previousDetails.Parameters
+		}
+	}
+
+	if params, ok := requestParams["parameters"].(map[string]interface{}); ok {
+		for key, value := range params {
+			parameters[key] = value
+		}
+	}
+
+	details := broker.InstanceDetails{PlanID: planID, Parameters: map[string]interface{}{}}
+	for _, name := range d.RetrievableParameters[planID] {
+		if value, found := parameters[name]; found {
+			details.Parameters[name] = value
+		}
+	}
+
+	return broker.AddInstanceDetailsToManifest(manifest, details)
 }
 
 func marshalBoshManifest(rawManifest []byte) (bosh.BoshManifest, error) {
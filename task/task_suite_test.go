@@ -42,3 +42,11 @@ func TestTask(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Task Suite")
 }
+
+func withInstanceDetails(manifest, planID, parameters string) string {
+	details := "\nodb_instance_details:\n  plan_id: " + planID + "\n"
+	if parameters != "" {
+		details += "  parameters: '" + parameters + "'\n"
+	}
+	return manifest + details
+}
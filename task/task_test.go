@@ -7,6 +7,7 @@
 package task_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -16,6 +17,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/credhub"
+	"github.com/pivotal-cf/on-demand-service-broker/manifestdiff"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
 	"github.com/pivotal-cf/on-demand-service-broker/task/fakes"
 	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
@@ -54,6 +56,11 @@ var _ = Describe("Deployer", func() {
 		odbSecrets = new(fakes.FakeODBSecrets)
 		bulkSetter = new(fakes.FakeBulkSetter)
 		deployer = task.NewDeployer(boshClient, manifestGenerator, odbSecrets, bulkSetter)
+		deployer.RecordInstanceDetails = true
+		deployer.RetrievableParameters = map[string][]string{
+			existingPlanID: {"foo", "baz"},
+			secondPlanID:   {"foo", "baz"},
+		}
 
 		planID = existingPlanID
 		previousPlanID = nil
@@ -87,6 +94,7 @@ var _ = Describe("Deployer", func() {
 	Describe("Create()", func() {
 		JustBeforeEach(func() {
 			returnedTaskID, deployedManifest, deployError = deployer.Create(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -194,12 +202,12 @@ var _ = Describe("Deployer", func() {
 
 				By("substituting the odb_secret marker with the credhub path")
 				deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
-				Expect(string(deployedManifest)).To(Equal(manifestWithPaths))
+				Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(manifestWithPaths, existingPlanID, `{"foo":"bar"}`)))
 			})
 
 			It("errors when fail to store the secret", func() {
 				bulkSetter.BulkSetReturns(errors.New("what is this?"))
-				_, _, deployError = deployer.Create(deploymentName, planID, requestParams, boshContextID, logger)
+				_, _, deployError = deployer.Create(context.Background(), deploymentName, planID, requestParams, boshContextID, logger)
 				Expect(deployError).To(MatchError(ContainSubstring("what is this?")))
 			})
 
@@ -210,7 +218,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("doesn't error", func() {
-					_, _, deployError = deployer.Create(deploymentName, planID, requestParams, boshContextID, logger)
+					_, _, deployError = deployer.Create(context.Background(), deploymentName, planID, requestParams, boshContextID, logger)
 					Expect(deployError).ToNot(HaveOccurred())
 
 					Expect(bulkSetter.BulkSetCallCount()).To(Equal(0))
@@ -239,7 +247,60 @@ var _ = Describe("Deployer", func() {
 			It("Creates a bosh deployment using provided manifest", func() {
 				Expect(boshClient.DeployCallCount()).To(Equal(1))
 				deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
-				Expect(string(deployedManifest)).To(Equal(generatedManifest))
+				Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, `{"foo":"bar"}`)))
+			})
+
+			It("returns the deployed manifest", func() {
+				Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, `{"foo":"bar"}`)))
+			})
+
+			Context("when the request has no arbitrary parameters", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{"parameters": map[string]interface{}{}}
+				})
+
+				It("records only the plan in the manifest", func() {
+					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, "")))
+				})
+			})
+
+			Context("when the request has parameters the plan does not allow to be retrieved", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{"foo": "bar", "admin_password": "secret"},
+					}
+				})
+
+				It("records only the retrievable parameters in the manifest", func() {
+					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, `{"foo":"bar"}`)))
+					Expect(string(deployedManifest)).NotTo(ContainSubstring("secret"))
+				})
+			})
+
+			Context("when the plan has no retrievable parameters", func() {
+				BeforeEach(func() {
+					deployer.RetrievableParameters = nil
+				})
+
+				It("records only the plan in the manifest", func() {
+					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, "")))
+				})
+			})
+
+			Context("when instances are not retrievable", func() {
+				BeforeEach(func() {
+					deployer.RecordInstanceDetails = false
+					generatedManifest = "name: a-manifest\n# kept as generated\nreleases: []\n"
+					manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: generatedManifest}, nil)
+				})
+
+				It("deploys the generated manifest unchanged", func() {
+					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(Equal(generatedManifest))
+				})
 			})
 
 			It("does not return an error", func() {
@@ -351,6 +412,7 @@ var _ = Describe("Deployer", func() {
 	Describe("Upgrade()", func() {
 		JustBeforeEach(func() {
 			returnedTaskID, deployedManifest, deployError = deployer.Upgrade(
+				context.Background(),
 				deploymentName,
 				planID,
 				previousPlanID,
@@ -395,11 +457,23 @@ var _ = Describe("Deployer", func() {
 			It("Creates a bosh deployment using generated manifest", func() {
 				Expect(boshClient.DeployCallCount()).To(Equal(1))
 				deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
-				Expect(string(deployedManifest)).To(Equal(generatedManifest))
+				Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, "")))
 			})
 
 			It("return the newly generated manifest", func() {
-				Expect(string(deployedManifest)).To(Equal(generatedManifest))
+				Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, "")))
+			})
+
+			Context("when the deployed manifest records instance parameters", func() {
+				BeforeEach(func() {
+					oldManifest = []byte(withInstanceDetails("name: a-manifest", "old-plan-id", `{"foo":"bar"}`))
+					boshClient.GetDeploymentReturns(oldManifest, true, nil)
+				})
+
+				It("carries the parameters over to the new manifest", func() {
+					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, `{"foo":"bar"}`)))
+				})
 			})
 
 			It("does not return an error", func() {
@@ -454,7 +528,7 @@ var _ = Describe("Deployer", func() {
 
 		It("returns the bosh task ID and new manifest", func() {
 			Expect(returnedTaskID).To(Equal(42))
-			Expect(string(deployedManifest)).To(Equal(generatedManifest))
+			Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, "")))
 			Expect(deployError).NotTo(HaveOccurred())
 		})
 
@@ -555,7 +629,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("sends the old configs to service adapter", func() {
-				_, _, _, _, _, _, previousConfigs, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, _, _, _, _, _, _, previousConfigs, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 				Expect(previousConfigs).To(Equal(configsMap))
 			})
 		})
@@ -607,6 +681,7 @@ var _ = Describe("Deployer", func() {
 				copyParams[k] = v
 			}
 			_, _, err := deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				params,
@@ -618,13 +693,14 @@ var _ = Describe("Deployer", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-			_, _, actualRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+			_, _, _, actualRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 			Expect(actualRequestParams).To(Equal(copyParams))
 		})
 
 		Context("passing secret map", func() {
 			It("manifest regeneration is passed the secrets map", func() {
 				_, _, err := deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					nil,
@@ -637,7 +713,7 @@ var _ = Describe("Deployer", func() {
 
 				Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
 				for i := 0; i < 2; i++ {
-					_, _, _, _, _, actualSecretsMap, _, _ := manifestGenerator.GenerateManifestArgsForCall(i)
+					_, _, _, _, _, _, actualSecretsMap, _, _ := manifestGenerator.GenerateManifestArgsForCall(i)
 					Expect(actualSecretsMap).To(Equal(secretsMap), fmt.Sprintf("call %d", i+1))
 				}
 			})
@@ -650,6 +726,7 @@ var _ = Describe("Deployer", func() {
 
 			It("wraps the error", func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -668,6 +745,7 @@ var _ = Describe("Deployer", func() {
 				BeforeEach(func() {
 					requestParams = map[string]interface{}{"foo": "bar"}
 					manifestGenerator.GenerateManifestStub = func(
+						_ context.Context,
 						_, _ string,
 						requestParams map[string]interface{},
 						previousManifest []byte,
@@ -687,6 +765,7 @@ var _ = Describe("Deployer", func() {
 
 				It("deploys successfully", func() {
 					returnedTaskID, deployedManifest, deployError = deployer.Update(
+						context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -706,15 +785,15 @@ var _ = Describe("Deployer", func() {
 
 					Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
 
-					_, _, passedRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+					_, _, _, passedRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 					Expect(passedRequestParams).To(BeEmpty())
 
-					_, _, passedRequestParams, _, _, _, _, _ = manifestGenerator.GenerateManifestArgsForCall(1)
+					_, _, _, passedRequestParams, _, _, _, _, _ = manifestGenerator.GenerateManifestArgsForCall(1)
 					Expect(passedRequestParams).To(Equal(requestParams))
 
 					Expect(boshClient.DeployCallCount()).To(Equal(1))
 					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
-					Expect(string(deployedManifest)).To(Equal(string(generatedManifest)))
+					Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, existingPlanID, "")))
 
 					Expect(returnedTaskID).To(Equal(boshTaskID))
 				})
@@ -724,6 +803,7 @@ var _ = Describe("Deployer", func() {
 						requestParams = map[string]interface{}{}
 
 						returnedTaskID, deployedManifest, deployError = deployer.Update(
+							context.Background(),
 							deploymentName,
 							planID,
 							requestParams,
@@ -736,11 +816,39 @@ var _ = Describe("Deployer", func() {
 						Expect(deployError).NotTo(HaveOccurred())
 					})
 				})
+
+				Context("and the deployed manifest records instance parameters", func() {
+					BeforeEach(func() {
+						oldManifest = []byte(withInstanceDetails("name: a-manifest", existingPlanID, `{"foo":"bar","baz":1}`))
+						boshClient.GetDeploymentReturns(oldManifest, true, nil)
+					})
+
+					It("records the previous parameters merged with the requested ones", func() {
+						requestParams = map[string]interface{}{
+							"parameters": map[string]interface{}{"foo": "qux"},
+						}
+
+						_, deployedManifest, deployError = deployer.Update(
+							context.Background(),
+							deploymentName,
+							secondPlanID,
+							requestParams,
+							previousPlanID,
+							boshContextID,
+							secretsMap,
+							logger,
+						)
+
+						Expect(deployError).NotTo(HaveOccurred())
+						Expect(string(deployedManifest)).To(MatchYAML(withInstanceDetails(generatedManifest, secondPlanID, `{"baz":1,"foo":"qux"}`)))
+					})
+				})
 			})
 
 			Context("and the manifest generator fails to generate the manifest the second time", func() {
 				BeforeEach(func() {
 					manifestGenerator.GenerateManifestStub = func(
+						_ context.Context,
 						_, _ string,
 						requestParams map[string]interface{},
 						previousManifest []byte,
@@ -758,6 +866,7 @@ var _ = Describe("Deployer", func() {
 
 				It("wraps the error", func() {
 					returnedTaskID, deployedManifest, deployError = deployer.Update(
+						context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -780,6 +889,7 @@ var _ = Describe("Deployer", func() {
 
 			It("fails without deploying", func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -795,6 +905,37 @@ var _ = Describe("Deployer", func() {
 			})
 		})
 
+		It("lists the fields that differ when there are pending changes", func() {
+			boshClient.GetDeploymentReturns([]byte(`---
+name: a-manifest
+releases:
+- name: redis
+  version: 1
+update:
+  canaries: 1
+  max_in_flight: 1
+`), true, nil)
+			previousPlanID = stringPointer(existingPlanID)
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: `---
+name: a-manifest
+releases:
+- name: redis
+  version: 2
+update:
+  canaries: 2
+  max_in_flight: 1
+`}, nil)
+
+			_, _, deployError = deployer.Update(context.Background(), deploymentName, planID, requestParams, previousPlanID, boshContextID, secretsMap, logger)
+
+			Expect(deployError).To(MatchError("There are pending changes"))
+			pendingChangesErr, ok := deployError.(broker.PendingChangesNotAppliedError)
+			Expect(ok).To(BeTrue())
+			Expect(pendingChangesErr.Changes).To(Equal([]manifestdiff.Change{
+				{Path: "/releases/name=redis/version", Type: manifestdiff.Changed, Old: "1", New: "2"},
+			}))
+		})
+
 		It("fails when previous manifest contains odb managed secret syntax", func() {
 			manifestWithSecrets := serviceadapter.MarshalledGenerateManifest{
 				Manifest: "name: a-manifest\nproperties:\n  password: ((odb_secret:the_password))",
@@ -813,6 +954,7 @@ var _ = Describe("Deployer", func() {
 			odbSecrets.ReplaceODBRefsReturns(manifestWithInterpolatedSecrets)
 
 			_, deployedManifest, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -832,6 +974,7 @@ var _ = Describe("Deployer", func() {
 
 			It("returns a deployment not found error", func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -853,6 +996,7 @@ var _ = Describe("Deployer", func() {
 
 			It("wraps the error", func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -873,6 +1017,7 @@ var _ = Describe("Deployer", func() {
 
 			It("returns a deployment not found error", func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -894,6 +1039,7 @@ var _ = Describe("Deployer", func() {
 
 			JustBeforeEach(func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -905,7 +1051,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("sends the old configs to service adapter", func() {
-				_, _, _, _, _, _, previousConfigs, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, _, _, _, _, _, _, previousConfigs, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 				Expect(previousConfigs).To(Equal(configsMap))
 			})
 		})
@@ -917,6 +1063,7 @@ var _ = Describe("Deployer", func() {
 
 			JustBeforeEach(func() {
 				returnedTaskID, deployedManifest, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -940,6 +1087,7 @@ var _ = Describe("Deployer", func() {
 
 			It("doesn't call UpdateConfig or GetConfigs", func() {
 				returnedTaskID, _, deployError = deployer.Update(
+					context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -967,6 +1115,7 @@ var _ = Describe("Deployer", func() {
 			boshClient.DeployReturns(42, nil)
 
 			returnedTaskID, deployedManifest, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -979,11 +1128,11 @@ var _ = Describe("Deployer", func() {
 			Expect(deployError).To(BeNil())
 
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-			_, _, passedRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+			_, _, _, passedRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 			Expect(passedRequestParams).To(Equal(requestParams))
 
 			manifestToDeploy, _, _, _ := boshClient.DeployArgsForCall(0)
-			Expect(string(manifestToDeploy)).To(Equal(string(generatedManifest)))
+			Expect(string(manifestToDeploy)).To(MatchYAML(withInstanceDetails(string(generatedManifest), existingPlanID, "")))
 		})
 
 		It("ignores the update block in an instance_group when the manifest generator generates a new manifest with a different update block", func() {
@@ -1013,6 +1162,7 @@ instance_groups:
 			boshClient.DeployReturns(42, nil)
 
 			returnedTaskID, deployedManifest, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1025,11 +1175,11 @@ instance_groups:
 			Expect(deployError).To(BeNil())
 
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-			_, _, passedRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+			_, _, _, passedRequestParams, _, _, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 			Expect(passedRequestParams).To(Equal(requestParams))
 
 			manifestToDeploy, _, _, _ := boshClient.DeployArgsForCall(0)
-			Expect(string(manifestToDeploy)).To(Equal(string(generatedManifest)))
+			Expect(string(manifestToDeploy)).To(MatchYAML(withInstanceDetails(string(generatedManifest), existingPlanID, "")))
 		})
 
 		It("detects changes to the tags block in a manifest and prevents deployment", func() {
@@ -1047,6 +1197,7 @@ tags:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
 			_, _, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1076,6 +1227,7 @@ features:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
 			_, _, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1105,6 +1257,7 @@ features:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
 			_, _, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1142,6 +1295,7 @@ instance_groups:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
 			_, _, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1169,6 +1323,7 @@ instance_groups:
 			boshClient.DeployReturns(42, nil)
 
 			returnedTaskID, deployedManifest, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1195,6 +1350,7 @@ instance_groups:
 			boshClient.DeployReturns(42, nil)
 
 			returnedTaskID, deployedManifest, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1209,6 +1365,117 @@ instance_groups:
 		})
 	})
 
+	Describe("PreviewUpgrade()", func() {
+		var (
+			preview    broker.UpgradePreview
+			previewErr error
+		)
+
+		JustBeforeEach(func() {
+			preview, previewErr = deployer.PreviewUpgrade(context.Background(), deploymentName, planID, previousPlanID, logger)
+		})
+
+		BeforeEach(func() {
+			oldManifest = []byte(withInstanceDetails("name: a-manifest\nstemcells: [{alias: default, version: '1'}]", existingPlanID, ""))
+			previousPlanID = stringPointer(existingPlanID)
+
+			boshClient.GetDeploymentReturns(oldManifest, true, nil)
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{
+				Manifest: "name: a-manifest\nstemcells: [{alias: default, version: '2'}]",
+			}, nil)
+		})
+
+		It("returns the changes to the manifest", func() {
+			Expect(previewErr).NotTo(HaveOccurred())
+			Expect(preview.ManifestChanges).To(Equal([]manifestdiff.Change{
+				{Path: "/stemcells/0/version", Type: manifestdiff.Changed, Old: "1", New: "2"},
+			}))
+			Expect(preview.ConfigChanges).To(BeEmpty())
+		})
+
+		It("generates the manifest from the deployed manifest and configs", func() {
+			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(1))
+			_, actualDeploymentName, actualPlanID, _, actualOldManifest, actualPreviousPlanID, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualPlanID).To(Equal(planID))
+			Expect(actualOldManifest).To(Equal(oldManifest))
+			Expect(actualPreviousPlanID).To(Equal(previousPlanID))
+			Expect(boshClient.GetConfigsCallCount()).To(Equal(1))
+		})
+
+		It("does not deploy, update configs or store secrets", func() {
+			Expect(boshClient.GetTasksCallCount()).To(Equal(0))
+			Expect(boshClient.DeployCallCount()).To(Equal(0))
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(0))
+			Expect(bulkSetter.BulkSetCallCount()).To(Equal(0))
+		})
+
+		Context("when the adapter returns bosh configs", func() {
+			BeforeEach(func() {
+				boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+					{Type: "cloud", Name: deploymentName, Content: "vm_types: [{name: small}]"},
+					{Type: "cpi", Name: deploymentName, Content: "cpis: []"},
+				}, nil)
+				manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{
+					Manifest: "name: a-manifest",
+					Configs: serviceadapter.BOSHConfigs{
+						"cloud":   "vm_types: [{name: small}, {name: large}]",
+						"cpi":     "cpis: []",
+						"runtime": "addons: []",
+					},
+				}, nil)
+			})
+
+			It("returns the changes to each config that differs", func() {
+				Expect(previewErr).NotTo(HaveOccurred())
+				Expect(preview.ConfigChanges).To(Equal(map[string][]manifestdiff.Change{
+					"cloud": {
+						{Path: "/vm_types/name=large", Type: manifestdiff.Added, New: map[string]interface{}{"name": "large"}},
+					},
+					"runtime": {
+						{Path: "/addons", Type: manifestdiff.Added, New: []interface{}{}},
+					},
+				}))
+			})
+		})
+
+		Context("when bosh configs are disabled and the adapter returns configs", func() {
+			BeforeEach(func() {
+				deployer.DisableBoshConfigs = true
+				manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{
+					Manifest: "name: a-manifest",
+					Configs:  serviceadapter.BOSHConfigs{"cloud": "vm_types: []"},
+				}, nil)
+			})
+
+			It("returns an error without fetching the configs", func() {
+				Expect(previewErr).To(MatchError("adapter returned bosh configs but feature is turned off"))
+				Expect(boshClient.GetConfigsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the deployment cannot be found", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentReturns(nil, false, nil)
+			})
+
+			It("returns a deployment not found error", func() {
+				Expect(previewErr).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+				Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the manifest cannot be generated", func() {
+			BeforeEach(func() {
+				manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{}, errors.New("adapter failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(previewErr).To(MatchError("adapter failed"))
+			})
+		})
+	})
+
 	Describe("Recreate", func() {
 		var err error
 